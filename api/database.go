@@ -1,15 +1,79 @@
 package api
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"time"
+
+	"github.com/XSAM/otelsql"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/migrations"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func ConnectionDatabases() *sql.DB {
-	db, err := sql.Open("mysql", "root:root@tcp(localhost:3306)/sinaustudio?autocommit=true")
+// dbSystem is the db.system span attribute of each dialect.
+var dbSystem = map[repository.Dialect]string{
+	repository.MySQL:    "mysql",
+	repository.Postgres: "postgresql",
+	repository.SQLite:   "sqlite",
+}
+
+// ConnectionDatabases opens the database through otelsql, so every query made
+// with a request's ctx shows up as a span of its trace. sql.Open does not
+// connect, so it pings until the database answers or DBConnectTimeout runs
+// out; a database container that starts alongside the app gets the time it
+// needs instead of failing the first request.
+func ConnectionDatabases(config *Config) (*sql.DB, error) {
+	db, err := otelsql.Open(config.DBDriver, config.DBDSN,
+		otelsql.WithAttributes(attribute.String("db.system", dbSystem[config.Dialect])),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	if err := pingWithRetry(db, config.DBConnectTimeout); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if config.DBMigrate {
+		if err := migrations.Up(context.Background(), db, config.Dialect); err != nil {
+			db.Close()
+			return nil, err
+		}
 	}
 
-	return db
+	return db, nil
+}
+
+const (
+	pingBackoffMin = 500 * time.Millisecond
+	pingBackoffMax = 5 * time.Second
+)
+
+// pingWithRetry pings db, doubling the wait between attempts from
+// pingBackoffMin up to pingBackoffMax, and returns the last error once timeout
+// has passed. A timeout of 0 pings once.
+func pingWithRetry(db *sql.DB, timeout time.Duration) error {
+	log := logging.Default().Named("api")
+	deadline := time.Now().Add(timeout)
+	backoff := pingBackoffMin
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), pingBackoffMax)
+		err := db.PingContext(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return err
+		}
+		log.Warn("database belum dapat dihubungi, mencoba lagi", "attempt", attempt, "retry_in", backoff, "error", err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > pingBackoffMax {
+			backoff = pingBackoffMax
+		}
+	}
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/repository/memory"
 )
 
 type MicroServiceServer interface {
@@ -11,19 +12,36 @@ type MicroServiceServer interface {
 	LectureRepository() repository.LectureRepository
 	RoomRepository() repository.RoomRepository
 	MatkulRepository() repository.MataKuliahRepository
+	ClassMemberRepository() repository.ClassMemberRepository
+	ProfileRepository() repository.ProfileRepository
+	MaterialRepository() repository.MaterialRepository
 }
 
 type MicroService struct {
-	User    repository.UsersRepository
-	Auth    repository.AuthRepository
-	Class   repository.ClassRepository
-	Lecture repository.LectureRepository
-	Room    repository.RoomRepository
-	Matkul  repository.MataKuliahRepository
+	User     repository.UsersRepository
+	Auth     repository.AuthRepository
+	Class    repository.ClassRepository
+	Lecture  repository.LectureRepository
+	Room     repository.RoomRepository
+	Matkul   repository.MataKuliahRepository
+	Member   repository.ClassMemberRepository
+	Profile  repository.ProfileRepository
+	Material repository.MaterialRepository
 }
 
-func NewMicroService(usersRepository repository.UsersRepository, authRepository repository.AuthRepository, classRepository repository.ClassRepository, lectureRepository repository.LectureRepository, roomRepository repository.RoomRepository, matkulRepositoru repository.MataKuliahRepository) MicroServiceServer {
-	return &MicroService{User: usersRepository, Auth: authRepository, Class: classRepository, Lecture: lectureRepository, Room: roomRepository, Matkul: matkulRepositoru}
+func NewMicroService(usersRepository repository.UsersRepository, authRepository repository.AuthRepository, classRepository repository.ClassRepository, lectureRepository repository.LectureRepository, roomRepository repository.RoomRepository, matkulRepositoru repository.MataKuliahRepository, classMemberRepository repository.ClassMemberRepository, profileRepository repository.ProfileRepository, materialRepository repository.MaterialRepository) MicroServiceServer {
+	return &MicroService{User: usersRepository, Auth: authRepository, Class: classRepository, Lecture: lectureRepository, Room: roomRepository, Matkul: matkulRepositoru, Member: classMemberRepository, Profile: profileRepository, Material: materialRepository}
+}
+
+// NewSQLMicroService wires the SQL repositories of dialect.
+func NewSQLMicroService(dialect repository.Dialect) MicroServiceServer {
+	return NewMicroService(repository.NewUsersRepositoryImplementations(dialect), repository.NewAuthRepositoryImplementation(dialect), repository.NewClassRepositoryImplementation(dialect), repository.NewLectureRepositoryImplementation(dialect), repository.NewRoomRepositoryImplementation(dialect), repository.NewMataKuliahRepositoryImplementation(dialect), repository.NewClassMemberRepositoryImplementation(dialect), repository.NewProfileRepositoryImplementation(dialect), repository.NewMaterialRepositoryImplementation(dialect))
+}
+
+// NewMemoryMicroService wires the in-memory repositories sharing store. Services
+// built on it must use store.DB() as their database handle.
+func NewMemoryMicroService(store *memory.Store) MicroServiceServer {
+	return NewMicroService(memory.NewUsersRepository(store), memory.NewAuthRepository(store), memory.NewClassRepository(store), memory.NewLectureRepository(store), memory.NewRoomRepository(store), memory.NewMataKuliahRepository(store), memory.NewClassMemberRepository(store), memory.NewProfileRepository(store), memory.NewMaterialRepository(store))
 }
 
 func (m *MicroService) UserRepository() repository.UsersRepository {
@@ -49,3 +67,15 @@ func (m *MicroService) RoomRepository() repository.RoomRepository {
 func (m *MicroService) MatkulRepository() repository.MataKuliahRepository {
 	return m.Matkul
 }
+
+func (m *MicroService) ClassMemberRepository() repository.ClassMemberRepository {
+	return m.Member
+}
+
+func (m *MicroService) ProfileRepository() repository.ProfileRepository {
+	return m.Profile
+}
+
+func (m *MicroService) MaterialRepository() repository.MaterialRepository {
+	return m.Material
+}
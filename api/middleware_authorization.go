@@ -12,16 +12,26 @@ func MiddlewareAuthorization(c *gin.Context) {
 	authorization := c.Request.Header.Get("Authorization")
 	if authorization == "" {
 		errMsg := helpers.ToErrorMsg(http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER, "Key: Header with key Authorization: Bearer Token, Tag: Required")
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 	bearers := strings.Split(authorization, "Bearer")
 	if bearers[1] == "" {
 		errMsg := helpers.ToErrorMsg(http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER, "Key: Token not found, Tag: Required")
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	} else {
 		CheckingJWTToken(bearers[1], c)
+		if c.IsAborted() {
+			return
+		}
+		info, err := ParseJWTToken(bearers[1])
+		if err != nil {
+			errMsg := helpers.ToErrorMsg(http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER, "Token tidak valid!")
+			helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+			return
+		}
+		c.Request = c.Request.WithContext(ContextWithUserInfo(c.Request.Context(), info))
 		c.Next()
 	}
 }
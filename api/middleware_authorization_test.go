@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestMiddlewareAuthorizationRejectsUnparsedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The signature is valid, so CheckingJWTToken lets it through, but an id
+	// that is not a number cannot be read into CustomJWTClaims.
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":   "satu",
+		"role": "admin",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}).SignedString(mySigningKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reached bool
+	engine := gin.New()
+	engine.GET("/", MiddlewareAuthorization, func(c *gin.Context) {
+		reached = true
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusUnauthorized || reached {
+		t.Fatalf("expected 401 before the handler, got %d (handler reached: %v)", recorder.Code, reached)
+	}
+}
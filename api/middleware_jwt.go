@@ -1,10 +1,12 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 	"net/http"
@@ -29,6 +31,15 @@ type UserInfo struct {
 	ClassID int
 }
 
+// IsLecturer reports whether the user signed up as a dosen or guru.
+func (u *UserInfo) IsLecturer() bool {
+	return u.Role == "dosen" || u.Role == "guru"
+}
+
+func (u *UserInfo) IsAdmin() bool {
+	return u.Role == "admin"
+}
+
 var mySigningKey = []byte("7asd23&*^*($^&)**#$_hjagsd$#23496723")
 
 func CheckingJWTToken(tokenBearer string, c *gin.Context) {
@@ -50,7 +61,7 @@ func CheckingJWTToken(tokenBearer string, c *gin.Context) {
 	if err != nil {
 		if errors.Is(err, &jwt.ValidationError{}) {
 			errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, err.(*jwt.ValidationError).Error())
-			c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+			helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 			return
 		}
 	}
@@ -58,11 +69,62 @@ func CheckingJWTToken(tokenBearer string, c *gin.Context) {
 	_, ok := token.Claims.(jwt.MapClaims)
 	if !token.Valid || !ok {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Token tidak valid!")
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 }
 
+// ParseJWTToken validates a bearer token without a gin.Context, for transports
+// such as gRPC that carry it in metadata.
+func ParseJWTToken(tokenBearer string) (*UserInfo, error) {
+	claims := &CustomJWTClaims{RegisteredClaims: &jwt.RegisteredClaims{}}
+	token, err := jwt.ParseWithClaims(strings.TrimSpace(tokenBearer), claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("Signing method invalid")
+		}
+		return mySigningKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("Token tidak valid!")
+	}
+
+	return &UserInfo{ID: claims.ID, Name: claims.Name, Email: claims.Email, Role: claims.Role, ClassID: claims.ClassID}, nil
+}
+
+type userInfoKey struct{}
+
+// ContextWithUserInfo stores the authenticated user of a request in ctx. The
+// repositories record the user as the author of the rows the request writes.
+func ContextWithUserInfo(ctx context.Context, info *UserInfo) context.Context {
+	ctx = repository.ContextWithActor(ctx, info.ID)
+	return context.WithValue(ctx, userInfoKey{}, info)
+}
+
+// UserInfoFromContext returns the user stored by ContextWithUserInfo, if any.
+func UserInfoFromContext(ctx context.Context) (*UserInfo, bool) {
+	info, ok := ctx.Value(userInfoKey{}).(*UserInfo)
+	return info, ok
+}
+
+type systemActorKey struct{}
+
+// ContextWithSystemActor marks ctx as acting for the operator of the platform
+// rather than for a user, as the CLI commands and the seeder do. The services
+// let the system actor through their role checks and refuse a ctx that has
+// neither a user nor the system actor.
+func ContextWithSystemActor(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemActorKey{}, true)
+}
+
+// IsSystemActor reports whether ctx was marked by ContextWithSystemActor.
+func IsSystemActor(ctx context.Context) bool {
+	isSystem, _ := ctx.Value(systemActorKey{}).(bool)
+	return isSystem
+}
+
 func JWTGenereateToken(info *UserInfo) (string, error) {
 
 	claims := &CustomJWTClaims{
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery answers a panicking handler with a 500 ErrorMsg and logs the panic
+// with its stack through the request's logger, instead of gin's text output.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logging.FromContext(c.Request.Context()).Named("http").Error("panic", "panic", recovered, "stack", string(debug.Stack()))
+		errMsg := helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Terjadi kesalahan pada server.")
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+	})
+}
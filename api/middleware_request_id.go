@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/codegen"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDOrNew keeps a request ID sent by the client when it is at most 128
+// letters, digits, '-', '_', '.' or ':', and draws a new one otherwise.
+func RequestIDOrNew(requestID string) string {
+	if len(requestID) > 0 && len(requestID) <= 128 && strings.Trim(requestID, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.:") == "" {
+		return requestID
+	}
+	requestID, err := codegen.RequestID.Generate()
+	if err != nil {
+		return "unknown"
+	}
+	return requestID
+}
+
+// ContextWithRequestID stores requestID and a logger that adds it to every
+// line in ctx; services and repositories log through logging.FromContext.
+func ContextWithRequestID(ctx context.Context, log *logging.Logger, requestID string) context.Context {
+	ctx = logging.ContextWithRequestID(ctx, requestID)
+	return logging.NewContext(ctx, log.With("request_id", requestID))
+}
+
+// quietPaths are polled by load balancers and orchestrators every few
+// seconds; their successful requests are logged at debug level only.
+var quietPaths = map[string]bool{"/healthz": true, "/readyz": true}
+
+// RequestLogger takes the place of gin's text logger. It assigns every request
+// an X-Request-ID, echoes it in the response header, and writes one access
+// log line when the request is done. helpers.ToErrorResponse reads the ID
+// from the request context to put it in the body of error responses.
+func RequestLogger(log *logging.Logger) gin.HandlerFunc {
+	access := log.Named("http")
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := RequestIDOrNew(c.GetHeader(RequestIDHeader))
+		c.Request = c.Request.WithContext(ContextWithRequestID(c.Request.Context(), log, requestID))
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+
+		level := logging.LevelInfo
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			level = logging.LevelError
+		case status >= 400:
+			level = logging.LevelWarn
+		case quietPaths[c.Request.URL.Path]:
+			level = logging.LevelDebug
+		}
+		args := []any{
+			"request_id", requestID,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		}
+		if errMsg, ok := c.Value(helpers.ErrorMsgKey).(*response.ErrorMsg); ok {
+			args = append(args, "error_key", errMsg.ErrorKey, "error", errMsg.Msg)
+		}
+		access.Log(level, "request", args...)
+	}
+}
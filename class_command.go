@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+)
+
+// runClass implements "sinaustudio class list|regen-code".
+func runClass(args []string) {
+	subcommands := map[string]func(args []string){
+		"list":       runClassList,
+		"regen-code": runClassRegenCode,
+	}
+	if len(args) == 0 || subcommands[args[0]] == nil {
+		fmt.Fprintln(os.Stderr, "Usage: sinaustudio class list|regen-code [flags]")
+		os.Exit(2)
+	}
+	subcommands[args[0]](args[1:])
+}
+
+func runClassList(args []string) {
+	flags := flag.NewFlagSet("class list", flag.ExitOnError)
+	r := &requests.ListRequest{}
+	flags.StringVar(&r.Name, "name", "", "hanya kelas yang namanya memuat teks ini")
+	flags.StringVar(&r.Sort, "sort", "", "urutan: id, name, created_at atau updated_at, awali dengan - untuk menurun")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio class list [-name NAME] [-sort SORT]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	config, db := openDatabase()
+	defer db.Close()
+
+	classService := services.NewClassServiceImplementation(db, api.NewSQLMicroService(config.Dialect))
+	classes, errMsg := classService.FindAllClass(systemContext(), r)
+	if errMsg != nil {
+		fatalMsg(errMsg)
+	}
+	printJSON(classes)
+}
+
+func runClassRegenCode(args []string) {
+	flags := flag.NewFlagSet("class regen-code", flag.ExitOnError)
+	ID := flags.Int("id", 0, "ID kelas")
+	r := &requests.RegenerateKodeKelasRequest{}
+	flags.IntVar(&r.ExpiresInHours, "expires-in-hours", 0, "masa berlaku kode baru dalam jam, 0 berarti tidak kedaluwarsa")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio class regen-code -id ID [-expires-in-hours HOURS]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *ID == 0 || r.ExpiresInHours < 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	config, db := openDatabase()
+	defer db.Close()
+
+	classService := services.NewClassServiceImplementation(db, api.NewSQLMicroService(config.Dialect))
+	class, errMsg := classService.RegenerateKodeKelas(systemContext(), *ID, r)
+	if errMsg != nil {
+		fatalMsg(errMsg)
+	}
+	printJSON(class)
+}
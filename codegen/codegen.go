@@ -0,0 +1,110 @@
+// Package codegen draws the codes users type in or receive, such as kode
+// kelas, from crypto/rand. Uniqueness is the caller's job: Unique retries
+// against a lookup, and the table should still carry a unique constraint.
+package codegen
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Alphabets without characters that are easy to confuse when read aloud or
+// typed from a screen: 0/O, 1/I/L and, in the mixed-case one, l and o.
+const (
+	Unambiguous      = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+	UnambiguousMixed = Unambiguous + "abcdefghijkmnpqrstuvwxyz"
+	Digits           = "0123456789"
+)
+
+// MaxAttempts is how many codes Unique draws, and how often callers retry a
+// write rejected by the unique constraint, before giving up.
+const MaxAttempts = 5
+
+var ErrExhausted = errors.New("codegen: tidak menemukan kode yang belum digunakan")
+
+// Spec describes one kind of code. The predefined specs below may be
+// reassigned at startup to change an alphabet or length.
+type Spec struct {
+	Alphabet string
+	Length   int
+}
+
+var (
+	ClassCode = Spec{Alphabet: Unambiguous, Length: 10}
+	Password  = Spec{Alphabet: UnambiguousMixed, Length: 12}
+	RequestID = Spec{Alphabet: UnambiguousMixed, Length: 20}
+	FileToken = Spec{Alphabet: UnambiguousMixed, Length: 16}
+)
+
+// random is crypto/rand.Reader; tests swap it to force collisions.
+var random io.Reader = rand.Reader
+
+func (s Spec) Validate() error {
+	if s.Length < 1 {
+		return fmt.Errorf("codegen: panjang kode harus lebih dari 0, bukan %d", s.Length)
+	}
+	if len(s.Alphabet) < 2 || len(s.Alphabet) > 256 {
+		return fmt.Errorf("codegen: alfabet harus berisi 2 sampai 256 karakter, bukan %d", len(s.Alphabet))
+	}
+	seen := map[byte]bool{}
+	for i := 0; i < len(s.Alphabet); i++ {
+		if s.Alphabet[i] >= 0x80 {
+			return fmt.Errorf("codegen: alfabet hanya boleh berisi karakter ASCII")
+		}
+		if seen[s.Alphabet[i]] {
+			return fmt.Errorf("codegen: karakter %q muncul dua kali di alfabet", s.Alphabet[i])
+		}
+		seen[s.Alphabet[i]] = true
+	}
+	return nil
+}
+
+// Generate draws a code with every character of the alphabet equally likely.
+// Random bytes that would bias the result towards the start of the alphabet
+// are thrown away.
+func (s Spec) Generate() (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	n := len(s.Alphabet)
+	limit := 256 - 256%n
+	code := make([]byte, 0, s.Length)
+	buf := make([]byte, s.Length+s.Length/2)
+	for len(code) < s.Length {
+		if _, err := io.ReadFull(random, buf); err != nil {
+			return "", fmt.Errorf("codegen: %w", err)
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			code = append(code, s.Alphabet[int(b)%n])
+			if len(code) == s.Length {
+				break
+			}
+		}
+	}
+	return string(code), nil
+}
+
+// Unique draws up to MaxAttempts codes and returns the first one exists
+// reports as free.
+func (s Spec) Unique(exists func(code string) (bool, error)) (string, error) {
+	for attempt := 0; attempt < MaxAttempts; attempt++ {
+		code, err := s.Generate()
+		if err != nil {
+			return "", err
+		}
+		taken, err := exists(code)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return code, nil
+		}
+	}
+	return "", ErrExhausted
+}
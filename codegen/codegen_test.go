@@ -0,0 +1,73 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateUsesAlphabet(t *testing.T) {
+	for _, spec := range []Spec{ClassCode, Password, RequestID, FileToken} {
+		code, err := spec.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != spec.Length {
+			t.Fatalf("expected %d characters, got %q", spec.Length, code)
+		}
+		for _, r := range code {
+			if !strings.ContainsRune(spec.Alphabet, r) {
+				t.Fatalf("character %q of %q is not in %q", r, code, spec.Alphabet)
+			}
+		}
+	}
+
+	for _, ambiguous := range "0O1IL" {
+		if strings.ContainsRune(Unambiguous, ambiguous) {
+			t.Fatalf("Unambiguous contains %q", ambiguous)
+		}
+	}
+}
+
+func TestGenerateRejectsBiasedBytes(t *testing.T) {
+	original := random
+	defer func() { random = original }()
+
+	// 255 and 254 are above the largest multiple of 10, so only 7 and 3 are used.
+	random = bytes.NewReader([]byte{255, 7, 254, 3, 0, 0, 0, 0, 0, 0})
+	code, err := Spec{Alphabet: Digits, Length: 2}.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "73" {
+		t.Fatalf("expected 73, got %q", code)
+	}
+}
+
+func TestUniqueRetries(t *testing.T) {
+	var tried []string
+	code, err := ClassCode.Unique(func(code string) (bool, error) {
+		tried = append(tried, code)
+		return len(tried) < 3, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tried) != 3 || code != tried[2] {
+		t.Fatalf("expected the third code, got %q after %v", code, tried)
+	}
+
+	_, err = ClassCode.Unique(func(string) (bool, error) { return true, nil })
+	if !errors.Is(err, ErrExhausted) {
+		t.Fatalf("expected ErrExhausted, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, spec := range []Spec{{Alphabet: Digits}, {Alphabet: "A", Length: 4}, {Alphabet: "ABA", Length: 4}} {
+		if _, err := spec.Generate(); err == nil {
+			t.Fatalf("expected %+v to be rejected", spec)
+		}
+	}
+}
@@ -1,12 +1,12 @@
 package controllers
 
 import (
-	"fmt"
 	"github.com/dimassfeb-09/sinaustudio.git/api"
 	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
 	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
 	"github.com/dimassfeb-09/sinaustudio.git/services"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -32,13 +32,13 @@ func (a *AuthControllerImplementation) AuthRegisterUser(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := a.AuthService.AuthRegisterUser(c.Request.Context(), &auth)
 	if errMsg != nil {
-		c.JSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -58,16 +58,16 @@ func (a *AuthControllerImplementation) AuthLoginUser(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
-	fmt.Println(user)
+	logging.FromContext(c.Request.Context()).Named("controllers").Debug("login", "email", user.Email)
 
 	userInfo, errMsg := a.AuthService.AuthLoginUser(c.Request.Context(), user.Email, user.Password)
 	if errMsg != nil && userInfo == nil {
 
-		c.JSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -82,7 +82,7 @@ func (a *AuthControllerImplementation) AuthLoginUser(c *gin.Context) {
 		}
 		token, err := api.JWTGenereateToken(userInfoJWT)
 		if err != nil {
-			c.JSON(errMsg.StatusCode, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, err))
+			helpers.ToErrorResponse(c, http.StatusBadRequest, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, err))
 			return
 		}
 
@@ -90,9 +90,7 @@ func (a *AuthControllerImplementation) AuthLoginUser(c *gin.Context) {
 			Success:    true,
 			StatusCode: http.StatusOK,
 			Msg:        "Sukses Login",
-			Data: map[string]any{
-				"token": token,
-			},
+			Data:       &response.AuthTokenResponse{Token: token},
 		})
 		return
 	}
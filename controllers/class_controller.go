@@ -34,13 +34,13 @@ func (k ClassControllerImplementation) AddClass(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := k.ClassService.AddClass(c.Request.Context(), &class)
 	if !isSuccess && errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -61,7 +61,7 @@ func (k ClassControllerImplementation) UpdateClass(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, must integer")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -70,14 +70,14 @@ func (k ClassControllerImplementation) UpdateClass(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	class.ID = ID
 	isSuccess, errMsg := k.ClassService.UpdateClass(c.Request.Context(), &class)
 	if !isSuccess && errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -96,13 +96,13 @@ func (k ClassControllerImplementation) DeleteClassByID(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Invalid ID, fill with number/integer")
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := k.ClassService.DeleteClassByID(c.Request.Context(), ID)
 	if !isSuccess && errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -121,13 +121,13 @@ func (k ClassControllerImplementation) FindClassByID(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Invalid ID, fill with number/integer")
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
 	result, isIDRegistered, errMsg := k.ClassService.FindClassByID(c.Request.Context(), ID)
 	if !isIDRegistered && errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -148,7 +148,7 @@ func (k ClassControllerImplementation) FindClassByName(c *gin.Context) {
 	name := c.Query("name")
 	result, isIDRegistered, errMsg := k.ClassService.FindClassByName(c.Request.Context(), name)
 	if !isIDRegistered && errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
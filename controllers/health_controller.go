@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HealthPath = "/healthz"
+	ReadyPath  = "/readyz"
+)
+
+type HealthController interface {
+	Health(c *gin.Context)
+	Ready(c *gin.Context)
+}
+
+type HealthControllerImplementation struct {
+	HealthService services.HealthService
+}
+
+func NewHealthController(healthService services.HealthService) HealthController {
+	return &HealthControllerImplementation{HealthService: healthService}
+}
+
+// Health answers 200 as long as the process serves HTTP; it checks nothing
+// else, so a database outage does not get the process restarted.
+func (h *HealthControllerImplementation) Health(c *gin.Context) {
+	c.JSON(http.StatusOK, &response.HealthResponse{Status: services.HealthOK})
+}
+
+// Ready answers 503 while the database cannot take requests, so the load
+// balancer stops routing to this instance until it can.
+func (h *HealthControllerImplementation) Ready(c *gin.Context) {
+	health, isReady := h.HealthService.Ready(c.Request.Context())
+	if !isReady {
+		c.JSON(http.StatusServiceUnavailable, health)
+		return
+	}
+	c.JSON(http.StatusOK, health)
+}
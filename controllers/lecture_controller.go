@@ -32,13 +32,13 @@ func (l *LectureControllerImplementation) InsertLecture(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := l.LectureService.InsertLecture(c.Request.Context(), &lecture)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -58,7 +58,7 @@ func (l *LectureControllerImplementation) UpdateLecture(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -67,14 +67,14 @@ func (l *LectureControllerImplementation) UpdateLecture(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	lecture.ID = ID
 	isSuccess, errMsg := l.LectureService.UpdateLecture(c.Request.Context(), &lecture)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -93,13 +93,13 @@ func (l *LectureControllerImplementation) DeleteLecture(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := l.LectureService.DeleteLectureByID(c.Request.Context(), ID)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -119,13 +119,13 @@ func (l *LectureControllerImplementation) FindLectureByID(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	lecture, isIDValid, errMsg := l.LectureService.FindLectureByID(c.Request.Context(), ID)
 	if errMsg != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -146,7 +146,7 @@ func (l *LectureControllerImplementation) FindLectureByName(c *gin.Context) {
 	name := c.Query("name")
 	lecture, isIDValid, errMsg := l.LectureService.FindLectureByName(c.Request.Context(), name)
 	if errMsg != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
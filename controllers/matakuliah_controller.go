@@ -32,13 +32,13 @@ func (l *MatkulControllerImplementation) InsertMatkul(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := l.MataKuliahService.InsertMatkul(c.Request.Context(), &matkul)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -58,7 +58,7 @@ func (l *MatkulControllerImplementation) UpdateMatkul(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -67,14 +67,25 @@ func (l *MatkulControllerImplementation) UpdateMatkul(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
-	matkul.ID = ID
+	version, ok := expectedVersion(c, matkul.Version)
+	if !ok {
+		return
+	}
+
+	matkul.ID, matkul.Version = ID, version
 	isSuccess, errMsg := l.MataKuliahService.UpdateMatkul(c.Request.Context(), &matkul)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		abortUpdate(c, errMsg, func() (any, int, bool) {
+			current, found, _ := l.MataKuliahService.FindMatkulByID(c.Request.Context(), ID)
+			if !found {
+				return nil, 0, false
+			}
+			return current, current.Version, true
+		})
 		return
 	}
 
@@ -93,13 +104,13 @@ func (l *MatkulControllerImplementation) DeleteMatkul(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
-	isSuccess, errMsg := l.MataKuliahService.DeleteMatkulByID(c.Request.Context(), ID)
+	isSuccess, errMsg := l.MataKuliahService.DeleteMatkulByID(c.Request.Context(), ID, 0)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -119,17 +130,18 @@ func (l *MatkulControllerImplementation) FindMatkulByID(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	matkul, isIDValid, errMsg := l.MataKuliahService.FindMatkulByID(c.Request.Context(), ID)
 	if errMsg != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	if isIDValid {
+		setETag(c, matkul.Version)
 		c.JSON(http.StatusOK, &response.SuccessResponse{
 			Success:    true,
 			StatusCode: http.StatusOK,
@@ -146,7 +158,7 @@ func (l *MatkulControllerImplementation) FindMatkulByName(c *gin.Context) {
 	name := c.Query("name")
 	matkuls, errMsg := l.MataKuliahService.FindMatkulByName(c.Request.Context(), name)
 	if errMsg != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -32,13 +32,13 @@ func (l *RoomControllerImplementation) InsertRoom(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := l.RoomService.InsertRoom(c.Request.Context(), &room)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -58,7 +58,7 @@ func (l *RoomControllerImplementation) UpdateRoom(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -67,14 +67,25 @@ func (l *RoomControllerImplementation) UpdateRoom(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
-	room.ID = ID
+	version, ok := expectedVersion(c, room.Version)
+	if !ok {
+		return
+	}
+
+	room.ID, room.Version = ID, version
 	isSuccess, errMsg := l.RoomService.UpdateRoom(c.Request.Context(), &room)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		abortUpdate(c, errMsg, func() (any, int, bool) {
+			current, found, _ := l.RoomService.FindRoomByID(c.Request.Context(), ID)
+			if !found {
+				return nil, 0, false
+			}
+			return current, current.Version, true
+		})
 		return
 	}
 
@@ -93,13 +104,13 @@ func (l *RoomControllerImplementation) DeleteRoom(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
-	isSuccess, errMsg := l.RoomService.DeleteRoomByID(c.Request.Context(), ID)
+	isSuccess, errMsg := l.RoomService.DeleteRoomByID(c.Request.Context(), ID, 0)
 	if errMsg != nil && !isSuccess {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -119,17 +130,18 @@ func (l *RoomControllerImplementation) FindRoomByID(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	room, isIDValid, errMsg := l.RoomService.FindRoomByID(c.Request.Context(), ID)
 	if errMsg != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	if isIDValid {
+		setETag(c, room.Version)
 		c.JSON(http.StatusOK, &response.SuccessResponse{
 			Success:    true,
 			StatusCode: http.StatusOK,
@@ -35,13 +35,13 @@ func (u *UsersControllerImplementation) InsertDataUser(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := u.UsersService.InsertDataUser(c.Request.Context(), &user)
 	if errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -61,13 +61,13 @@ func (u *UsersControllerImplementation) UpdateDataUser(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := u.UsersService.UpdateDataUser(c.Request.Context(), &user)
 	if errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -86,7 +86,7 @@ func (u *UsersControllerImplementation) DeleteDataUser(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -95,13 +95,13 @@ func (u *UsersControllerImplementation) DeleteDataUser(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := u.UsersService.DeleteDataUser(c.Request.Context(), user.ConfirmPassword, ID)
 	if errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
@@ -130,7 +130,7 @@ func (u *UsersControllerImplementation) ChangePasswordUser(c *gin.Context) {
 	ID, err := strconv.Atoi(c.Query("id"))
 	if err != nil {
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", "Invalid ID, fill with number ID")
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
@@ -139,13 +139,13 @@ func (u *UsersControllerImplementation) ChangePasswordUser(c *gin.Context) {
 	if err != nil {
 		errorList := helpers.ErrorValidateHandler(err)
 		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, "ERR_BAD_REQUEST_FIELD", errorList)
-		c.JSON(http.StatusBadRequest, errMsg)
+		helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
 		return
 	}
 
 	isSuccess, errMsg := u.UsersService.ChangePasswordUser(c.Request.Context(), ID, user.RecentPassword, user.NewPassword)
 	if !isSuccess && errMsg != nil {
-		c.AbortWithStatusJSON(errMsg.StatusCode, errMsg)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
 		return
 	}
 
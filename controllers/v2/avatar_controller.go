@@ -0,0 +1,157 @@
+package v2
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+// avatarCacheControl lets browsers and proxies keep an avatar for good: a new
+// picture is served under a new URL.
+const avatarCacheControl = "public, max-age=31536000, immutable"
+
+type AvatarController interface {
+	UploadAvatar(c *gin.Context)
+	DeleteAvatar(c *gin.Context)
+	GetAvatar(c *gin.Context)
+}
+
+type AvatarControllerImplementation struct {
+	AvatarService services.AvatarService
+	Users         *UsersControllerImplementation
+}
+
+func NewAvatarController(avatarService services.AvatarService, usersService services.UsersService) AvatarController {
+	return &AvatarControllerImplementation{AvatarService: avatarService, Users: &UsersControllerImplementation{UsersService: usersService}}
+}
+
+// UploadAvatar reads the multipart "avatar" field and makes it the avatar of
+// the user, answering the user with the profile pointing at it.
+func (a *AvatarControllerImplementation) UploadAvatar(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+
+	// The multipart envelope gets some room on top of the picture itself,
+	// which the service holds to MaxAvatarBytes.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxAvatarBytes+64<<10)
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "File avatar wajib diunggah pada field avatar.")
+		if errors.As(err, &tooLarge) {
+			errMsg = helpers.ToErrorMsg(http.StatusRequestEntityTooLarge, exception.ERR_BAD_REQUEST_FIELD, "Ukuran avatar maksimal 5 MB.")
+		}
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, services.MaxAvatarBytes+1))
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	current, found := a.Users.findUser(c, ID)
+	if !found || !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := a.AvatarService.UploadAvatar(c.Request.Context(), ID, current.Version, content); errMsg != nil {
+		abortWithCurrent(c, errMsg, a.Users.currentUser(c, ID))
+		return
+	}
+
+	if result, found := a.Users.findUser(c, ID); found {
+		setETag(c, result.Version)
+		ok(c, "Sukses Upload Avatar", result)
+	}
+}
+
+func (a *AvatarControllerImplementation) DeleteAvatar(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+	current, found := a.Users.findUser(c, ID)
+	if !found || !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := a.AvatarService.DeleteAvatar(c.Request.Context(), ID, current.Version); errMsg != nil {
+		abortWithCurrent(c, errMsg, a.Users.currentUser(c, ID))
+		return
+	}
+
+	noContent(c)
+}
+
+// GetAvatar serves the thumbnail of the size query of an avatar URL. It is
+// public, so that the URL works in an img tag.
+func (a *AvatarControllerImplementation) GetAvatar(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	var r requests.AvatarRequest
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+	if r.Size == 0 {
+		r.Size = services.DefaultAvatarSize
+	}
+
+	name := c.Param("name")
+	file, info, errMsg := a.AvatarService.OpenAvatar(c.Request.Context(), ID, name, r.Size)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	// ServeContent answers If-None-Match and If-Modified-Since with 304.
+	c.Header("Content-Type", info.ContentType)
+	c.Header("Cache-Control", avatarCacheControl)
+	c.Header("ETag", strconv.Quote(fmt.Sprintf("%s-%d", strings.TrimSuffix(name, ".jpg"), r.Size)))
+	c.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(c.Writer, c.Request, name, info.ModTime, bytes.NewReader(content))
+}
@@ -0,0 +1,271 @@
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ClassController interface {
+	ListClass(c *gin.Context)
+	CreateClass(c *gin.Context)
+	GetClass(c *gin.Context)
+	PatchClass(c *gin.Context)
+	DeleteClass(c *gin.Context)
+	ListClassUsers(c *gin.Context)
+	JoinClass(c *gin.Context)
+	ListClassMembers(c *gin.Context)
+	RemoveClassMember(c *gin.Context)
+	RegenerateKodeKelas(c *gin.Context)
+}
+
+type ClassControllerImplementation struct {
+	ClassService services.ClassService
+}
+
+func NewClassController(classService services.ClassService) ClassController {
+	return &ClassControllerImplementation{ClassService: classService}
+}
+
+func (k *ClassControllerImplementation) ListClass(c *gin.Context) {
+	var r requests.ListRequest
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	classes, errMsg := k.ClassService.FindAllClass(c.Request.Context(), &r)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if classes == nil {
+		classes = []*domain.Class{}
+	}
+
+	ok(c, "Sukses Get Data Kelas", classes)
+}
+
+func (k *ClassControllerImplementation) CreateClass(c *gin.Context) {
+	var class requests.InsertClassRequest
+	if err := c.ShouldBindJSON(&class); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	_, errMsg := k.ClassService.AddClass(c.Request.Context(), &class)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	result, _, errMsg := k.ClassService.FindClassByID(c.Request.Context(), class.ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	created(c, fmt.Sprintf("%s/classes/%d", BasePath, class.ID), "Sukses Create Data Kelas", result)
+}
+
+func (k *ClassControllerImplementation) GetClass(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	result, _, errMsg := k.ClassService.FindClassByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	ok(c, "Sukses Get Data Kelas", result)
+}
+
+func (k *ClassControllerImplementation) PatchClass(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+
+	var patch requests.PatchClassRequest
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	current, _, errMsg := k.ClassService.FindClassByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := k.ClassService.UpdateClass(c.Request.Context(), patch.Apply(ID, current)); errMsg != nil {
+		abortWithCurrent(c, errMsg, func() (any, int, bool) {
+			current, found, _ := k.ClassService.FindClassByID(c.Request.Context(), ID)
+			if !found {
+				return nil, 0, false
+			}
+			return current, current.Version, true
+		})
+		return
+	}
+
+	result, _, errMsg := k.ClassService.FindClassByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	ok(c, "Sukses Update Data Kelas", result)
+}
+
+func (k *ClassControllerImplementation) DeleteClass(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+	current, _, errMsg := k.ClassService.FindClassByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := k.ClassService.DeleteClassByID(c.Request.Context(), ID); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
+
+func (k *ClassControllerImplementation) ListClassUsers(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	users, errMsg := k.ClassService.FindUsersByClassID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if users == nil {
+		users = []*response.UserResponse{}
+	}
+
+	ok(c, "Sukses Get Data User Kelas", users)
+}
+
+// JoinClass adds the caller to the class whose kode kelas they send.
+func (k *ClassControllerImplementation) JoinClass(c *gin.Context) {
+	info, isLoggedIn := api.UserInfoFromContext(c.Request.Context())
+	if !isLoggedIn {
+		errMsg := helpers.ToErrorMsg(http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER, "Token tidak valid!")
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	var join requests.JoinClassRequest
+	if err := c.ShouldBindJSON(&join); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	class, errMsg := k.ClassService.JoinClass(c.Request.Context(), info.ID, &join)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	created(c, fmt.Sprintf("%s/classes/%d/members/%d", BasePath, class.ID, info.ID), "Sukses Bergabung ke Kelas", class)
+}
+
+func (k *ClassControllerImplementation) ListClassMembers(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	members, errMsg := k.ClassService.FindClassMembers(c.Request.Context(), ID, c.Query("role"))
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if members == nil {
+		members = []*response.ClassMemberResponse{}
+	}
+
+	ok(c, "Sukses Get Data Anggota Kelas", members)
+}
+
+func (k *ClassControllerImplementation) RemoveClassMember(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil || userID < 1 {
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Invalid User ID, fill with number ID")
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	if _, errMsg := k.ClassService.RemoveClassMember(c.Request.Context(), ID, userID); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
+
+func (k *ClassControllerImplementation) RegenerateKodeKelas(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	var regenerate requests.RegenerateKodeKelasRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&regenerate); err != nil {
+			abortBindError(c, err)
+			return
+		}
+	}
+
+	class, errMsg := k.ClassService.RegenerateKodeKelas(c.Request.Context(), ID, &regenerate)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	ok(c, "Sukses Membuat Ulang Kode Kelas", class)
+}
@@ -0,0 +1,72 @@
+package v2
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// Single resources answer their version as a strong ETag. PATCH and DELETE
+// must send it back in If-Match; a stale one is answered with 412 and the
+// current representation, so the client can merge and retry.
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatch returns the version the request's If-Match header expects, 0 for
+// "*". It aborts with 428 when the header is missing.
+func ifMatch(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		errMsg := helpers.ToErrorMsg(http.StatusPreconditionRequired, exception.ERR_PRECONDITION_REQUIRED, "Header If-Match wajib diisi dengan ETag data terbaru.")
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return 0, false
+	}
+	if header == "*" {
+		return 0, true
+	}
+
+	// A weak or malformed tag can never match a strong ETag.
+	tag, err := strconv.Unquote(header)
+	version, errAtoi := strconv.Atoi(tag)
+	if err != nil || errAtoi != nil || version < 1 {
+		errMsg := helpers.ToVersionConflict()
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return 0, false
+	}
+	return version, true
+}
+
+// checkVersion aborts with 412 and current when it is not at the expected
+// version; 0 accepts any version.
+func checkVersion(c *gin.Context, expected, version int, current any) bool {
+	if expected == 0 || expected == version {
+		return true
+	}
+	abortStale(c, helpers.ToVersionConflict(), version, current)
+	return false
+}
+
+// abortWithCurrent answers errMsg, attaching the current representation from
+// find when the write failed on a stale version.
+func abortWithCurrent(c *gin.Context, errMsg *response.ErrorMsg, find func() (current any, version int, found bool)) {
+	if errMsg.ErrorKey == exception.ERR_PRECONDITION_FAILED {
+		if current, version, found := find(); found {
+			abortStale(c, errMsg, version, current)
+			return
+		}
+	}
+	abortWithError(c, errMsg)
+}
+
+func abortStale(c *gin.Context, errMsg *response.ErrorMsg, version int, current any) {
+	setETag(c, version)
+	errMsg.Current = current
+	helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+}
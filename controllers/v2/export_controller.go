@@ -0,0 +1,99 @@
+package v2
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+	"github.com/gin-gonic/gin"
+)
+
+type ExportController interface {
+	Export(c *gin.Context)
+}
+
+type ExportControllerImplementation struct {
+	ExportService services.ExportService
+}
+
+func NewExportController(exportService services.ExportService) ExportController {
+	return &ExportControllerImplementation{ExportService: exportService}
+}
+
+// Export streams the :resource as CSV, XLSX or NDJSON, chosen by the format
+// query or else the Accept header; CSV is the default.
+func (e *ExportControllerImplementation) Export(c *gin.Context) {
+	r := requests.ExportRequest{Resource: c.Param("resource")}
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	format, err := exportFormat(c)
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, err.Error())
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+	if errMsg := e.ExportService.Validate(c.Request.Context(), &r); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	w, err := spreadsheet.NewWriter(c.Writer, format)
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": r.Resource + "." + string(format)}))
+	c.Status(http.StatusOK)
+
+	// Rows are buffered before they reach the client; once they did, the
+	// status line is sent and a failure can only cut the body short.
+	if errMsg := e.ExportService.Export(c.Request.Context(), &r, w); errMsg != nil {
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			abortWithError(c, errMsg)
+			return
+		}
+		logging.FromContext(c.Request.Context()).Named("controllers").Error("export terputus", "resource", r.Resource, "error", errMsg.Msg)
+		c.Abort()
+		return
+	}
+	if err := w.Close(); err != nil {
+		logging.FromContext(c.Request.Context()).Named("controllers").Error("export terputus", "resource", r.Resource, "error", err)
+	}
+}
+
+var acceptFormats = map[string]spreadsheet.Format{
+	"text/csv":                     spreadsheet.CSV,
+	"application/x-ndjson":         spreadsheet.NDJSON,
+	"application/ndjson":           spreadsheet.NDJSON,
+	spreadsheet.XLSX.ContentType(): spreadsheet.XLSX,
+}
+
+func exportFormat(c *gin.Context) (spreadsheet.Format, error) {
+	if format := c.Query("format"); format != "" {
+		return spreadsheet.ParseFormat(format)
+	}
+	for _, accept := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if format, ok := acceptFormats[mediaType]; ok {
+			return format, nil
+		}
+	}
+	return spreadsheet.CSV, nil
+}
@@ -0,0 +1,62 @@
+package v2
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type FileController interface {
+	Usage(c *gin.Context)
+	Download(c *gin.Context)
+}
+
+type FileControllerImplementation struct {
+	FileService services.FileService
+}
+
+func NewFileController(fileService services.FileService) FileController {
+	return &FileControllerImplementation{FileService: fileService}
+}
+
+func (f *FileControllerImplementation) Usage(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	result, errMsg := f.FileService.Usage(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	ok(c, "Sukses Get Penyimpanan", result)
+}
+
+// Download serves a file through a signed URL of the local storage. The URL
+// itself is the credential, so the route takes no token; the file is sent
+// as an attachment in a sandbox, so that an uploaded page cannot run on the
+// API's origin.
+func (f *FileControllerImplementation) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	file, info, errMsg := f.FileService.OpenSigned(c.Request.Context(), key, c.Query("expires"), c.Query("signature"))
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", info.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	c.Header("Content-Disposition", "attachment; filename="+strconv.Quote(path.Base(key)))
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Security-Policy", "sandbox")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	io.Copy(c.Writer, file)
+}
@@ -0,0 +1,62 @@
+// Package v2 holds the controllers of the resource-style /api/v2 routes.
+package v2
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+const BasePath = "/api/v2"
+
+func paramID(c *gin.Context) (int, bool) {
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || ID < 1 {
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Invalid ID, fill with number ID")
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return 0, false
+	}
+	return ID, true
+}
+
+func abortBindError(c *gin.Context, err error) {
+	errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, helpers.ErrorValidateHandler(err))
+	helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+}
+
+// abortWithError answers with the service error. v1 services report some
+// missing records as 500, v2 always answers those with 404.
+func abortWithError(c *gin.Context, errMsg *response.ErrorMsg) {
+	if errMsg.ErrorKey == exception.ERR_NOT_FOUND {
+		errMsg.StatusCode = http.StatusNotFound
+	}
+	helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+}
+
+func ok(c *gin.Context, msg string, data any) {
+	helpers.ToWebResponse(c, &response.SuccessResponse{
+		Success:    true,
+		StatusCode: http.StatusOK,
+		Msg:        msg,
+		Data:       data,
+	})
+}
+
+func created(c *gin.Context, location string, msg string, data any) {
+	c.Header("Location", location)
+	helpers.ToWebResponse(c, &response.SuccessResponse{
+		Success:    true,
+		StatusCode: http.StatusCreated,
+		Msg:        msg,
+		Data:       data,
+	})
+}
+
+func noContent(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+}
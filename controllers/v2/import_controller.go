@@ -0,0 +1,91 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxImportSize = 10 << 20
+	maxImportRows = 5000
+)
+
+type ImportController interface {
+	Import(c *gin.Context)
+}
+
+type ImportControllerImplementation struct {
+	ImportService services.ImportService
+}
+
+func NewImportController(importService services.ImportService) ImportController {
+	return &ImportControllerImplementation{ImportService: importService}
+}
+
+// Import reads the multipart "file" field (CSV or XLSX, chosen by the file
+// extension or the format query) and imports it into the :resource. Only
+// admins get as far as the upload being read.
+func (i *ImportControllerImplementation) Import(c *gin.Context) {
+	if errMsg := i.ImportService.Authorize(c.Request.Context()); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	r := requests.ImportRequest{Resource: c.Param("resource")}
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	header, err := c.FormFile("file")
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "File wajib diunggah pada field file, maksimal 10 MB.")
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	name := header.Filename
+	if format := c.Query("format"); format != "" {
+		name = format
+	}
+	format, err := spreadsheet.ParseFormat(name)
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, err.Error())
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+	defer file.Close()
+
+	records, err := spreadsheet.Read(file, format, maxImportRows)
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, err.Error())
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	report, errMsg := i.ImportService.Import(c.Request.Context(), &r, records)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	msg := "Sukses Import Data"
+	if r.DryRun {
+		msg = "Sukses Validasi Data Import"
+	}
+	ok(c, msg, report)
+}
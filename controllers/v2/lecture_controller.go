@@ -0,0 +1,166 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type LectureController interface {
+	ListLecture(c *gin.Context)
+	CreateLecture(c *gin.Context)
+	GetLecture(c *gin.Context)
+	PatchLecture(c *gin.Context)
+	DeleteLecture(c *gin.Context)
+}
+
+type LectureControllerImplementation struct {
+	LectureService services.LectureService
+}
+
+func NewLectureController(lectureService services.LectureService) LectureController {
+	return &LectureControllerImplementation{LectureService: lectureService}
+}
+
+func (l *LectureControllerImplementation) ListLecture(c *gin.Context) {
+	var r requests.LectureListRequest
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	lectures, errMsg := l.LectureService.FindAllLecture(c.Request.Context(), &r)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if lectures == nil {
+		lectures = []*response.LectureResponse{}
+	}
+
+	ok(c, "Sukses Get Data Dosen", lectures)
+}
+
+func (l *LectureControllerImplementation) CreateLecture(c *gin.Context) {
+	var lecture requests.InsertLectureRequest
+	if err := c.ShouldBindJSON(&lecture); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	_, errMsg := l.LectureService.InsertLecture(c.Request.Context(), &lecture)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	result, _, errMsg := l.LectureService.FindLectureByID(c.Request.Context(), lecture.ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	created(c, fmt.Sprintf("%s/lectures/%d", BasePath, lecture.ID), "Sukses Create Data Dosen", result)
+}
+
+func (l *LectureControllerImplementation) GetLecture(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	result, _, errMsg := l.LectureService.FindLectureByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	ok(c, "Sukses Get Data Dosen", result)
+}
+
+func (l *LectureControllerImplementation) PatchLecture(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+
+	var patch requests.PatchLectureRequest
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	current, _, errMsg := l.LectureService.FindLectureByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := l.LectureService.UpdateLecture(c.Request.Context(), patch.Apply(ID, current)); errMsg != nil {
+		abortWithCurrent(c, errMsg, func() (any, int, bool) {
+			current, found, _ := l.LectureService.FindLectureByID(c.Request.Context(), ID)
+			if !found {
+				return nil, 0, false
+			}
+			return current, current.Version, true
+		})
+		return
+	}
+
+	result, _, errMsg := l.LectureService.FindLectureByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	ok(c, "Sukses Update Data Dosen", result)
+}
+
+func (l *LectureControllerImplementation) DeleteLecture(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	r := requests.DeleteLectureRequest{ID: ID}
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+	if r.Rooms == "" {
+		r.Rooms = requests.DeleteCascade
+	}
+
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+	current, _, errMsg := l.LectureService.FindLectureByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := l.LectureService.DeleteLecture(c.Request.Context(), &r); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
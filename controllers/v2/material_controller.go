@@ -0,0 +1,298 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type MaterialController interface {
+	ListClassMatkul(c *gin.Context)
+	EnrollClass(c *gin.Context)
+	UnenrollClass(c *gin.Context)
+	ListTopics(c *gin.Context)
+	CreateTopic(c *gin.Context)
+	DeleteTopic(c *gin.Context)
+	CreateLink(c *gin.Context)
+	UploadFile(c *gin.Context)
+	GetMaterial(c *gin.Context)
+	DeleteMaterial(c *gin.Context)
+	DownloadMaterial(c *gin.Context)
+}
+
+type MaterialControllerImplementation struct {
+	MaterialService services.MaterialService
+}
+
+func NewMaterialController(materialService services.MaterialService) MaterialController {
+	return &MaterialControllerImplementation{MaterialService: materialService}
+}
+
+// paramSubID reads the ID path parameter name that follows :id, such as
+// :topic_id.
+func paramSubID(c *gin.Context, name string) (int, bool) {
+	ID, err := strconv.Atoi(c.Param(name))
+	if err != nil || ID < 1 {
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, fmt.Sprintf("Invalid %s, fill with number ID", name))
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return 0, false
+	}
+	return ID, true
+}
+
+func (m *MaterialControllerImplementation) ListClassMatkul(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	matkuls, errMsg := m.MaterialService.FindMatkulByClassID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if matkuls == nil {
+		matkuls = []*response.MatkulResponse{}
+	}
+
+	ok(c, "Sukses Get Data Matkul Kelas", matkuls)
+}
+
+func (m *MaterialControllerImplementation) EnrollClass(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	matkulID, valid := paramSubID(c, "matkul_id")
+	if !valid {
+		return
+	}
+
+	if _, errMsg := m.MaterialService.EnrollClass(c.Request.Context(), ID, matkulID); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
+
+func (m *MaterialControllerImplementation) UnenrollClass(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	matkulID, valid := paramSubID(c, "matkul_id")
+	if !valid {
+		return
+	}
+
+	if _, errMsg := m.MaterialService.UnenrollClass(c.Request.Context(), ID, matkulID); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
+
+func (m *MaterialControllerImplementation) ListTopics(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	topics, errMsg := m.MaterialService.FindTopics(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	ok(c, "Sukses Get Data Materi", topics)
+}
+
+func (m *MaterialControllerImplementation) CreateTopic(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	var topic requests.InsertMaterialTopicRequest
+	if err := c.ShouldBindJSON(&topic); err != nil {
+		abortBindError(c, err)
+		return
+	}
+	topic.MatkulID = ID
+
+	result, errMsg := m.MaterialService.InsertTopic(c.Request.Context(), &topic)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	created(c, fmt.Sprintf("%s/matkul/%d/topics", BasePath, ID), "Sukses Create Topik Materi", result)
+}
+
+func (m *MaterialControllerImplementation) DeleteTopic(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	topicID, valid := paramSubID(c, "topic_id")
+	if !valid {
+		return
+	}
+
+	if _, errMsg := m.MaterialService.DeleteTopic(c.Request.Context(), ID, topicID); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
+
+func (m *MaterialControllerImplementation) CreateLink(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	topicID, valid := paramSubID(c, "topic_id")
+	if !valid {
+		return
+	}
+
+	var link requests.InsertMaterialLinkRequest
+	if err := c.ShouldBindJSON(&link); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	if _, errMsg := m.MaterialService.InsertLink(c.Request.Context(), ID, topicID, &link); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	m.createdMaterial(c, ID, link.ID)
+}
+
+// UploadFile reads the multipart "file" field, with an optional "title", and
+// stores it as a material of the topic.
+func (m *MaterialControllerImplementation) UploadFile(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	topicID, valid := paramSubID(c, "topic_id")
+	if !valid {
+		return
+	}
+
+	// The multipart envelope gets some room on top of the file itself, which
+	// the service holds to MaxMaterialBytes.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxMaterialBytes+64<<10)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		errMsg := helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "File materi wajib diunggah pada field file.")
+		if errors.As(err, &tooLarge) {
+			errMsg = helpers.ToErrorMsg(http.StatusRequestEntityTooLarge, exception.ERR_BAD_REQUEST_FIELD, "Ukuran file materi maksimal 50 MB.")
+		}
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+
+	var upload requests.InsertMaterialFileRequest
+	if err := c.ShouldBind(&upload); err != nil {
+		abortBindError(c, err)
+		return
+	}
+	upload.FileName, upload.Size = header.Filename, header.Size
+
+	file, err := header.Open()
+	if err != nil {
+		errMsg := helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+	defer file.Close()
+
+	if _, errMsg := m.MaterialService.InsertFile(c.Request.Context(), ID, topicID, &upload, file); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	m.createdMaterial(c, ID, upload.ID)
+}
+
+func (m *MaterialControllerImplementation) createdMaterial(c *gin.Context, matkulID, materialID int) {
+	result, errMsg := m.MaterialService.FindMaterialByID(c.Request.Context(), matkulID, materialID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	created(c, fmt.Sprintf("%s/matkul/%d/materials/%d", BasePath, matkulID, materialID), "Sukses Create Materi", result)
+}
+
+func (m *MaterialControllerImplementation) GetMaterial(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	materialID, valid := paramSubID(c, "material_id")
+	if !valid {
+		return
+	}
+
+	result, errMsg := m.MaterialService.FindMaterialByID(c.Request.Context(), ID, materialID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	ok(c, "Sukses Get Data Materi", result)
+}
+
+func (m *MaterialControllerImplementation) DeleteMaterial(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	materialID, valid := paramSubID(c, "material_id")
+	if !valid {
+		return
+	}
+
+	if _, errMsg := m.MaterialService.DeleteMaterial(c.Request.Context(), ID, materialID); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
+
+// DownloadMaterial redirects a member to the link of the material, or to a
+// signed URL of its file, which works without a token until it expires.
+func (m *MaterialControllerImplementation) DownloadMaterial(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	materialID, valid := paramSubID(c, "material_id")
+	if !valid {
+		return
+	}
+
+	location, errMsg := m.MaterialService.DownloadURL(c.Request.Context(), ID, materialID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-store")
+	c.Redirect(http.StatusFound, location)
+}
@@ -0,0 +1,163 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type MatkulController interface {
+	ListMatkul(c *gin.Context)
+	CreateMatkul(c *gin.Context)
+	GetMatkul(c *gin.Context)
+	PatchMatkul(c *gin.Context)
+	DeleteMatkul(c *gin.Context)
+}
+
+type MatkulControllerImplementation struct {
+	MataKuliahService services.MataKuliahService
+}
+
+func NewMatkulController(matkulService services.MataKuliahService) MatkulController {
+	return &MatkulControllerImplementation{MataKuliahService: matkulService}
+}
+
+func (m *MatkulControllerImplementation) ListMatkul(c *gin.Context) {
+	var r requests.ListRequest
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	matkuls, errMsg := m.MataKuliahService.FindAllMatkul(c.Request.Context(), &r)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if matkuls == nil {
+		matkuls = []*response.MatkulResponse{}
+	}
+
+	ok(c, "Sukses Get Data Matkul", matkuls)
+}
+
+func (m *MatkulControllerImplementation) CreateMatkul(c *gin.Context) {
+	var matkul requests.InsertMatkulRequest
+	if err := c.ShouldBindJSON(&matkul); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	_, errMsg := m.MataKuliahService.InsertMatkul(c.Request.Context(), &matkul)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	result, _, errMsg := m.MataKuliahService.FindMatkulByID(c.Request.Context(), matkul.ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	created(c, fmt.Sprintf("%s/matkul/%d", BasePath, matkul.ID), "Sukses Create Data Matkul", result)
+}
+
+func (m *MatkulControllerImplementation) GetMatkul(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	result, _, errMsg := m.MataKuliahService.FindMatkulByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	ok(c, "Sukses Get Data Matkul", result)
+}
+
+func (m *MatkulControllerImplementation) PatchMatkul(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+
+	var patch requests.PatchMatkulRequest
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	current, _, errMsg := m.MataKuliahService.FindMatkulByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := m.MataKuliahService.UpdateMatkul(c.Request.Context(), patch.Apply(ID, current)); errMsg != nil {
+		abortWithCurrent(c, errMsg, func() (any, int, bool) {
+			current, found, _ := m.MataKuliahService.FindMatkulByID(c.Request.Context(), ID)
+			if !found {
+				return nil, 0, false
+			}
+			return current, current.Version, true
+		})
+		return
+	}
+
+	result, _, errMsg := m.MataKuliahService.FindMatkulByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	ok(c, "Sukses Update Data Matkul", result)
+}
+
+func (m *MatkulControllerImplementation) DeleteMatkul(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+	current, _, errMsg := m.MataKuliahService.FindMatkulByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := m.MataKuliahService.DeleteMatkulByID(c.Request.Context(), ID, version); errMsg != nil {
+		abortWithCurrent(c, errMsg, func() (any, int, bool) {
+			current, found, _ := m.MataKuliahService.FindMatkulByID(c.Request.Context(), ID)
+			if !found {
+				return nil, 0, false
+			}
+			return current, current.Version, true
+		})
+		return
+	}
+
+	noContent(c)
+}
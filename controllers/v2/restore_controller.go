@@ -0,0 +1,33 @@
+package v2
+
+import (
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RestoreController interface {
+	Restore(c *gin.Context)
+}
+
+type RestoreControllerImplementation struct {
+	RestoreService services.RestoreService
+}
+
+func NewRestoreController(restoreService services.RestoreService) RestoreController {
+	return &RestoreControllerImplementation{RestoreService: restoreService}
+}
+
+// Restore brings back the soft deleted :id of :resource.
+func (r *RestoreControllerImplementation) Restore(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	restored, errMsg := r.RestoreService.Restore(c.Request.Context(), c.Param("resource"), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	ok(c, "Sukses Memulihkan Data", restored)
+}
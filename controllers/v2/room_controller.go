@@ -0,0 +1,163 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type RoomController interface {
+	ListRoom(c *gin.Context)
+	CreateRoom(c *gin.Context)
+	GetRoom(c *gin.Context)
+	PatchRoom(c *gin.Context)
+	DeleteRoom(c *gin.Context)
+}
+
+type RoomControllerImplementation struct {
+	RoomService services.RoomService
+}
+
+func NewRoomController(roomService services.RoomService) RoomController {
+	return &RoomControllerImplementation{RoomService: roomService}
+}
+
+func (l *RoomControllerImplementation) ListRoom(c *gin.Context) {
+	var r requests.ListRequest
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	rooms, errMsg := l.RoomService.FindAllRoom(c.Request.Context(), &r)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if rooms == nil {
+		rooms = []*response.RoomResponse{}
+	}
+
+	ok(c, "Sukses Get Data Room", rooms)
+}
+
+func (l *RoomControllerImplementation) CreateRoom(c *gin.Context) {
+	var room requests.InsertRoomRequest
+	if err := c.ShouldBindJSON(&room); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	_, errMsg := l.RoomService.InsertRoom(c.Request.Context(), &room)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	result, _, errMsg := l.RoomService.FindRoomByID(c.Request.Context(), room.ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	created(c, fmt.Sprintf("%s/rooms/%d", BasePath, room.ID), "Sukses Create Data Room", result)
+}
+
+func (l *RoomControllerImplementation) GetRoom(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	result, _, errMsg := l.RoomService.FindRoomByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	ok(c, "Sukses Get Data Room", result)
+}
+
+func (l *RoomControllerImplementation) PatchRoom(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+
+	var patch requests.PatchRoomRequest
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	current, _, errMsg := l.RoomService.FindRoomByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := l.RoomService.UpdateRoom(c.Request.Context(), patch.Apply(ID, current)); errMsg != nil {
+		abortWithCurrent(c, errMsg, func() (any, int, bool) {
+			current, found, _ := l.RoomService.FindRoomByID(c.Request.Context(), ID)
+			if !found {
+				return nil, 0, false
+			}
+			return current, current.Version, true
+		})
+		return
+	}
+
+	result, _, errMsg := l.RoomService.FindRoomByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	setETag(c, result.Version)
+	ok(c, "Sukses Update Data Room", result)
+}
+
+func (l *RoomControllerImplementation) DeleteRoom(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+	current, _, errMsg := l.RoomService.FindRoomByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := l.RoomService.DeleteRoomByID(c.Request.Context(), ID, version); errMsg != nil {
+		abortWithCurrent(c, errMsg, func() (any, int, bool) {
+			current, found, _ := l.RoomService.FindRoomByID(c.Request.Context(), ID)
+			if !found {
+				return nil, 0, false
+			}
+			return current, current.Version, true
+		})
+		return
+	}
+
+	noContent(c)
+}
@@ -0,0 +1,204 @@
+package v2
+
+import (
+	"fmt"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+type UsersController interface {
+	ListUser(c *gin.Context)
+	CreateUser(c *gin.Context)
+	GetUser(c *gin.Context)
+	PatchUser(c *gin.Context)
+	DeleteUser(c *gin.Context)
+	ChangePasswordUser(c *gin.Context)
+}
+
+type UsersControllerImplementation struct {
+	UsersService services.UsersService
+}
+
+func NewUsersController(usersService services.UsersService) UsersController {
+	return &UsersControllerImplementation{UsersService: usersService}
+}
+
+func (u *UsersControllerImplementation) ListUser(c *gin.Context) {
+	var r requests.UserListRequest
+	if err := c.ShouldBindQuery(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	users, errMsg := u.UsersService.FindAllUsers(c.Request.Context(), &r)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+	if users == nil {
+		users = []*response.UserResponse{}
+	}
+
+	ok(c, "Sukses Get Data User", users)
+}
+
+func (u *UsersControllerImplementation) CreateUser(c *gin.Context) {
+	var r requests.UserCreateRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	user := &requests.UserInsertRequest{Name: r.Name, Email: r.Email, Password: r.Password, Role: r.Role, ClassID: r.ClassID, Profile: r.Profile}
+	if _, errMsg := u.UsersService.InsertDataUser(c.Request.Context(), user); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	result, ok := u.findUser(c, user.ID)
+	if !ok {
+		return
+	}
+
+	setETag(c, result.Version)
+	created(c, fmt.Sprintf("%s/users/%d", BasePath, user.ID), "Sukses Create Data User", result)
+}
+
+func (u *UsersControllerImplementation) GetUser(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	if result, found := u.findUser(c, ID); found {
+		setETag(c, result.Version)
+		ok(c, "Sukses Get Data User", result)
+	}
+}
+
+func (u *UsersControllerImplementation) PatchUser(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+
+	var patch requests.UserPatchRequest
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	current, found := u.findUser(c, ID)
+	if !found || !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	patch.Version = current.Version
+	if _, errMsg := u.UsersService.PatchDataUser(c.Request.Context(), ID, &patch); errMsg != nil {
+		abortWithCurrent(c, errMsg, u.currentUser(c, ID))
+		return
+	}
+
+	if result, found := u.findUser(c, ID); found {
+		setETag(c, result.Version)
+		ok(c, "Sukses Update Data User", result)
+	}
+}
+
+func (u *UsersControllerImplementation) DeleteUser(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	var r requests.UserDeleteRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	version, valid := ifMatch(c)
+	if !valid {
+		return
+	}
+	current, found := u.findUser(c, ID)
+	if !found || !checkVersion(c, version, current.Version, current) {
+		return
+	}
+
+	if _, errMsg := u.UsersService.DeleteDataUser(c.Request.Context(), r.ConfirmPassword, ID); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
+
+func (u *UsersControllerImplementation) ChangePasswordUser(c *gin.Context) {
+	ID, valid := paramID(c)
+	if !valid {
+		return
+	}
+
+	var r requests.UserChangePassword
+	if err := c.ShouldBindJSON(&r); err != nil {
+		abortBindError(c, err)
+		return
+	}
+
+	if _, errMsg := u.UsersService.ChangePasswordUser(c.Request.Context(), ID, r.RecentPassword, r.NewPassword); errMsg != nil {
+		abortWithError(c, errMsg)
+		return
+	}
+
+	noContent(c)
+}
+
+func (u *UsersControllerImplementation) findUser(c *gin.Context, ID int) (*response.UserResponse, bool) {
+	result, errMsg := u.loadUser(c, ID)
+	if errMsg != nil {
+		abortWithError(c, errMsg)
+		return nil, false
+	}
+	return result, true
+}
+
+// currentUser finds the user for abortWithCurrent.
+func (u *UsersControllerImplementation) currentUser(c *gin.Context, ID int) func() (any, int, bool) {
+	return func() (any, int, bool) {
+		result, errMsg := u.loadUser(c, ID)
+		if errMsg != nil {
+			return nil, 0, false
+		}
+		return result, result.Version, true
+	}
+}
+
+// loadUser reads the user with their profile.
+func (u *UsersControllerImplementation) loadUser(c *gin.Context, ID int) (*response.UserResponse, *response.ErrorMsg) {
+	user, errMsg := u.UsersService.FindUserByID(c.Request.Context(), ID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	profile, errMsg := u.UsersService.FindUserProfile(c.Request.Context(), ID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	result := toUserResponse(user)
+	result.Profile = profile
+	return result, nil
+}
+
+func toUserResponse(user *domain.Users) *response.UserResponse {
+	return &response.UserResponse{ID: user.ID, Name: user.Name, Email: user.Email, Role: user.Role, ClassID: user.ClassID, Version: user.Version, Audit: user.Audit}
+}
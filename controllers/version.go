@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// Updates name the version they are based on, either in the version field of
+// the body or as the ETag in If-Match, so an edit made meanwhile by someone
+// else is answered with 412 and the current data instead of overwritten.
+
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// expectedVersion returns the version the update is based on, 0 for an
+// If-Match of "*". It aborts with 428 when the request names none.
+func expectedVersion(c *gin.Context, body int) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	switch {
+	case header == "" && body > 0:
+		return body, true
+	case header == "":
+		errMsg := helpers.ToErrorMsg(http.StatusPreconditionRequired, exception.ERR_PRECONDITION_REQUIRED, "Isi field version atau header If-Match dengan versi data terbaru.")
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return 0, false
+	case header == "*":
+		return 0, true
+	}
+
+	tag, err := strconv.Unquote(header)
+	version, errAtoi := strconv.Atoi(tag)
+	if err != nil || errAtoi != nil || version < 1 {
+		errMsg := helpers.ToVersionConflict()
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return 0, false
+	}
+	return version, true
+}
+
+// abortUpdate answers a failed update, with the current data when it failed
+// on a stale version.
+func abortUpdate(c *gin.Context, errMsg *response.ErrorMsg, find func() (current any, version int, found bool)) {
+	if errMsg.ErrorKey == exception.ERR_PRECONDITION_FAILED {
+		if current, version, found := find(); found {
+			setETag(c, version)
+			errMsg.Current = current
+		}
+		helpers.ToErrorResponse(c, errMsg.StatusCode, errMsg)
+		return
+	}
+	helpers.ToErrorResponse(c, http.StatusBadRequest, errMsg)
+}
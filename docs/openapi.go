@@ -0,0 +1,336 @@
+// Package docs builds the OpenAPI 3 document of the API from the structs in
+// entity/requests and entity/response and serves it with a Swagger UI page.
+package docs
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SpecPath = "/api/docs/openapi.json"
+	UIPath   = "/api/docs"
+)
+
+// OneOf documents a response whose data can take several shapes, e.g. a GET
+// that returns one record by id or a list by name.
+type OneOf []any
+
+// Operation documents one route. Status is the success status code; zero
+// means 200. A 204 or 302 operation is documented without a response body. A
+// Raw operation answers with Response itself instead of the SuccessResponse
+// envelope. Upload names the multipart/form-data file field of an upload
+// operation. Produces lists the media types of an operation that answers
+// with a file instead of JSON. Header lists the request headers an operation
+// requires, such as If-Match on versioned updates.
+type Operation struct {
+	Method   string
+	Path     string
+	Tag      string
+	Summary  string
+	Query    []string
+	Header   []string
+	Request  any
+	Upload   string
+	Response any
+	Produces []string
+	Status   int
+	Raw      bool
+	Public   bool
+}
+
+// listQuery are the filters and order the listing routes take, see requests.ListRequest.
+var listQuery = []string{"name", "created_by", "updated_by", "created_after", "created_before", "updated_after", "updated_before", "sort"}
+
+// profileQuery are the profile filters of the user and lecture listings, see requests.ProfileListRequest.
+var profileQuery = []string{"npm", "nidn", "program_studi", "angkatan"}
+
+var Operations = []Operation{
+	{Method: http.MethodGet, Path: SpecPath, Tag: "docs", Summary: "Dokumen OpenAPI", Public: true},
+	{Method: http.MethodGet, Path: UIPath, Tag: "docs", Summary: "Swagger UI", Public: true},
+
+	{Method: http.MethodPost, Path: "/api/v.1/auth/register", Tag: "auth", Summary: "Registrasi user", Request: requests.AuthRegisterRequest{}, Public: true},
+	{Method: http.MethodPost, Path: "/api/v.1/auth/login", Tag: "auth", Summary: "Login user", Request: requests.AuthLoginRequest{}, Response: response.AuthTokenResponse{}, Public: true},
+
+	{Method: http.MethodPost, Path: "/api/v.1/user/create", Tag: "user", Summary: "Tambah user", Request: requests.UserInsertRequest{}},
+	{Method: http.MethodPut, Path: "/api/v.1/user/update", Tag: "user", Summary: "Ubah user", Request: requests.UserUpdateRequest{}},
+	{Method: http.MethodDelete, Path: "/api/v.1/user/delete", Tag: "user", Summary: "Hapus user", Query: []string{"id"}, Request: requests.UserDeleteRequest{}},
+	{Method: http.MethodPut, Path: "/api/v.1/user/changepassword", Tag: "user", Summary: "Ganti password user", Query: []string{"id"}, Request: requests.UserChangePassword{}},
+
+	{Method: http.MethodPost, Path: "/api/v.1/class/create", Tag: "class", Summary: "Tambah kelas", Request: requests.InsertClassRequest{}},
+	{Method: http.MethodPut, Path: "/api/v.1/class/update", Tag: "class", Summary: "Ubah kelas", Query: []string{"id"}, Request: requests.UpdateClassRequest{}},
+	{Method: http.MethodDelete, Path: "/api/v.1/class/delete", Tag: "class", Summary: "Hapus kelas", Query: []string{"id"}},
+	{Method: http.MethodGet, Path: "/api/v.1/class/", Tag: "class", Summary: "Cari kelas berdasarkan id atau name", Query: []string{"id", "name"}, Response: domain.Class{}},
+
+	{Method: http.MethodPost, Path: "/api/v.1/lecture/create", Tag: "lecture", Summary: "Tambah dosen", Request: requests.InsertLectureRequest{}},
+	{Method: http.MethodPut, Path: "/api/v.1/lecture/update", Tag: "lecture", Summary: "Ubah dosen", Query: []string{"id"}, Request: requests.UpdateLectureRequest{}},
+	{Method: http.MethodDelete, Path: "/api/v.1/lecture/delete", Tag: "lecture", Summary: "Hapus dosen", Query: []string{"id"}},
+	{Method: http.MethodGet, Path: "/api/v.1/lecture/", Tag: "lecture", Summary: "Cari dosen berdasarkan id atau name", Query: []string{"id", "name"}, Response: response.LectureResponse{}},
+
+	{Method: http.MethodPost, Path: "/api/v.1/room/create", Tag: "room", Summary: "Tambah room", Request: requests.InsertRoomRequest{}},
+	{Method: http.MethodPut, Path: "/api/v.1/room/update", Tag: "room", Summary: "Ubah room; versi data dari field version atau header If-Match", Query: []string{"id"}, Request: requests.UpdateRoomRequest{}},
+	{Method: http.MethodDelete, Path: "/api/v.1/room/delete", Tag: "room", Summary: "Hapus room", Query: []string{"id"}},
+	{Method: http.MethodGet, Path: "/api/v.1/room/", Tag: "room", Summary: "Cari room berdasarkan id", Query: []string{"id"}, Response: response.RoomResponse{}},
+
+	{Method: http.MethodPost, Path: "/api/v.1/matkul/create", Tag: "matkul", Summary: "Tambah mata kuliah", Request: requests.InsertMatkulRequest{}},
+	{Method: http.MethodPut, Path: "/api/v.1/matkul/update", Tag: "matkul", Summary: "Ubah mata kuliah; versi data dari field version atau header If-Match", Query: []string{"id"}, Request: requests.UpdateMatkulRequest{}},
+	{Method: http.MethodDelete, Path: "/api/v.1/matkul/delete", Tag: "matkul", Summary: "Hapus mata kuliah", Query: []string{"id"}},
+	{Method: http.MethodGet, Path: "/api/v.1/matkul/", Tag: "matkul", Summary: "Cari mata kuliah berdasarkan id atau name", Query: []string{"id", "name"}, Response: OneOf{response.MatkulResponse{}, []response.MatkulResponse{}}},
+
+	{Method: http.MethodGet, Path: "/api/v2/users", Tag: "v2 users", Summary: "Daftar user, dapat dicari berdasarkan role dan profil", Query: append(append([]string{"role"}, profileQuery...), listQuery...), Response: []response.UserResponse{}},
+	{Method: http.MethodPost, Path: "/api/v2/users", Tag: "v2 users", Summary: "Tambah user", Request: requests.UserCreateRequest{}, Response: response.UserResponse{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v2/users/:id", Tag: "v2 users", Summary: "Detail user", Response: response.UserResponse{}},
+	{Method: http.MethodPatch, Path: "/api/v2/users/:id", Tag: "v2 users", Summary: "Ubah sebagian data user; profil hanya oleh pemiliknya atau admin", Request: requests.UserPatchRequest{}, Header: []string{"If-Match"}, Response: response.UserResponse{}},
+	{Method: http.MethodDelete, Path: "/api/v2/users/:id", Tag: "v2 users", Summary: "Hapus user", Request: requests.UserDeleteRequest{}, Header: []string{"If-Match"}, Status: http.StatusNoContent},
+	{Method: http.MethodPut, Path: "/api/v2/users/:id/password", Tag: "v2 users", Summary: "Ganti password user", Request: requests.UserChangePassword{}, Status: http.StatusNoContent},
+	{Method: http.MethodPut, Path: "/api/v2/users/:id/avatar", Tag: "v2 users", Summary: "Unggah avatar JPEG, PNG, GIF atau WebP maksimal 5 MB; hanya oleh pemiliknya atau admin", Upload: "avatar", Header: []string{"If-Match"}, Response: response.UserResponse{}},
+	{Method: http.MethodDelete, Path: "/api/v2/users/:id/avatar", Tag: "v2 users", Summary: "Hapus avatar; hanya oleh pemiliknya atau admin", Header: []string{"If-Match"}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/api/v2/avatars/:id/:name", Tag: "v2 users", Summary: "Gambar avatar persegi, size 64, 128, 256 (default) atau 512 piksel", Query: []string{"size"}, Produces: []string{"image/jpeg"}, Public: true},
+	{Method: http.MethodGet, Path: "/api/v2/users/:id/storage", Tag: "v2 users", Summary: "Pemakaian penyimpanan file pengguna terhadap kuotanya dalam byte; hanya oleh pemiliknya atau admin", Response: response.StorageUsageResponse{}},
+	{Method: http.MethodGet, Path: "/api/v2/files/*key", Tag: "v2 users", Summary: "Unduh file lewat URL bertanda tangan yang berlaku sementara", Query: []string{"expires", "signature"}, Produces: []string{"application/octet-stream"}, Public: true},
+
+	{Method: http.MethodGet, Path: "/api/v2/classes", Tag: "v2 classes", Summary: "Daftar kelas", Query: listQuery, Response: []domain.Class{}},
+	{Method: http.MethodPost, Path: "/api/v2/classes", Tag: "v2 classes", Summary: "Tambah kelas", Request: requests.InsertClassRequest{}, Response: domain.Class{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v2/classes/:id", Tag: "v2 classes", Summary: "Detail kelas", Response: domain.Class{}},
+	{Method: http.MethodPatch, Path: "/api/v2/classes/:id", Tag: "v2 classes", Summary: "Ubah sebagian data kelas", Request: requests.PatchClassRequest{}, Header: []string{"If-Match"}, Response: domain.Class{}},
+	{Method: http.MethodDelete, Path: "/api/v2/classes/:id", Tag: "v2 classes", Summary: "Hapus kelas", Header: []string{"If-Match"}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/api/v2/classes/:id/users", Tag: "v2 classes", Summary: "Daftar user di kelas", Response: []response.UserResponse{}},
+	{Method: http.MethodPost, Path: "/api/v2/classes/join", Tag: "v2 classes", Summary: "Bergabung ke kelas dengan kode kelas", Request: requests.JoinClassRequest{}, Response: domain.Class{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v2/classes/:id/members", Tag: "v2 classes", Summary: "Daftar anggota kelas (khusus dosen)", Query: []string{"role"}, Response: []response.ClassMemberResponse{}},
+	{Method: http.MethodDelete, Path: "/api/v2/classes/:id/members/:user_id", Tag: "v2 classes", Summary: "Keluarkan anggota dari kelas (khusus dosen)", Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/api/v2/classes/:id/code", Tag: "v2 classes", Summary: "Buat ulang kode kelas (khusus dosen)", Request: requests.RegenerateKodeKelasRequest{}, Response: domain.Class{}},
+	{Method: http.MethodGet, Path: "/api/v2/classes/:id/matkul", Tag: "v2 classes", Summary: "Daftar mata kuliah yang diambil kelas; hanya oleh anggota kelas atau admin", Response: []response.MatkulResponse{}},
+	{Method: http.MethodPut, Path: "/api/v2/classes/:id/matkul/:matkul_id", Tag: "v2 classes", Summary: "Daftarkan kelas ke mata kuliah (khusus dosen kelas atau admin)", Status: http.StatusNoContent},
+	{Method: http.MethodDelete, Path: "/api/v2/classes/:id/matkul/:matkul_id", Tag: "v2 classes", Summary: "Lepaskan kelas dari mata kuliah (khusus dosen kelas atau admin)", Status: http.StatusNoContent},
+
+	{Method: http.MethodGet, Path: "/api/v2/lectures", Tag: "v2 lectures", Summary: "Daftar dosen, dapat dicari berdasarkan profil", Query: append(append([]string{}, profileQuery...), listQuery...), Response: []response.LectureResponse{}},
+	{Method: http.MethodPost, Path: "/api/v2/lectures", Tag: "v2 lectures", Summary: "Tambah dosen", Request: requests.InsertLectureRequest{}, Response: response.LectureResponse{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v2/lectures/:id", Tag: "v2 lectures", Summary: "Detail dosen", Response: response.LectureResponse{}},
+	{Method: http.MethodPatch, Path: "/api/v2/lectures/:id", Tag: "v2 lectures", Summary: "Ubah sebagian data dosen", Request: requests.PatchLectureRequest{}, Header: []string{"If-Match"}, Response: response.LectureResponse{}},
+	{Method: http.MethodDelete, Path: "/api/v2/lectures/:id", Tag: "v2 lectures", Summary: "Hapus dosen beserta, tanpa, atau dengan memindahkan room-nya", Query: []string{"rooms", "reassign_to"}, Header: []string{"If-Match"}, Status: http.StatusNoContent},
+
+	{Method: http.MethodGet, Path: "/api/v2/rooms", Tag: "v2 rooms", Summary: "Daftar room", Query: listQuery, Response: []response.RoomResponse{}},
+	{Method: http.MethodPost, Path: "/api/v2/rooms", Tag: "v2 rooms", Summary: "Tambah room", Request: requests.InsertRoomRequest{}, Response: response.RoomResponse{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v2/rooms/:id", Tag: "v2 rooms", Summary: "Detail room", Response: response.RoomResponse{}},
+	{Method: http.MethodPatch, Path: "/api/v2/rooms/:id", Tag: "v2 rooms", Summary: "Ubah sebagian data room", Request: requests.PatchRoomRequest{}, Header: []string{"If-Match"}, Response: response.RoomResponse{}},
+	{Method: http.MethodDelete, Path: "/api/v2/rooms/:id", Tag: "v2 rooms", Summary: "Hapus room", Header: []string{"If-Match"}, Status: http.StatusNoContent},
+
+	{Method: http.MethodGet, Path: "/api/v2/matkul", Tag: "v2 matkul", Summary: "Daftar mata kuliah", Query: listQuery, Response: []response.MatkulResponse{}},
+	{Method: http.MethodPost, Path: "/api/v2/matkul", Tag: "v2 matkul", Summary: "Tambah mata kuliah", Request: requests.InsertMatkulRequest{}, Response: response.MatkulResponse{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v2/matkul/:id", Tag: "v2 matkul", Summary: "Detail mata kuliah", Response: response.MatkulResponse{}},
+	{Method: http.MethodPatch, Path: "/api/v2/matkul/:id", Tag: "v2 matkul", Summary: "Ubah sebagian data mata kuliah", Request: requests.PatchMatkulRequest{}, Header: []string{"If-Match"}, Response: response.MatkulResponse{}},
+	{Method: http.MethodDelete, Path: "/api/v2/matkul/:id", Tag: "v2 matkul", Summary: "Hapus mata kuliah", Header: []string{"If-Match"}, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/api/v2/matkul/:id/topics", Tag: "v2 matkul", Summary: "Daftar topik mingguan beserta materinya; hanya oleh anggota kelas yang mengambil mata kuliah atau admin", Response: []response.MaterialTopicResponse{}},
+	{Method: http.MethodPost, Path: "/api/v2/matkul/:id/topics", Tag: "v2 matkul", Summary: "Tambah topik mingguan (khusus dosen pengampu atau admin)", Request: requests.InsertMaterialTopicRequest{}, Response: response.MaterialTopicResponse{}, Status: http.StatusCreated},
+	{Method: http.MethodDelete, Path: "/api/v2/matkul/:id/topics/:topic_id", Tag: "v2 matkul", Summary: "Hapus topik beserta materinya (khusus dosen pengampu atau admin)", Status: http.StatusNoContent},
+	{Method: http.MethodPost, Path: "/api/v2/matkul/:id/topics/:topic_id/links", Tag: "v2 matkul", Summary: "Tambah materi berupa link http atau https (khusus dosen pengampu atau admin)", Request: requests.InsertMaterialLinkRequest{}, Response: response.MaterialResponse{}, Status: http.StatusCreated},
+	{Method: http.MethodPost, Path: "/api/v2/matkul/:id/topics/:topic_id/files", Tag: "v2 matkul", Summary: "Unggah materi berupa file maksimal 50 MB dengan field title opsional (khusus dosen pengampu atau admin)", Upload: "file", Response: response.MaterialResponse{}, Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v2/matkul/:id/materials/:material_id", Tag: "v2 matkul", Summary: "Detail materi", Response: response.MaterialResponse{}},
+	{Method: http.MethodDelete, Path: "/api/v2/matkul/:id/materials/:material_id", Tag: "v2 matkul", Summary: "Hapus materi (khusus dosen pengampu atau admin)", Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/api/v2/matkul/:id/materials/:material_id/download", Tag: "v2 matkul", Summary: "Alihkan ke link materi atau ke URL bertanda tangan file materi", Status: http.StatusFound},
+
+	{Method: http.MethodPost, Path: "/api/v2/import/:resource", Tag: "v2 import", Summary: "Import users, classes atau matkul dari CSV/XLSX (khusus admin)", Query: []string{"mode", "dry_run", "format"}, Upload: "file", Response: response.ImportReport{}},
+
+	{Method: http.MethodGet, Path: "/api/v2/export/:resource", Tag: "v2 export", Summary: "Export classes, users, lectures, rooms atau matkul sebagai CSV, XLSX atau NDJSON (khusus admin)", Query: append(append([]string{"format", "role"}, profileQuery...), listQuery...), Produces: []string{spreadsheet.CSV.ContentType(), spreadsheet.XLSX.ContentType(), spreadsheet.NDJSON.ContentType()}},
+
+	{Method: http.MethodPost, Path: "/api/v2/admin/:resource/:id/restore", Tag: "v2 admin", Summary: "Pulihkan users, classes, lectures, rooms atau matkul yang terhapus (khusus admin)", Response: response.RestoreResponse{}},
+
+	{Method: http.MethodGet, Path: "/graphql", Tag: "graphql", Summary: "Query GraphQL lewat query parameter", Query: []string{"query", "operationName", "variables"}, Response: response.GraphQLResponse{}, Raw: true},
+	{Method: http.MethodPost, Path: "/graphql", Tag: "graphql", Summary: "Query GraphQL", Request: requests.GraphQLRequest{}, Response: response.GraphQLResponse{}, Raw: true},
+
+	{Method: http.MethodGet, Path: "/metrics", Tag: "ops", Summary: "Metrik Prometheus", Produces: []string{"text/plain"}, Public: true},
+	{Method: http.MethodGet, Path: "/healthz", Tag: "ops", Summary: "Liveness: proses masih melayani HTTP", Response: response.HealthResponse{}, Raw: true, Public: true},
+	{Method: http.MethodGet, Path: "/readyz", Tag: "ops", Summary: "Readiness: database dapat dihubungi dan migrasi lengkap, 503 bila tidak", Response: response.HealthResponse{}, Raw: true, Public: true},
+}
+
+var pathParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// OpenAPIPath converts a gin route path (/classes/:id) into an OpenAPI path (/classes/{id}).
+func OpenAPIPath(ginPath string) string {
+	return pathParam.ReplaceAllString(ginPath, "{$1}")
+}
+
+// HasOperation reports whether a gin route is documented in Operations.
+func HasOperation(method, ginPath string) bool {
+	for _, operation := range Operations {
+		if operation.Method == method && OpenAPIPath(operation.Path) == OpenAPIPath(ginPath) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	specOnce sync.Once
+	spec     map[string]any
+)
+
+// Spec returns the OpenAPI document built from Operations.
+func Spec() map[string]any {
+	specOnce.Do(func() {
+		spec = buildSpec(Operations)
+	})
+	return spec
+}
+
+func buildSpec(operations []Operation) map[string]any {
+	schemas := map[string]any{}
+	errorSchema := schemaOf(reflect.TypeOf(response.ErrorMsg{}), schemas)
+	// api.RequestLogger adds the request ID to every error body as it is written.
+	schemas["ErrorMsg"].(map[string]any)["properties"].(map[string]any)["request_id"] = map[string]any{"type": "string"}
+	paths := map[string]any{}
+
+	for _, operation := range operations {
+		path := OpenAPIPath(operation.Path)
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[path] = item
+		}
+
+		var parameters []any
+		for _, match := range pathParam.FindAllStringSubmatch(operation.Path, -1) {
+			parameters = append(parameters, map[string]any{"name": match[1], "in": "path", "required": true, "schema": map[string]any{"type": "string"}})
+		}
+		for _, name := range operation.Query {
+			parameters = append(parameters, map[string]any{"name": name, "in": "query", "schema": map[string]any{"type": "string"}})
+		}
+		for _, name := range operation.Header {
+			parameters = append(parameters, map[string]any{"name": name, "in": "header", "required": true, "schema": map[string]any{"type": "string"}})
+		}
+
+		status := operation.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]any{"description": "Sukses"}
+		if len(operation.Produces) > 0 {
+			content := map[string]any{}
+			for _, mediaType := range operation.Produces {
+				content[mediaType] = map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}}
+			}
+			success["content"] = content
+		} else if operation.Raw {
+			success["content"] = jsonContent(schemaOf(reflect.TypeOf(operation.Response), schemas))
+		} else if status != http.StatusNoContent && status != http.StatusFound {
+			success["content"] = jsonContent(successSchema(operation.Response, schemas))
+		}
+
+		op := map[string]any{
+			"tags":        []string{operation.Tag},
+			"summary":     operation.Summary,
+			"operationId": operationID(operation),
+			"responses": map[string]any{
+				strconv.Itoa(status): success,
+				"default":            map[string]any{"description": "Error", "content": jsonContent(errorSchema)},
+			},
+		}
+		if len(parameters) > 0 {
+			op["parameters"] = parameters
+		}
+		if operation.Request != nil {
+			op["requestBody"] = map[string]any{"required": true, "content": jsonContent(schemaOf(reflect.TypeOf(operation.Request), schemas))}
+		}
+		if operation.Upload != "" {
+			op["requestBody"] = map[string]any{"required": true, "content": map[string]any{"multipart/form-data": map[string]any{"schema": map[string]any{
+				"type":       "object",
+				"required":   []string{operation.Upload},
+				"properties": map[string]any{operation.Upload: map[string]any{"type": "string", "format": "binary"}},
+			}}}}
+		}
+		if !operation.Public {
+			op["security"] = []any{map[string]any{"bearerAuth": []string{}}}
+		}
+		item[strings.ToLower(operation.Method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "SinauStudio API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+}
+
+func successSchema(data any, schemas map[string]any) map[string]any {
+	envelope := schemaOf(reflect.TypeOf(response.SuccessResponse{}), schemas)
+	if data == nil {
+		return envelope
+	}
+
+	var dataSchema map[string]any
+	if oneOf, ok := data.(OneOf); ok {
+		var variants []any
+		for _, variant := range oneOf {
+			variants = append(variants, schemaOf(reflect.TypeOf(variant), schemas))
+		}
+		dataSchema = map[string]any{"oneOf": variants}
+	} else {
+		dataSchema = schemaOf(reflect.TypeOf(data), schemas)
+	}
+
+	return map[string]any{"allOf": []any{
+		envelope,
+		map[string]any{"type": "object", "properties": map[string]any{"data": dataSchema}},
+	}}
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+func operationID(operation Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(operation.Method))
+	for _, part := range strings.FieldsFunc(OpenAPIPath(operation.Path), func(r rune) bool {
+		return r == '/' || r == '.' || r == '{' || r == '}' || r == '-'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// Register serves the OpenAPI document and the Swagger UI page.
+func Register(route gin.IRoutes) {
+	route.GET(SpecPath, func(c *gin.Context) {
+		c.JSON(http.StatusOK, Spec())
+	})
+	route.GET(UIPath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUI))
+	})
+}
+
+const swaggerUI = `<!DOCTYPE html>
+<html lang="id">
+<head>
+  <meta charset="utf-8">
+  <title>SinauStudio API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "` + SpecPath + `", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,153 @@
+package docs
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf converts a Go type into an OpenAPI schema. Structs are registered
+// once under components/schemas and referenced with $ref; json tags give the
+// property names and gin binding tags give required, min/max and formats.
+func schemaOf(t reflect.Type, components map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		name := t.Name()
+		if _, ok := components[name]; !ok {
+			components[name] = map[string]any{}
+			components[name] = structSchema(t, components)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), components)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem(), components)}
+	default:
+		return map[string]any{}
+	}
+}
+
+func structSchema(t reflect.Type, components map[string]any) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		// Embedded structs without a json name are flattened, as encoding/json does.
+		if field.Anonymous && field.Tag.Get("json") == "" && field.Type.Kind() == reflect.Struct {
+			embedded := structSchema(field.Type, components)
+			for name, property := range embedded["properties"].(map[string]any) {
+				properties[name] = property
+			}
+			if names, ok := embedded["required"].([]string); ok {
+				required = append(required, names...)
+			}
+			continue
+		}
+
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if jsonName, _, _ := strings.Cut(tag, ","); jsonName != "" {
+				name = jsonName
+			}
+		}
+
+		property := schemaOf(field.Type, components)
+		if _, isRef := property["$ref"]; !isRef {
+			isRequired := applyBinding(property, field.Tag.Get("binding"))
+			if isRequired {
+				required = append(required, name)
+			}
+		} else if hasRule(field.Tag.Get("binding"), "required") {
+			required = append(required, name)
+		}
+		properties[name] = property
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// applyBinding maps validator rules onto the schema and reports whether the field is required.
+func applyBinding(property map[string]any, binding string) bool {
+	isRequired := false
+	isString := property["type"] == "string"
+
+	for _, rule := range strings.Split(binding, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			isRequired = true
+		case "email":
+			property["format"] = "email"
+		case "url":
+			property["format"] = "uri"
+		case "alpha":
+			property["pattern"] = "^[a-zA-Z]+$"
+		case "alphanum":
+			property["pattern"] = "^[a-zA-Z0-9]+$"
+		case "oneof":
+			property["enum"] = strings.Fields(value)
+		case "min", "max", "len":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			if isString {
+				if key != "max" {
+					property["minLength"] = n
+				}
+				if key != "min" {
+					property["maxLength"] = n
+				}
+			} else {
+				if key != "max" {
+					property["minimum"] = n
+				}
+				if key != "min" {
+					property["maximum"] = n
+				}
+			}
+		}
+	}
+	return isRequired
+}
+
+func hasRule(binding, rule string) bool {
+	for _, r := range strings.Split(binding, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package docs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+)
+
+func TestSchemaFromBindingTags(t *testing.T) {
+	schemas := map[string]any{}
+	ref := schemaOf(reflect.TypeOf(requests.AuthRegisterRequest{}), schemas)
+	if ref["$ref"] != "#/components/schemas/AuthRegisterRequest" {
+		t.Fatalf("unexpected ref %v", ref)
+	}
+
+	schema := schemas["AuthRegisterRequest"].(map[string]any)
+	required := schema["required"].([]string)
+	if !reflect.DeepEqual(required, []string{"name", "email", "password", "role", "class_id"}) {
+		t.Fatalf("unexpected required fields %v", required)
+	}
+
+	properties := schema["properties"].(map[string]any)
+	email := properties["email"].(map[string]any)
+	if email["format"] != "email" || email["minLength"] != 5 {
+		t.Fatalf("unexpected email schema %v", email)
+	}
+	if classID := properties["class_id"].(map[string]any); classID["type"] != "integer" {
+		t.Fatalf("unexpected class_id schema %v", classID)
+	}
+}
+
+func TestSchemaFlattensEmbeddedStructs(t *testing.T) {
+	schemas := map[string]any{}
+	schemaOf(reflect.TypeOf(response.RoomResponse{}), schemas)
+
+	properties := schemas["RoomResponse"].(map[string]any)["properties"].(map[string]any)
+	if _, ok := properties["Audit"]; ok {
+		t.Fatalf("embedded Audit documented as a property: %v", properties)
+	}
+	if createdAt, _ := properties["created_at"].(map[string]any); createdAt["format"] != "date-time" {
+		t.Fatalf("unexpected created_at schema %v", properties["created_at"])
+	}
+	if createdBy, _ := properties["created_by"].(map[string]any); createdBy["type"] != "integer" {
+		t.Fatalf("unexpected created_by schema %v", properties["created_by"])
+	}
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	if got := OpenAPIPath("/api/v2/classes/:id/users"); got != "/api/v2/classes/{id}/users" {
+		t.Fatalf("unexpected path %s", got)
+	}
+}
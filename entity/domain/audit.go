@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Audit records when and by whom a row was created and last modified. The
+// repositories maintain it on every write; the *By fields stay nil for writes
+// made without a signed in user, such as a registration.
+type Audit struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	CreatedBy *int       `json:"created_by,omitempty"`
+	UpdatedBy *int       `json:"updated_by,omitempty"`
+}
+
+// NewAudit stamps a row created at by by.
+func NewAudit(at time.Time, by *int) Audit {
+	return Audit{CreatedAt: &at, UpdatedAt: &at, CreatedBy: by, UpdatedBy: by}
+}
+
+// Touch stamps a modification at by by.
+func (a *Audit) Touch(at time.Time, by *int) {
+	a.UpdatedAt, a.UpdatedBy = &at, by
+}
+
+// ListFilter narrows and orders a listing; zero fields don't filter. Sort
+// names a column, prefixed with "-" for descending order, and ties are broken
+// by ID.
+type ListFilter struct {
+	Name          string
+	CreatedBy     int
+	UpdatedBy     int
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	Sort          string
+	// Profile only narrows the user and lecture listings.
+	Profile ProfileFilter
+}
@@ -1,7 +1,18 @@
 package domain
 
+import "time"
+
 type Class struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	KodeKelas string `json:"kode_kelas"`
+	ID                 int        `json:"id"`
+	Name               string     `json:"name"`
+	KodeKelas          string     `json:"kode_kelas,omitempty"`
+	KodeKelasExpiresAt *time.Time `json:"kode_kelas_expires_at,omitempty"`
+	Version            int        `json:"version"`
+	DeletedAt          *time.Time `json:"deleted_at,omitempty"`
+	Audit
+}
+
+// KodeKelasExpired reports whether the join code can no longer be used at now.
+func (c *Class) KodeKelasExpired(now time.Time) bool {
+	return c.KodeKelasExpiresAt != nil && !now.Before(*c.KodeKelasExpiresAt)
 }
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ClassMember is one row of class_member. Name, Email and Role are filled
+// when members are listed with their user.
+type ClassMember struct {
+	ClassID  int
+	UserID   int
+	Name     string
+	Email    string
+	Role     string
+	JoinedAt time.Time
+}
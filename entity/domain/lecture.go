@@ -1,7 +1,12 @@
 package domain
 
+import "time"
+
 type Lecture struct {
-	ID     int
-	Name   string
-	UserID int
+	ID        int
+	Name      string
+	UserID    int
+	Version   int
+	DeletedAt *time.Time
+	Audit
 }
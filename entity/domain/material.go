@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Kinds of material stored in material.kind: an uploaded file or a link to
+// a page elsewhere, such as a video or a shared document.
+const (
+	MaterialFile = "file"
+	MaterialLink = "link"
+)
+
+// MaterialTopic groups the materials of one week of a matkul; a matkul has
+// at most one topic a week.
+type MaterialTopic struct {
+	ID          int        `json:"id"`
+	MatkulID    int        `json:"matkul_id"`
+	Week        int        `json:"week"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Version     int        `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	Audit
+}
+
+// Material is one file or link of a topic. A file is kept in the storage
+// under FileKey, among the files of the lecturer who uploaded it; a link
+// only has its URL.
+type Material struct {
+	ID          int
+	TopicID     int
+	Kind        string
+	Title       string
+	URL         string
+	FileKey     string
+	FileName    string
+	ContentType string
+	Size        int64
+	Version     int
+	DeletedAt   *time.Time
+	Audit
+}
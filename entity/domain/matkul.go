@@ -1,7 +1,12 @@
 package domain
 
+import "time"
+
 type Matkul struct {
-	ID         int    `json:"id"`
-	KodeMatkul string `json:"kode_matkul"`
-	Name       string `json:"name"`
+	ID         int        `json:"id"`
+	KodeMatkul string     `json:"kode_matkul"`
+	Name       string     `json:"name"`
+	Version    int        `json:"version"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	Audit
 }
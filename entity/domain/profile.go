@@ -0,0 +1,30 @@
+package domain
+
+// Profile is the academic identity of a user: the NPM of a mahasiswa, the
+// NIDN of a dosen and the details both share. A user without a profile row
+// has an empty one; empty fields are stored as NULL. Avatar is an http URL
+// set by the user or the URL an uploaded avatar is served at.
+type Profile struct {
+	UserID       int    `json:"-"`
+	NPM          string `json:"npm,omitempty"`
+	NIDN         string `json:"nidn,omitempty"`
+	ProgramStudi string `json:"program_studi,omitempty"`
+	Angkatan     int    `json:"angkatan,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+	Avatar       string `json:"avatar,omitempty"`
+	Audit
+}
+
+// ProfileFilter narrows the user and lecture listings to the users whose
+// profile matches; zero fields don't filter. NPM and NIDN match exactly, the
+// program studi by part and case-insensitively.
+type ProfileFilter struct {
+	NPM          string
+	NIDN         string
+	ProgramStudi string
+	Angkatan     int
+}
+
+func (f ProfileFilter) IsZero() bool {
+	return f == ProfileFilter{}
+}
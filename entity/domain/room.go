@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type Room struct {
 	ID        int
 	Name      string
@@ -7,4 +9,7 @@ type Room struct {
 	LectureID int
 	StartRoom string
 	EndRoom   string
+	Version   int
+	DeletedAt *time.Time
+	Audit
 }
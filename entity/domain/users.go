@@ -1,10 +1,23 @@
 package domain
 
+import "time"
+
+// Roles stored in users.role. Admins are created and granted only through the
+// command line, never by registering.
+const (
+	RoleDosen     = "dosen"
+	RoleMahasiswa = "mahasiswa"
+	RoleAdmin     = "admin"
+)
+
 type Users struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-	Role     string `json:"role"`
-	ClassID  int    `json:"class_id"`
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Password  string     `json:"password"`
+	Role      string     `json:"role"`
+	ClassID   int        `json:"class_id"`
+	Version   int        `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Audit
 }
@@ -0,0 +1,7 @@
+package requests
+
+// RegenerateKodeKelasRequest replaces the kode kelas of a class. Without
+// expires_in_hours the new code never expires.
+type RegenerateKodeKelasRequest struct {
+	ExpiresInHours int `binding:"omitempty,min=1,max=8760" json:"expires_in_hours"`
+}
@@ -1,6 +1,7 @@
 package requests
 
 type InsertClassRequest struct {
+	ID        int    `json:"-"`
 	Name      string `binding:"required,min=4,max=12" json:"name"`
 	KodeKelas string
 }
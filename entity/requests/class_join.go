@@ -0,0 +1,5 @@
+package requests
+
+type JoinClassRequest struct {
+	KodeKelas string `binding:"required,max=32" json:"kode_kelas"`
+}
@@ -0,0 +1,17 @@
+package requests
+
+import "github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+
+type PatchClassRequest struct {
+	Name *string `binding:"omitempty,min=4,max=12" json:"name"`
+}
+
+// Apply overlays the fields set in the patch on the current class. The update
+// only succeeds while the class is still at the version it was read at.
+func (p *PatchClassRequest) Apply(ID int, current *domain.Class) *UpdateClassRequest {
+	update := &UpdateClassRequest{ID: ID, Name: current.Name, Version: current.Version}
+	if p.Name != nil {
+		update.Name = *p.Name
+	}
+	return update
+}
@@ -3,4 +3,6 @@ package requests
 type UpdateClassRequest struct {
 	ID   int    `json:"id"`
 	Name string `binding:"required,min=4" json:"name"`
+	// Version is the version of the row the change is based on; 0 skips the check.
+	Version int `json:"-"`
 }
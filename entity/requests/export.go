@@ -0,0 +1,9 @@
+package requests
+
+// ExportRequest takes the same filters as the listing route of the
+// resource; role only applies to users and the profile filters to users and
+// lectures.
+type ExportRequest struct {
+	Resource string `json:"-"`
+	UserListRequest
+}
@@ -0,0 +1,7 @@
+package requests
+
+type GraphQLRequest struct {
+	Query         string         `binding:"required" json:"query" form:"query"`
+	OperationName string         `json:"operationName" form:"operationName"`
+	Variables     map[string]any `json:"variables" form:"-"`
+}
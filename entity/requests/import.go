@@ -0,0 +1,31 @@
+package requests
+
+const (
+	ImportAtomic     = "atomic"
+	ImportBestEffort = "best_effort"
+)
+
+type ImportRequest struct {
+	Resource string `json:"-"`
+	Mode     string `binding:"omitempty,oneof=atomic best_effort" form:"mode" json:"mode"`
+	DryRun   bool   `form:"dry_run" json:"dry_run"`
+}
+
+// ImportUserRow holds the user columns validated per row; the class is
+// resolved separately from class_id or class_name.
+type ImportUserRow struct {
+	Name     string `binding:"required,min=5" json:"name"`
+	Email    string `binding:"required,email,min=5" json:"email"`
+	Password string `binding:"required,min=6" json:"password"`
+	Role     string `binding:"required,alpha,min=5" json:"role"`
+	ClassID  int    `json:"class_id"`
+}
+
+type ImportClassRow struct {
+	Name string `binding:"required,min=4,max=12" json:"name"`
+}
+
+type ImportMatkulRow struct {
+	KodeMatkul string `binding:"required" json:"kode_matkul"`
+	Name       string `binding:"required" json:"name"`
+}
@@ -0,0 +1,16 @@
+package requests
+
+const (
+	DeleteRestrict = "restrict"
+	DeleteCascade  = "cascade"
+	DeleteReassign = "reassign"
+)
+
+// DeleteLectureRequest picks what happens to the rooms of the lecture: they
+// are deleted with it (cascade, the default), block the delete (restrict) or
+// move to the lecture ReassignTo (reassign).
+type DeleteLectureRequest struct {
+	ID         int    `json:"-"`
+	Rooms      string `binding:"omitempty,oneof=restrict cascade reassign" form:"rooms" json:"rooms"`
+	ReassignTo int    `binding:"required_if=Rooms reassign,omitempty,min=1" form:"reassign_to" json:"reassign_to"`
+}
@@ -0,0 +1,17 @@
+package requests
+
+import "github.com/dimassfeb-09/sinaustudio.git/entity/response"
+
+type PatchLectureRequest struct {
+	Name *string `binding:"omitempty,min=1" json:"name"`
+}
+
+// Apply overlays the fields set in the patch on the current lecture. The update
+// only succeeds while the lecture is still at the version it was read at.
+func (p *PatchLectureRequest) Apply(ID int, current *response.LectureResponse) *UpdateLectureRequest {
+	update := &UpdateLectureRequest{ID: ID, Name: current.Name, Version: current.Version}
+	if p.Name != nil {
+		update.Name = *p.Name
+	}
+	return update
+}
@@ -3,4 +3,6 @@ package requests
 type UpdateLectureRequest struct {
 	ID   int    `json:"id"`
 	Name string `binding:"required" json:"name"`
+	// Version is the version of the row the change is based on; 0 skips the check.
+	Version int `json:"-"`
 }
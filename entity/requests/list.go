@@ -0,0 +1,17 @@
+package requests
+
+import "time"
+
+// ListRequest takes the filters of the listing routes: name matches part of
+// the name, the *_by filters a user ID and the *_after / *_before filters an
+// RFC 3339 time. Sort names the order column, "-" first for descending.
+type ListRequest struct {
+	Name          string     `form:"name" json:"name"`
+	CreatedBy     int        `binding:"omitempty,min=1" form:"created_by" json:"created_by"`
+	UpdatedBy     int        `binding:"omitempty,min=1" form:"updated_by" json:"updated_by"`
+	CreatedAfter  *time.Time `form:"created_after" json:"created_after"`
+	CreatedBefore *time.Time `form:"created_before" json:"created_before"`
+	UpdatedAfter  *time.Time `form:"updated_after" json:"updated_after"`
+	UpdatedBefore *time.Time `form:"updated_before" json:"updated_before"`
+	Sort          string     `binding:"omitempty,oneof=id -id name -name created_at -created_at updated_at -updated_at" form:"sort" json:"sort"`
+}
@@ -0,0 +1,26 @@
+package requests
+
+type InsertMaterialTopicRequest struct {
+	ID          int    `json:"-"`
+	MatkulID    int    `json:"-"`
+	Week        int    `binding:"required,min=1,max=52" json:"week"`
+	Title       string `binding:"required,max=255" json:"title"`
+	Description string `binding:"max=2000" json:"description"`
+}
+
+// InsertMaterialLinkRequest shares a link under a topic.
+type InsertMaterialLinkRequest struct {
+	ID    int    `json:"-"`
+	Title string `binding:"required,max=255" json:"title"`
+	URL   string `binding:"required,max=2048" json:"url"`
+}
+
+// InsertMaterialFileRequest uploads a file under a topic. The title defaults
+// to the name of the file.
+type InsertMaterialFileRequest struct {
+	ID          int    `form:"-"`
+	Title       string `binding:"max=255" form:"title"`
+	FileName    string `form:"-"`
+	ContentType string `form:"-"`
+	Size        int64  `form:"-"`
+}
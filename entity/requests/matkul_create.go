@@ -1,6 +1,7 @@
 package requests
 
 type InsertMatkulRequest struct {
+	ID         int    `json:"-"`
 	KodeMatkul string `binding:"required" json:"kode_matkul"`
 	Name       string `binding:"required" json:"name"`
 }
@@ -0,0 +1,21 @@
+package requests
+
+import "github.com/dimassfeb-09/sinaustudio.git/entity/response"
+
+type PatchMatkulRequest struct {
+	Name       *string `binding:"omitempty,min=1" json:"name"`
+	KodeMatkul *string `binding:"omitempty,min=1" json:"kode_matkul"`
+}
+
+// Apply overlays the fields set in the patch on the current matkul. The update
+// only succeeds while the matkul is still at the version it was read at.
+func (p *PatchMatkulRequest) Apply(ID int, current *response.MatkulResponse) *UpdateMatkulRequest {
+	update := &UpdateMatkulRequest{ID: ID, Name: current.Name, KodeMatkul: current.KodeMatkul, Version: current.Version}
+	if p.Name != nil {
+		update.Name = *p.Name
+	}
+	if p.KodeMatkul != nil {
+		update.KodeMatkul = *p.KodeMatkul
+	}
+	return update
+}
@@ -4,4 +4,7 @@ type UpdateMatkulRequest struct {
 	ID         int    `json:"id"`
 	Name       string `json:"name"`
 	KodeMatkul string `json:"kode_matkul"`
+	// Version is the version of the row the change is based on; 0 skips the check.
+	// The v1 API takes it from the body when If-Match is not sent.
+	Version int `json:"version"`
 }
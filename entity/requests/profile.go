@@ -0,0 +1,52 @@
+package requests
+
+// ProfileRequest is the profile given with a new user. The formats are
+// checked by the service, since which identity number a user may have
+// depends on the role: an NPM of 8 to 15 digits for a mahasiswa, an NIDN of
+// 10 digits for a dosen.
+type ProfileRequest struct {
+	NPM          string `json:"npm"`
+	NIDN         string `json:"nidn"`
+	ProgramStudi string `json:"program_studi"`
+	Angkatan     int    `json:"angkatan"`
+	Phone        string `json:"phone"`
+}
+
+// ProfilePatchRequest only changes the fields that are set; an empty string
+// or a zero angkatan clears the field.
+type ProfilePatchRequest struct {
+	NPM          *string `json:"npm"`
+	NIDN         *string `json:"nidn"`
+	ProgramStudi *string `json:"program_studi"`
+	Angkatan     *int    `json:"angkatan"`
+	Phone        *string `json:"phone"`
+	Avatar       *string `json:"avatar"`
+}
+
+// UserListRequest takes the filters of the user listing on top of those of
+// every listing.
+type UserListRequest struct {
+	ListRequest
+	ProfileListRequest
+	Role string `binding:"omitempty,oneof=dosen mahasiswa admin" form:"role" json:"role"`
+}
+
+// ProfileListRequest narrows a listing to the users whose profile matches.
+type ProfileListRequest struct {
+	NPM          string `form:"npm" json:"npm"`
+	NIDN         string `form:"nidn" json:"nidn"`
+	ProgramStudi string `form:"program_studi" json:"program_studi"`
+	Angkatan     int    `binding:"omitempty,min=1" form:"angkatan" json:"angkatan"`
+}
+
+// LectureListRequest takes the filters of the lecture listing.
+type LectureListRequest struct {
+	ListRequest
+	ProfileListRequest
+}
+
+// AvatarRequest picks the thumbnail an avatar URL serves, in pixels; zero
+// serves the default size.
+type AvatarRequest struct {
+	Size int `binding:"omitempty,min=1" form:"size" json:"size"`
+}
@@ -0,0 +1,41 @@
+package requests
+
+import "github.com/dimassfeb-09/sinaustudio.git/entity/response"
+
+type PatchRoomRequest struct {
+	Name      *string `binding:"omitempty,min=1" json:"name"`
+	URL       *string `binding:"omitempty,min=1" json:"url"`
+	LectureID *int    `binding:"omitempty,min=1" json:"lecture_id"`
+	StartRoom *string `binding:"omitempty" json:"start_room"`
+	EndRoom   *string `binding:"omitempty" json:"end_room"`
+}
+
+// Apply overlays the fields set in the patch on the current room. The update
+// only succeeds while the room is still at the version it was read at.
+func (p *PatchRoomRequest) Apply(ID int, current *response.RoomResponse) *UpdateRoomRequest {
+	update := &UpdateRoomRequest{
+		ID:        ID,
+		Name:      current.Name,
+		URL:       current.URL,
+		LectureID: current.LectureID,
+		StartRoom: current.StartRoom,
+		EndRoom:   current.EndRoom,
+		Version:   current.Version,
+	}
+	if p.Name != nil {
+		update.Name = *p.Name
+	}
+	if p.URL != nil {
+		update.URL = *p.URL
+	}
+	if p.LectureID != nil {
+		update.LectureID = *p.LectureID
+	}
+	if p.StartRoom != nil {
+		update.StartRoom = *p.StartRoom
+	}
+	if p.EndRoom != nil {
+		update.EndRoom = *p.EndRoom
+	}
+	return update
+}
@@ -7,4 +7,7 @@ type UpdateRoomRequest struct {
 	LectureID int    `binding:"required" json:"lecture_id"`
 	StartRoom string `binding:"required" json:"start_room"`
 	EndRoom   string `binding:"required" json:"end_room"`
+	// Version is the version of the row the change is based on; 0 skips the check.
+	// The v1 API takes it from the body when If-Match is not sent.
+	Version int `json:"version"`
 }
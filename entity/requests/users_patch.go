@@ -0,0 +1,12 @@
+package requests
+
+type UserPatchRequest struct {
+	Name    *string `binding:"omitempty,min=5" json:"name"`
+	Email   *string `binding:"omitempty,email,min=5" json:"email"`
+	Role    *string `binding:"omitempty,alpha,min=5" json:"role"`
+	ClassID *int    `binding:"omitempty,min=1" json:"class_id"`
+	// Profile can only be changed by the user and by admins.
+	Profile *ProfilePatchRequest `json:"profile"`
+	// Version is the version of the row the change is based on; 0 skips the check.
+	Version int `json:"-"`
+}
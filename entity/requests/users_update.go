@@ -6,4 +6,6 @@ type UserUpdateRequest struct {
 	Email   string `binding:"required,email,min=5" json:"email"`
 	Role    string `binding:"required,alpha,min=5" json:"role"`
 	ClassID int    `binding:"required,numeric" json:"class_id"`
+	// Version is the version of the row the change is based on; 0 skips the check.
+	Version int `json:"-"`
 }
@@ -7,3 +7,7 @@ type UserInfoLogin struct {
 	Role    string
 	ClassID int
 }
+
+type AuthTokenResponse struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+type ClassMemberResponse struct {
+	UserID   int       `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
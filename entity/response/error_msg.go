@@ -1,8 +1,18 @@
 package response
 
 type ErrorMsg struct {
-	Success    bool   `json:"success"`
-	StatusCode int    `json:"status_code"`
-	ErrorKey   string `json:"error_key"`
-	Msg        any    `json:"message"`
+	Success    bool         `json:"success"`
+	StatusCode int          `json:"status_code"`
+	ErrorKey   string       `json:"error_key"`
+	Msg        any          `json:"message"`
+	RequestID  string       `json:"request_id,omitempty"`
+	Dependents []*Dependent `json:"dependents,omitempty"`
+	// Current is the latest representation of a resource whose version did not match.
+	Current any `json:"current,omitempty"`
+}
+
+// Dependent lists the rows of one resource that block a delete.
+type Dependent struct {
+	Resource string `json:"resource"`
+	IDs      []int  `json:"ids"`
 }
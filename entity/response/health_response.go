@@ -0,0 +1,8 @@
+package response
+
+// HealthResponse is the body of /healthz and /readyz: Status is "ok" or
+// "unavailable", and Checks has the outcome of every check by name.
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
@@ -0,0 +1,30 @@
+package response
+
+const (
+	ImportRowValid   = "valid"
+	ImportRowInvalid = "invalid"
+	ImportRowCreated = "created"
+	ImportRowFailed  = "failed"
+	ImportRowSkipped = "skipped"
+)
+
+type ImportReport struct {
+	Resource  string             `json:"resource"`
+	Mode      string             `json:"mode"`
+	DryRun    bool               `json:"dry_run"`
+	Total     int                `json:"total"`
+	Created   int                `json:"created"`
+	Failed    int                `json:"failed"`
+	Committed bool               `json:"committed"`
+	Rows      []*ImportRowResult `json:"rows"`
+}
+
+// ImportRowResult reports one data row. Password is only set for users
+// created with a generated initial password.
+type ImportRowResult struct {
+	Line     int      `json:"line"`
+	Status   string   `json:"status"`
+	ID       int      `json:"id,omitempty"`
+	Password string   `json:"password,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
@@ -1,6 +1,10 @@
 package response
 
+import "github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+
 type LectureResponse struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Version int    `json:"version,omitempty"`
+	domain.Audit
 }
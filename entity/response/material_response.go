@@ -0,0 +1,25 @@
+package response
+
+import "github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+
+// MaterialResponse is a material as listed to the members of a matkul.
+// DownloadURL leads, after the same access check, to the file or the link.
+type MaterialResponse struct {
+	ID          int    `json:"id"`
+	TopicID     int    `json:"topic_id"`
+	Kind        string `json:"kind"`
+	Title       string `json:"title"`
+	URL         string `json:"url,omitempty"`
+	FileName    string `json:"file_name,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	DownloadURL string `json:"download_url"`
+	Version     int    `json:"version"`
+	domain.Audit
+}
+
+// MaterialTopicResponse is a weekly topic with its materials, oldest first.
+type MaterialTopicResponse struct {
+	domain.MaterialTopic
+	Materials []*MaterialResponse `json:"materials"`
+}
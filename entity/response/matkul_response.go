@@ -1,7 +1,11 @@
 package response
 
+import "github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+
 type MatkulResponse struct {
 	ID         int    `json:"id"`
 	Name       string `json:"name"`
 	KodeMatkul string `json:"kode_matkul"`
+	Version    int    `json:"version,omitempty"`
+	domain.Audit
 }
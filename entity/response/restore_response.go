@@ -0,0 +1,6 @@
+package response
+
+type RestoreResponse struct {
+	Resource string `json:"resource"`
+	ID       int    `json:"id"`
+}
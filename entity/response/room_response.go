@@ -1,5 +1,7 @@
 package response
 
+import "github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+
 type RoomResponse struct {
 	ID        int    `json:"id"`
 	Name      string `json:"name"`
@@ -7,4 +9,6 @@ type RoomResponse struct {
 	LectureID int    `json:"lecture_id"`
 	StartRoom string `json:"start_room"`
 	EndRoom   string `json:"end_room"`
+	Version   int    `json:"version,omitempty"`
+	domain.Audit
 }
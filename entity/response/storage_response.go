@@ -0,0 +1,8 @@
+package response
+
+// StorageUsageResponse is the space a user's files take, in bytes, against
+// their quota; a zero quota means no limit.
+type StorageUsageResponse struct {
+	Used  int64 `json:"used"`
+	Quota int64 `json:"quota"`
+}
@@ -0,0 +1,15 @@
+package response
+
+import "github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+
+type UserResponse struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	Role    string `json:"role,omitempty"`
+	ClassID int    `json:"class_id"`
+	Version int    `json:"version,omitempty"`
+	// Profile is only loaded by the v2 user routes.
+	Profile *domain.Profile `json:"profile,omitempty"`
+	domain.Audit
+}
@@ -4,6 +4,7 @@ package exception
 var (
 	ERR_UNAUTHORIZED_BEARER string = "ERR_UNAUTHORIZED_BEARER"
 	ERR_BAD_REQUEST_FIELD   string = "ERR_BAD_REQUEST_FIELD"
+	ERR_FORBIDDEN           string = "ERR_FORBIDDEN"
 )
 
 // user
@@ -22,3 +23,8 @@ var (
 var (
 	ERR_CONFLICT string = "ERR_CONFLICT"
 )
+
+var (
+	ERR_PRECONDITION_FAILED   string = "ERR_PRECONDITION_FAILED"
+	ERR_PRECONDITION_REQUIRED string = "ERR_PRECONDITION_REQUIRED"
+)
@@ -0,0 +1,35 @@
+package graphqlapi
+
+import (
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+// serviceError carries a service ErrorMsg through graphql-go so the error key
+// and status code show up in the extensions of the formatted error.
+type serviceError struct {
+	errMsg *response.ErrorMsg
+}
+
+func (e *serviceError) Error() string {
+	if msg, ok := e.errMsg.Msg.(string); ok {
+		return msg
+	}
+	return e.errMsg.ErrorKey
+}
+
+func (e *serviceError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"error_key":   e.errMsg.ErrorKey,
+		"status_code": e.errMsg.StatusCode,
+	}
+}
+
+// resolveError turns errMsg into a resolver error; a missing record resolves
+// to null instead.
+func resolveError(errMsg *response.ErrorMsg) error {
+	if errMsg == nil || errMsg.ErrorKey == exception.ERR_NOT_FOUND {
+		return nil
+	}
+	return &serviceError{errMsg}
+}
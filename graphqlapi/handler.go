@@ -0,0 +1,82 @@
+package graphqlapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const Path = "/graphql"
+
+type Handler struct {
+	Schema   graphql.Schema
+	Services Services
+	Limits   Limits
+}
+
+func NewHandler(s Services, limits Limits) (*Handler, error) {
+	schema, err := NewSchema(s, limits)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{Schema: schema, Services: s, Limits: limits}, nil
+}
+
+// Serve answers a GraphQL request sent as JSON body (POST) or as query
+// parameters (GET). Requests that cannot be parsed, exceed the limits or do
+// not validate are answered with 400; everything else with 200 and the
+// per-field errors in the result.
+func (h *Handler) Serve(c *gin.Context) {
+	var r requests.GraphQLRequest
+	if c.Request.Method == http.MethodGet {
+		if err := c.ShouldBindQuery(&r); err != nil {
+			abortWithErrors(c, errors.New(helpers.ErrorValidateHandler(err)))
+			return
+		}
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &r.Variables); err != nil {
+				abortWithErrors(c, errors.New("Variables harus berupa objek JSON."))
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&r); err != nil {
+		abortWithErrors(c, errors.New(helpers.ErrorValidateHandler(err)))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(r.Query), Name: "GraphQL request"})})
+	if err != nil {
+		abortWithErrors(c, err)
+		return
+	}
+	if err := h.Limits.check(&h.Schema, doc, r.OperationName, r.Variables); err != nil {
+		abortWithErrors(c, err)
+		return
+	}
+	if validation := graphql.ValidateDocument(&h.Schema, doc, nil); !validation.IsValid {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &graphql.Result{Errors: validation.Errors})
+		return
+	}
+
+	ctx := withLoaders(c.Request.Context(), newLoaders(h.Services))
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.Schema,
+		AST:           doc,
+		OperationName: r.OperationName,
+		Args:          r.Variables,
+		Context:       ctx,
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+func abortWithErrors(c *gin.Context, err error) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+}
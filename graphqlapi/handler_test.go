@@ -0,0 +1,292 @@
+package graphqlapi
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/repository/memory"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/gin-gonic/gin"
+)
+
+// The counting repositories record how often each batch method runs, so the
+// tests can tell batched loading from one query per row.
+type counts map[string]int
+
+type countingClassRepository struct {
+	repository.ClassRepository
+	counts counts
+}
+
+func (r *countingClassRepository) FindClassByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Class, *response.ErrorMsg) {
+	r.counts["FindClassByIDs"]++
+	return r.ClassRepository.FindClassByIDs(ctx, db, IDs)
+}
+
+type countingUsersRepository struct {
+	repository.UsersRepository
+	counts counts
+}
+
+func (r *countingUsersRepository) FindUsersByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Users, *response.ErrorMsg) {
+	r.counts["FindUsersByIDs"]++
+	return r.UsersRepository.FindUsersByIDs(ctx, db, IDs)
+}
+
+func (r *countingUsersRepository) FindUsersByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) ([]*domain.Users, *response.ErrorMsg) {
+	r.counts["FindUsersByClassIDs"]++
+	return r.UsersRepository.FindUsersByClassIDs(ctx, db, classIDs)
+}
+
+type countingLectureRepository struct {
+	repository.LectureRepository
+	counts counts
+}
+
+func (r *countingLectureRepository) FindLectureByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Lecture, *response.ErrorMsg) {
+	r.counts["FindLectureByIDs"]++
+	return r.LectureRepository.FindLectureByIDs(ctx, db, IDs)
+}
+
+func (r *countingLectureRepository) FindLectureByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (map[int][]*domain.Lecture, *response.ErrorMsg) {
+	r.counts["FindLectureByClassIDs"]++
+	return r.LectureRepository.FindLectureByClassIDs(ctx, db, classIDs)
+}
+
+type countingRoomRepository struct {
+	repository.RoomRepository
+	counts counts
+}
+
+func (r *countingRoomRepository) FindRoomByLectureIDs(ctx context.Context, db *sql.DB, lectureIDs []int) ([]*domain.Room, *response.ErrorMsg) {
+	r.counts["FindRoomByLectureIDs"]++
+	return r.RoomRepository.FindRoomByLectureIDs(ctx, db, lectureIDs)
+}
+
+func (r *countingRoomRepository) FindRoomByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (map[int][]*domain.Room, *response.ErrorMsg) {
+	r.counts["FindRoomByClassIDs"]++
+	return r.RoomRepository.FindRoomByClassIDs(ctx, db, classIDs)
+}
+
+type testAPI struct {
+	engine *gin.Engine
+	counts counts
+}
+
+// newTestAPI serves the handler over a memory.Store with two classes, each
+// holding one dosen with two rooms and one mahasiswa. The clock is fixed so
+// the first room of every dosen is over and the second is upcoming.
+func newTestAPI(t *testing.T, limits Limits) *testAPI {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	now = func() time.Time { return time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+
+	ctx := context.Background()
+	store := memory.NewStore()
+	t.Cleanup(func() { store.DB().Close() })
+
+	c := counts{}
+	m := api.NewMemoryMicroService(store).(*api.MicroService)
+	m.Class = &countingClassRepository{m.Class, c}
+	m.User = &countingUsersRepository{m.User, c}
+	m.Lecture = &countingLectureRepository{m.Lecture, c}
+	m.Room = &countingRoomRepository{m.Room, c}
+
+	db := store.DB()
+	s := Services{
+		Class:   services.NewClassServiceImplementation(db, m),
+		Users:   services.NewUserServiceImplementation(db, m),
+		Lecture: services.NewLectureServiceImplementation(db, m),
+		Room:    services.NewRoomServiceImplementation(db, m),
+		Matkul:  services.NewMataKuliahServiceImplementation(db, m),
+	}
+	auth := services.NewAuthServiceImplementation(db, m)
+
+	for i, name := range []string{"TI-1A", "TI-1B"} {
+		class := &requests.InsertClassRequest{Name: name}
+		if _, errMsg := s.Class.AddClass(ctx, class); errMsg != nil {
+			t.Fatalf("AddClass: %v", errMsg.Msg)
+		}
+
+		for _, user := range []requests.AuthRegisterRequest{
+			{Name: "Dosen " + name, Email: "dosen" + string(rune('a'+i)) + "@sinaustudio.test", Password: "rahasia123", Role: "dosen", ClassID: class.ID},
+			{Name: "Mahasiswa " + name, Email: "mhs" + string(rune('a'+i)) + "@sinaustudio.test", Password: "rahasia123", Role: "mahasiswa", ClassID: class.ID},
+		} {
+			user := user
+			if _, errMsg := auth.AuthRegisterUser(ctx, &user); errMsg != nil {
+				t.Fatalf("AuthRegisterUser: %v", errMsg.Msg)
+			}
+		}
+
+		lecture, _, errMsg := s.Lecture.FindLectureByName(ctx, "Dosen "+name)
+		if errMsg != nil {
+			t.Fatalf("FindLectureByName: %v", errMsg.Msg)
+		}
+		for _, room := range []requests.InsertRoomRequest{
+			{Name: "Selesai " + name, URL: "https://meet.example.com/a", LectureID: lecture.ID, StartRoom: "2023-02-01 08:00:00", EndRoom: "2023-02-01 10:00:00"},
+			{Name: "Nanti " + name, URL: "https://meet.example.com/b", LectureID: lecture.ID, StartRoom: "2023-03-02 08:00:00", EndRoom: "2023-03-02 10:00:00"},
+		} {
+			room := room
+			if _, errMsg := s.Room.InsertRoom(ctx, &room); errMsg != nil {
+				t.Fatalf("InsertRoom: %v", errMsg.Msg)
+			}
+		}
+	}
+
+	handler, err := NewHandler(s, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := gin.New()
+	engine.POST(Path, handler.Serve)
+	engine.GET(Path, handler.Serve)
+
+	for key := range c {
+		delete(c, key)
+	}
+	return &testAPI{engine: engine, counts: c}
+}
+
+func (a *testAPI) query(t *testing.T, query string, wantStatus int) map[string]any {
+	t.Helper()
+	return a.queryVariables(t, query, nil, wantStatus)
+}
+
+func (a *testAPI) queryVariables(t *testing.T, query string, variables map[string]any, wantStatus int) map[string]any {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	req := httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	a.engine.ServeHTTP(rec, req)
+
+	if rec.Code != wantStatus {
+		t.Fatalf("expected status %d, got %d: %s", wantStatus, rec.Code, rec.Body)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestBatchedLoading(t *testing.T) {
+	a := newTestAPI(t, DefaultLimits)
+
+	result := a.query(t, `{
+		classes {
+			name
+			students { name class { kode_kelas } }
+			lecturers { name user { email } rooms { name lecture { id } } }
+			upcoming_rooms(limit: 5) { name }
+		}
+	}`, http.StatusOK)
+	if result["errors"] != nil {
+		t.Fatalf("unexpected errors: %v", result["errors"])
+	}
+
+	classes := result["data"].(map[string]any)["classes"].([]any)
+	if len(classes) != 2 {
+		t.Fatalf("expected 2 classes, got %v", classes)
+	}
+	for _, class := range classes {
+		class := class.(map[string]any)
+		if students := class["students"].([]any); len(students) != 1 {
+			t.Fatalf("expected 1 student in %v, got %v", class["name"], students)
+		}
+		lecturers := class["lecturers"].([]any)
+		if len(lecturers) != 1 || len(lecturers[0].(map[string]any)["rooms"].([]any)) != 2 {
+			t.Fatalf("expected 1 lecturer with 2 rooms in %v, got %v", class["name"], lecturers)
+		}
+		upcoming := class["upcoming_rooms"].([]any)
+		if len(upcoming) != 1 || upcoming[0].(map[string]any)["name"] != "Nanti "+class["name"].(string) {
+			t.Fatalf("expected only the upcoming room of %v, got %v", class["name"], upcoming)
+		}
+	}
+
+	for _, method := range []string{"FindUsersByClassIDs", "FindClassByIDs", "FindLectureByClassIDs", "FindUsersByIDs", "FindRoomByLectureIDs", "FindLectureByIDs", "FindRoomByClassIDs"} {
+		if a.counts[method] != 1 {
+			t.Errorf("expected %s to run once, ran %d times", method, a.counts[method])
+		}
+	}
+}
+
+func TestMissingRecordIsNull(t *testing.T) {
+	a := newTestAPI(t, DefaultLimits)
+
+	result := a.query(t, `{ class(id: 99) { name } room(id: 99) { name } }`, http.StatusOK)
+	if result["errors"] != nil {
+		t.Fatalf("unexpected errors: %v", result["errors"])
+	}
+	data := result["data"].(map[string]any)
+	if data["class"] != nil || data["room"] != nil {
+		t.Fatalf("expected null records, got %v", data)
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	a := newTestAPI(t, Limits{MaxDepth: 4, MaxComplexity: 50, DefaultListSize: 10})
+
+	for name, query := range map[string]string{
+		"too deep":          `{ classes { students { class { students { name } } } } }`,
+		"too complex":       `{ classes { name students { name email } } }`,
+		"fragment too deep": `{ classes { ...deep } } fragment deep on Class { students { class { students { name } } } }`,
+		"invalid field":     `{ classes { umur } }`,
+		"syntax error":      `{ classes {`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			result := a.query(t, query, http.StatusBadRequest)
+			if errors, _ := result["errors"].([]any); len(errors) == 0 {
+				t.Fatalf("expected errors, got %v", result)
+			}
+		})
+	}
+
+	result := a.query(t, `{ classes { name upcoming_rooms(limit: 2) { name } } __schema { types { name } } }`, http.StatusOK)
+	if result["errors"] != nil {
+		t.Fatalf("unexpected errors: %v", result["errors"])
+	}
+
+	// A limit passed as a variable counts like a literal one.
+	const upcoming = `query Upcoming($limit: Int) { classes { name upcoming_rooms(limit: $limit) { name } } }`
+	result = a.queryVariables(t, upcoming, map[string]any{"limit": 100000}, http.StatusBadRequest)
+	if errors, _ := result["errors"].([]any); len(errors) == 0 {
+		t.Fatalf("expected errors, got %v", result)
+	}
+	result = a.queryVariables(t, `query Upcoming($limit: Int = 100000) { classes { upcoming_rooms(limit: $limit) { name } } }`, nil, http.StatusBadRequest)
+	if errors, _ := result["errors"].([]any); len(errors) == 0 {
+		t.Fatalf("expected errors, got %v", result)
+	}
+	result = a.queryVariables(t, upcoming, map[string]any{"limit": 2}, http.StatusOK)
+	if result["errors"] != nil {
+		t.Fatalf("unexpected errors: %v", result["errors"])
+	}
+}
+
+func TestUpcomingRoomsDefaultLimit(t *testing.T) {
+	a := newTestAPI(t, Limits{DefaultListSize: 0})
+
+	result := a.query(t, `{ classes { upcoming_rooms { name } } }`, http.StatusOK)
+	if result["errors"] != nil {
+		t.Fatalf("unexpected errors: %v", result["errors"])
+	}
+	for _, class := range result["data"].(map[string]any)["classes"].([]any) {
+		if upcoming := class.(map[string]any)["upcoming_rooms"].([]any); len(upcoming) != 0 {
+			t.Fatalf("expected the rooms capped at DefaultListSize, got %v", upcoming)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package graphqlapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound how expensive a single query may be. Depth counts nested
+// fields; complexity counts every field once, multiplied for list fields by
+// their limit argument, literal or variable, or by DefaultListSize.
+// Introspection fields are not counted.
+type Limits struct {
+	MaxDepth        int
+	MaxComplexity   int
+	DefaultListSize int
+}
+
+var DefaultLimits = Limits{MaxDepth: 6, MaxComplexity: 5000, DefaultListSize: 10}
+
+// check reports the first limit the selected operation of doc exceeds when
+// run with variables.
+func (l Limits) check(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]any) error {
+	w := &costWalker{schema: schema, limits: l, fragments: map[string]*ast.FragmentDefinition{}}
+	var operations []*ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			w.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operations = append(operations, definition)
+			}
+		}
+	}
+
+	for _, operation := range operations {
+		var root graphql.Type = schema.QueryType()
+		if operation.Operation == ast.OperationTypeMutation {
+			root = schema.MutationType()
+		}
+
+		w.variables = map[string]any{}
+		for _, definition := range operation.VariableDefinitions {
+			if value, ok := definition.DefaultValue.(*ast.IntValue); ok {
+				w.variables[definition.Variable.Name.Value] = value.Value
+			}
+		}
+		for name, value := range variables {
+			w.variables[name] = value
+		}
+
+		depth, complexity := w.selectionSet(operation.SelectionSet, root, map[string]bool{})
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return fmt.Errorf("Kedalaman query %d melebihi batas %d.", depth, l.MaxDepth)
+		}
+		if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+			return fmt.Errorf("Kompleksitas query %d melebihi batas %d.", complexity, l.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+type costWalker struct {
+	schema    *graphql.Schema
+	limits    Limits
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+func (w *costWalker) selectionSet(set *ast.SelectionSet, parent graphql.Type, visiting map[string]bool) (depth int, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			d, c = w.field(selection, parent, visiting)
+		case *ast.InlineFragment:
+			typeCondition := parent
+			if selection.TypeCondition != nil {
+				typeCondition = w.schema.Type(selection.TypeCondition.Name.Value)
+			}
+			d, c = w.selectionSet(selection.SelectionSet, typeCondition, visiting)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := w.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			d, c = w.selectionSet(fragment.SelectionSet, w.schema.Type(fragment.TypeCondition.Name.Value), visiting)
+			delete(visiting, name)
+		}
+
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+	return depth, complexity
+}
+
+func (w *costWalker) field(field *ast.Field, parent graphql.Type, visiting map[string]bool) (depth int, complexity int) {
+	if strings.HasPrefix(field.Name.Value, "__") {
+		return 0, 0
+	}
+
+	var fieldType graphql.Type
+	if fields, ok := parent.(interface {
+		Fields() graphql.FieldDefinitionMap
+	}); ok {
+		if definition, ok := fields.Fields()[field.Name.Value]; ok {
+			fieldType = definition.Type
+		}
+	}
+
+	isList := false
+	for {
+		if nonNull, ok := fieldType.(*graphql.NonNull); ok {
+			fieldType = nonNull.OfType
+			continue
+		}
+		if list, ok := fieldType.(*graphql.List); ok {
+			isList = true
+			fieldType = list.OfType
+			continue
+		}
+		break
+	}
+
+	childDepth, childComplexity := w.selectionSet(field.SelectionSet, fieldType, visiting)
+	if isList {
+		childComplexity *= w.listSize(field)
+	}
+	return 1 + childDepth, 1 + childComplexity
+}
+
+func (w *costWalker) listSize(field *ast.Field) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "limit" {
+			continue
+		}
+		var value any = argument.Value
+		if variable, ok := value.(*ast.Variable); ok {
+			value = w.variables[variable.Name.Value]
+		}
+		if n, ok := intValue(value); ok && n >= 0 {
+			return n
+		}
+	}
+	return w.limits.DefaultListSize
+}
+
+// intValue reads an int from a literal, a default value or a JSON variable.
+func intValue(value any) (int, bool) {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(value.Value)
+		return n, err == nil
+	case string:
+		n, err := strconv.Atoi(value)
+		return n, err == nil
+	case float64:
+		return int(value), value == float64(int(value))
+	case int:
+		return value, true
+	}
+	return 0, false
+}
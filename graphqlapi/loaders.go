@@ -0,0 +1,136 @@
+package graphqlapi
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+)
+
+// loader collects the keys asked for while one level of the query resolves
+// and fetches all of them with a single batch call when the first result is
+// needed. graphql-go resolves thunks breadth first, so every sibling field of
+// a level has queued its key by then.
+type loader[V any] struct {
+	fetch func(ctx context.Context, keys []int) (map[int]V, *response.ErrorMsg)
+
+	mu      sync.Mutex
+	pending []int
+	queued  map[int]bool
+	results map[int]V
+	errs    map[int]*response.ErrorMsg
+}
+
+func newLoader[V any](fetch func(ctx context.Context, keys []int) (map[int]V, *response.ErrorMsg)) *loader[V] {
+	return &loader[V]{fetch: fetch, queued: map[int]bool{}, results: map[int]V{}, errs: map[int]*response.ErrorMsg{}}
+}
+
+func (l *loader[V]) load(ctx context.Context, key int) func() (V, bool, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+			results, errMsg := l.fetch(ctx, keys)
+			for _, k := range keys {
+				if errMsg != nil {
+					l.errs[k] = errMsg
+				} else if v, ok := results[k]; ok {
+					l.results[k] = v
+				}
+			}
+		}
+
+		if errMsg := l.errs[key]; errMsg != nil {
+			var zero V
+			return zero, false, &serviceError{errMsg}
+		}
+		v, ok := l.results[key]
+		return v, ok, nil
+	}
+}
+
+// later turns a loader thunk into the thunk graphql-go expects, mapping the
+// loaded value (or its absence) to the field result.
+func later[V any](thunk func() (V, bool, error), result func(v V, found bool) any) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		v, found, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		return result(v, found), nil
+	}
+}
+
+type loaders struct {
+	classes         *loader[*domain.Class]
+	users           *loader[*response.UserResponse]
+	usersByClass    *loader[[]*response.UserResponse]
+	lectures        *loader[*domain.Lecture]
+	lecturesByClass *loader[[]*domain.Lecture]
+	roomsByLecture  *loader[[]*response.RoomResponse]
+	roomsByClass    *loader[[]*response.RoomResponse]
+}
+
+func newLoaders(s Services) *loaders {
+	return &loaders{
+		classes: newLoader(func(ctx context.Context, IDs []int) (map[int]*domain.Class, *response.ErrorMsg) {
+			classes, errMsg := s.Class.FindClassByIDs(ctx, IDs)
+			return indexBy(classes, func(class *domain.Class) int { return class.ID }), errMsg
+		}),
+		users: newLoader(func(ctx context.Context, IDs []int) (map[int]*response.UserResponse, *response.ErrorMsg) {
+			users, errMsg := s.Users.FindUsersByIDs(ctx, IDs)
+			return indexBy(users, func(user *response.UserResponse) int { return user.ID }), errMsg
+		}),
+		usersByClass: newLoader(func(ctx context.Context, classIDs []int) (map[int][]*response.UserResponse, *response.ErrorMsg) {
+			users, errMsg := s.Users.FindUsersByClassIDs(ctx, classIDs)
+			return groupBy(users, func(user *response.UserResponse) int { return user.ClassID }), errMsg
+		}),
+		lectures: newLoader(func(ctx context.Context, IDs []int) (map[int]*domain.Lecture, *response.ErrorMsg) {
+			lectures, errMsg := s.Lecture.FindLectureByIDs(ctx, IDs)
+			return indexBy(lectures, func(lecture *domain.Lecture) int { return lecture.ID }), errMsg
+		}),
+		lecturesByClass: newLoader(s.Lecture.FindLectureByClassIDs),
+		roomsByLecture: newLoader(func(ctx context.Context, lectureIDs []int) (map[int][]*response.RoomResponse, *response.ErrorMsg) {
+			rooms, errMsg := s.Room.FindRoomByLectureIDs(ctx, lectureIDs)
+			return groupBy(rooms, func(room *response.RoomResponse) int { return room.LectureID }), errMsg
+		}),
+		roomsByClass: newLoader(s.Room.FindRoomByClassIDs),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+func indexBy[V any](values []V, key func(V) int) map[int]V {
+	index := make(map[int]V, len(values))
+	for _, v := range values {
+		index[key(v)] = v
+	}
+	return index
+}
+
+func groupBy[V any](values []V, key func(V) int) map[int][]V {
+	groups := map[int][]V{}
+	for _, v := range values {
+		groups[key(v)] = append(groups[key(v)], v)
+	}
+	return groups
+}
@@ -0,0 +1,319 @@
+// Package graphqlapi serves the read side of the services as a GraphQL schema
+// on /graphql. Related records are loaded through per-request batch loaders so
+// a query costs one repository call per relation and level, not per row.
+package graphqlapi
+
+import (
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/graphql-go/graphql"
+)
+
+type Services struct {
+	Class   services.ClassService
+	Users   services.UsersService
+	Lecture services.LectureService
+	Room    services.RoomService
+	Matkul  services.MataKuliahService
+}
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// now is replaced in tests to make upcoming rooms deterministic.
+var now = time.Now
+
+func NewSchema(s Services, limits Limits) (graphql.Schema, error) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"id":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: user(func(u *response.UserResponse) any { return u.ID })},
+			"name":  &graphql.Field{Type: graphql.String, Resolve: user(func(u *response.UserResponse) any { return u.Name })},
+			"email": &graphql.Field{Type: graphql.String, Resolve: user(func(u *response.UserResponse) any { return u.Email })},
+			"role":  &graphql.Field{Type: graphql.String, Resolve: user(func(u *response.UserResponse) any { return u.Role })},
+		},
+	})
+
+	roomType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Room",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: room(func(r *response.RoomResponse) any { return r.ID })},
+			"name":       &graphql.Field{Type: graphql.String, Resolve: room(func(r *response.RoomResponse) any { return r.Name })},
+			"url":        &graphql.Field{Type: graphql.String, Resolve: room(func(r *response.RoomResponse) any { return r.URL })},
+			"start_room": &graphql.Field{Type: graphql.String, Resolve: room(func(r *response.RoomResponse) any { return r.StartRoom })},
+			"end_room":   &graphql.Field{Type: graphql.String, Resolve: room(func(r *response.RoomResponse) any { return r.EndRoom })},
+		},
+	})
+
+	lectureType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Lecture",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: lecture(func(l *domain.Lecture) any { return l.ID })},
+			"name": &graphql.Field{Type: graphql.String, Resolve: lecture(func(l *domain.Lecture) any { return l.Name })},
+			"user": &graphql.Field{
+				Type: userType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					l := p.Source.(*domain.Lecture)
+					return later(loadersFrom(p.Context).users.load(p.Context, l.UserID), found[*response.UserResponse]), nil
+				},
+			},
+			"rooms": &graphql.Field{
+				Type:        graphql.NewList(roomType),
+				Description: "Room milik dosen, urut berdasarkan start_room.",
+				Args: graphql.FieldConfigArgument{
+					"upcoming": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					l := p.Source.(*domain.Lecture)
+					upcoming, _ := p.Args["upcoming"].(bool)
+					return later(loadersFrom(p.Context).roomsByLecture.load(p.Context, l.ID), func(rooms []*response.RoomResponse, _ bool) any {
+						if upcoming {
+							return upcomingRooms(rooms, -1)
+						}
+						return orEmpty(rooms)
+					}), nil
+				},
+			},
+		},
+	})
+
+	roomType.AddFieldConfig("lecture", &graphql.Field{
+		Type: lectureType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			r := p.Source.(*response.RoomResponse)
+			return later(loadersFrom(p.Context).lectures.load(p.Context, r.LectureID), found[*domain.Lecture]), nil
+		},
+	})
+
+	classType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Class",
+		Fields: graphql.Fields{
+			"id":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: class(func(c *domain.Class) any { return c.ID })},
+			"name":       &graphql.Field{Type: graphql.String, Resolve: class(func(c *domain.Class) any { return c.Name })},
+			"kode_kelas": &graphql.Field{Type: graphql.String, Resolve: class(func(c *domain.Class) any { return c.KodeKelas })},
+			"users": &graphql.Field{
+				Type: graphql.NewList(userType),
+				Args: graphql.FieldConfigArgument{
+					"role": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					role, _ := p.Args["role"].(string)
+					return classUsers(p, role), nil
+				},
+			},
+			"students": &graphql.Field{
+				Type: graphql.NewList(userType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return classUsers(p, "mahasiswa"), nil
+				},
+			},
+			"lecturers": &graphql.Field{
+				Type:        graphql.NewList(lectureType),
+				Description: "Dosen yang mengajar mahasiswa kelas ini.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					c := p.Source.(*domain.Class)
+					return later(loadersFrom(p.Context).lecturesByClass.load(p.Context, c.ID), all[*domain.Lecture]), nil
+				},
+			},
+			"upcoming_rooms": &graphql.Field{
+				Type:        graphql.NewList(roomType),
+				Description: "Room dosen kelas ini yang belum selesai, urut berdasarkan start_room.",
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					c := p.Source.(*domain.Class)
+					limit, ok := p.Args["limit"].(int)
+					if !ok || limit < 0 {
+						limit = limits.DefaultListSize
+					}
+					return later(loadersFrom(p.Context).roomsByClass.load(p.Context, c.ID), func(rooms []*response.RoomResponse, _ bool) any {
+						return upcomingRooms(rooms, limit)
+					}), nil
+				},
+			},
+		},
+	})
+
+	userType.AddFieldConfig("class", &graphql.Field{
+		Type: classType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			u := p.Source.(*response.UserResponse)
+			return later(loadersFrom(p.Context).classes.load(p.Context, u.ClassID), found[*domain.Class]), nil
+		},
+	})
+
+	matkulType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Matkul",
+		Fields: graphql.Fields{
+			"id":          &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: matkul(func(m *response.MatkulResponse) any { return m.ID })},
+			"name":        &graphql.Field{Type: graphql.String, Resolve: matkul(func(m *response.MatkulResponse) any { return m.Name })},
+			"kode_matkul": &graphql.Field{Type: graphql.String, Resolve: matkul(func(m *response.MatkulResponse) any { return m.KodeMatkul })},
+		},
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+	}
+	nameArgs := graphql.FieldConfigArgument{
+		"name": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"class": &graphql.Field{
+				Type: classType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return later(loadersFrom(p.Context).classes.load(p.Context, p.Args["id"].(int)), found[*domain.Class]), nil
+				},
+			},
+			"classes": &graphql.Field{
+				Type: graphql.NewList(classType),
+				Args: nameArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					classes, errMsg := s.Class.FindAllClass(p.Context, &requests.ListRequest{Name: p.Args["name"].(string)})
+					return classes, resolveError(errMsg)
+				},
+			},
+			"user": &graphql.Field{
+				Type: userType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return later(loadersFrom(p.Context).users.load(p.Context, p.Args["id"].(int)), found[*response.UserResponse]), nil
+				},
+			},
+			"lecture": &graphql.Field{
+				Type: lectureType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return later(loadersFrom(p.Context).lectures.load(p.Context, p.Args["id"].(int)), found[*domain.Lecture]), nil
+				},
+			},
+			"lectures": &graphql.Field{
+				Type: graphql.NewList(lectureType),
+				Args: nameArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					matches, errMsg := s.Lecture.FindAllLecture(p.Context, &requests.LectureListRequest{ListRequest: requests.ListRequest{Name: p.Args["name"].(string)}})
+					if errMsg != nil {
+						return nil, resolveError(errMsg)
+					}
+					IDs := make([]int, 0, len(matches))
+					for _, l := range matches {
+						IDs = append(IDs, l.ID)
+					}
+					lectures, errMsg := s.Lecture.FindLectureByIDs(p.Context, IDs)
+					return lectures, resolveError(errMsg)
+				},
+			},
+			"room": &graphql.Field{
+				Type: roomType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r, _, errMsg := s.Room.FindRoomByID(p.Context, p.Args["id"].(int))
+					if errMsg != nil {
+						return nil, resolveError(errMsg)
+					}
+					return r, nil
+				},
+			},
+			"rooms": &graphql.Field{
+				Type: graphql.NewList(roomType),
+				Args: nameArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					rooms, errMsg := s.Room.FindAllRoom(p.Context, &requests.ListRequest{Name: p.Args["name"].(string)})
+					return rooms, resolveError(errMsg)
+				},
+			},
+			"matkul": &graphql.Field{
+				Type: graphql.NewList(matkulType),
+				Args: nameArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					matkuls, errMsg := s.Matkul.FindMatkulByName(p.Context, p.Args["name"].(string))
+					return matkuls, resolveError(errMsg)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func classUsers(p graphql.ResolveParams, role string) func() (interface{}, error) {
+	c := p.Source.(*domain.Class)
+	return later(loadersFrom(p.Context).usersByClass.load(p.Context, c.ID), func(users []*response.UserResponse, _ bool) any {
+		filtered := []*response.UserResponse{}
+		for _, u := range users {
+			if role == "" || u.Role == role {
+				filtered = append(filtered, u)
+			}
+		}
+		return filtered
+	})
+}
+
+// upcomingRooms keeps the rooms that have not ended yet, at most limit of
+// them when limit is not negative. rooms are already ordered by start_room.
+func upcomingRooms(rooms []*response.RoomResponse, limit int) []*response.RoomResponse {
+	current := now().Format(timeLayout)
+	upcoming := []*response.RoomResponse{}
+	for _, r := range rooms {
+		if limit >= 0 && len(upcoming) == limit {
+			break
+		}
+		if r.EndRoom >= current {
+			upcoming = append(upcoming, r)
+		}
+	}
+	return upcoming
+}
+
+// found resolves a loaded record to null when the loader did not find it.
+func found[V any](v V, found bool) any {
+	if !found {
+		return nil
+	}
+	return v
+}
+
+// all resolves a loaded list to an empty list when nothing was found.
+func all[V any](v []V, _ bool) any {
+	return orEmpty(v)
+}
+
+func orEmpty[V any](v []V) []V {
+	if v == nil {
+		return []V{}
+	}
+	return v
+}
+
+func class(field func(*domain.Class) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) { return field(p.Source.(*domain.Class)), nil }
+}
+
+func user(field func(*response.UserResponse) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(p.Source.(*response.UserResponse)), nil
+	}
+}
+
+func lecture(field func(*domain.Lecture) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) { return field(p.Source.(*domain.Lecture)), nil }
+}
+
+func room(field func(*response.RoomResponse) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(p.Source.(*response.RoomResponse)), nil
+	}
+}
+
+func matkul(field func(*response.MatkulResponse) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return field(p.Source.(*response.MatkulResponse)), nil
+	}
+}
@@ -0,0 +1,57 @@
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type authServer struct {
+	pb.UnimplementedAuthServiceServer
+	AuthService services.AuthService
+}
+
+func (a *authServer) Register(ctx context.Context, in *pb.RegisterRequest) (*emptypb.Empty, error) {
+	r := &requests.AuthRegisterRequest{Name: in.Name, Email: in.Email, Password: in.Password, Role: in.Role, ClassID: int(in.ClassId)}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := a.AuthService.AuthRegisterUser(ctx, r); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (a *authServer) Login(ctx context.Context, in *pb.LoginRequest) (*pb.LoginResponse, error) {
+	r := &requests.AuthLoginRequest{Email: in.Email, Password: in.Password}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	userInfo, errMsg := a.AuthService.AuthLoginUser(ctx, r.Email, r.Password)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	token, err := api.JWTGenereateToken(&api.UserInfo{
+		ID:      userInfo.ID,
+		Name:    userInfo.Name,
+		Email:   userInfo.Email,
+		Role:    userInfo.Role,
+		ClassID: userInfo.ClassID,
+	})
+	if err != nil {
+		return nil, toStatus(helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err.Error()))
+	}
+
+	return &pb.LoginResponse{Token: token}, nil
+}
@@ -0,0 +1,117 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type classServer struct {
+	pb.UnimplementedClassServiceServer
+	ClassService services.ClassService
+}
+
+func (k *classServer) ListClasses(ctx context.Context, in *pb.ListRequest) (*pb.ListClassesResponse, error) {
+	classes, errMsg := k.ClassService.FindAllClass(ctx, &requests.ListRequest{Name: in.Name})
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	result := &pb.ListClassesResponse{}
+	for _, class := range classes {
+		result.Classes = append(result.Classes, toPBClass(class))
+	}
+	return result, nil
+}
+
+func (k *classServer) CreateClass(ctx context.Context, in *pb.CreateClassRequest) (*pb.Class, error) {
+	r := &requests.InsertClassRequest{Name: in.Name}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := k.ClassService.AddClass(ctx, r); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return k.findClass(ctx, r.ID)
+}
+
+func (k *classServer) GetClass(ctx context.Context, in *pb.IDRequest) (*pb.Class, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.findClass(ctx, ID)
+}
+
+func (k *classServer) UpdateClass(ctx context.Context, in *pb.UpdateClassRequest) (*pb.Class, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &requests.PatchClassRequest{Name: in.Name}
+	if err := validate(patch); err != nil {
+		return nil, err
+	}
+
+	current, _, errMsg := k.ClassService.FindClassByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	if _, errMsg := k.ClassService.UpdateClass(ctx, patch.Apply(ID, current)); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return k.findClass(ctx, ID)
+}
+
+func (k *classServer) DeleteClass(ctx context.Context, in *pb.IDRequest) (*emptypb.Empty, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := k.ClassService.DeleteClassByID(ctx, ID); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (k *classServer) ListClassUsers(ctx context.Context, in *pb.IDRequest) (*pb.ListUsersResponse, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	users, errMsg := k.ClassService.FindUsersByClassID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	result := &pb.ListUsersResponse{}
+	for _, user := range users {
+		result.Users = append(result.Users, &pb.User{Id: int64(user.ID), Name: user.Name, Email: user.Email, Role: user.Role, ClassId: int64(user.ClassID)})
+	}
+	return result, nil
+}
+
+func (k *classServer) findClass(ctx context.Context, ID int) (*pb.Class, error) {
+	class, _, errMsg := k.ClassService.FindClassByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+	return toPBClass(class), nil
+}
+
+func toPBClass(class *domain.Class) *pb.Class {
+	return &pb.Class{Id: int64(class.ID), Name: class.Name, KodeKelas: class.KodeKelas}
+}
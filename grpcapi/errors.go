@@ -0,0 +1,69 @@
+package grpcapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain under which the REST error_key is sent.
+const errorDomain = "sinaustudio"
+
+// toStatus converts a service error into a gRPC status. The error_key of the
+// REST API travels as the reason of an ErrorInfo detail.
+func toStatus(errMsg *response.ErrorMsg) error {
+	code := codes.Internal
+	switch {
+	case errMsg.ErrorKey == exception.ERR_NOT_FOUND:
+		code = codes.NotFound
+	case errMsg.ErrorKey == exception.ERR_ALREADY_USE:
+		code = codes.AlreadyExists
+	case errMsg.ErrorKey == exception.ERR_CONFLICT, errMsg.ErrorKey == exception.ERR_PRECONDITION_REQUIRED:
+		code = codes.FailedPrecondition
+	case errMsg.ErrorKey == exception.ERR_PRECONDITION_FAILED:
+		code = codes.Aborted
+	case errMsg.StatusCode == http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case errMsg.StatusCode == http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case errMsg.StatusCode == http.StatusForbidden:
+		code = codes.PermissionDenied
+	}
+
+	st := status.New(code, fmt.Sprint(errMsg.Msg))
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: errMsg.ErrorKey, Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// validate runs the gin binding rules of a request struct, so gRPC calls are
+// held to the same constraints as JSON bodies.
+func validate(r any) error {
+	if err := binding.Validator.ValidateStruct(r); err != nil {
+		return toStatus(helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, helpers.ErrorValidateHandler(err)))
+	}
+	return nil
+}
+
+func validID(ID int64) (int, error) {
+	if ID < 1 {
+		return 0, toStatus(helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Invalid ID, fill with number ID"))
+	}
+	return int(ID), nil
+}
+
+func intPtr(v *int64) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
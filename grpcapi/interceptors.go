@@ -0,0 +1,125 @@
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethods can be called without an authorization metadata entry.
+var publicMethods = map[string]bool{
+	pb.AuthService_Register_FullMethodName: true,
+	pb.AuthService_Login_FullMethodName:    true,
+}
+
+// authenticate checks the "authorization: Bearer <token>" metadata, the gRPC
+// counterpart of api.MiddlewareAuthorization, and stores the user in ctx.
+func authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if publicMethods[fullMethod] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, toStatus(helpers.ToErrorMsg(http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER, "Key: Metadata with key authorization: Bearer Token, Tag: Required"))
+	}
+
+	token := strings.TrimPrefix(values[0], "Bearer")
+	if !strings.HasPrefix(values[0], "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, toStatus(helpers.ToErrorMsg(http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER, "Key: Token not found, Tag: Required"))
+	}
+
+	info, err := api.ParseJWTToken(token)
+	if err != nil {
+		return nil, toStatus(helpers.ToErrorMsg(http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER, "Token tidak valid!"))
+	}
+
+	return api.ContextWithUserInfo(ctx, info), nil
+}
+
+func UnaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func StreamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// requestIDMetadata is the gRPC counterpart of the X-Request-ID header.
+const requestIDMetadata = "x-request-id"
+
+// withRequestID is the gRPC counterpart of api.RequestLogger: it takes the
+// x-request-id metadata or draws a new ID, sends it back as a header and
+// stores it, with a logger carrying it, in ctx.
+func withRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var requestID string
+	if values := md.Get(requestIDMetadata); len(values) > 0 {
+		requestID = values[0]
+	}
+	requestID = api.RequestIDOrNew(requestID)
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, requestID))
+	return api.ContextWithRequestID(ctx, logging.Default(), requestID)
+}
+
+func logCall(ctx context.Context, fullMethod string, start time.Time, err error) {
+	level := logging.LevelInfo
+	code := status.Code(err)
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = logging.LevelError
+	default:
+		level = logging.LevelWarn
+	}
+	args := []any{"method", fullMethod, "code", code, "duration_ms", time.Since(start).Milliseconds()}
+	if err != nil {
+		args = append(args, "error", status.Convert(err).Message())
+	}
+	logging.FromContext(ctx).Named("grpc").Log(level, "call", args...)
+}
+
+func UnaryRequestIDInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	ctx = withRequestID(ctx)
+	res, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, start, err)
+	return res, err
+}
+
+func StreamRequestIDInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx := withRequestID(ss.Context())
+	err := handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	logCall(ctx, info.FullMethod, start, err)
+	return err
+}
@@ -0,0 +1,99 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type lectureServer struct {
+	pb.UnimplementedLectureServiceServer
+	LectureService services.LectureService
+}
+
+func (l *lectureServer) ListLectures(ctx context.Context, in *pb.ListRequest) (*pb.ListLecturesResponse, error) {
+	lectures, errMsg := l.LectureService.FindAllLecture(ctx, &requests.LectureListRequest{ListRequest: requests.ListRequest{Name: in.Name}})
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	result := &pb.ListLecturesResponse{}
+	for _, lecture := range lectures {
+		result.Lectures = append(result.Lectures, toPBLecture(lecture))
+	}
+	return result, nil
+}
+
+func (l *lectureServer) CreateLecture(ctx context.Context, in *pb.CreateLectureRequest) (*pb.Lecture, error) {
+	r := &requests.InsertLectureRequest{Name: in.Name, UserID: int(in.UserId)}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := l.LectureService.InsertLecture(ctx, r); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return l.findLecture(ctx, r.ID)
+}
+
+func (l *lectureServer) GetLecture(ctx context.Context, in *pb.IDRequest) (*pb.Lecture, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.findLecture(ctx, ID)
+}
+
+func (l *lectureServer) UpdateLecture(ctx context.Context, in *pb.UpdateLectureRequest) (*pb.Lecture, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &requests.PatchLectureRequest{Name: in.Name}
+	if err := validate(patch); err != nil {
+		return nil, err
+	}
+
+	current, _, errMsg := l.LectureService.FindLectureByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	if _, errMsg := l.LectureService.UpdateLecture(ctx, patch.Apply(ID, current)); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return l.findLecture(ctx, ID)
+}
+
+func (l *lectureServer) DeleteLecture(ctx context.Context, in *pb.IDRequest) (*emptypb.Empty, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := l.LectureService.DeleteLectureByID(ctx, ID); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (l *lectureServer) findLecture(ctx context.Context, ID int) (*pb.Lecture, error) {
+	lecture, _, errMsg := l.LectureService.FindLectureByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+	return toPBLecture(lecture), nil
+}
+
+func toPBLecture(lecture *response.LectureResponse) *pb.Lecture {
+	return &pb.Lecture{Id: int64(lecture.ID), Name: lecture.Name}
+}
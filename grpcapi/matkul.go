@@ -0,0 +1,108 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type matkulServer struct {
+	pb.UnimplementedMatkulServiceServer
+	MataKuliahService services.MataKuliahService
+}
+
+func (m *matkulServer) ListMatkul(ctx context.Context, in *pb.ListRequest) (*pb.ListMatkulResponse, error) {
+	matkuls, errMsg := m.MataKuliahService.FindMatkulByName(ctx, in.Name)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	result := &pb.ListMatkulResponse{}
+	for _, matkul := range matkuls {
+		result.Matkul = append(result.Matkul, toPBMatkul(matkul))
+	}
+	return result, nil
+}
+
+func (m *matkulServer) CreateMatkul(ctx context.Context, in *pb.CreateMatkulRequest) (*pb.Matkul, error) {
+	r := &requests.InsertMatkulRequest{KodeMatkul: in.KodeMatkul, Name: in.Name}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := m.MataKuliahService.InsertMatkul(ctx, r); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return m.findMatkul(ctx, r.ID)
+}
+
+func (m *matkulServer) GetMatkul(ctx context.Context, in *pb.IDRequest) (*pb.Matkul, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.findMatkul(ctx, ID)
+}
+
+func (m *matkulServer) UpdateMatkul(ctx context.Context, in *pb.UpdateMatkulRequest) (*pb.Matkul, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &requests.PatchMatkulRequest{Name: in.Name, KodeMatkul: in.KodeMatkul}
+	if err := validate(patch); err != nil {
+		return nil, err
+	}
+
+	expected, err := ifMatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, _, errMsg := m.MataKuliahService.FindMatkulByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+	if err := checkVersion(expected, current.Version); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := m.MataKuliahService.UpdateMatkul(ctx, patch.Apply(ID, current)); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return m.findMatkul(ctx, ID)
+}
+
+func (m *matkulServer) DeleteMatkul(ctx context.Context, in *pb.IDRequest) (*emptypb.Empty, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := m.MataKuliahService.DeleteMatkulByID(ctx, ID, 0); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (m *matkulServer) findMatkul(ctx context.Context, ID int) (*pb.Matkul, error) {
+	matkul, _, errMsg := m.MataKuliahService.FindMatkulByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+	setETag(ctx, matkul.Version)
+	return toPBMatkul(matkul), nil
+}
+
+func toPBMatkul(matkul *response.MatkulResponse) *pb.Matkul {
+	return &pb.Matkul{Id: int64(matkul.ID), Name: matkul.Name, KodeMatkul: matkul.KodeMatkul}
+}
@@ -0,0 +1,144 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type roomServer struct {
+	pb.UnimplementedRoomServiceServer
+	RoomService services.RoomService
+}
+
+var roomEventTypes = map[services.RoomEventType]pb.RoomEvent_Type{
+	services.RoomCreated: pb.RoomEvent_TYPE_CREATED,
+	services.RoomUpdated: pb.RoomEvent_TYPE_UPDATED,
+	services.RoomDeleted: pb.RoomEvent_TYPE_DELETED,
+}
+
+func (l *roomServer) ListRooms(ctx context.Context, in *pb.ListRequest) (*pb.ListRoomsResponse, error) {
+	rooms, errMsg := l.RoomService.FindAllRoom(ctx, &requests.ListRequest{Name: in.Name})
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	result := &pb.ListRoomsResponse{}
+	for _, room := range rooms {
+		result.Rooms = append(result.Rooms, toPBRoom(room))
+	}
+	return result, nil
+}
+
+func (l *roomServer) CreateRoom(ctx context.Context, in *pb.CreateRoomRequest) (*pb.Room, error) {
+	r := &requests.InsertRoomRequest{Name: in.Name, URL: in.Url, LectureID: int(in.LectureId), StartRoom: in.StartRoom, EndRoom: in.EndRoom}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := l.RoomService.InsertRoom(ctx, r); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return l.findRoom(ctx, r.ID)
+}
+
+func (l *roomServer) GetRoom(ctx context.Context, in *pb.IDRequest) (*pb.Room, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.findRoom(ctx, ID)
+}
+
+func (l *roomServer) UpdateRoom(ctx context.Context, in *pb.UpdateRoomRequest) (*pb.Room, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &requests.PatchRoomRequest{Name: in.Name, URL: in.Url, LectureID: intPtr(in.LectureId), StartRoom: in.StartRoom, EndRoom: in.EndRoom}
+	if err := validate(patch); err != nil {
+		return nil, err
+	}
+
+	expected, err := ifMatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, _, errMsg := l.RoomService.FindRoomByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+	if err := checkVersion(expected, current.Version); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := l.RoomService.UpdateRoom(ctx, patch.Apply(ID, current)); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return l.findRoom(ctx, ID)
+}
+
+func (l *roomServer) DeleteRoom(ctx context.Context, in *pb.IDRequest) (*emptypb.Empty, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := l.RoomService.DeleteRoomByID(ctx, ID, 0); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// WatchRooms forwards room events until the client goes away. Headers are sent
+// once subscribed, so a client that waited for them misses no later write.
+// Deleted rooms only carry their id, so the lecture filter cannot apply to
+// them and they are always sent.
+func (l *roomServer) WatchRooms(in *pb.WatchRoomsRequest, stream pb.RoomService_WatchRoomsServer) error {
+	events := l.RoomService.WatchRoom(stream.Context())
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for event := range events {
+		if in.LectureId != 0 && event.Type != services.RoomDeleted && int64(event.Room.LectureID) != in.LectureId {
+			continue
+		}
+
+		if err := stream.Send(&pb.RoomEvent{Type: roomEventTypes[event.Type], Room: toPBRoom(event.Room)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *roomServer) findRoom(ctx context.Context, ID int) (*pb.Room, error) {
+	room, _, errMsg := l.RoomService.FindRoomByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+	setETag(ctx, room.Version)
+	return toPBRoom(room), nil
+}
+
+func toPBRoom(room *response.RoomResponse) *pb.Room {
+	return &pb.Room{
+		Id:        int64(room.ID),
+		Name:      room.Name,
+		Url:       room.URL,
+		LectureId: int64(room.LectureID),
+		StartRoom: room.StartRoom,
+		EndRoom:   room.EndRoom,
+	}
+}
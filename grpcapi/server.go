@@ -0,0 +1,32 @@
+// Package grpcapi serves the operations of the REST API over gRPC, on top of
+// the same services.*Service implementations.
+package grpcapi
+
+import (
+	"database/sql"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"google.golang.org/grpc"
+)
+
+// NewServer registers every gRPC service with the request ID and JWT
+// interceptors installed.
+// Extra options are appended after the interceptors.
+func NewServer(db *sql.DB, microServices api.MicroServiceServer, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryRequestIDInterceptor, UnaryAuthInterceptor),
+		grpc.ChainStreamInterceptor(StreamRequestIDInterceptor, StreamAuthInterceptor),
+	}, opts...)
+	server := grpc.NewServer(opts...)
+
+	pb.RegisterAuthServiceServer(server, &authServer{AuthService: services.NewAuthServiceImplementation(db, microServices)})
+	pb.RegisterUserServiceServer(server, &userServer{UsersService: services.NewUserServiceImplementation(db, microServices)})
+	pb.RegisterClassServiceServer(server, &classServer{ClassService: services.NewClassServiceImplementation(db, microServices)})
+	pb.RegisterLectureServiceServer(server, &lectureServer{LectureService: services.NewLectureServiceImplementation(db, microServices)})
+	pb.RegisterRoomServiceServer(server, &roomServer{RoomService: services.NewRoomServiceImplementation(db, microServices)})
+	pb.RegisterMatkulServiceServer(server, &matkulServer{MataKuliahService: services.NewMataKuliahServiceImplementation(db, microServices)})
+
+	return server
+}
@@ -0,0 +1,241 @@
+package grpcapi_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/grpcapi"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"github.com/dimassfeb-09/sinaustudio.git/repository/memory"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type testClient struct {
+	conn    *grpc.ClientConn
+	classID int64
+}
+
+// newTestClient serves grpcapi.NewServer on an in-memory listener backed by a
+// memory.Store holding one class.
+func newTestClient(t *testing.T) *testClient {
+	t.Helper()
+
+	store := memory.NewStore()
+	m := api.NewMemoryMicroService(store)
+
+	class := &requests.InsertClassRequest{Name: "TI-1A"}
+	if _, errMsg := services.NewClassServiceImplementation(store.DB(), m).AddClass(context.Background(), class); errMsg != nil {
+		t.Fatalf("AddClass: %v", errMsg.Msg)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpcapi.NewServer(store.DB(), m)
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+		store.DB().Close()
+	})
+	return &testClient{conn: conn, classID: int64(class.ID)}
+}
+
+// login registers a dosen and returns a context carrying its bearer token.
+func (c *testClient) login(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx := context.Background()
+	auth := pb.NewAuthServiceClient(c.conn)
+	if _, err := auth.Register(ctx, &pb.RegisterRequest{Name: "Dimas Saputra", Email: "dimas@sinaustudio.test", Password: "rahasia123", Role: "dosen", ClassId: c.classID}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	res, err := auth.Login(ctx, &pb.LoginRequest{Email: "dimas@sinaustudio.test", Password: "rahasia123"})
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+res.Token)
+}
+
+func assertStatus(t *testing.T, err error, code codes.Code, errorKey string) {
+	t.Helper()
+
+	st, _ := status.FromError(err)
+	if st.Code() != code {
+		t.Fatalf("expected code %s, got %v", code, err)
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Reason == errorKey {
+			return
+		}
+	}
+	t.Fatalf("expected error_key %s in details of %v", errorKey, err)
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	c := newTestClient(t)
+	classes := pb.NewClassServiceClient(c.conn)
+
+	_, err := classes.ListClasses(context.Background(), &pb.ListRequest{})
+	assertStatus(t, err, codes.Unauthenticated, exception.ERR_UNAUTHORIZED_BEARER)
+
+	bad := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer bukan.token.valid")
+	_, err = classes.ListClasses(bad, &pb.ListRequest{})
+	assertStatus(t, err, codes.Unauthenticated, exception.ERR_UNAUTHORIZED_BEARER)
+
+	res, err := classes.ListClasses(c.login(t), &pb.ListRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Classes) != 1 || res.Classes[0].Name != "TI-1A" {
+		t.Fatalf("unexpected classes: %v", res.Classes)
+	}
+}
+
+func TestClassService(t *testing.T) {
+	c := newTestClient(t)
+	ctx := c.login(t)
+	classes := pb.NewClassServiceClient(c.conn)
+
+	created, err := classes.CreateClass(ctx, &pb.CreateClassRequest{Name: "TI-2B"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The dosen does not teach the new class, so its kode_kelas stays hidden.
+	if created.Id == 0 || created.KodeKelas != "" {
+		t.Fatalf("unexpected created class: %v", created)
+	}
+
+	_, err = classes.CreateClass(ctx, &pb.CreateClassRequest{Name: "TI"})
+	assertStatus(t, err, codes.InvalidArgument, exception.ERR_BAD_REQUEST_FIELD)
+
+	updated, err := classes.UpdateClass(ctx, &pb.UpdateClassRequest{Id: created.Id, Name: proto.String("TI-2C")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "TI-2C" || updated.KodeKelas != "" {
+		t.Fatalf("unexpected update: %v", updated)
+	}
+
+	users, err := classes.ListClassUsers(ctx, &pb.IDRequest{Id: c.classID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users.Users) != 1 || users.Users[0].Name != "Dimas Saputra" {
+		t.Fatalf("unexpected users: %v", users.Users)
+	}
+
+	if _, err := classes.DeleteClass(ctx, &pb.IDRequest{Id: created.Id}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = classes.GetClass(ctx, &pb.IDRequest{Id: created.Id})
+	assertStatus(t, err, codes.NotFound, exception.ERR_NOT_FOUND)
+}
+
+func TestUpdateMatkulVersion(t *testing.T) {
+	c := newTestClient(t)
+	ctx := c.login(t)
+	matkul := pb.NewMatkulServiceClient(c.conn)
+
+	var header metadata.MD
+	created, err := matkul.CreateMatkul(ctx, &pb.CreateMatkulRequest{Name: "Algoritma Pemrograman", KodeMatkul: "IF101"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("etag"); len(got) != 1 || got[0] != `"1"` {
+		t.Fatalf("expected etag \"1\", got %v", got)
+	}
+
+	update := &pb.UpdateMatkulRequest{Id: created.Id, Name: proto.String("Algoritma dan Pemrograman")}
+	_, err = matkul.UpdateMatkul(ctx, update)
+	assertStatus(t, err, codes.FailedPrecondition, exception.ERR_PRECONDITION_REQUIRED)
+
+	if _, err := matkul.UpdateMatkul(metadata.AppendToOutgoingContext(ctx, "if-match", `"1"`), update, grpc.Header(&header)); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("etag"); len(got) != 1 || got[0] != `"2"` {
+		t.Fatalf("expected etag \"2\", got %v", got)
+	}
+
+	// An update based on the version before the one above must not overwrite it.
+	_, err = matkul.UpdateMatkul(metadata.AppendToOutgoingContext(ctx, "if-match", `"1"`), &pb.UpdateMatkulRequest{Id: created.Id, Name: proto.String("Pemrograman Dasar")})
+	assertStatus(t, err, codes.Aborted, exception.ERR_PRECONDITION_FAILED)
+
+	got, err := matkul.GetMatkul(ctx, &pb.IDRequest{Id: created.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Algoritma dan Pemrograman" {
+		t.Fatalf("stale update overwrote the matkul: %v", got)
+	}
+}
+
+func TestWatchRooms(t *testing.T) {
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(c.login(t), 10*time.Second)
+	defer cancel()
+	rooms := pb.NewRoomServiceClient(c.conn)
+
+	lectures, err := pb.NewLectureServiceClient(c.conn).ListLectures(ctx, &pb.ListRequest{Name: "Dimas"})
+	if err != nil || len(lectures.Lectures) != 1 {
+		t.Fatalf("ListLectures: %v %v", lectures, err)
+	}
+	lectureID := lectures.Lectures[0].Id
+
+	stream, err := rooms.WatchRooms(ctx, &pb.WatchRoomsRequest{LectureId: lectureID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	room, err := rooms.CreateRoom(ctx, &pb.CreateRoomRequest{Name: "Algoritma", Url: "https://meet.example.com/algo", LectureId: lectureID, StartRoom: "2023-02-19 20:25:50", EndRoom: "2023-02-19 22:25:50"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rooms.UpdateRoom(metadata.AppendToOutgoingContext(ctx, "if-match", `"1"`), &pb.UpdateRoomRequest{Id: room.Id, Url: proto.String("https://meet.example.com/algo-2")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rooms.DeleteRoom(ctx, &pb.IDRequest{Id: room.Id}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		Type pb.RoomEvent_Type
+		URL  string
+	}{
+		{pb.RoomEvent_TYPE_CREATED, "https://meet.example.com/algo"},
+		{pb.RoomEvent_TYPE_UPDATED, "https://meet.example.com/algo-2"},
+		{pb.RoomEvent_TYPE_DELETED, ""},
+	}
+	for _, w := range want {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Type != w.Type || event.Room.Id != room.Id || event.Room.Url != w.URL {
+			t.Fatalf("expected %s with url %q, got %v", w.Type, w.URL, event)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/proto/pb"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+	UsersService services.UsersService
+}
+
+func (u *userServer) CreateUser(ctx context.Context, in *pb.CreateUserRequest) (*pb.User, error) {
+	r := &requests.UserCreateRequest{Name: in.Name, Email: in.Email, Password: in.Password, Role: in.Role, ClassID: int(in.ClassId)}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	user := &requests.UserInsertRequest{Name: r.Name, Email: r.Email, Password: r.Password, Role: r.Role, ClassID: r.ClassID}
+	if _, errMsg := u.UsersService.InsertDataUser(ctx, user); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return u.findUser(ctx, user.ID)
+}
+
+func (u *userServer) GetUser(ctx context.Context, in *pb.IDRequest) (*pb.User, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.findUser(ctx, ID)
+}
+
+func (u *userServer) UpdateUser(ctx context.Context, in *pb.UpdateUserRequest) (*pb.User, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &requests.UserPatchRequest{Name: in.Name, Email: in.Email, Role: in.Role, ClassID: intPtr(in.ClassId)}
+	if err := validate(patch); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := u.UsersService.PatchDataUser(ctx, ID, patch); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return u.findUser(ctx, ID)
+}
+
+func (u *userServer) DeleteUser(ctx context.Context, in *pb.DeleteUserRequest) (*emptypb.Empty, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &requests.UserDeleteRequest{ConfirmPassword: in.ConfirmPassword}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := u.UsersService.DeleteDataUser(ctx, r.ConfirmPassword, ID); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (u *userServer) ChangePassword(ctx context.Context, in *pb.ChangePasswordRequest) (*emptypb.Empty, error) {
+	ID, err := validID(in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &requests.UserChangePassword{RecentPassword: in.RecentPassword, NewPassword: in.NewPassword}
+	if err := validate(r); err != nil {
+		return nil, err
+	}
+
+	if _, errMsg := u.UsersService.ChangePasswordUser(ctx, ID, r.RecentPassword, r.NewPassword); errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (u *userServer) findUser(ctx context.Context, ID int) (*pb.User, error) {
+	user, errMsg := u.UsersService.FindUserByID(ctx, ID)
+	if errMsg != nil {
+		return nil, toStatus(errMsg)
+	}
+
+	return &pb.User{Id: int64(user.ID), Name: user.Name, Email: user.Email, Role: user.Role, ClassId: int64(user.ClassID)}, nil
+}
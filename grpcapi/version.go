@@ -0,0 +1,52 @@
+package grpcapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Like the REST ETag, a single room or matkul answers its version in the
+// "etag" header, and an update must send it back in "if-match" metadata; a
+// stale one fails with Aborted instead of overwriting the other write.
+
+func setETag(ctx context.Context, version int) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs("etag", strconv.Quote(strconv.Itoa(version))))
+}
+
+// ifMatch returns the version the call's "if-match" metadata expects, 0 for "*".
+func ifMatch(ctx context.Context) (int, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("if-match")
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+		return 0, toStatus(helpers.ToErrorMsg(http.StatusPreconditionRequired, exception.ERR_PRECONDITION_REQUIRED, "Metadata if-match wajib diisi dengan etag data terbaru."))
+	}
+
+	value := strings.TrimSpace(values[0])
+	if value == "*" {
+		return 0, nil
+	}
+	if tag, err := strconv.Unquote(value); err == nil {
+		value = tag
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, toStatus(helpers.ToVersionConflict())
+	}
+	return version, nil
+}
+
+// checkVersion fails with Aborted when version is not the expected one; 0
+// accepts any version.
+func checkVersion(expected, version int) error {
+	if expected == 0 || expected == version {
+		return nil
+	}
+	return toStatus(helpers.ToVersionConflict())
+}
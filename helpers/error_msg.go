@@ -2,8 +2,14 @@ package helpers
 
 import (
 	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/gin-gonic/gin"
 )
 
+// ErrorMsgKey is the gin context key ToErrorResponse keeps the answered error
+// under, for the access log.
+const ErrorMsgKey = "error_msg"
+
 func ToErrorMsg(StatusCode int, Code string, Msg any) *response.ErrorMsg {
 	return &response.ErrorMsg{
 		Success:    false,
@@ -12,3 +18,11 @@ func ToErrorMsg(StatusCode int, Code string, Msg any) *response.ErrorMsg {
 		Msg:        Msg,
 	}
 }
+
+// ToErrorResponse answers with errMsg and status, carrying the request ID of
+// the request context so clients can quote it.
+func ToErrorResponse(c *gin.Context, status int, errMsg *response.ErrorMsg) {
+	errMsg.RequestID = logging.RequestID(c.Request.Context())
+	c.Set(ErrorMsgKey, errMsg)
+	c.AbortWithStatusJSON(status, errMsg)
+}
@@ -1,20 +1,38 @@
 package helpers
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
 )
 
-func RollbackOrCommit(tx *sql.Tx) {
-	err := recover()
-	if err != nil {
-		err := tx.Rollback()
-		if err != nil {
-			return
+// RollbackOrCommit is deferred right after Begin with the address of the
+// service's named error result: it rolls back when the service returns an
+// error or panicked and commits otherwise. A recovered panic or a failed
+// commit is logged with ctx's logger and reported through errMsg.
+func RollbackOrCommit(ctx context.Context, tx *sql.Tx, errMsg **response.ErrorMsg) {
+	log := logging.FromContext(ctx).Named("services")
+	if recovered := recover(); recovered != nil {
+		log.Error("transaksi di-rollback karena panic", "panic", recovered)
+		if err := tx.Rollback(); err != nil {
+			log.Error("rollback transaksi gagal", "error", err)
 		}
-	} else {
-		err := tx.Commit()
-		if err != nil {
-			return
+		*errMsg = ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, fmt.Errorf("panic: %v", recovered))
+		return
+	}
+	if *errMsg != nil {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			log.Error("rollback transaksi gagal", "error", err)
 		}
+		return
+	}
+	if err := tx.Commit(); err != nil && err != sql.ErrTxDone {
+		log.Error("commit transaksi gagal", "error", err)
+		*errMsg = ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
 }
@@ -0,0 +1,13 @@
+package helpers
+
+import (
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+// ToVersionConflict is the error of a write that expected another version of the row.
+func ToVersionConflict() *response.ErrorMsg {
+	return ToErrorMsg(http.StatusPreconditionFailed, exception.ERR_PRECONDITION_FAILED, "Data sudah diubah oleh pengguna lain, muat ulang data terbaru.")
+}
@@ -0,0 +1,98 @@
+// Package imaging decodes uploaded pictures and turns them into square
+// thumbnails. Thumbnails are re-encoded from pixels only, so nothing of the
+// upload's metadata, EXIF included, survives; the EXIF orientation is applied
+// to the pixels first so photos from phones stay upright.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	stddraw "image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ContentTypes are the types Sniff accepts.
+var ContentTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+var (
+	ErrUnsupported = errors.New("format gambar harus JPEG, PNG, GIF atau WebP")
+	ErrTooLarge    = errors.New("ukuran gambar terlalu besar")
+)
+
+// Sniff tells the content type of data from its first bytes, ignoring any
+// type or file name the client claims, and checks it is one of ContentTypes.
+func Sniff(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	for _, accepted := range ContentTypes {
+		if contentType == accepted {
+			return contentType, nil
+		}
+	}
+	return "", ErrUnsupported
+}
+
+// Image is a decoded picture with its EXIF orientation, 1 when it has none.
+type Image struct {
+	image.Image
+	Orientation int
+}
+
+// Decode reads a picture of at most maxSide pixels on either side. The size
+// is checked from the header before the pixels are decoded, so a small file
+// claiming huge dimensions is turned down without allocating them.
+func Decode(data []byte, maxSide int) (*Image, error) {
+	if _, err := Sniff(data); err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gambar tidak dapat dibaca: %w", err)
+	}
+	if config.Width < 1 || config.Height < 1 {
+		return nil, fmt.Errorf("gambar tidak dapat dibaca: ukuran %dx%d", config.Width, config.Height)
+	}
+	if config.Width > maxSide || config.Height > maxSide {
+		return nil, fmt.Errorf("%w: %dx%d piksel, maksimal %dx%d", ErrTooLarge, config.Width, config.Height, maxSide, maxSide)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gambar tidak dapat dibaca: %w", err)
+	}
+	return &Image{Image: img, Orientation: Orientation(data)}, nil
+}
+
+// Thumbnail crops the centre square of img, scales it to size by size and
+// turns it upright. Cropping and scaling commute with the orientation, so
+// they run first on the larger picture and only the thumbnail is turned.
+// Transparent parts are laid over white, since thumbnails are JPEG.
+func (img *Image) Thumbnail(size int) image.Image {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	min := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	crop := image.Rectangle{Min: min, Max: min.Add(image.Pt(side, side))}
+
+	thumbnail := image.NewNRGBA(image.Rect(0, 0, size, size))
+	stddraw.Draw(thumbnail, thumbnail.Bounds(), image.NewUniform(color.White), image.Point{}, stddraw.Src)
+	draw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img.Image, crop, draw.Over, nil)
+	return orient(thumbnail, img.Orientation)
+}
+
+// EncodeJPEG writes img as a JPEG without any metadata.
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+}
@@ -0,0 +1,131 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// withOrientation inserts an EXIF APP1 segment holding orientation right
+// after the SOI marker of a JPEG.
+func withOrientation(t *testing.T, jpg []byte, orientation int) []byte {
+	t.Helper()
+
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	binary.Write(&tiff, binary.BigEndian, uint16(42))
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{tagOrientation, 3})
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{uint16(orientation), 0})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	var out bytes.Buffer
+	out.Write(jpg[:2])
+	out.Write([]byte{0xff, markerAPP1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(jpg[2:])
+	return out.Bytes()
+}
+
+// halves is a w by h picture, red on the left half and blue on the right.
+func halves(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func isRed(c color.Color) bool {
+	r, _, b, _ := c.RGBA()
+	return r > 0xc000 && b < 0x4000
+}
+
+func TestOrientation(t *testing.T) {
+	jpg := encodeJPEG(t, halves(16, 16))
+	for orientation := 1; orientation <= 8; orientation++ {
+		if got := Orientation(withOrientation(t, jpg, orientation)); got != orientation {
+			t.Errorf("Orientation = %d, want %d", got, orientation)
+		}
+	}
+	if got := Orientation(jpg); got != 1 {
+		t.Errorf("Orientation without EXIF = %d, want 1", got)
+	}
+	if got := Orientation(withOrientation(t, jpg, 9)); got != 1 {
+		t.Errorf("Orientation of an invalid value = %d, want 1", got)
+	}
+	if got := Orientation([]byte{0xff, markerSOI, 0xff}); got != 1 {
+		t.Errorf("Orientation of a truncated JPEG = %d, want 1", got)
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	// Orientation 6 is stored turned a quarter to the left: the red half on
+	// the left ends up on top once turned upright.
+	img, err := Decode(withOrientation(t, encodeJPEG(t, halves(64, 32)), 6), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumbnail := img.Thumbnail(16)
+	if size := thumbnail.Bounds().Size(); size != image.Pt(16, 16) {
+		t.Fatalf("size = %v, want 16x16", size)
+	}
+	if !isRed(thumbnail.At(8, 1)) || isRed(thumbnail.At(8, 14)) {
+		t.Errorf("thumbnail is not upright: top %v, bottom %v", thumbnail.At(8, 1), thumbnail.At(8, 14))
+	}
+
+	var out bytes.Buffer
+	if err := EncodeJPEG(&out, thumbnail); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(out.Bytes(), []byte("Exif")) {
+		t.Error("thumbnail still carries EXIF")
+	}
+
+	// Transparent pixels become white.
+	var transparent bytes.Buffer
+	png.Encode(&transparent, image.NewNRGBA(image.Rect(0, 0, 8, 8)))
+	img, err = Decode(transparent.Bytes(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, g, b, _ := img.Thumbnail(4).At(1, 1).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+		t.Errorf("transparent pixel = %x %x %x, want white", r, g, b)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	if _, err := Decode([]byte("bukan gambar"), 100); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Decode of text: %v, want ErrUnsupported", err)
+	}
+	if _, err := Decode(encodeJPEG(t, halves(120, 10)), 100); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Decode of 120x10: %v, want ErrTooLarge", err)
+	}
+	if _, err := Decode(encodeJPEG(t, halves(10, 10))[:40], 100); err == nil {
+		t.Error("Decode of a truncated JPEG succeeded")
+	}
+}
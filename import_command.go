@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+)
+
+// runImport implements "sinaustudio import [flags] FILE". It prints the import
+// report as JSON and exits with status 1 when any row was not imported.
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	resource := flags.String("resource", "", "resource yang diimport: users, classes atau matkul")
+	mode := flags.String("mode", requests.ImportAtomic, "atomic atau best_effort")
+	dryRun := flags.Bool("dry-run", false, "hanya validasi, tanpa menyimpan data")
+	format := flags.String("format", "", "csv atau xlsx, default dari ekstensi file")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio import -resource users|classes|matkul [-mode atomic|best_effort] [-dry-run] FILE")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || *resource == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		*format = path
+	}
+	fileFormat, err := spreadsheet.ParseFormat(*format)
+	if err != nil {
+		fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fatal(err)
+	}
+	defer file.Close()
+
+	records, err := spreadsheet.Read(file, fileFormat, 0)
+	if err != nil {
+		fatal(err)
+	}
+
+	config, db := openDatabase()
+	defer db.Close()
+
+	r := &requests.ImportRequest{Resource: *resource, Mode: *mode, DryRun: *dryRun}
+	importService := services.NewImportServiceImplementation(db, api.NewSQLMicroService(config.Dialect))
+	report, errMsg := importService.Import(systemContext(), r, records)
+
+	if errMsg != nil {
+		fatalMsg(errMsg)
+	}
+	printJSON(report)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
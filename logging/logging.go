@@ -0,0 +1,229 @@
+// Package logging writes one JSON object per log line, in the spirit of
+// log/slog which this module's Go version does not have yet. Loggers carry a
+// package name so the minimum level can be set per package, and a logger for
+// the current request travels in the context.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+var levelNames = map[Level]string{LevelDebug: "DEBUG", LevelInfo: "INFO", LevelWarn: "WARN", LevelError: "ERROR"}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(l))
+}
+
+func ParseLevel(s string) (Level, error) {
+	for level, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("level log %q tidak dikenal, gunakan debug, info, warn atau error", s)
+}
+
+// Levels is the minimum level per package name; the "" entry applies to
+// packages without one of their own.
+type Levels map[string]Level
+
+// ParseLevels reads a spec such as "info,repository=debug,services=warn". An
+// entry without a package sets the default, which is info when left out.
+func ParseLevels(spec string) (Levels, error) {
+	levels := Levels{"": LevelInfo}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pkg, name, found := strings.Cut(entry, "=")
+		if !found {
+			pkg, name = "", entry
+		}
+		level, err := ParseLevel(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		levels[strings.TrimSpace(pkg)] = level
+	}
+	return levels, nil
+}
+
+func (l Levels) For(pkg string) Level {
+	if level, ok := l[pkg]; ok {
+		return level
+	}
+	return l[""]
+}
+
+type output struct {
+	mu     sync.Mutex
+	w      io.Writer
+	levels Levels
+	now    func() time.Time
+}
+
+// Logger is safe for concurrent use; Named and With return derived loggers
+// writing to the same output.
+type Logger struct {
+	out   *output
+	pkg   string
+	level Level
+	attrs []byte
+}
+
+func New(w io.Writer, levels Levels) *Logger {
+	if levels == nil {
+		levels = Levels{"": LevelInfo}
+	}
+	return &Logger{out: &output{w: w, levels: levels, now: time.Now}, level: levels.For("")}
+}
+
+// Named returns a logger for pkg, filtered by the level configured for it.
+func (l *Logger) Named(pkg string) *Logger {
+	named := *l
+	named.pkg = pkg
+	named.level = l.out.levels.For(pkg)
+	return &named
+}
+
+// With returns a logger that adds the key/value pairs args to every line.
+func (l *Logger) With(args ...any) *Logger {
+	with := *l
+	var buf bytes.Buffer
+	buf.Write(l.attrs)
+	appendAttrs(&buf, args)
+	with.attrs = buf.Bytes()
+	return &with
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+func (l *Logger) Debug(msg string, args ...any) { l.Log(LevelDebug, msg, args...) }
+func (l *Logger) Info(msg string, args ...any)  { l.Log(LevelInfo, msg, args...) }
+func (l *Logger) Warn(msg string, args ...any)  { l.Log(LevelWarn, msg, args...) }
+func (l *Logger) Error(msg string, args ...any) { l.Log(LevelError, msg, args...) }
+
+// Log writes msg with the alternating keys and values of args. Errors and
+// fmt.Stringers are written as their text.
+func (l *Logger) Log(level Level, msg string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeValue(&buf, l.out.now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeValue(&buf, level.String())
+	if l.pkg != "" {
+		buf.WriteString(`,"package":`)
+		writeValue(&buf, l.pkg)
+	}
+	buf.WriteString(`,"msg":`)
+	writeValue(&buf, msg)
+	buf.Write(l.attrs)
+	appendAttrs(&buf, args)
+	buf.WriteString("}\n")
+
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(buf.Bytes())
+}
+
+func appendAttrs(buf *bytes.Buffer, args []any) {
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok || i+1 == len(args) {
+			buf.WriteString(`,"!BADKEY":`)
+			writeValue(buf, args[i])
+			i--
+			continue
+		}
+		buf.WriteByte(',')
+		writeValue(buf, key)
+		buf.WriteByte(':')
+		writeValue(buf, args[i+1])
+	}
+}
+
+func writeValue(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case error:
+		value = v.Error()
+	case fmt.Stringer:
+		value = v.String()
+	case time.Duration:
+		value = v.String()
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(b)
+}
+
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	defaultLogger.Store(New(os.Stderr, nil))
+}
+
+// Default is the logger used when the context carries none, writing to
+// stderr at info level until SetDefault replaces it.
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+func SetDefault(l *Logger) {
+	defaultLogger.Store(l)
+}
+
+type loggerKey struct{}
+type requestIDKey struct{}
+
+// NewContext stores the logger of the current request in ctx.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored by NewContext, or Default.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*Logger); ok {
+		return l
+	}
+	return Default()
+}
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID stored by ContextWithRequestID, or "".
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
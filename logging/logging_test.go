@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLogger(t *testing.T, spec string) (*Logger, *bytes.Buffer) {
+	t.Helper()
+
+	levels, err := ParseLevels(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	l := New(&buf, levels)
+	l.out.now = func() time.Time { return time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC) }
+	return l, &buf
+}
+
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels("warn, repository=debug,services=ERROR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for pkg, want := range map[string]Level{"": LevelWarn, "http": LevelWarn, "repository": LevelDebug, "services": LevelError} {
+		if got := levels.For(pkg); got != want {
+			t.Errorf("For(%q) = %v, want %v", pkg, got, want)
+		}
+	}
+
+	if levels, _ := ParseLevels(""); levels.For("http") != LevelInfo {
+		t.Errorf("expected info by default, got %v", levels.For("http"))
+	}
+	if _, err := ParseLevels("repository=verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestLoggerWritesJSONLines(t *testing.T) {
+	l, buf := newTestLogger(t, "info,repository=debug")
+
+	l.Named("services").Debug("tidak ditulis")
+	l.Named("repository").With("request_id", "abc").Debug("query", "rows", 3, "error", errors.New("gagal"), "took", time.Second)
+	l.Warn("tanpa pasangan", "sendiri")
+
+	entries := lines(t, buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(entries), buf)
+	}
+
+	want := map[string]any{
+		"time": "2024-03-01T08:00:00Z", "level": "DEBUG", "package": "repository", "msg": "query",
+		"request_id": "abc", "rows": float64(3), "error": "gagal", "took": "1s",
+	}
+	for key, value := range want {
+		if entries[0][key] != value {
+			t.Errorf("%s = %v, want %v", key, entries[0][key], value)
+		}
+	}
+	if entries[1]["!BADKEY"] != "sendiri" || entries[1]["package"] != nil {
+		t.Errorf("unexpected line %v", entries[1])
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != Default() {
+		t.Error("expected the default logger without one in the context")
+	}
+
+	l, _ := newTestLogger(t, "")
+	ctx := NewContext(ContextWithRequestID(context.Background(), "abc"), l)
+	if FromContext(ctx) != l || RequestID(ctx) != "abc" {
+		t.Errorf("expected the stored logger and request ID, got %q", RequestID(ctx))
+	}
+}
@@ -1,118 +1,107 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+
 	"github.com/dimassfeb-09/sinaustudio.git/api"
-	"github.com/dimassfeb-09/sinaustudio.git/controllers"
-	"github.com/dimassfeb-09/sinaustudio.git/repository"
-	"github.com/dimassfeb-09/sinaustudio.git/services"
-	"github.com/gin-gonic/gin"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
 	_ "github.com/go-sql-driver/mysql"
-	"log"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-func main() {
-	db := api.ConnectionDatabases()
-	defer db.Close()
-
-	route := gin.Default()
-	route.Use(api.ControllAccessAllow())
-
-	Router(route, db)
+type command struct {
+	run     func(args []string)
+	summary string
 }
 
-func Router(route *gin.Engine, db *sql.DB) {
-	v1 := route.Group("/api/v.1/")
-
-	usersRepository := repository.NewUsersRepositoryImplementations()
-	authRepository := repository.NewAuthRepositoryImplementation()
-	classRepository := repository.NewClassRepositoryImplementation()
-	lectureRepository := repository.NewLectureRepositoryImplementation()
-	roomRepository := repository.NewRoomRepositoryImplementation()
-	matkulRepository := repository.NewMataKuliahRepositoryImplementation()
-
-	microServices := api.NewMicroService(usersRepository, authRepository, classRepository, lectureRepository, roomRepository, matkulRepository)
+var commands = map[string]command{
+	"serve":   {runServe, "jalankan server HTTP dan gRPC (default)"},
+	"migrate": {runMigrate, "jalankan migrasi database yang belum diterapkan"},
+	"import":  {runImport, "import users, classes atau matkul dari CSV/XLSX"},
+	"user":    {runUser, "create, reset-password atau set-role user"},
+	"class":   {runClass, "list kelas atau regen-code kode kelas"},
+	"room":    {runRoom, "list room"},
+	"seed":    {runSeed, "isi database dengan data contoh"},
+}
 
-	usersService := services.NewUserServiceImplementation(db, microServices)
-	authService := services.NewAuthServiceImplementation(db, microServices)
-	classService := services.NewClassServiceImplementation(db, microServices)
-	lectureService := services.NewLectureServiceImplementation(db, microServices)
-	roomService := services.NewRoomServiceImplementation(db, microServices)
-	matkulService := services.NewMataKuliahServiceImplementation(db, microServices)
+// commandOrder is the order commands are listed in by usage.
+var commandOrder = []string{"serve", "migrate", "import", "user", "class", "room", "seed"}
 
-	usersController := controllers.NewUsersControllerImplementation(usersService)
-	authController := controllers.NewAuthControllerImplementation(authService)
-	classController := controllers.NewClassControllerImplementation(classService)
-	lectureController := controllers.NewLectureController(lectureService)
-	roomController := controllers.NewRoomController(roomService)
-	matkulController := controllers.NewMatkulController(matkulService)
+// main runs the command named by the first argument, serve when there is
+// none. Every command other than serve goes through the services layer with
+// systemContext.
+func main() {
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
 
-	auth := v1.Group("/auth")
-	auth.POST("/register", authController.AuthRegisterUser)
-	auth.POST("/login", authController.AuthLoginUser)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "perintah %q tidak dikenal\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+	cmd.run(args)
+}
 
-	v1.Use(api.MiddlewareAuthorization)
-	user := v1.Group("/user")
-	user.POST("/create", usersController.InsertDataUser)
-	user.PUT("/update", usersController.UpdateDataUser)
-	user.DELETE("/delete", usersController.DeleteDataUser)
-	user.PUT("/changepassword", usersController.ChangePasswordUser)
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: sinaustudio [command] [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `Jalankan "sinaustudio <command> -h" untuk flag setiap command.`)
+}
 
-	class := v1.Group("/class")
-	class.POST("/create", classController.AddClass)
-	class.PUT("/update", classController.UpdateClass)
-	class.DELETE("/delete", classController.DeleteClassByID)
-	class.GET("/", func(c *gin.Context) {
-		if ID := c.Query("id"); ID != "" {
-			classController.FindClassByID(c)
-			return
-		} else if name := c.Query("name"); name != "" {
-			classController.FindClassByName(c)
-			return
-		}
-	})
+// openDatabase loads the config and connects for a command other than serve,
+// logging to stderr so stdout holds only the command's output.
+func openDatabase() (*api.Config, *sql.DB) {
+	config, err := api.LoadConfig()
+	if err != nil {
+		fatal(err)
+	}
+	logging.SetDefault(logging.New(os.Stderr, config.LogLevels))
+	db, err := api.ConnectionDatabases(config)
+	if err != nil {
+		fatal(err)
+	}
+	return config, db
+}
 
-	lecture := v1.Group("/lecture")
-	lecture.POST("/create", lectureController.InsertLecture)
-	lecture.PUT("/update", lectureController.UpdateLecture)
-	lecture.DELETE("/delete", lectureController.DeleteLecture)
-	lecture.GET("/", func(c *gin.Context) {
-		if id := c.Query("id"); id != "" {
-			lectureController.FindLectureByID(c)
-			return
-		} else if name := c.Query("name"); name != "" {
-			lectureController.FindLectureByName(c)
-			return
-		}
-	})
+// systemContext is the ctx the commands call the services with. It acts for
+// the operator of the platform, the system actor, which passes the role checks
+// a user of the API is held to.
+func systemContext() context.Context {
+	return api.ContextWithSystemActor(context.Background())
+}
 
-	room := v1.Group("/room")
-	room.POST("/create", roomController.InsertRoom)
-	room.PUT("/update", roomController.UpdateRoom)
-	room.DELETE("/delete", roomController.DeleteRoom)
-	room.GET("/", func(c *gin.Context) {
-		if id := c.Query("id"); id != "" {
-			roomController.FindRoomByID(c)
-			return
-		}
-	})
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v any) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
 
-	matkul := v1.Group("/matkul")
-	matkul.POST("/create", matkulController.InsertMatkul)
-	matkul.PUT("/update", matkulController.UpdateMatkul)
-	matkul.DELETE("/delete", matkulController.DeleteMatkul)
-	matkul.GET("/", func(c *gin.Context) {
-		if id := c.Query("id"); id != "" {
-			matkulController.FindMatkulByID(c)
-			return
-		} else if name := c.Query("name"); name != "" {
-			matkulController.FindMatkulByName(c)
-			return
-		}
-	})
+// fatalMsg prints errMsg as JSON, like the API would answer, and exits.
+func fatalMsg(errMsg *response.ErrorMsg) {
+	printJSON(errMsg)
+	os.Exit(1)
+}
 
-	err := route.Run(":8081")
-	if err != nil {
-		log.Fatalln(err)
-	}
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
 }
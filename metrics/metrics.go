@@ -0,0 +1,133 @@
+// Package metrics holds the Prometheus collectors of SinauStudio and serves
+// them on /metrics. Request and login metrics live in one registry for the
+// whole process; the pool stats and business gauges are read from the
+// database handed to Handler when Prometheus scrapes.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	Path      = "/metrics"
+	namespace = "sinaustudio"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by result, success or failure.",
+	}, []string{"result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration, Logins,
+	)
+}
+
+// LoginResult counts one login attempt.
+func LoginResult(success bool) {
+	if success {
+		Logins.WithLabelValues("success").Inc()
+	} else {
+		Logins.WithLabelValues("failure").Inc()
+	}
+}
+
+// Middleware records every request under its route template, such as
+// /api/v2/rooms/:id, so IDs do not turn into label values. Requests that
+// matched no route are recorded as "unmatched".
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		HTTPRequests.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		HTTPDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Stats reads the business gauges; services.StatsService implements it.
+type Stats interface {
+	CountActiveRooms(ctx context.Context) (count int, errMsg *response.ErrorMsg)
+	CountUsers(ctx context.Context) (count int, errMsg *response.ErrorMsg)
+}
+
+// Handler serves the process metrics together with the connection pool of
+// db and the gauges of stats.
+func Handler(db *sql.DB, stats Stats) http.Handler {
+	local := prometheus.NewRegistry()
+	local.MustRegister(collectors.NewDBStatsCollector(db, namespace), &statsCollector{stats: stats})
+	return promhttp.HandlerFor(prometheus.Gatherers{registry, local}, promhttp.HandlerOpts{})
+}
+
+var (
+	activeRoomsDesc = prometheus.NewDesc(namespace+"_active_rooms", "Rooms whose schedule includes the time of the scrape.", nil, nil)
+	usersDesc       = prometheus.NewDesc(namespace+"_registered_users", "Registered users that are not deleted.", nil, nil)
+)
+
+// statsCollector queries stats on every scrape; a failing count is left out
+// of the scrape and logged.
+type statsCollector struct {
+	stats Stats
+}
+
+func (s *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- activeRoomsDesc
+	ch <- usersDesc
+}
+
+func (s *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gauges := []struct {
+		desc  *prometheus.Desc
+		count func(ctx context.Context) (int, *response.ErrorMsg)
+	}{
+		{activeRoomsDesc, s.stats.CountActiveRooms},
+		{usersDesc, s.stats.CountUsers},
+	}
+	for _, gauge := range gauges {
+		count, errMsg := gauge.count(ctx)
+		if errMsg != nil {
+			logging.Default().Named("metrics").Error("gauge gagal dibaca", "gauge", gauge.desc.String(), "error", errMsg.Msg)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(gauge.desc, prometheus.GaugeValue, float64(count))
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/migrations"
+)
+
+// runMigrate implements "sinaustudio migrate [-status]". It applies the
+// pending migrations whether DB_MIGRATE is set or not, and prints the
+// migrations applied and still pending as JSON.
+func runMigrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	statusOnly := flags.Bool("status", false, "hanya tampilkan status migrasi, tanpa menjalankannya")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio migrate [-status]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	config, err := api.LoadConfig()
+	if err != nil {
+		fatal(err)
+	}
+	logging.SetDefault(logging.New(os.Stderr, config.LogLevels))
+	config.DBMigrate = false
+	db, err := api.ConnectionDatabases(config)
+	if err != nil {
+		fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if !*statusOnly {
+		if err := migrations.Up(ctx, db, config.Dialect); err != nil {
+			fatal(err)
+		}
+	}
+
+	applied, pending, err := migrations.Status(ctx, db, config.Dialect)
+	if err != nil {
+		fatal(err)
+	}
+	printJSON(map[string][]string{"applied": applied, "pending": pending})
+}
@@ -0,0 +1,285 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite3/*.sql
+var files embed.FS
+
+const createVersionTable = "CREATE TABLE IF NOT EXISTS schema_migrations (version VARCHAR(255) NOT NULL PRIMARY KEY)"
+
+// createStepTable records the statements of a MySQL migration that already
+// ran, so that a failed migration resumes after them instead of starting over.
+const createStepTable = "CREATE TABLE IF NOT EXISTS schema_migration_steps (version VARCHAR(255) NOT NULL, step INT NOT NULL, PRIMARY KEY (version, step))"
+
+// Versions returns the migration file names for the dialect in the order they are applied.
+func Versions(dialect repository.Dialect) ([]string, error) {
+	entries, err := fs.ReadDir(files, string(dialect))
+	if err != nil {
+		return nil, fmt.Errorf("migrasi untuk dialect %s tidak ditemukan: %w", dialect, err)
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// Up applies every migration of the dialect that is not yet recorded in schema_migrations.
+func Up(ctx context.Context, db *sql.DB, dialect repository.Dialect) error {
+	if _, err := db.ExecContext(ctx, createVersionTable); err != nil {
+		return err
+	}
+
+	applied, err := Applied(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	versions, err := Versions(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		if applied[version] {
+			continue
+		}
+
+		content, err := files.ReadFile(string(dialect) + "/" + version)
+		if err != nil {
+			return err
+		}
+
+		if err := apply(ctx, db, dialect, version, string(content)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply runs one migration and records it in a single transaction. SQLite
+// migrations run with foreign key enforcement off, which is the only way
+// SQLite allows rebuilding a table other tables refer to; the foreign keys are
+// checked with foreign_key_check before the commit instead. MySQL commits
+// every DDL statement on its own, so its migrations are applied by
+// applySteps instead.
+func apply(ctx context.Context, db *sql.DB, dialect repository.Dialect, version, content string) error {
+	if dialect == repository.MySQL {
+		return applySteps(ctx, db, dialect, version, content)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if dialect == repository.SQLite {
+		// PRAGMA foreign_keys is ignored inside a transaction.
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.Background(), "PRAGMA foreign_keys = ON")
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, statement := range splitStatements(content) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrasi %s gagal: %w", version, err)
+		}
+	}
+	if dialect == repository.SQLite {
+		if err := checkForeignKeys(ctx, tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrasi %s gagal: %w", version, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, dialect.Rebind("INSERT INTO schema_migrations(version) VALUES(?)"), version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// applySteps runs the statements of one migration one by one, recording each
+// in schema_migration_steps as soon as it succeeds, and the migration in
+// schema_migrations once all of them did. A migration that failed halfway
+// resumes at the statement that failed. Only a crash between a DDL statement
+// and its record can leave a statement applied but not recorded.
+func applySteps(ctx context.Context, db *sql.DB, dialect repository.Dialect, version, content string) error {
+	if _, err := db.ExecContext(ctx, createStepTable); err != nil {
+		return err
+	}
+	var done int
+	if err := db.QueryRowContext(ctx, dialect.Rebind("SELECT COALESCE(MAX(step), 0) FROM schema_migration_steps WHERE version = ?"), version).Scan(&done); err != nil {
+		return err
+	}
+
+	for i, statement := range splitStatements(content) {
+		if step := i + 1; step > done {
+			if err := applyStep(ctx, db, dialect, version, step, statement); err != nil {
+				return fmt.Errorf("migrasi %s gagal di statement %d: %w", version, step, err)
+			}
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, dialect.Rebind("INSERT INTO schema_migrations(version) VALUES(?)"), version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, dialect.Rebind("DELETE FROM schema_migration_steps WHERE version = ?"), version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyStep runs statement and records it; a DML statement and its record
+// commit together.
+func applyStep(ctx context.Context, db *sql.DB, dialect repository.Dialect, version string, step int, statement string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, statement); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, dialect.Rebind("INSERT INTO schema_migration_steps(version, step) VALUES(?, ?)"), version, step); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func checkForeignKeys(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var table, parent string
+		var rowID sql.NullInt64
+		var fkID int
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return err
+		}
+		return fmt.Errorf("foreign key %s ke %s dilanggar oleh rowid %d", table, parent, rowID.Int64)
+	}
+	return rows.Err()
+}
+
+// Applied returns the set of migration versions already recorded in the database.
+func Applied(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// splitStatements splits a migration on the ';' ending each statement, but
+// not on one inside a quoted string literal.
+func splitStatements(content string) []string {
+	var statements []string
+	add := func(statement string) {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	start, quoted := 0, false
+	for i := 0; i < len(content); i++ {
+		switch {
+		case content[i] == '\'':
+			quoted = !quoted
+		case content[i] == ';' && !quoted:
+			add(content[start:i])
+			start = i + 1
+		}
+	}
+	add(content[start:])
+	return statements
+}
+
+// Pending returns the migrations of the dialect not yet recorded in
+// schema_migrations, in the order Up would apply them.
+func Pending(ctx context.Context, db *sql.DB, dialect repository.Dialect) ([]string, error) {
+	applied, err := Applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := Versions(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, version := range versions {
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+	return pending, nil
+}
+
+// Status returns the migrations of the dialect already applied and those still
+// pending, creating schema_migrations on a database never migrated before.
+func Status(ctx context.Context, db *sql.DB, dialect repository.Dialect) (applied, pending []string, err error) {
+	if _, err := db.ExecContext(ctx, createVersionTable); err != nil {
+		return nil, nil, err
+	}
+	done, err := Applied(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+	versions, err := Versions(dialect)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applied, pending = []string{}, []string{}
+	for _, version := range versions {
+		if done[version] {
+			applied = append(applied, version)
+		} else {
+			pending = append(pending, version)
+		}
+	}
+	return applied, pending, nil
+}
@@ -0,0 +1,208 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"one statement", "CREATE TABLE a (id INTEGER)", []string{"CREATE TABLE a (id INTEGER)"}},
+		{"trailing semicolon", "CREATE TABLE a (id INTEGER);\n", []string{"CREATE TABLE a (id INTEGER)"}},
+		{"several statements", "CREATE TABLE a (id INTEGER);\n\nCREATE INDEX a_id ON a (id);\n", []string{"CREATE TABLE a (id INTEGER)", "CREATE INDEX a_id ON a (id)"}},
+		{"empty statements", ";\n ;;", nil},
+		{"semicolon in literal", "UPDATE a SET name = 'satu; dua';\nDELETE FROM a", []string{"UPDATE a SET name = 'satu; dua'", "DELETE FROM a"}},
+		{"escaped quote in literal", "UPDATE a SET name = 'it''s; ok';", []string{"UPDATE a SET name = 'it''s; ok'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("splitStatements(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+// An unbalanced quote would swallow the statements after it, so every
+// migration must split at each of its ';'.
+func TestMigrationsSplit(t *testing.T) {
+	for _, dialect := range []repository.Dialect{repository.MySQL, repository.Postgres, repository.SQLite} {
+		versions, err := Versions(dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, version := range versions {
+			content, err := files.ReadFile(string(dialect) + "/" + version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(splitStatements(string(content))), strings.Count(string(content), ";"); got != want {
+				t.Errorf("%s/%s: %d statements, expected %d", dialect, version, got, want)
+			}
+		}
+	}
+}
+
+// migrateTo opens a fresh SQLite database migrated up to, not including, version.
+func migrateTo(t *testing.T, version string) *sql.DB {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on", filepath.Join(t.TempDir(), "sinaustudio.db"))
+	db, err := sql.Open(repository.SQLite.DriverName(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, createVersionTable); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := Versions(repository.SQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range versions {
+		if v >= version {
+			break
+		}
+		content, err := files.ReadFile("sqlite3/" + v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := apply(ctx, db, repository.SQLite, v, string(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestForeignKeyMigrationKeepsOrphans(t *testing.T) {
+	ctx := context.Background()
+	db := migrateTo(t, "0005_foreign_keys.sql")
+
+	seed := []string{
+		"INSERT INTO class (id, name, kode_kelas) VALUES (1, 'TI-1A', 'ABC123')",
+		"INSERT INTO users (id, name, email, password, class_id, role) VALUES (1, 'Agus', 'agus@example.com', 'x', 1, 'dosen')",
+		"INSERT INTO lecture (id, name, user_id) VALUES (1, 'Agus', 1)",
+		"INSERT INTO lecture (id, name, user_id) VALUES (2, 'Tanpa User', 99)",
+		"INSERT INTO room (id, name, url, lecture_id, start_room, end_room) VALUES (1, 'Algoritma', 'https://meet.example.com/1', 1, '2023-02-19 20:25:50', '2023-02-19 22:25:50')",
+		"INSERT INTO room (id, name, url, lecture_id, start_room, end_room) VALUES (2, 'Basis Data', 'https://meet.example.com/2', 2, '2023-02-19 20:25:50', '2023-02-19 22:25:50')",
+		"INSERT INTO room (id, name, url, lecture_id, start_room, end_room) VALUES (3, 'Jaringan', 'https://meet.example.com/3', 99, '2023-02-19 20:25:50', '2023-02-19 22:25:50')",
+		"INSERT INTO class_member (class_id, user_id) VALUES (1, 1)",
+		"INSERT INTO class_member (class_id, user_id) VALUES (99, 1)",
+	}
+	for _, statement := range seed {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Up(ctx, db, repository.SQLite); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{
+		"lecture": 1, "lecture_orphan": 1,
+		"room": 1, "room_orphan": 2,
+		"class_member": 1, "class_member_orphan": 1,
+	}
+	for table, want := range counts {
+		var got int
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("expected %d rows in %s, got %d", want, table, got)
+		}
+	}
+}
+
+func TestProfileMigrationKeepsDuplicateNPM(t *testing.T) {
+	ctx := context.Background()
+	db := migrateTo(t, "0009_profile.sql")
+
+	seed := []string{
+		"INSERT INTO class (id, name, kode_kelas) VALUES (1, 'TI-1A', 'ABC123')",
+		"INSERT INTO users (id, name, email, password, class_id, role, npm) VALUES (1, 'Agus', 'agus@example.com', 'x', 1, 'mahasiswa', '2110511001')",
+		"INSERT INTO users (id, name, email, password, class_id, role, npm) VALUES (2, 'Budi', 'budi@example.com', 'x', 1, 'mahasiswa', '2110511001')",
+		"INSERT INTO users (id, name, email, password, class_id, role, npm) VALUES (3, 'Citra', 'citra@example.com', 'x', 1, 'mahasiswa', '2110511003')",
+	}
+	for _, statement := range seed {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Up(ctx, db, repository.SQLite); err != nil {
+		t.Fatal(err)
+	}
+
+	npms := func(query string) map[int]string {
+		t.Helper()
+		rows, err := db.QueryContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		got := map[int]string{}
+		for rows.Next() {
+			var userID int
+			var npm string
+			if err := rows.Scan(&userID, &npm); err != nil {
+				t.Fatal(err)
+			}
+			got[userID] = npm
+		}
+		return got
+	}
+
+	if got := npms("SELECT user_id, npm FROM profile"); len(got) != 2 || got[1] != "2110511001" || got[3] != "2110511003" {
+		t.Fatalf("expected the first holder of each npm in profile, got %v", got)
+	}
+	if got := npms("SELECT user_id, npm FROM profile_npm_conflict"); len(got) != 1 || got[2] != "2110511001" {
+		t.Fatalf("expected the duplicate npm of user 2 kept, got %v", got)
+	}
+}
+
+// applySteps is what MySQL uses; SQLite runs it just as well.
+func TestApplyStepsResumes(t *testing.T) {
+	ctx := context.Background()
+	db := migrateTo(t, "0001")
+
+	failing := "CREATE TABLE a (id INTEGER);\nINSERT INTO a (id) VALUES (1);\nINSERT INTO b (id) VALUES (1);\n"
+	if err := applySteps(ctx, db, repository.SQLite, "9999_test.sql", failing); err == nil {
+		t.Fatal("expected the insert into the missing table to fail")
+	}
+	if applied, err := Applied(ctx, db); err != nil || applied["9999_test.sql"] {
+		t.Fatalf("expected the failed migration not recorded, got %v (%v)", applied, err)
+	}
+
+	// A rerun skips the statements that already ran instead of failing on them.
+	fixed := "CREATE TABLE a (id INTEGER);\nINSERT INTO a (id) VALUES (1);\nCREATE TABLE b (id INTEGER);\n"
+	if err := applySteps(ctx, db, repository.SQLite, "9999_test.sql", fixed); err != nil {
+		t.Fatal(err)
+	}
+	var rows, steps int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM a").Scan(&rows); err != nil || rows != 1 {
+		t.Fatalf("expected the insert run once, got %d rows (%v)", rows, err)
+	}
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migration_steps").Scan(&steps); err != nil || steps != 0 {
+		t.Fatalf("expected the steps cleared, got %d (%v)", steps, err)
+	}
+	if applied, err := Applied(ctx, db); err != nil || !applied["9999_test.sql"] {
+		t.Fatalf("expected the migration recorded, got %v (%v)", applied, err)
+	}
+}
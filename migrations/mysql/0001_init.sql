@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS class (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kode_kelas VARCHAR(20) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(100) NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    class_id INT NOT NULL,
+    role ENUM('dosen', 'mahasiswa') NOT NULL,
+    npm VARCHAR(100)
+);
+
+CREATE TABLE IF NOT EXISTS lecture (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    user_id INT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS room (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    lecture_id INT NOT NULL,
+    start_room DATETIME NOT NULL,
+    end_room DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS matakuliah (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    kode_matkul VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL
+);
@@ -0,0 +1,6 @@
+UPDATE class JOIN (
+    SELECT DISTINCT later.id FROM class AS later JOIN class AS other ON other.kode_kelas = later.kode_kelas AND other.id < later.id
+) AS duplicate ON duplicate.id = class.id
+SET class.kode_kelas = CONCAT(class.kode_kelas, '-', class.id);
+
+CREATE UNIQUE INDEX class_kode_kelas ON class (kode_kelas);
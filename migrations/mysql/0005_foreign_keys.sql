@@ -0,0 +1,27 @@
+-- Rows left pointing at a missing parent are moved to *_orphan tables for
+-- review instead of being deleted, so the foreign keys can be added.
+-- users.class_id gets no foreign key: admins are stored with class_id 0 and
+-- class membership lives in class_member since 0002.
+
+CREATE TABLE IF NOT EXISTS lecture_orphan AS
+SELECT * FROM lecture WHERE user_id NOT IN (SELECT id FROM users);
+
+CREATE TABLE IF NOT EXISTS room_orphan AS
+SELECT * FROM room WHERE lecture_id NOT IN (SELECT id FROM lecture WHERE user_id IN (SELECT id FROM users));
+
+CREATE TABLE IF NOT EXISTS class_member_orphan AS
+SELECT * FROM class_member WHERE class_id NOT IN (SELECT id FROM class) OR user_id NOT IN (SELECT id FROM users);
+
+DELETE FROM room WHERE id IN (SELECT id FROM room_orphan);
+
+DELETE FROM lecture WHERE id IN (SELECT id FROM lecture_orphan);
+
+DELETE FROM class_member WHERE class_id NOT IN (SELECT id FROM class) OR user_id NOT IN (SELECT id FROM users);
+
+ALTER TABLE lecture ADD CONSTRAINT lecture_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE room ADD CONSTRAINT room_lecture_id_fkey FOREIGN KEY (lecture_id) REFERENCES lecture (id);
+
+ALTER TABLE class_member ADD CONSTRAINT class_member_class_id_fkey FOREIGN KEY (class_id) REFERENCES class (id);
+
+ALTER TABLE class_member ADD CONSTRAINT class_member_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE class ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE lecture ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE room ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE matakuliah ADD COLUMN version INT NOT NULL DEFAULT 1;
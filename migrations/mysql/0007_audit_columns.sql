@@ -0,0 +1,29 @@
+ALTER TABLE users ADD COLUMN created_at DATETIME NULL;
+ALTER TABLE users ADD COLUMN updated_at DATETIME NULL;
+ALTER TABLE users ADD COLUMN created_by INT NULL;
+ALTER TABLE users ADD COLUMN updated_by INT NULL;
+UPDATE users SET created_at = UTC_TIMESTAMP(), updated_at = UTC_TIMESTAMP();
+
+ALTER TABLE class ADD COLUMN created_at DATETIME NULL;
+ALTER TABLE class ADD COLUMN updated_at DATETIME NULL;
+ALTER TABLE class ADD COLUMN created_by INT NULL;
+ALTER TABLE class ADD COLUMN updated_by INT NULL;
+UPDATE class SET created_at = UTC_TIMESTAMP(), updated_at = UTC_TIMESTAMP();
+
+ALTER TABLE lecture ADD COLUMN created_at DATETIME NULL;
+ALTER TABLE lecture ADD COLUMN updated_at DATETIME NULL;
+ALTER TABLE lecture ADD COLUMN created_by INT NULL;
+ALTER TABLE lecture ADD COLUMN updated_by INT NULL;
+UPDATE lecture SET created_at = UTC_TIMESTAMP(), updated_at = UTC_TIMESTAMP();
+
+ALTER TABLE room ADD COLUMN created_at DATETIME NULL;
+ALTER TABLE room ADD COLUMN updated_at DATETIME NULL;
+ALTER TABLE room ADD COLUMN created_by INT NULL;
+ALTER TABLE room ADD COLUMN updated_by INT NULL;
+UPDATE room SET created_at = UTC_TIMESTAMP(), updated_at = UTC_TIMESTAMP();
+
+ALTER TABLE matakuliah ADD COLUMN created_at DATETIME NULL;
+ALTER TABLE matakuliah ADD COLUMN updated_at DATETIME NULL;
+ALTER TABLE matakuliah ADD COLUMN created_by INT NULL;
+ALTER TABLE matakuliah ADD COLUMN updated_by INT NULL;
+UPDATE matakuliah SET created_at = UTC_TIMESTAMP(), updated_at = UTC_TIMESTAMP();
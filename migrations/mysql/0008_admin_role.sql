@@ -0,0 +1 @@
+ALTER TABLE users MODIFY role ENUM('dosen', 'mahasiswa', 'admin') NOT NULL;
@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS class_matkul (
+    class_id INT NOT NULL,
+    matkul_id INT NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    deleted_at DATETIME NULL,
+    PRIMARY KEY (class_id, matkul_id),
+    CONSTRAINT class_matkul_class_id_fkey FOREIGN KEY (class_id) REFERENCES class (id),
+    CONSTRAINT class_matkul_matkul_id_fkey FOREIGN KEY (matkul_id) REFERENCES matakuliah (id)
+);
+
+CREATE INDEX class_matkul_matkul_id ON class_matkul (matkul_id);
+
+CREATE TABLE IF NOT EXISTS material_topic (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    matkul_id INT NOT NULL,
+    week INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    created_by INT NULL,
+    updated_by INT NULL,
+    version INT NOT NULL DEFAULT 1,
+    deleted_at DATETIME NULL,
+    CONSTRAINT material_topic_matkul_id_fkey FOREIGN KEY (matkul_id) REFERENCES matakuliah (id)
+);
+
+CREATE INDEX material_topic_week ON material_topic (matkul_id, week);
+
+CREATE TABLE IF NOT EXISTS material (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    topic_id INT NOT NULL,
+    kind ENUM('file', 'link') NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    url VARCHAR(2048) NULL,
+    file_key VARCHAR(512) NULL,
+    file_name VARCHAR(255) NULL,
+    content_type VARCHAR(255) NULL,
+    size BIGINT NULL,
+    created_at DATETIME NULL,
+    updated_at DATETIME NULL,
+    created_by INT NULL,
+    updated_by INT NULL,
+    version INT NOT NULL DEFAULT 1,
+    deleted_at DATETIME NULL,
+    CONSTRAINT material_topic_id_fkey FOREIGN KEY (topic_id) REFERENCES material_topic (id)
+);
+
+CREATE INDEX material_topic_id ON material (topic_id);
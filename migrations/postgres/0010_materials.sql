@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS class_matkul (
+    class_id INTEGER NOT NULL,
+    matkul_id INTEGER NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP NULL,
+    PRIMARY KEY (class_id, matkul_id),
+    CONSTRAINT class_matkul_class_id_fkey FOREIGN KEY (class_id) REFERENCES class (id),
+    CONSTRAINT class_matkul_matkul_id_fkey FOREIGN KEY (matkul_id) REFERENCES matakuliah (id)
+);
+
+CREATE INDEX IF NOT EXISTS class_matkul_matkul_id ON class_matkul (matkul_id);
+
+CREATE TABLE IF NOT EXISTS material_topic (
+    id SERIAL PRIMARY KEY,
+    matkul_id INTEGER NOT NULL,
+    week INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    created_by INTEGER NULL,
+    updated_by INTEGER NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT material_topic_matkul_id_fkey FOREIGN KEY (matkul_id) REFERENCES matakuliah (id)
+);
+
+CREATE INDEX IF NOT EXISTS material_topic_week ON material_topic (matkul_id, week);
+
+CREATE TABLE IF NOT EXISTS material (
+    id SERIAL PRIMARY KEY,
+    topic_id INTEGER NOT NULL,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('file', 'link')),
+    title VARCHAR(255) NOT NULL,
+    url VARCHAR(2048) NULL,
+    file_key VARCHAR(512) NULL,
+    file_name VARCHAR(255) NULL,
+    content_type VARCHAR(255) NULL,
+    size BIGINT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    created_by INTEGER NULL,
+    updated_by INTEGER NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT material_topic_id_fkey FOREIGN KEY (topic_id) REFERENCES material_topic (id)
+);
+
+CREATE INDEX IF NOT EXISTS material_topic_id ON material (topic_id);
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS class (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    kode_kelas TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    password TEXT NOT NULL,
+    class_id INTEGER NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('dosen', 'mahasiswa')),
+    npm TEXT
+);
+
+CREATE TABLE IF NOT EXISTS lecture (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    user_id INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS room (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    lecture_id INTEGER NOT NULL,
+    start_room TEXT NOT NULL,
+    end_room TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS matakuliah (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kode_matkul TEXT NOT NULL,
+    name TEXT NOT NULL
+);
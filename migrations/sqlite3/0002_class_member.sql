@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS class_member (
+    class_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (class_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS class_member_user_id ON class_member (user_id);
+
+ALTER TABLE class ADD COLUMN kode_kelas_expires_at TIMESTAMP NULL;
+
+INSERT INTO class_member (class_id, user_id, joined_at)
+SELECT users.class_id, users.id, CURRENT_TIMESTAMP FROM users JOIN class ON class.id = users.class_id;
@@ -0,0 +1,4 @@
+UPDATE class SET kode_kelas = kode_kelas || '-' || id
+WHERE EXISTS (SELECT 1 FROM class AS other WHERE other.kode_kelas = class.kode_kelas AND other.id < class.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS class_kode_kelas ON class (kode_kelas);
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL;
+
+ALTER TABLE class ADD COLUMN deleted_at TIMESTAMP NULL;
+
+ALTER TABLE lecture ADD COLUMN deleted_at TIMESTAMP NULL;
+
+ALTER TABLE room ADD COLUMN deleted_at TIMESTAMP NULL;
+
+ALTER TABLE matakuliah ADD COLUMN deleted_at TIMESTAMP NULL;
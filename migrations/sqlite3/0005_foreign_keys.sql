@@ -0,0 +1,65 @@
+-- Rows left pointing at a missing parent are moved to *_orphan tables for
+-- review instead of being deleted, so the foreign keys can be added.
+-- users.class_id gets no foreign key: admins are stored with class_id 0 and
+-- class membership lives in class_member since 0002.
+
+CREATE TABLE IF NOT EXISTS lecture_orphan AS
+SELECT * FROM lecture WHERE user_id NOT IN (SELECT id FROM users);
+
+CREATE TABLE IF NOT EXISTS room_orphan AS
+SELECT * FROM room WHERE lecture_id NOT IN (SELECT id FROM lecture WHERE user_id IN (SELECT id FROM users));
+
+CREATE TABLE IF NOT EXISTS class_member_orphan AS
+SELECT * FROM class_member WHERE class_id NOT IN (SELECT id FROM class) OR user_id NOT IN (SELECT id FROM users);
+
+CREATE TABLE lecture_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    deleted_at TIMESTAMP NULL
+);
+
+INSERT INTO lecture_new (id, name, user_id, deleted_at)
+SELECT id, name, user_id, deleted_at FROM lecture WHERE user_id IN (SELECT id FROM users);
+
+DROP TABLE lecture;
+
+ALTER TABLE lecture_new RENAME TO lecture;
+
+CREATE INDEX IF NOT EXISTS lecture_user_id ON lecture (user_id);
+
+CREATE TABLE room_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    lecture_id INTEGER NOT NULL REFERENCES lecture (id),
+    start_room TEXT NOT NULL,
+    end_room TEXT NOT NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+INSERT INTO room_new (id, name, url, lecture_id, start_room, end_room, deleted_at)
+SELECT id, name, url, lecture_id, start_room, end_room, deleted_at FROM room WHERE lecture_id IN (SELECT id FROM lecture);
+
+DROP TABLE room;
+
+ALTER TABLE room_new RENAME TO room;
+
+CREATE INDEX IF NOT EXISTS room_lecture_id ON room (lecture_id);
+
+CREATE TABLE class_member_new (
+    class_id INTEGER NOT NULL REFERENCES class (id),
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (class_id, user_id)
+);
+
+INSERT INTO class_member_new (class_id, user_id, joined_at)
+SELECT class_id, user_id, joined_at FROM class_member
+WHERE class_id IN (SELECT id FROM class) AND user_id IN (SELECT id FROM users);
+
+DROP TABLE class_member;
+
+ALTER TABLE class_member_new RENAME TO class_member;
+
+CREATE INDEX IF NOT EXISTS class_member_user_id ON class_member (user_id);
@@ -0,0 +1,29 @@
+ALTER TABLE users ADD COLUMN created_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN updated_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN created_by INTEGER NULL;
+ALTER TABLE users ADD COLUMN updated_by INTEGER NULL;
+UPDATE users SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
+
+ALTER TABLE class ADD COLUMN created_at TIMESTAMP NULL;
+ALTER TABLE class ADD COLUMN updated_at TIMESTAMP NULL;
+ALTER TABLE class ADD COLUMN created_by INTEGER NULL;
+ALTER TABLE class ADD COLUMN updated_by INTEGER NULL;
+UPDATE class SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
+
+ALTER TABLE lecture ADD COLUMN created_at TIMESTAMP NULL;
+ALTER TABLE lecture ADD COLUMN updated_at TIMESTAMP NULL;
+ALTER TABLE lecture ADD COLUMN created_by INTEGER NULL;
+ALTER TABLE lecture ADD COLUMN updated_by INTEGER NULL;
+UPDATE lecture SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
+
+ALTER TABLE room ADD COLUMN created_at TIMESTAMP NULL;
+ALTER TABLE room ADD COLUMN updated_at TIMESTAMP NULL;
+ALTER TABLE room ADD COLUMN created_by INTEGER NULL;
+ALTER TABLE room ADD COLUMN updated_by INTEGER NULL;
+UPDATE room SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
+
+ALTER TABLE matakuliah ADD COLUMN created_at TIMESTAMP NULL;
+ALTER TABLE matakuliah ADD COLUMN updated_at TIMESTAMP NULL;
+ALTER TABLE matakuliah ADD COLUMN created_by INTEGER NULL;
+ALTER TABLE matakuliah ADD COLUMN updated_by INTEGER NULL;
+UPDATE matakuliah SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
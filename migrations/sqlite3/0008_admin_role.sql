@@ -0,0 +1,22 @@
+CREATE TABLE users_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    password TEXT NOT NULL,
+    class_id INTEGER NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('dosen', 'mahasiswa', 'admin')),
+    npm TEXT,
+    deleted_at TIMESTAMP NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    created_by INTEGER NULL,
+    updated_by INTEGER NULL
+);
+
+INSERT INTO users_new (id, name, email, password, class_id, role, npm, deleted_at, version, created_at, updated_at, created_by, updated_by)
+SELECT id, name, email, password, class_id, role, npm, deleted_at, version, created_at, updated_at, created_by, updated_by FROM users;
+
+DROP TABLE users;
+
+ALTER TABLE users_new RENAME TO users;
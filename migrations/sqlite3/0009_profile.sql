@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS profile (
+    user_id INTEGER NOT NULL PRIMARY KEY REFERENCES users (id),
+    npm TEXT NULL,
+    nidn TEXT NULL,
+    program_studi TEXT NULL,
+    angkatan INTEGER NULL,
+    phone TEXT NULL,
+    avatar TEXT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    created_by INTEGER NULL,
+    updated_by INTEGER NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS profile_npm ON profile (npm);
+
+CREATE UNIQUE INDEX IF NOT EXISTS profile_nidn ON profile (nidn);
+
+INSERT INTO profile (user_id, npm, created_at, updated_at)
+SELECT users.id, users.npm, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM users
+WHERE users.npm IS NOT NULL AND users.npm <> ''
+AND NOT EXISTS (SELECT 1 FROM users AS other WHERE other.npm = users.npm AND other.id < users.id);
+
+CREATE TABLE IF NOT EXISTS profile_npm_conflict (
+    user_id INTEGER NOT NULL PRIMARY KEY,
+    npm TEXT NOT NULL
+);
+
+INSERT INTO profile_npm_conflict (user_id, npm)
+SELECT users.id, users.npm FROM users
+WHERE users.npm IS NOT NULL AND users.npm <> ''
+AND EXISTS (SELECT 1 FROM users AS other WHERE other.npm = users.npm AND other.id < users.id);
+
+ALTER TABLE users DROP COLUMN npm;
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS class_matkul (
+    class_id INTEGER NOT NULL REFERENCES class (id),
+    matkul_id INTEGER NOT NULL REFERENCES matakuliah (id),
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP NULL,
+    PRIMARY KEY (class_id, matkul_id)
+);
+
+CREATE INDEX IF NOT EXISTS class_matkul_matkul_id ON class_matkul (matkul_id);
+
+CREATE TABLE IF NOT EXISTS material_topic (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    matkul_id INTEGER NOT NULL REFERENCES matakuliah (id),
+    week INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    created_by INTEGER NULL,
+    updated_by INTEGER NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS material_topic_week ON material_topic (matkul_id, week);
+
+CREATE TABLE IF NOT EXISTS material (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    topic_id INTEGER NOT NULL REFERENCES material_topic (id),
+    kind TEXT NOT NULL CHECK (kind IN ('file', 'link')),
+    title TEXT NOT NULL,
+    url TEXT NULL,
+    file_key TEXT NULL,
+    file_name TEXT NULL,
+    content_type TEXT NULL,
+    size INTEGER NULL,
+    created_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NULL,
+    created_by INTEGER NULL,
+    updated_by INTEGER NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS material_topic_id ON material (topic_id);
@@ -0,0 +1,5 @@
+// Package proto holds the protobuf definition of the gRPC API; the Go code
+// generated from it lives in proto/pb.
+package proto
+
+//go:generate protoc --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative sinaustudio.proto
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+)
+
+// Every row records when and by whom it was created and last modified. The
+// user comes from the principal stored in ctx with ContextWithActor; writes
+// without one leave created_by and updated_by NULL.
+
+type actorKey struct{}
+
+// ContextWithActor records userID as the user the writes made under ctx act for.
+func ContextWithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// Stamp returns the time and user a write made under ctx is recorded with.
+// The time keeps whole seconds only, like a MySQL DATETIME.
+func Stamp(ctx context.Context) (time.Time, *int) {
+	now := time.Now().UTC().Truncate(time.Second)
+	if userID, ok := ctx.Value(actorKey{}).(int); ok {
+		return now, &userID
+	}
+	return now, nil
+}
+
+// auditColumns are the audit columns in the order scanAudited reads them.
+const auditColumns = "created_at, updated_at, created_by, updated_by"
+
+// insertAudit stamps a new row and returns the values for the audit columns.
+func insertAudit(ctx context.Context, audit *domain.Audit) []any {
+	*audit = domain.NewAudit(Stamp(ctx))
+	return []any{*audit.CreatedAt, *audit.UpdatedAt, intValue(audit.CreatedBy), intValue(audit.UpdatedBy)}
+}
+
+// touch returns the SET clause every update adds: a new version and the
+// updated_at / updated_by stamp.
+func touch(ctx context.Context) (string, []any) {
+	at, by := Stamp(ctx)
+	return "version = version + 1, updated_at = ?, updated_by = ?", []any{at, intValue(by)}
+}
+
+func intValue(n *int) any {
+	if n == nil {
+		return nil
+	}
+	return *n
+}
+
+// auditScanner receives the audit columns of a row.
+type auditScanner struct {
+	createdAt, updatedAt nullTime
+	createdBy, updatedBy sql.NullInt64
+}
+
+func (a *auditScanner) dest() []any {
+	return []any{&a.createdAt, &a.updatedAt, &a.createdBy, &a.updatedBy}
+}
+
+func (a *auditScanner) audit() domain.Audit {
+	return domain.Audit{CreatedAt: a.createdAt.Ptr(), UpdatedAt: a.updatedAt.Ptr(), CreatedBy: intPtr(a.createdBy), UpdatedBy: intPtr(a.updatedBy)}
+}
+
+func intPtr(n sql.NullInt64) *int {
+	if !n.Valid {
+		return nil
+	}
+	v := int(n.Int64)
+	return &v
+}
+
+// scanAudited scans a row whose audit columns follow dest.
+func scanAudited(rows *sql.Rows, audit *domain.Audit, dest ...any) error {
+	var scanner auditScanner
+	if err := rows.Scan(append(dest, scanner.dest()...)...); err != nil {
+		return err
+	}
+	*audit = scanner.audit()
+	return nil
+}
+
+var listOrder = map[string]string{"id": "id", "name": "name", "created_at": "created_at", "updated_at": "updated_at"}
+
+// listWhere returns the conditions of filter, to follow a WHERE clause, and
+// their arguments. The name always matches by part, like the listings did
+// before they took a filter.
+func (d Dialect) listWhere(filter *domain.ListFilter) (string, []any) {
+	conditions := []string{"name " + d.Like() + " ?"}
+	args := []any{"%" + filter.Name + "%"}
+	add := func(condition string, arg any) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+
+	if filter.CreatedBy != 0 {
+		add("created_by = ?", filter.CreatedBy)
+	}
+	if filter.UpdatedBy != 0 {
+		add("updated_by = ?", filter.UpdatedBy)
+	}
+	if filter.CreatedAfter != nil {
+		add("created_at > ?", filter.CreatedAfter.UTC())
+	}
+	if filter.CreatedBefore != nil {
+		add("created_at < ?", filter.CreatedBefore.UTC())
+	}
+	if filter.UpdatedAfter != nil {
+		add("updated_at > ?", filter.UpdatedAfter.UTC())
+	}
+	if filter.UpdatedBefore != nil {
+		add("updated_at < ?", filter.UpdatedBefore.UTC())
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// listOrderBy returns the ORDER BY clause of filter; unknown columns order by id.
+func listOrderBy(filter *domain.ListFilter) string {
+	direction := ""
+	if strings.HasPrefix(filter.Sort, "-") {
+		direction = " DESC"
+	}
+	column, ok := listOrder[strings.TrimPrefix(filter.Sort, "-")]
+	switch {
+	case !ok:
+		return "ORDER BY id"
+	case column == "id":
+		return "ORDER BY id" + direction
+	}
+	return "ORDER BY " + column + direction + ", id"
+}
@@ -16,31 +16,30 @@ type AuthRepository interface {
 }
 
 type AuthRepositoryImplementation struct {
+	Dialect Dialect
 }
 
-func NewAuthRepositoryImplementation() AuthRepository {
-	return &AuthRepositoryImplementation{}
+func NewAuthRepositoryImplementation(dialect Dialect) AuthRepository {
+	return &AuthRepositoryImplementation{Dialect: dialect}
 }
 
 func (a *AuthRepositoryImplementation) AuthRegisterUser(ctx context.Context, tx *sql.Tx, user *domain.AuthRegisterUser) (isSuccess bool, lastID int, errMsg *response.ErrorMsg) {
-	sqlQuery := "INSERT INTO users(name, email, password, class_id, role) VALUES (?,?,?,?,?)"
-	result, err := tx.ExecContext(ctx, sqlQuery, &user.Name, &user.Email, &user.Password, &user.ClassID, &user.Role)
+	var audit domain.Audit
+	sqlQuery := "INSERT INTO users(name, email, password, class_id, role, " + auditColumns + ") VALUES (?,?,?,?,?,?,?,?,?)"
+	args := append([]any{&user.Name, &user.Email, &user.Password, &user.ClassID, &user.Role}, insertAudit(ctx, &audit)...)
+	ID, err := a.Dialect.InsertID(ctx, tx, sqlQuery, args...)
 	if err != nil {
-		return false, 0, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
-	}
-	if err != nil {
-		return false, 0, nil
+		return false, 0, internalError(ctx, err)
 	}
 
-	ID, _ := result.LastInsertId()
 	return true, int(ID), nil
 }
 
 func (a *AuthRepositoryImplementation) AuthLoginUser(ctx context.Context, db *sql.DB, email string) (isSuccess bool, result *domain.AuthLoginUser, errMsg *response.ErrorMsg) {
-	sqlQuery := "SELECT id, email, password FROM users WHERE email = ?"
-	rows, err := db.QueryContext(ctx, sqlQuery, email)
+	sqlQuery := "SELECT id, email, password FROM users WHERE email = ? AND deleted_at IS NULL"
+	rows, err := db.QueryContext(ctx, a.Dialect.Rebind(sqlQuery), email)
 	if err != nil {
-		return false, nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return false, nil, internalError(ctx, err)
 	}
 	defer rows.Close()
 
@@ -48,7 +47,7 @@ func (a *AuthRepositoryImplementation) AuthLoginUser(ctx context.Context, db *sq
 	if rows.Next() {
 		err := rows.Scan(&user.ID, &user.Email, &user.Password)
 		if err != nil {
-			return false, nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+			return false, nil, internalError(ctx, err)
 		}
 		return true, &user, nil
 	} else {
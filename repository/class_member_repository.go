@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+)
+
+type ClassMemberRepository interface {
+	AddClassMember(ctx context.Context, tx *sql.Tx, member *domain.ClassMember) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteClassMember(ctx context.Context, tx *sql.Tx, classID, userID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	IsClassMember(ctx context.Context, db *sql.DB, classID, userID int) (isMember bool, errMsg *response.ErrorMsg)
+	FindClassMembers(ctx context.Context, db *sql.DB, classID int, role string) (members []*domain.ClassMember, errMsg *response.ErrorMsg)
+	PurgeClassMembers(ctx context.Context, tx *sql.Tx, before time.Time) (purged int64, errMsg *response.ErrorMsg)
+}
+
+type ClassMemberRepositoryImplementation struct {
+	Dialect Dialect
+}
+
+func NewClassMemberRepositoryImplementation(dialect Dialect) ClassMemberRepository {
+	return &ClassMemberRepositoryImplementation{Dialect: dialect}
+}
+
+func (c *ClassMemberRepositoryImplementation) AddClassMember(ctx context.Context, tx *sql.Tx, member *domain.ClassMember) (bool, *response.ErrorMsg) {
+	querySql := "INSERT INTO class_member(class_id, user_id, joined_at) VALUES(?, ?, ?)"
+	_, err := tx.ExecContext(ctx, c.Dialect.Rebind(querySql), member.ClassID, member.UserID, timeValue(&member.JoinedAt))
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	return true, nil
+}
+
+func (c *ClassMemberRepositoryImplementation) DeleteClassMember(ctx context.Context, tx *sql.Tx, classID, userID int) (bool, *response.ErrorMsg) {
+	querySql := "DELETE FROM class_member WHERE class_id = ? AND user_id = ?"
+	_, err := tx.ExecContext(ctx, c.Dialect.Rebind(querySql), classID, userID)
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	return true, nil
+}
+
+func (c *ClassMemberRepositoryImplementation) IsClassMember(ctx context.Context, db *sql.DB, classID, userID int) (bool, *response.ErrorMsg) {
+	querySql := "SELECT 1 FROM class_member JOIN users ON users.id = class_member.user_id WHERE class_member.class_id = ? AND class_member.user_id = ? AND users.deleted_at IS NULL"
+	rows, err := db.QueryContext(ctx, c.Dialect.Rebind(querySql), classID, userID)
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	return rows.Next(), nil
+}
+
+// FindClassMembers lists the members of the class with their user, oldest
+// first; an empty role lists every role.
+func (c *ClassMemberRepositoryImplementation) FindClassMembers(ctx context.Context, db *sql.DB, classID int, role string) ([]*domain.ClassMember, *response.ErrorMsg) {
+	querySql := "SELECT class_member.class_id, class_member.user_id, users.name, users.email, users.role, class_member.joined_at FROM class_member " +
+		"JOIN users ON users.id = class_member.user_id WHERE class_member.class_id = ? AND users.deleted_at IS NULL"
+	args := []any{classID}
+	if role != "" {
+		querySql += " AND users.role = ?"
+		args = append(args, role)
+	}
+	querySql += " ORDER BY class_member.joined_at, class_member.user_id"
+
+	rows, err := db.QueryContext(ctx, c.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var members []*domain.ClassMember
+	for rows.Next() {
+		var member domain.ClassMember
+		var joinedAt nullTime
+		if err := rows.Scan(&member.ClassID, &member.UserID, &member.Name, &member.Email, &member.Role, &joinedAt); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		member.JoinedAt = joinedAt.Time
+		members = append(members, &member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ctx, err)
+	}
+
+	return members, nil
+}
+
+// PurgeClassMembers removes the memberships of users and classes soft deleted
+// before before; memberships are kept while either side can still be restored.
+func (c *ClassMemberRepositoryImplementation) PurgeClassMembers(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	querySql := "DELETE FROM class_member WHERE user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?) " +
+		"OR class_id IN (SELECT id FROM class WHERE deleted_at IS NOT NULL AND deleted_at < ?)"
+	result, err := tx.ExecContext(ctx, c.Dialect.Rebind(querySql), before.UTC(), before.UTC())
+	if err != nil {
+		return 0, internalError(ctx, err)
+	}
+	n, _ := result.RowsAffected()
+	return n, nil
+}
@@ -8,82 +8,161 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"net/http"
+	"time"
 )
 
 type ClassRepository interface {
 	InsertClass(ctx context.Context, tx *sql.Tx, class *domain.Class) (isSuccess bool, errMsg *response.ErrorMsg)
 	UpdateClass(ctx context.Context, tx *sql.Tx, class *domain.Class) (isSuccess bool, errMsg *response.ErrorMsg)
-	DeleteClassByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteClassByID(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	RestoreClassByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindDeletedClassByID(ctx context.Context, db *sql.DB, ID int) (*domain.Class, bool, *response.ErrorMsg)
+	PurgeClass(ctx context.Context, tx *sql.Tx, before time.Time) (purged int64, errMsg *response.ErrorMsg)
 	FindClassByID(ctx context.Context, db *sql.DB, ID int) (*domain.Class, bool, *response.ErrorMsg)
 	FindClassByName(ctx context.Context, db *sql.DB, name string) (*domain.Class, bool, *response.ErrorMsg)
+	FindAllClass(ctx context.Context, db *sql.DB, filter *domain.ListFilter) (classes []*domain.Class, errMsg *response.ErrorMsg)
+	EachClass(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(class *domain.Class) error) (errMsg *response.ErrorMsg)
+	FindClassByIDs(ctx context.Context, db *sql.DB, IDs []int) (classes []*domain.Class, errMsg *response.ErrorMsg)
+	FindClassByKode(ctx context.Context, db *sql.DB, kodeKelas string) (*domain.Class, bool, *response.ErrorMsg)
+	UpdateKodeKelas(ctx context.Context, tx *sql.Tx, class *domain.Class) (isSuccess bool, errMsg *response.ErrorMsg)
 }
 
 type ClassRepositoryImplementation struct {
+	Dialect Dialect
 }
 
-func NewClassRepositoryImplementation() ClassRepository {
-	return &ClassRepositoryImplementation{}
+func NewClassRepositoryImplementation(dialect Dialect) ClassRepository {
+	return &ClassRepositoryImplementation{Dialect: dialect}
 }
 
 func (c *ClassRepositoryImplementation) InsertClass(ctx context.Context, tx *sql.Tx, class *domain.Class) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "INSERT INTO class(name, kode_kelas) VALUES(?, ?)"
-	_, err := tx.ExecContext(ctx, querySql, &class.Name, &class.KodeKelas)
+	querySql := "INSERT INTO class(name, kode_kelas, kode_kelas_expires_at, " + auditColumns + ") VALUES(?, ?, ?, ?, ?, ?, ?)"
+	args := append([]any{&class.Name, &class.KodeKelas, timeValue(class.KodeKelasExpiresAt)}, insertAudit(ctx, &class.Audit)...)
+	ID, err := c.Dialect.InsertID(ctx, tx, querySql, args...)
 	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return false, c.writeError(ctx, err)
 	}
+	class.ID = int(ID)
+	class.Version = 1
 	return true, nil
 }
 
 func (c *ClassRepositoryImplementation) UpdateClass(ctx context.Context, tx *sql.Tx, class *domain.Class) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "UPDATE class SET name = ? WHERE id = ?"
-	_, err := tx.ExecContext(ctx, querySql, &class.Name, &class.ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	if errMsg := c.Dialect.updateVersioned(ctx, tx, "class", "name = ?", "id = ?", class.Version, class.Name, class.ID); errMsg != nil {
+		return false, errMsg
+	}
+	if class.Version != 0 {
+		class.Version++
 	}
 	return true, nil
 }
 
-func (c *ClassRepositoryImplementation) DeleteClassByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "DELETE FROM class WHERE id = ?"
-	_, err := tx.ExecContext(ctx, querySql, ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+func (c *ClassRepositoryImplementation) DeleteClassByID(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := c.Dialect.softDelete(ctx, tx, "class", "id = ?", deletedAt, ID); errMsg != nil {
+		return false, errMsg
 	}
 	return true, nil
 }
 
+func (c *ClassRepositoryImplementation) RestoreClassByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := c.Dialect.restore(ctx, tx, "class", "id = ?", ID); errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+func (c *ClassRepositoryImplementation) FindDeletedClassByID(ctx context.Context, db *sql.DB, ID int) (*domain.Class, bool, *response.ErrorMsg) {
+	querySql := "SELECT id, name, kode_kelas, kode_kelas_expires_at, version, deleted_at FROM class WHERE id = ? AND deleted_at IS NOT NULL"
+	rows, err := db.QueryContext(ctx, c.Dialect.Rebind(querySql), ID)
+	if err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Kelas terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	var class domain.Class
+	var expiresAt, deletedAt nullTime
+	if err := rows.Scan(&class.ID, &class.Name, &class.KodeKelas, &expiresAt, &class.Version, &deletedAt); err != nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_GET_DATA, err)
+	}
+	class.KodeKelasExpiresAt, class.DeletedAt = expiresAt.Ptr(), deletedAt.Ptr()
+	return &class, true, nil
+}
+
+func (c *ClassRepositoryImplementation) PurgeClass(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	return c.Dialect.purge(ctx, tx, "class", before)
+}
+
 func (c *ClassRepositoryImplementation) FindClassByID(ctx context.Context, db *sql.DB, ID int) (*domain.Class, bool, *response.ErrorMsg) {
-	querySql := "SELECT id, name, kode_kelas FROM class WHERE id = ?"
-	row, err := db.QueryContext(ctx, querySql, ID)
+	querySql := "SELECT id, name, kode_kelas, kode_kelas_expires_at, version, " + auditColumns + " FROM class WHERE id = ? AND deleted_at IS NULL"
+	return c.findClass(ctx, db, querySql, ID, "Data Class By ID tidak ditemukan.")
+}
+
+func (c *ClassRepositoryImplementation) FindClassByKode(ctx context.Context, db *sql.DB, kodeKelas string) (*domain.Class, bool, *response.ErrorMsg) {
+	querySql := "SELECT id, name, kode_kelas, kode_kelas_expires_at, version, " + auditColumns + " FROM class WHERE kode_kelas = ? AND deleted_at IS NULL"
+	return c.findClass(ctx, db, querySql, kodeKelas, "Kode kelas tidak ditemukan.")
+}
+
+func (c *ClassRepositoryImplementation) findClass(ctx context.Context, db *sql.DB, querySql string, arg any, notFound string) (*domain.Class, bool, *response.ErrorMsg) {
+	row, err := db.QueryContext(ctx, c.Dialect.Rebind(querySql), arg)
 	if err != nil {
-		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, false, internalError(ctx, err)
 	}
 	defer row.Close()
 
+	if !row.Next() {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, notFound)
+	}
+	class, err := scanClass(row)
+	if err != nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_GET_DATA, err)
+	}
+	return class, true, nil
+}
+
+func (c *ClassRepositoryImplementation) UpdateKodeKelas(ctx context.Context, tx *sql.Tx, class *domain.Class) (bool, *response.ErrorMsg) {
+	stamp, args := touch(ctx)
+	querySql := "UPDATE class SET " + stamp + ", kode_kelas = ?, kode_kelas_expires_at = ? WHERE id = ? AND deleted_at IS NULL"
+	_, err := tx.ExecContext(ctx, c.Dialect.Rebind(querySql), append(args, class.KodeKelas, timeValue(class.KodeKelasExpiresAt), class.ID)...)
+	if err != nil {
+		return false, c.writeError(ctx, err)
+	}
+	return true, nil
+}
+
+// writeError reports a kode kelas taken by another class as ERR_ALREADY_USE,
+// so the caller can draw a new code and retry.
+func (c *ClassRepositoryImplementation) writeError(ctx context.Context, err error) *response.ErrorMsg {
+	if c.Dialect.IsUniqueViolation(err) {
+		return helpers.ToErrorMsg(http.StatusConflict, exception.ERR_ALREADY_USE, "Kode kelas sudah digunakan.")
+	}
+	return internalError(ctx, err)
+}
+
+// scanClass reads id, name, kode_kelas, kode_kelas_expires_at, version and the audit columns.
+func scanClass(rows *sql.Rows) (*domain.Class, error) {
 	var class domain.Class
-	if row.Next() {
-		err := row.Scan(&class.ID, &class.Name, &class.KodeKelas)
-		if err != nil {
-			return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_GET_DATA, err)
-		} else {
-			return &class, true, nil
-		}
-	} else {
-		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Data Class By ID tidak ditemukan.")
+	var expiresAt nullTime
+	if err := scanAudited(rows, &class.Audit, &class.ID, &class.Name, &class.KodeKelas, &expiresAt, &class.Version); err != nil {
+		return nil, err
 	}
+	class.KodeKelasExpiresAt = expiresAt.Ptr()
+	return &class, nil
 }
 
 func (c *ClassRepositoryImplementation) FindClassByName(ctx context.Context, db *sql.DB, name string) (*domain.Class, bool, *response.ErrorMsg) {
-	querySql := "SELECT id, name FROM class WHERE name = ?"
-	row, err := db.QueryContext(ctx, querySql, name)
+	querySql := "SELECT id, name, version FROM class WHERE name = ? AND deleted_at IS NULL"
+	row, err := db.QueryContext(ctx, c.Dialect.Rebind(querySql), name)
 	if err != nil {
-		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, false, internalError(ctx, err)
 	}
 	defer row.Close()
 
 	var class domain.Class
 	if row.Next() {
-		err := row.Scan(&class.ID, &class.Name)
+		err := row.Scan(&class.ID, &class.Name, &class.Version)
 		if err != nil {
 			return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_GET_DATA, err)
 		} else {
@@ -93,3 +172,66 @@ func (c *ClassRepositoryImplementation) FindClassByName(ctx context.Context, db
 		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Data Class By ID tidak ditemukan.")
 	}
 }
+
+func (c *ClassRepositoryImplementation) FindAllClass(ctx context.Context, db *sql.DB, filter *domain.ListFilter) ([]*domain.Class, *response.ErrorMsg) {
+	var classes []*domain.Class
+	errMsg := c.EachClass(ctx, db, filter, func(class *domain.Class) error {
+		classes = append(classes, class)
+		return nil
+	})
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return classes, nil
+}
+
+// EachClass calls fn for every class matching filter while the rows are read,
+// stopping at the first error fn returns.
+func (c *ClassRepositoryImplementation) EachClass(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(class *domain.Class) error) *response.ErrorMsg {
+	where, args := c.Dialect.listWhere(filter)
+	querySql := "SELECT id, name, kode_kelas, kode_kelas_expires_at, version, " + auditColumns + " FROM class WHERE " + where + " AND deleted_at IS NULL " + listOrderBy(filter)
+	rows, err := db.QueryContext(ctx, c.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		class, err := scanClass(rows)
+		if err != nil {
+			return internalError(ctx, err)
+		}
+		if err := fn(class); err != nil {
+			return internalError(ctx, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return internalError(ctx, err)
+	}
+
+	return nil
+}
+
+func (c *ClassRepositoryImplementation) FindClassByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Class, *response.ErrorMsg) {
+	if len(IDs) == 0 {
+		return nil, nil
+	}
+
+	querySql := "SELECT id, name, kode_kelas, kode_kelas_expires_at, version, " + auditColumns + " FROM class WHERE id IN " + inClause(len(IDs)) + " AND deleted_at IS NULL ORDER BY id"
+	rows, err := db.QueryContext(ctx, c.Dialect.Rebind(querySql), intArgs(IDs)...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var classes []*domain.Class
+	for rows.Next() {
+		class, err := scanClass(rows)
+		if err != nil {
+			return nil, internalError(ctx, err)
+		}
+		classes = append(classes, class)
+	}
+
+	return classes, nil
+}
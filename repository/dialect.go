@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite3"
+)
+
+func ParseDialect(driver string) (Dialect, error) {
+	switch strings.ToLower(driver) {
+	case "", "mysql":
+		return MySQL, nil
+	case "postgres", "postgresql", "pgx":
+		return Postgres, nil
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	default:
+		return "", fmt.Errorf("dialect %q tidak didukung", driver)
+	}
+}
+
+// DriverName is the database/sql driver registered for the dialect.
+func (d Dialect) DriverName() string {
+	return string(d)
+}
+
+// Rebind rewrites the '?' placeholders used by every repository query into the
+// bind style of the dialect. MySQL and SQLite keep '?', Postgres uses $1..$n.
+// A '?' inside a quoted string literal is left as it is.
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	quoted := false
+	for i := 0; i < len(query); i++ {
+		if query[i] == '\'' {
+			quoted = !quoted
+		}
+		if query[i] == '?' && !quoted {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(query[i])
+	}
+	return b.String()
+}
+
+// Like is the case-insensitive LIKE operator of the dialect.
+func (d Dialect) Like() string {
+	if d == Postgres {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// InsertID executes an INSERT and returns the generated id. Postgres has no
+// LastInsertId support, so the id is read back with RETURNING.
+func (d Dialect) InsertID(ctx context.Context, tx *sql.Tx, query string, args ...any) (int64, error) {
+	if d == Postgres {
+		var ID int64
+		err := tx.QueryRowContext(ctx, d.Rebind(query+" RETURNING id"), args...).Scan(&ID)
+		return ID, err
+	}
+
+	result, err := tx.ExecContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// IsUniqueViolation reports whether err is a driver error for a duplicate key
+// in a unique index or primary key.
+func (d Dialect) IsUniqueViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	var pqErr *pq.Error
+	var sqliteErr sqlite3.Error
+	switch {
+	case errors.As(err, &mysqlErr):
+		return mysqlErr.Number == 1062
+	case errors.As(err, &pqErr):
+		return pqErr.Code == "23505"
+	case errors.As(err, &sqliteErr):
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+	return false
+}
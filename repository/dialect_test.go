@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		query   string
+		want    string
+	}{
+		{"mysql keeps ?", MySQL, "SELECT id FROM room WHERE id = ? AND lecture_id = ?", "SELECT id FROM room WHERE id = ? AND lecture_id = ?"},
+		{"sqlite keeps ?", SQLite, "SELECT id FROM room WHERE id = ?", "SELECT id FROM room WHERE id = ?"},
+		{"postgres numbers ?", Postgres, "UPDATE room SET name = ?, url = ? WHERE id = ?", "UPDATE room SET name = $1, url = $2 WHERE id = $3"},
+		{"postgres without ?", Postgres, "SELECT id FROM room", "SELECT id FROM room"},
+		{"postgres skips ? in literal", Postgres, "SELECT id FROM room WHERE url = 'https://meet.example.com/?room=1' AND id = ?", "SELECT id FROM room WHERE url = 'https://meet.example.com/?room=1' AND id = $1"},
+		{"postgres skips ? after escaped quote", Postgres, "SELECT id FROM room WHERE name = 'Kenapa ''Algoritma''?' AND id = ?", "SELECT id FROM room WHERE name = 'Kenapa ''Algoritma''?' AND id = $1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.Rebind(tt.query); got != tt.want {
+				t.Fatalf("Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInsertID(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{MySQL, "INSERT INTO class (name, kode_kelas) VALUES (?, ?)"},
+		{SQLite, "INSERT INTO class (name, kode_kelas) VALUES (?, ?)"},
+		{Postgres, "INSERT INTO class (name, kode_kelas) VALUES ($1, $2) RETURNING id"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			recorder := &recordingConn{}
+			db := sql.OpenDB(recorder)
+			defer db.Close()
+
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tx.Rollback()
+
+			ID, err := tt.dialect.InsertID(context.Background(), tx, "INSERT INTO class (name, kode_kelas) VALUES (?, ?)", "TI-1A", "ABCDEFGHJK")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ID != insertedID || recorder.query != tt.want {
+				t.Fatalf("expected id %d from %q, got %d from %q", insertedID, tt.want, ID, recorder.query)
+			}
+		})
+	}
+}
+
+// insertedID is the id recordingConn answers for every insert.
+const insertedID = 7
+
+// recordingConn is a database/sql driver that records the last statement it
+// runs and answers insertedID, through LastInsertId or a RETURNING row.
+type recordingConn struct {
+	query string
+}
+
+func (c *recordingConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c *recordingConn) Driver() driver.Driver                        { return nil }
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("recording: prepare tidak didukung")
+}
+func (c *recordingConn) Close() error              { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *recordingConn) Commit() error             { return nil }
+func (c *recordingConn) Rollback() error           { return nil }
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.query = query
+	return insertResult{}, nil
+}
+
+type insertResult struct{}
+
+func (insertResult) LastInsertId() (int64, error) { return insertedID, nil }
+func (insertResult) RowsAffected() (int64, error) { return 1, nil }
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.query = query
+	return &idRows{}, nil
+}
+
+type idRows struct {
+	done bool
+}
+
+func (r *idRows) Columns() []string { return []string{"id"} }
+func (r *idRows) Close() error      { return nil }
+func (r *idRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(insertedID)
+	return nil
+}
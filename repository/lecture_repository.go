@@ -8,62 +8,114 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"net/http"
+	"time"
 )
 
 type LectureRepository interface {
 	InsertLecture(ctx context.Context, tx *sql.Tx, lecture *domain.Lecture) (isSuccess bool, errMsg *response.ErrorMsg)
 	UpdateLecture(ctx context.Context, tx *sql.Tx, lecture *domain.Lecture) (isSuccess bool, errMsg *response.ErrorMsg)
-	DeleteLectureByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteLectureByID(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	RestoreLectureByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindDeletedLectureByID(ctx context.Context, db *sql.DB, ID int) (lecture *domain.Lecture, isRegistered bool, errMsg *response.ErrorMsg)
+	FindDeletedLectureByUserID(ctx context.Context, db *sql.DB, userID int) (lecture *domain.Lecture, isRegistered bool, errMsg *response.ErrorMsg)
+	PurgeLecture(ctx context.Context, tx *sql.Tx, before time.Time) (purged int64, errMsg *response.ErrorMsg)
 	FindLectureByID(ctx context.Context, db *sql.DB, ID int) (lecture *domain.Lecture, isRegistered bool, errMsg *response.ErrorMsg)
 	FindLectureByUserID(ctx context.Context, db *sql.DB, userID int) (lecture *domain.Lecture, isRegistered bool, errMsg *response.ErrorMsg)
 	FindLectureByName(ctx context.Context, db *sql.DB, name string) (lecture *domain.Lecture, isRegistered bool, errMsg *response.ErrorMsg)
+	FindAllLecture(ctx context.Context, db *sql.DB, filter *domain.ListFilter) (lectures []*domain.Lecture, errMsg *response.ErrorMsg)
+	EachLecture(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(lecture *domain.Lecture) error) (errMsg *response.ErrorMsg)
+	FindLectureByIDs(ctx context.Context, db *sql.DB, IDs []int) (lectures []*domain.Lecture, errMsg *response.ErrorMsg)
+	FindLectureByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (lectures map[int][]*domain.Lecture, errMsg *response.ErrorMsg)
 }
 
 type LectureRepositoryImplementation struct {
+	Dialect Dialect
 }
 
-func NewLectureRepositoryImplementation() LectureRepository {
-	return &LectureRepositoryImplementation{}
+func NewLectureRepositoryImplementation(dialect Dialect) LectureRepository {
+	return &LectureRepositoryImplementation{Dialect: dialect}
 }
 
 func (l *LectureRepositoryImplementation) InsertLecture(ctx context.Context, tx *sql.Tx, lecture *domain.Lecture) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "INSERT INTO lecture(name, user_id) VALUES(?, ?)"
-	_, err := tx.ExecContext(ctx, querySql, lecture.Name, lecture.UserID)
+	querySql := "INSERT INTO lecture(name, user_id, " + auditColumns + ") VALUES(?, ?, ?, ?, ?, ?)"
+	args := append([]any{lecture.Name, lecture.UserID}, insertAudit(ctx, &lecture.Audit)...)
+	ID, err := l.Dialect.InsertID(ctx, tx, querySql, args...)
 	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return false, internalError(ctx, err)
 	}
+	lecture.ID = int(ID)
+	lecture.Version = 1
 	return true, nil
 }
 
 func (l *LectureRepositoryImplementation) UpdateLecture(ctx context.Context, tx *sql.Tx, lecture *domain.Lecture) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "UPDATE lecture SET name = ? WHERE id = ?"
-	_, err := tx.ExecContext(ctx, querySql, &lecture.Name, &lecture.ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	if errMsg := l.Dialect.updateVersioned(ctx, tx, "lecture", "name = ?", "id = ?", lecture.Version, lecture.Name, lecture.ID); errMsg != nil {
+		return false, errMsg
+	}
+	if lecture.Version != 0 {
+		lecture.Version++
 	}
 	return true, nil
 }
 
-func (l *LectureRepositoryImplementation) DeleteLectureByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "DELETE FROM lecture WHERE id = ?"
-	_, err := tx.ExecContext(ctx, querySql, ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+func (l *LectureRepositoryImplementation) DeleteLectureByID(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := l.Dialect.softDelete(ctx, tx, "lecture", "id = ?", deletedAt, ID); errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+func (l *LectureRepositoryImplementation) RestoreLectureByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := l.Dialect.restore(ctx, tx, "lecture", "id = ?", ID); errMsg != nil {
+		return false, errMsg
 	}
 	return true, nil
 }
 
+func (l *LectureRepositoryImplementation) FindDeletedLectureByID(ctx context.Context, db *sql.DB, ID int) (*domain.Lecture, bool, *response.ErrorMsg) {
+	return l.findDeletedLecture(ctx, db, "id = ?", ID)
+}
+
+// FindDeletedLectureByUserID returns the lecture of userID deleted most recently.
+func (l *LectureRepositoryImplementation) FindDeletedLectureByUserID(ctx context.Context, db *sql.DB, userID int) (*domain.Lecture, bool, *response.ErrorMsg) {
+	return l.findDeletedLecture(ctx, db, "user_id = ?", userID)
+}
+
+func (l *LectureRepositoryImplementation) findDeletedLecture(ctx context.Context, db *sql.DB, where string, args ...any) (*domain.Lecture, bool, *response.ErrorMsg) {
+	querySql := "SELECT id, name, user_id, version, deleted_at FROM lecture WHERE " + where + " AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC"
+	rows, err := db.QueryContext(ctx, l.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	var lecture domain.Lecture
+	var deletedAt nullTime
+	if err := rows.Scan(&lecture.ID, &lecture.Name, &lecture.UserID, &lecture.Version, &deletedAt); err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	lecture.DeletedAt = deletedAt.Ptr()
+	return &lecture, true, nil
+}
+
+func (l *LectureRepositoryImplementation) PurgeLecture(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	return l.Dialect.purge(ctx, tx, "lecture", before)
+}
+
 func (l *LectureRepositoryImplementation) FindLectureByID(ctx context.Context, db *sql.DB, ID int) (*domain.Lecture, bool, *response.ErrorMsg) {
-	querySql := "SELECT id, name FROM lecture WHERE id = ?"
-	row, err := db.QueryContext(ctx, querySql, ID)
+	querySql := "SELECT id, name, user_id, version, " + auditColumns + " FROM lecture WHERE id = ? AND deleted_at IS NULL"
+	row, err := db.QueryContext(ctx, l.Dialect.Rebind(querySql), ID)
 	if err != nil {
-		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, false, internalError(ctx, err)
 	}
 	defer row.Close()
 
 	var lecture domain.Lecture
 	if row.Next() {
-		err := row.Scan(&lecture.ID, &lecture.Name)
+		err := scanAudited(row, &lecture.Audit, &lecture.ID, &lecture.Name, &lecture.UserID, &lecture.Version)
 		if err != nil {
 			return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, err)
 		} else {
@@ -75,16 +127,16 @@ func (l *LectureRepositoryImplementation) FindLectureByID(ctx context.Context, d
 }
 
 func (l *LectureRepositoryImplementation) FindLectureByUserID(ctx context.Context, db *sql.DB, userID int) (*domain.Lecture, bool, *response.ErrorMsg) {
-	querySql := "SELECT id, name, user_id FROM lecture WHERE user_id = ?"
-	row, err := db.QueryContext(ctx, querySql, userID)
+	querySql := "SELECT id, name, user_id, version, " + auditColumns + " FROM lecture WHERE user_id = ? AND deleted_at IS NULL"
+	row, err := db.QueryContext(ctx, l.Dialect.Rebind(querySql), userID)
 	if err != nil {
-		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, false, internalError(ctx, err)
 	}
 	defer row.Close()
 
 	var lecture domain.Lecture
 	if row.Next() {
-		err := row.Scan(&lecture.ID, &lecture.Name, &lecture.UserID)
+		err := scanAudited(row, &lecture.Audit, &lecture.ID, &lecture.Name, &lecture.UserID, &lecture.Version)
 		if err != nil {
 			return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, err)
 		} else {
@@ -96,16 +148,16 @@ func (l *LectureRepositoryImplementation) FindLectureByUserID(ctx context.Contex
 }
 
 func (l *LectureRepositoryImplementation) FindLectureByName(ctx context.Context, db *sql.DB, name string) (*domain.Lecture, bool, *response.ErrorMsg) {
-	querySql := "SELECT id, name FROM lecture WHERE name = ?"
-	row, err := db.QueryContext(ctx, querySql, name)
+	querySql := "SELECT id, name, user_id, version, " + auditColumns + " FROM lecture WHERE name = ? AND deleted_at IS NULL"
+	row, err := db.QueryContext(ctx, l.Dialect.Rebind(querySql), name)
 	if err != nil {
-		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, false, internalError(ctx, err)
 	}
 	defer row.Close()
 
 	var lecture domain.Lecture
 	if row.Next() {
-		err := row.Scan(&lecture.ID, &lecture.Name)
+		err := scanAudited(row, &lecture.Audit, &lecture.ID, &lecture.Name, &lecture.UserID, &lecture.Version)
 		if err != nil {
 			return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, err)
 		} else {
@@ -115,3 +167,95 @@ func (l *LectureRepositoryImplementation) FindLectureByName(ctx context.Context,
 		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen dengan Nama tidak ditemukan.")
 	}
 }
+
+func (l *LectureRepositoryImplementation) FindAllLecture(ctx context.Context, db *sql.DB, filter *domain.ListFilter) ([]*domain.Lecture, *response.ErrorMsg) {
+	var lectures []*domain.Lecture
+	errMsg := l.EachLecture(ctx, db, filter, func(lecture *domain.Lecture) error {
+		lectures = append(lectures, lecture)
+		return nil
+	})
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return lectures, nil
+}
+
+// EachLecture calls fn for every lecture matching filter while the rows are
+// read, stopping at the first error fn returns.
+func (l *LectureRepositoryImplementation) EachLecture(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(lecture *domain.Lecture) error) *response.ErrorMsg {
+	where, args := l.Dialect.listWhere(filter)
+	profileWhere, profileArgs := l.Dialect.profileWhere("user_id", filter.Profile)
+	querySql := "SELECT id, name, user_id, version, " + auditColumns + " FROM lecture WHERE " + where + profileWhere + " AND deleted_at IS NULL " + listOrderBy(filter)
+	args = append(args, profileArgs...)
+	rows, err := db.QueryContext(ctx, l.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lecture domain.Lecture
+		err := scanAudited(rows, &lecture.Audit, &lecture.ID, &lecture.Name, &lecture.UserID, &lecture.Version)
+		if err != nil {
+			return internalError(ctx, err)
+		}
+		if err := fn(&lecture); err != nil {
+			return internalError(ctx, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return internalError(ctx, err)
+	}
+
+	return nil
+}
+
+func (l *LectureRepositoryImplementation) FindLectureByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Lecture, *response.ErrorMsg) {
+	if len(IDs) == 0 {
+		return nil, nil
+	}
+
+	querySql := "SELECT id, name, user_id, version, " + auditColumns + " FROM lecture WHERE id IN " + inClause(len(IDs)) + " AND deleted_at IS NULL ORDER BY id"
+	rows, err := db.QueryContext(ctx, l.Dialect.Rebind(querySql), intArgs(IDs)...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var lectures []*domain.Lecture
+	for rows.Next() {
+		var lecture domain.Lecture
+		if err := scanAudited(rows, &lecture.Audit, &lecture.ID, &lecture.Name, &lecture.UserID, &lecture.Version); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		lectures = append(lectures, &lecture)
+	}
+
+	return lectures, nil
+}
+
+// FindLectureByClassIDs groups by class the lectures whose user is a member of one of classIDs.
+func (l *LectureRepositoryImplementation) FindLectureByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (map[int][]*domain.Lecture, *response.ErrorMsg) {
+	lectures := map[int][]*domain.Lecture{}
+	if len(classIDs) == 0 {
+		return lectures, nil
+	}
+
+	querySql := "SELECT lecture.id, lecture.name, lecture.user_id, lecture.version, class_member.class_id FROM lecture JOIN class_member ON class_member.user_id = lecture.user_id WHERE class_member.class_id IN " + inClause(len(classIDs)) + " AND lecture.deleted_at IS NULL ORDER BY lecture.id"
+	rows, err := db.QueryContext(ctx, l.Dialect.Rebind(querySql), intArgs(classIDs)...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lecture domain.Lecture
+		var classID int
+		if err := rows.Scan(&lecture.ID, &lecture.Name, &lecture.UserID, &lecture.Version, &classID); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		lectures[classID] = append(lectures[classID], &lecture)
+	}
+
+	return lectures, nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+)
+
+// internalError logs a failed query with the request's logger, which carries
+// its request ID, and reports it to the service as a 500.
+func internalError(ctx context.Context, err error) *response.ErrorMsg {
+	logging.FromContext(ctx).Named("repository").Error("query gagal", "error", err)
+	return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+}
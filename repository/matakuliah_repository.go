@@ -8,56 +8,97 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"net/http"
+	"time"
 )
 
 type MataKuliahRepository interface {
 	InsertMatkul(ctx context.Context, tx *sql.Tx, matkul *domain.Matkul) (isSuccess bool, errMsg *response.ErrorMsg)
 	UpdateMatkul(ctx context.Context, tx *sql.Tx, matkul *domain.Matkul) (isSuccess bool, errMsg *response.ErrorMsg)
-	DeleteMatkulByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteMatkulByID(ctx context.Context, tx *sql.Tx, ID, version int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	RestoreMatkulByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindDeletedMatkulByID(ctx context.Context, db *sql.DB, ID int) (matkul *domain.Matkul, isRegistered bool, errMsg *response.ErrorMsg)
+	PurgeMatkul(ctx context.Context, tx *sql.Tx, before time.Time) (purged int64, errMsg *response.ErrorMsg)
 	FindMatkulByID(ctx context.Context, db *sql.DB, ID int) (matkul *domain.Matkul, isRegistered bool, errMsg *response.ErrorMsg)
-	FindMatkulByName(ctx context.Context, db *sql.DB, name string) (matkuls []*domain.Matkul, errMsg *response.ErrorMsg)
+	FindAllMatkul(ctx context.Context, db *sql.DB, filter *domain.ListFilter) (matkuls []*domain.Matkul, errMsg *response.ErrorMsg)
+	EachMatkul(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(matkul *domain.Matkul) error) (errMsg *response.ErrorMsg)
 }
 
 type MataKuliahRepositoryImplementation struct {
+	Dialect Dialect
 }
 
-func NewMataKuliahRepositoryImplementation() MataKuliahRepository {
-	return &MataKuliahRepositoryImplementation{}
+func NewMataKuliahRepositoryImplementation(dialect Dialect) MataKuliahRepository {
+	return &MataKuliahRepositoryImplementation{Dialect: dialect}
 }
 
 func (m *MataKuliahRepositoryImplementation) InsertMatkul(ctx context.Context, tx *sql.Tx, matkul *domain.Matkul) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "INSERT INTO matakuliah(name, kode_matkul) VALUES(?, ?)"
-	_, err := tx.ExecContext(ctx, querySql, &matkul.Name, &matkul.KodeMatkul)
+	querySql := "INSERT INTO matakuliah(name, kode_matkul, " + auditColumns + ") VALUES(?, ?, ?, ?, ?, ?)"
+	args := append([]any{&matkul.Name, &matkul.KodeMatkul}, insertAudit(ctx, &matkul.Audit)...)
+	ID, err := m.Dialect.InsertID(ctx, tx, querySql, args...)
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Internal Server Error")
 	}
+	matkul.ID = int(ID)
+	matkul.Version = 1
 
 	return true, nil
 }
 
 func (m *MataKuliahRepositoryImplementation) UpdateMatkul(ctx context.Context, tx *sql.Tx, matkul *domain.Matkul) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "UPDATE matakuliah SET name = ?, kode_matkul = ? WHERE id = ?"
-	_, err := tx.ExecContext(ctx, querySql, &matkul.Name, &matkul.KodeMatkul, matkul.ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Internal Server Error")
+	errMsg = m.Dialect.updateVersioned(ctx, tx, "matakuliah", "name = ?, kode_matkul = ?", "id = ?", matkul.Version, matkul.Name, matkul.KodeMatkul, matkul.ID)
+	if errMsg != nil {
+		return false, errMsg
 	}
 
+	if matkul.Version != 0 {
+		matkul.Version++
+	}
 	return true, nil
 }
 
-func (m *MataKuliahRepositoryImplementation) DeleteMatkulByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "DELETE FROM matakuliah WHERE id  = ?"
-	_, err := tx.ExecContext(ctx, querySql, ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Internal Server Error")
+func (m *MataKuliahRepositoryImplementation) DeleteMatkulByID(ctx context.Context, tx *sql.Tx, ID, version int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := m.Dialect.softDeleteVersioned(ctx, tx, "matakuliah", "id = ?", deletedAt, version, ID); errMsg != nil {
+		return false, errMsg
 	}
 
 	return true, nil
 }
 
+func (m *MataKuliahRepositoryImplementation) RestoreMatkulByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := m.Dialect.restore(ctx, tx, "matakuliah", "id = ?", ID); errMsg != nil {
+		return false, errMsg
+	}
+
+	return true, nil
+}
+
+func (m *MataKuliahRepositoryImplementation) FindDeletedMatkulByID(ctx context.Context, db *sql.DB, ID int) (*domain.Matkul, bool, *response.ErrorMsg) {
+	querySql := "SELECT id, name, kode_matkul, version, deleted_at FROM matakuliah WHERE id = ? AND deleted_at IS NOT NULL"
+	rows, err := db.QueryContext(ctx, m.Dialect.Rebind(querySql), ID)
+	if err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Matkul terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	var matkul domain.Matkul
+	var deletedAt nullTime
+	if err := rows.Scan(&matkul.ID, &matkul.Name, &matkul.KodeMatkul, &matkul.Version, &deletedAt); err != nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_GET_DATA, err)
+	}
+	matkul.DeletedAt = deletedAt.Ptr()
+	return &matkul, true, nil
+}
+
+func (m *MataKuliahRepositoryImplementation) PurgeMatkul(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	return m.Dialect.purge(ctx, tx, "matakuliah", before)
+}
+
 func (m *MataKuliahRepositoryImplementation) FindMatkulByID(ctx context.Context, db *sql.DB, ID int) (*domain.Matkul, bool, *response.ErrorMsg) {
-	querySql := "SELECT id, name, kode_matkul FROM matakuliah WHERE id = ?"
-	row, err := db.QueryContext(ctx, querySql, ID)
+	querySql := "SELECT id, name, kode_matkul, version, " + auditColumns + " FROM matakuliah WHERE id = ? AND deleted_at IS NULL"
+	row, err := db.QueryContext(ctx, m.Dialect.Rebind(querySql), ID)
 	if err != nil {
 		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Internal Server Error")
 	}
@@ -65,7 +106,7 @@ func (m *MataKuliahRepositoryImplementation) FindMatkulByID(ctx context.Context,
 
 	var matkul domain.Matkul
 	if row.Next() {
-		err := row.Scan(&matkul.ID, &matkul.Name, &matkul.KodeMatkul)
+		err := scanAudited(row, &matkul.Audit, &matkul.ID, &matkul.Name, &matkul.KodeMatkul, &matkul.Version)
 		if err != nil {
 			return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, err)
 		} else {
@@ -76,22 +117,42 @@ func (m *MataKuliahRepositoryImplementation) FindMatkulByID(ctx context.Context,
 	}
 }
 
-func (m *MataKuliahRepositoryImplementation) FindMatkulByName(ctx context.Context, db *sql.DB, name string) ([]*domain.Matkul, *response.ErrorMsg) {
-	rows, err := db.QueryContext(ctx, "SELECT id, name, kode_matkul FROM matakuliah WHERE name LIKE ?", "%"+name+"%")
+func (m *MataKuliahRepositoryImplementation) FindAllMatkul(ctx context.Context, db *sql.DB, filter *domain.ListFilter) ([]*domain.Matkul, *response.ErrorMsg) {
+	var matkuls []*domain.Matkul
+	errMsg := m.EachMatkul(ctx, db, filter, func(matkul *domain.Matkul) error {
+		matkuls = append(matkuls, matkul)
+		return nil
+	})
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return matkuls, nil
+}
+
+// EachMatkul calls fn for every mata kuliah matching filter while the rows are
+// read, stopping at the first error fn returns.
+func (m *MataKuliahRepositoryImplementation) EachMatkul(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(matkul *domain.Matkul) error) *response.ErrorMsg {
+	where, args := m.Dialect.listWhere(filter)
+	querySql := "SELECT id, name, kode_matkul, version, " + auditColumns + " FROM matakuliah WHERE " + where + " AND deleted_at IS NULL " + listOrderBy(filter)
+	rows, err := db.QueryContext(ctx, m.Dialect.Rebind(querySql), args...)
 	if err != nil {
-		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return internalError(ctx, err)
 	}
 	defer rows.Close()
 
-	var matkuls []*domain.Matkul
 	for rows.Next() {
 		var matkul domain.Matkul
-		err := rows.Scan(&matkul.ID, &matkul.Name, &matkul.KodeMatkul)
+		err := scanAudited(rows, &matkul.Audit, &matkul.ID, &matkul.Name, &matkul.KodeMatkul, &matkul.Version)
 		if err != nil {
-			return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+			return internalError(ctx, err)
 		}
-		matkuls = append(matkuls, &matkul)
+		if err := fn(&matkul); err != nil {
+			return internalError(ctx, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return internalError(ctx, err)
 	}
 
-	return matkuls, nil
+	return nil
 }
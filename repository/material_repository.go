@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+)
+
+// MaterialRepository keeps the materials library: the classes enrolled in a
+// matkul (class_matkul), its weekly topics and their materials.
+type MaterialRepository interface {
+	EnrollClass(ctx context.Context, tx *sql.Tx, classID, matkulID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	UnenrollClass(ctx context.Context, tx *sql.Tx, classID, matkulID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	IsEnrolled(ctx context.Context, db *sql.DB, classID, matkulID int) (isEnrolled bool, errMsg *response.ErrorMsg)
+	FindMatkulByClassID(ctx context.Context, db *sql.DB, classID int) (matkuls []*domain.Matkul, errMsg *response.ErrorMsg)
+	IsMatkulMember(ctx context.Context, db *sql.DB, matkulID, userID int) (isMember bool, errMsg *response.ErrorMsg)
+	InsertTopic(ctx context.Context, tx *sql.Tx, topic *domain.MaterialTopic) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteTopic(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindTopicByID(ctx context.Context, db *sql.DB, ID int) (topic *domain.MaterialTopic, isFound bool, errMsg *response.ErrorMsg)
+	FindTopicsByMatkulID(ctx context.Context, db *sql.DB, matkulID int) (topics []*domain.MaterialTopic, errMsg *response.ErrorMsg)
+	InsertMaterial(ctx context.Context, tx *sql.Tx, material *domain.Material) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteMaterial(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindMaterialByID(ctx context.Context, db *sql.DB, ID int) (material *domain.Material, isFound bool, errMsg *response.ErrorMsg)
+	FindMaterialsByMatkulID(ctx context.Context, db *sql.DB, matkulID int) (materials []*domain.Material, errMsg *response.ErrorMsg)
+	FindPurgedFileKeys(ctx context.Context, tx *sql.Tx, before time.Time) (fileKeys []string, errMsg *response.ErrorMsg)
+	PurgeMaterials(ctx context.Context, tx *sql.Tx, before time.Time) (purged int64, errMsg *response.ErrorMsg)
+}
+
+type MaterialRepositoryImplementation struct {
+	Dialect Dialect
+}
+
+func NewMaterialRepositoryImplementation(dialect Dialect) MaterialRepository {
+	return &MaterialRepositoryImplementation{Dialect: dialect}
+}
+
+// EnrollClass adds the enrolment, or brings back the one removed by
+// UnenrollClass while it is not purged yet.
+func (m *MaterialRepositoryImplementation) EnrollClass(ctx context.Context, tx *sql.Tx, classID, matkulID int) (bool, *response.ErrorMsg) {
+	querySql := "UPDATE class_matkul SET deleted_at = NULL, version = version + 1 WHERE class_id = ? AND matkul_id = ? AND deleted_at IS NOT NULL"
+	result, err := tx.ExecContext(ctx, m.Dialect.Rebind(querySql), classID, matkulID)
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return true, nil
+	}
+
+	querySql = "INSERT INTO class_matkul(class_id, matkul_id) VALUES(?, ?)"
+	_, err = tx.ExecContext(ctx, m.Dialect.Rebind(querySql), classID, matkulID)
+	if m.Dialect.IsUniqueViolation(err) {
+		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "Kelas sudah terdaftar di mata kuliah ini.")
+	}
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	return true, nil
+}
+
+// UnenrollClass soft deletes the enrolment. class_matkul has no audit
+// columns, so only the version moves.
+func (m *MaterialRepositoryImplementation) UnenrollClass(ctx context.Context, tx *sql.Tx, classID, matkulID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	querySql := "UPDATE class_matkul SET deleted_at = ?, version = version + 1 WHERE class_id = ? AND matkul_id = ? AND deleted_at IS NULL"
+	_, err := tx.ExecContext(ctx, m.Dialect.Rebind(querySql), deletedAt.UTC(), classID, matkulID)
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	return true, nil
+}
+
+func (m *MaterialRepositoryImplementation) IsEnrolled(ctx context.Context, db *sql.DB, classID, matkulID int) (bool, *response.ErrorMsg) {
+	return m.exists(ctx, db, "SELECT 1 FROM class_matkul WHERE class_id = ? AND matkul_id = ? AND deleted_at IS NULL", classID, matkulID)
+}
+
+// FindMatkulByClassID lists the live mata kuliah the class is enrolled in.
+func (m *MaterialRepositoryImplementation) FindMatkulByClassID(ctx context.Context, db *sql.DB, classID int) ([]*domain.Matkul, *response.ErrorMsg) {
+	querySql := "SELECT id, name, kode_matkul, version, " + auditColumns + " FROM matakuliah " +
+		"WHERE id IN (SELECT matkul_id FROM class_matkul WHERE class_id = ? AND deleted_at IS NULL) AND deleted_at IS NULL ORDER BY id"
+	rows, err := db.QueryContext(ctx, m.Dialect.Rebind(querySql), classID)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var matkuls []*domain.Matkul
+	for rows.Next() {
+		var matkul domain.Matkul
+		if err := scanAudited(rows, &matkul.Audit, &matkul.ID, &matkul.Name, &matkul.KodeMatkul, &matkul.Version); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		matkuls = append(matkuls, &matkul)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ctx, err)
+	}
+
+	return matkuls, nil
+}
+
+// IsMatkulMember reports whether userID is a member of a live class enrolled
+// in the matkul.
+func (m *MaterialRepositoryImplementation) IsMatkulMember(ctx context.Context, db *sql.DB, matkulID, userID int) (bool, *response.ErrorMsg) {
+	querySql := "SELECT 1 FROM class_matkul JOIN class ON class.id = class_matkul.class_id " +
+		"JOIN class_member ON class_member.class_id = class_matkul.class_id JOIN users ON users.id = class_member.user_id " +
+		"WHERE class_matkul.matkul_id = ? AND class_member.user_id = ? AND class_matkul.deleted_at IS NULL AND class.deleted_at IS NULL AND users.deleted_at IS NULL"
+	return m.exists(ctx, db, querySql, matkulID, userID)
+}
+
+func (m *MaterialRepositoryImplementation) exists(ctx context.Context, db *sql.DB, querySql string, args ...any) (bool, *response.ErrorMsg) {
+	rows, err := db.QueryContext(ctx, m.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	return rows.Next(), nil
+}
+
+// InsertTopic adds the topic unless the matkul has a live topic that week.
+// material_topic_week is not unique, so a deleted topic does not hold its
+// week until it is purged.
+func (m *MaterialRepositoryImplementation) InsertTopic(ctx context.Context, tx *sql.Tx, topic *domain.MaterialTopic) (bool, *response.ErrorMsg) {
+	querySql := "SELECT 1 FROM material_topic WHERE matkul_id = ? AND week = ? AND deleted_at IS NULL"
+	rows, err := tx.QueryContext(ctx, m.Dialect.Rebind(querySql), topic.MatkulID, topic.Week)
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	taken := rows.Next()
+	rows.Close()
+	if taken {
+		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "Topik untuk minggu tersebut sudah ada.")
+	}
+
+	querySql = "INSERT INTO material_topic(matkul_id, week, title, description, " + auditColumns + ") VALUES(?, ?, ?, ?, ?, ?, ?, ?)"
+	args := append([]any{topic.MatkulID, topic.Week, topic.Title, stringValue(topic.Description)}, insertAudit(ctx, &topic.Audit)...)
+	ID, err := m.Dialect.InsertID(ctx, tx, querySql, args...)
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	topic.ID, topic.Version = int(ID), 1
+	return true, nil
+}
+
+// DeleteTopic soft deletes the topic with its live materials, under the same stamp.
+func (m *MaterialRepositoryImplementation) DeleteTopic(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	if errMsg := m.Dialect.softDelete(ctx, tx, "material", "topic_id = ?", deletedAt, ID); errMsg != nil {
+		return false, errMsg
+	}
+	if errMsg := m.Dialect.softDelete(ctx, tx, "material_topic", "id = ?", deletedAt, ID); errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+// topicColumns are the columns scanTopic reads, before the audit columns.
+const topicColumns = "id, matkul_id, week, title, description, version"
+
+func (m *MaterialRepositoryImplementation) FindTopicByID(ctx context.Context, db *sql.DB, ID int) (*domain.MaterialTopic, bool, *response.ErrorMsg) {
+	topics, errMsg := m.findTopics(ctx, db, "id = ?", ID)
+	if errMsg != nil {
+		return nil, false, errMsg
+	}
+	if len(topics) == 0 {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Topik tidak ditemukan.")
+	}
+	return topics[0], true, nil
+}
+
+// FindTopicsByMatkulID lists the topics of the matkul by week.
+func (m *MaterialRepositoryImplementation) FindTopicsByMatkulID(ctx context.Context, db *sql.DB, matkulID int) ([]*domain.MaterialTopic, *response.ErrorMsg) {
+	return m.findTopics(ctx, db, "matkul_id = ?", matkulID)
+}
+
+func (m *MaterialRepositoryImplementation) findTopics(ctx context.Context, db *sql.DB, where string, args ...any) ([]*domain.MaterialTopic, *response.ErrorMsg) {
+	querySql := "SELECT " + topicColumns + ", " + auditColumns + " FROM material_topic WHERE " + where + " AND deleted_at IS NULL ORDER BY week, id"
+	rows, err := db.QueryContext(ctx, m.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var topics []*domain.MaterialTopic
+	for rows.Next() {
+		var topic domain.MaterialTopic
+		var description sql.NullString
+		if err := scanAudited(rows, &topic.Audit, &topic.ID, &topic.MatkulID, &topic.Week, &topic.Title, &description, &topic.Version); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		topic.Description = description.String
+		topics = append(topics, &topic)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ctx, err)
+	}
+
+	return topics, nil
+}
+
+// materialColumns are the columns scanMaterial reads, before the audit columns.
+const materialColumns = "id, topic_id, kind, title, url, file_key, file_name, content_type, size, version"
+
+func (m *MaterialRepositoryImplementation) InsertMaterial(ctx context.Context, tx *sql.Tx, material *domain.Material) (bool, *response.ErrorMsg) {
+	querySql := "INSERT INTO material(topic_id, kind, title, url, file_key, file_name, content_type, size, " + auditColumns + ") VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	var size any
+	if material.Kind == domain.MaterialFile {
+		size = material.Size
+	}
+	args := []any{material.TopicID, material.Kind, material.Title, stringValue(material.URL), stringValue(material.FileKey), stringValue(material.FileName), stringValue(material.ContentType), size}
+	ID, err := m.Dialect.InsertID(ctx, tx, querySql, append(args, insertAudit(ctx, &material.Audit)...)...)
+	if err != nil {
+		return false, internalError(ctx, err)
+	}
+	material.ID, material.Version = int(ID), 1
+	return true, nil
+}
+
+// DeleteMaterial soft deletes the material; its file is removed when the row is purged.
+func (m *MaterialRepositoryImplementation) DeleteMaterial(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	if errMsg := m.Dialect.softDelete(ctx, tx, "material", "id = ?", deletedAt, ID); errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+func (m *MaterialRepositoryImplementation) FindMaterialByID(ctx context.Context, db *sql.DB, ID int) (*domain.Material, bool, *response.ErrorMsg) {
+	materials, errMsg := m.findMaterials(ctx, db, "id = ?", ID)
+	if errMsg != nil {
+		return nil, false, errMsg
+	}
+	if len(materials) == 0 {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Materi tidak ditemukan.")
+	}
+	return materials[0], true, nil
+}
+
+// FindMaterialsByMatkulID lists the materials of every topic of the matkul,
+// oldest first.
+func (m *MaterialRepositoryImplementation) FindMaterialsByMatkulID(ctx context.Context, db *sql.DB, matkulID int) ([]*domain.Material, *response.ErrorMsg) {
+	return m.findMaterials(ctx, db, "topic_id IN (SELECT id FROM material_topic WHERE matkul_id = ? AND deleted_at IS NULL)", matkulID)
+}
+
+func (m *MaterialRepositoryImplementation) findMaterials(ctx context.Context, db *sql.DB, where string, args ...any) ([]*domain.Material, *response.ErrorMsg) {
+	querySql := "SELECT " + materialColumns + ", " + auditColumns + " FROM material WHERE " + where + " AND deleted_at IS NULL ORDER BY id"
+	rows, err := db.QueryContext(ctx, m.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var materials []*domain.Material
+	for rows.Next() {
+		var material domain.Material
+		var url, fileKey, fileName, contentType sql.NullString
+		var size sql.NullInt64
+		err := scanAudited(rows, &material.Audit, &material.ID, &material.TopicID, &material.Kind, &material.Title, &url, &fileKey, &fileName, &contentType, &size, &material.Version)
+		if err != nil {
+			return nil, internalError(ctx, err)
+		}
+		material.URL, material.FileKey, material.FileName, material.ContentType = url.String, fileKey.String, fileName.String, contentType.String
+		material.Size = size.Int64
+		materials = append(materials, &material)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ctx, err)
+	}
+
+	return materials, nil
+}
+
+// purgedMaterial selects the materials PurgeMaterials removes: those soft
+// deleted before before, on their own or with their topic or matkul.
+const purgedMaterial = "SELECT material.id FROM material JOIN material_topic ON material_topic.id = material.topic_id " +
+	"JOIN matakuliah ON matakuliah.id = material_topic.matkul_id " +
+	"WHERE (material.deleted_at IS NOT NULL AND material.deleted_at < ?) OR (material_topic.deleted_at IS NOT NULL AND material_topic.deleted_at < ?) " +
+	"OR (matakuliah.deleted_at IS NOT NULL AND matakuliah.deleted_at < ?)"
+
+// FindPurgedFileKeys returns the keys of the stored files of the materials
+// PurgeMaterials removes for before, so they can be deleted once it commits.
+func (m *MaterialRepositoryImplementation) FindPurgedFileKeys(ctx context.Context, tx *sql.Tx, before time.Time) ([]string, *response.ErrorMsg) {
+	querySql := "SELECT file_key FROM material WHERE id IN (" + purgedMaterial + ") AND file_key IS NOT NULL ORDER BY id"
+	rows, err := tx.QueryContext(ctx, m.Dialect.Rebind(querySql), before.UTC(), before.UTC(), before.UTC())
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var fileKeys []string
+	for rows.Next() {
+		var fileKey string
+		if err := rows.Scan(&fileKey); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		fileKeys = append(fileKeys, fileKey)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ctx, err)
+	}
+	return fileKeys, nil
+}
+
+// PurgeMaterials removes the materials, topics and enrolments soft deleted
+// before before, along with those of the classes and mata kuliah soft
+// deleted before before, ahead of the classes and mata kuliah themselves.
+// The stored files of the materials are not touched, see FindPurgedFileKeys.
+func (m *MaterialRepositoryImplementation) PurgeMaterials(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	const purgedMatkul = "SELECT id FROM matakuliah WHERE deleted_at IS NOT NULL AND deleted_at < ?"
+	const purgedClass = "SELECT id FROM class WHERE deleted_at IS NOT NULL AND deleted_at < ?"
+	steps := []struct {
+		querySql string
+		args     int
+	}{
+		// MySQL refuses a subquery on the table being deleted from, hence the derived table.
+		{"DELETE FROM material WHERE id IN (SELECT id FROM (" + purgedMaterial + ") purged)", 3},
+		{"DELETE FROM material_topic WHERE (deleted_at IS NOT NULL AND deleted_at < ?) OR matkul_id IN (" + purgedMatkul + ")", 2},
+		{"DELETE FROM class_matkul WHERE (deleted_at IS NOT NULL AND deleted_at < ?) OR matkul_id IN (" + purgedMatkul + ") OR class_id IN (" + purgedClass + ")", 3},
+	}
+
+	var purged int64
+	for _, step := range steps {
+		args := make([]any, step.args)
+		for i := range args {
+			args[i] = before.UTC()
+		}
+		result, err := tx.ExecContext(ctx, m.Dialect.Rebind(step.querySql), args...)
+		if err != nil {
+			return 0, internalError(ctx, err)
+		}
+		n, _ := result.RowsAffected()
+		purged += n
+	}
+	return purged, nil
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type AuthRepository struct {
+	Store *Store
+	users *UsersRepository
+}
+
+func NewAuthRepository(store *Store) repository.AuthRepository {
+	return &AuthRepository{Store: store, users: &UsersRepository{Store: store}}
+}
+
+func (a *AuthRepository) AuthRegisterUser(ctx context.Context, tx *sql.Tx, user *domain.AuthRegisterUser) (bool, int, *response.ErrorMsg) {
+	row := domain.Users{Name: user.Name, Email: user.Email, Password: user.Password, Role: user.Role, ClassID: user.ClassID}
+	row.ID = a.Store.nextID("users")
+	row.Version = 1
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := a.Store.write(ctx, tx, func(s *Store) {
+		s.users[row.ID] = row
+	})
+	if errMsg != nil {
+		return false, 0, errMsg
+	}
+	return true, row.ID, nil
+}
+
+func (a *AuthRepository) AuthLoginUser(ctx context.Context, db *sql.DB, email string) (bool, *domain.AuthLoginUser, *response.ErrorMsg) {
+	row, ok := a.users.findByEmail(email)
+	if !ok {
+		return false, nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Username atau Password salah.")
+	}
+	return true, &domain.AuthLoginUser{ID: row.ID, Email: row.Email, Password: row.Password}, nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type ClassMemberRepository struct {
+	Store *Store
+}
+
+func NewClassMemberRepository(store *Store) repository.ClassMemberRepository {
+	return &ClassMemberRepository{Store: store}
+}
+
+func (c *ClassMemberRepository) AddClassMember(ctx context.Context, tx *sql.Tx, member *domain.ClassMember) (bool, *response.ErrorMsg) {
+	row := domain.ClassMember{ClassID: member.ClassID, UserID: member.UserID, JoinedAt: member.JoinedAt}
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		s.members[memberKey{classID: row.ClassID, userID: row.UserID}] = row
+	})
+	return errMsg == nil, errMsg
+}
+
+func (c *ClassMemberRepository) DeleteClassMember(ctx context.Context, tx *sql.Tx, classID, userID int) (bool, *response.ErrorMsg) {
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		delete(s.members, memberKey{classID: classID, userID: userID})
+	})
+	return errMsg == nil, errMsg
+}
+
+func (c *ClassMemberRepository) IsClassMember(ctx context.Context, db *sql.DB, classID, userID int) (bool, *response.ErrorMsg) {
+	var isMember bool
+	c.Store.read(func(s *Store) {
+		_, isMember = s.members[memberKey{classID: classID, userID: userID}]
+		isMember = isMember && s.users[userID].DeletedAt == nil
+	})
+	return isMember, nil
+}
+
+func (c *ClassMemberRepository) FindClassMembers(ctx context.Context, db *sql.DB, classID int, role string) ([]*domain.ClassMember, *response.ErrorMsg) {
+	var members []*domain.ClassMember
+	c.Store.read(func(s *Store) {
+		for key, row := range s.members {
+			user, ok := s.users[key.userID]
+			if key.classID != classID || !ok || user.DeletedAt != nil || (role != "" && user.Role != role) {
+				continue
+			}
+			row.Name, row.Email, row.Role = user.Name, user.Email, user.Role
+			member := row
+			members = append(members, &member)
+		}
+	})
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].JoinedAt.Before(members[j].JoinedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	return members, nil
+}
+
+func (c *ClassMemberRepository) PurgeClassMembers(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	var keys []memberKey
+	c.Store.read(func(s *Store) {
+		for key := range s.members {
+			if purgeable(s.users[key.userID].DeletedAt, before) || purgeable(s.classes[key.classID].DeletedAt, before) {
+				keys = append(keys, key)
+			}
+		}
+	})
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		for _, key := range keys {
+			delete(s.members, key)
+		}
+	})
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	return int64(len(keys)), nil
+}
@@ -0,0 +1,243 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type ClassRepository struct {
+	Store *Store
+}
+
+func NewClassRepository(store *Store) repository.ClassRepository {
+	return &ClassRepository{Store: store}
+}
+
+func (c *ClassRepository) InsertClass(ctx context.Context, tx *sql.Tx, class *domain.Class) (bool, *response.ErrorMsg) {
+	if c.kodeKelasTaken(class.KodeKelas, 0) {
+		return false, errKodeKelasTaken()
+	}
+	row := domain.Class{Name: class.Name, KodeKelas: class.KodeKelas, KodeKelasExpiresAt: class.KodeKelasExpiresAt}
+	row.ID = c.Store.nextID("class")
+	row.Version = 1
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		s.classes[row.ID] = row
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	class.ID, class.Version, class.Audit = row.ID, row.Version, row.Audit
+	return true, nil
+}
+
+func (c *ClassRepository) UpdateClass(ctx context.Context, tx *sql.Tx, class *domain.Class) (bool, *response.ErrorMsg) {
+	update := *class
+	var stale bool
+	c.Store.read(func(s *Store) { stale = staleVersion(update.Version, s.classes[update.ID].Version) })
+	if stale {
+		return false, helpers.ToVersionConflict()
+	}
+	at, by := repository.Stamp(ctx)
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.classes[update.ID]; ok && row.DeletedAt == nil {
+			row.Name = update.Name
+			row.Version++
+			row.Touch(at, by)
+			s.classes[update.ID] = row
+		}
+	})
+	if errMsg == nil && class.Version != 0 {
+		class.Version++
+	}
+	return errMsg == nil, errMsg
+}
+
+func (c *ClassRepository) DeleteClassByID(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.classes[ID]; ok && row.DeletedAt == nil {
+			row.DeletedAt = stamp(deletedAt)
+			row.Version++
+			row.Touch(at, by)
+			s.classes[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (c *ClassRepository) RestoreClassByID(ctx context.Context, tx *sql.Tx, ID int) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.classes[ID]; ok {
+			row.DeletedAt = nil
+			row.Version++
+			row.Touch(at, by)
+			s.classes[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (c *ClassRepository) FindDeletedClassByID(ctx context.Context, db *sql.DB, ID int) (*domain.Class, bool, *response.ErrorMsg) {
+	var class *domain.Class
+	c.Store.read(func(s *Store) {
+		if row, ok := s.classes[ID]; ok && row.DeletedAt != nil {
+			class = &row
+		}
+	})
+	if class == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Kelas terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	return class, true, nil
+}
+
+func (c *ClassRepository) PurgeClass(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	var IDs []int
+	c.Store.read(func(s *Store) {
+		for ID, row := range s.classes {
+			if purgeable(row.DeletedAt, before) {
+				IDs = append(IDs, ID)
+			}
+		}
+	})
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		for _, ID := range IDs {
+			delete(s.classes, ID)
+		}
+	})
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	return int64(len(IDs)), nil
+}
+
+func (c *ClassRepository) FindClassByID(ctx context.Context, db *sql.DB, ID int) (*domain.Class, bool, *response.ErrorMsg) {
+	var class *domain.Class
+	c.Store.read(func(s *Store) {
+		if row, ok := s.classes[ID]; ok && row.DeletedAt == nil {
+			class = &row
+		}
+	})
+	if class == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Data Class By ID tidak ditemukan.")
+	}
+	return class, true, nil
+}
+
+func (c *ClassRepository) FindClassByName(ctx context.Context, db *sql.DB, name string) (*domain.Class, bool, *response.ErrorMsg) {
+	var class *domain.Class
+	c.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.classes) {
+			if row := s.classes[ID]; row.DeletedAt == nil && row.Name == name {
+				class = &domain.Class{ID: row.ID, Name: row.Name}
+				return
+			}
+		}
+	})
+	if class == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Data Class By ID tidak ditemukan.")
+	}
+	return class, true, nil
+}
+
+func (c *ClassRepository) FindAllClass(ctx context.Context, db *sql.DB, filter *domain.ListFilter) ([]*domain.Class, *response.ErrorMsg) {
+	var classes []*domain.Class
+	c.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.classes) {
+			if row := s.classes[ID]; row.DeletedAt == nil && listed(filter, row.Name, row.Audit) {
+				classes = append(classes, &row)
+			}
+		}
+	})
+	sortListed(classes, filter, func(row *domain.Class) (string, domain.Audit) { return row.Name, row.Audit })
+	return classes, nil
+}
+
+// EachClass reads the matching rows under the store lock and calls fn after
+// releasing it, so fn may take its time.
+func (c *ClassRepository) EachClass(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(class *domain.Class) error) *response.ErrorMsg {
+	classes, errMsg := c.FindAllClass(ctx, db, filter)
+	if errMsg != nil {
+		return errMsg
+	}
+	for _, row := range classes {
+		if err := fn(row); err != nil {
+			return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+	}
+	return nil
+}
+
+func (c *ClassRepository) FindClassByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Class, *response.ErrorMsg) {
+	wanted := intSet(IDs)
+	var classes []*domain.Class
+	c.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.classes) {
+			if row := s.classes[ID]; row.DeletedAt == nil && wanted[ID] {
+				classes = append(classes, &row)
+			}
+		}
+	})
+	return classes, nil
+}
+
+func (c *ClassRepository) FindClassByKode(ctx context.Context, db *sql.DB, kodeKelas string) (*domain.Class, bool, *response.ErrorMsg) {
+	var class *domain.Class
+	c.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.classes) {
+			if row := s.classes[ID]; row.DeletedAt == nil && row.KodeKelas == kodeKelas {
+				class = &row
+				return
+			}
+		}
+	})
+	if class == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Kode kelas tidak ditemukan.")
+	}
+	return class, true, nil
+}
+
+func (c *ClassRepository) UpdateKodeKelas(ctx context.Context, tx *sql.Tx, class *domain.Class) (bool, *response.ErrorMsg) {
+	if c.kodeKelasTaken(class.KodeKelas, class.ID) {
+		return false, errKodeKelasTaken()
+	}
+	update := *class
+	at, by := repository.Stamp(ctx)
+	errMsg := c.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.classes[update.ID]; ok && row.DeletedAt == nil {
+			row.KodeKelas, row.KodeKelasExpiresAt = update.KodeKelas, update.KodeKelasExpiresAt
+			row.Version++
+			row.Touch(at, by)
+			s.classes[update.ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+// kodeKelasTaken stands in for the unique index on class.kode_kelas; it only
+// sees committed rows.
+func (c *ClassRepository) kodeKelasTaken(kodeKelas string, exceptID int) bool {
+	var taken bool
+	c.Store.read(func(s *Store) {
+		for ID, row := range s.classes {
+			if ID != exceptID && row.KodeKelas == kodeKelas {
+				taken = true
+				return
+			}
+		}
+	})
+	return taken
+}
+
+func errKodeKelasTaken() *response.ErrorMsg {
+	return helpers.ToErrorMsg(http.StatusConflict, exception.ERR_ALREADY_USE, "Kode kelas sudah digunakan.")
+}
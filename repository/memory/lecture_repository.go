@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type LectureRepository struct {
+	Store *Store
+}
+
+func NewLectureRepository(store *Store) repository.LectureRepository {
+	return &LectureRepository{Store: store}
+}
+
+func (l *LectureRepository) InsertLecture(ctx context.Context, tx *sql.Tx, lecture *domain.Lecture) (bool, *response.ErrorMsg) {
+	row := domain.Lecture{Name: lecture.Name, UserID: lecture.UserID}
+	row.ID = l.Store.nextID("lecture")
+	row.Version = 1
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := l.Store.write(ctx, tx, func(s *Store) {
+		s.lectures[row.ID] = row
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	lecture.ID, lecture.Version, lecture.Audit = row.ID, row.Version, row.Audit
+	return true, nil
+}
+
+func (l *LectureRepository) UpdateLecture(ctx context.Context, tx *sql.Tx, lecture *domain.Lecture) (bool, *response.ErrorMsg) {
+	update := *lecture
+	var stale bool
+	l.Store.read(func(s *Store) { stale = staleVersion(update.Version, s.lectures[update.ID].Version) })
+	if stale {
+		return false, helpers.ToVersionConflict()
+	}
+	at, by := repository.Stamp(ctx)
+	errMsg := l.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.lectures[update.ID]; ok && row.DeletedAt == nil {
+			row.Version++
+			row.Touch(at, by)
+			row.Name = update.Name
+			s.lectures[update.ID] = row
+		}
+	})
+	if errMsg == nil && lecture.Version != 0 {
+		lecture.Version++
+	}
+	return errMsg == nil, errMsg
+}
+
+func (l *LectureRepository) DeleteLectureByID(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := l.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.lectures[ID]; ok && row.DeletedAt == nil {
+			row.DeletedAt = stamp(deletedAt)
+			row.Version++
+			row.Touch(at, by)
+			s.lectures[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (l *LectureRepository) RestoreLectureByID(ctx context.Context, tx *sql.Tx, ID int) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := l.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.lectures[ID]; ok {
+			row.DeletedAt = nil
+			row.Version++
+			row.Touch(at, by)
+			s.lectures[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (l *LectureRepository) FindDeletedLectureByID(ctx context.Context, db *sql.DB, ID int) (*domain.Lecture, bool, *response.ErrorMsg) {
+	return l.findDeleted(func(row domain.Lecture) bool { return row.ID == ID })
+}
+
+func (l *LectureRepository) FindDeletedLectureByUserID(ctx context.Context, db *sql.DB, userID int) (*domain.Lecture, bool, *response.ErrorMsg) {
+	return l.findDeleted(func(row domain.Lecture) bool { return row.UserID == userID })
+}
+
+// findDeleted returns the matching lecture deleted most recently.
+func (l *LectureRepository) findDeleted(match func(row domain.Lecture) bool) (*domain.Lecture, bool, *response.ErrorMsg) {
+	var lecture *domain.Lecture
+	l.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.lectures) {
+			row := s.lectures[ID]
+			if row.DeletedAt == nil || !match(row) {
+				continue
+			}
+			if lecture == nil || !row.DeletedAt.Before(*lecture.DeletedAt) {
+				lecture = &row
+			}
+		}
+	})
+	if lecture == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	return lecture, true, nil
+}
+
+func (l *LectureRepository) PurgeLecture(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	var IDs []int
+	l.Store.read(func(s *Store) {
+		for ID, row := range s.lectures {
+			if purgeable(row.DeletedAt, before) {
+				IDs = append(IDs, ID)
+			}
+		}
+	})
+	errMsg := l.Store.write(ctx, tx, func(s *Store) {
+		for _, ID := range IDs {
+			delete(s.lectures, ID)
+		}
+	})
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	return int64(len(IDs)), nil
+}
+
+func (l *LectureRepository) FindLectureByID(ctx context.Context, db *sql.DB, ID int) (*domain.Lecture, bool, *response.ErrorMsg) {
+	var lecture *domain.Lecture
+	l.Store.read(func(s *Store) {
+		if row, ok := s.lectures[ID]; ok && row.DeletedAt == nil {
+			lecture = &domain.Lecture{ID: row.ID, Name: row.Name}
+		}
+	})
+	if lecture == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen dengan ID tidak ditemukan.")
+	}
+	return lecture, true, nil
+}
+
+func (l *LectureRepository) FindLectureByUserID(ctx context.Context, db *sql.DB, userID int) (*domain.Lecture, bool, *response.ErrorMsg) {
+	lecture := l.find(func(row domain.Lecture) bool { return row.UserID == userID })
+	if lecture == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen dengan ID tidak ditemukan.")
+	}
+	return lecture, true, nil
+}
+
+func (l *LectureRepository) FindLectureByName(ctx context.Context, db *sql.DB, name string) (*domain.Lecture, bool, *response.ErrorMsg) {
+	lecture := l.find(func(row domain.Lecture) bool { return row.Name == name })
+	if lecture == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen dengan Nama tidak ditemukan.")
+	}
+	return &domain.Lecture{ID: lecture.ID, Name: lecture.Name}, true, nil
+}
+
+func (l *LectureRepository) find(match func(row domain.Lecture) bool) (lecture *domain.Lecture) {
+	l.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.lectures) {
+			if row := s.lectures[ID]; row.DeletedAt == nil && match(row) {
+				lecture = &row
+				return
+			}
+		}
+	})
+	return lecture
+}
+
+func (l *LectureRepository) FindAllLecture(ctx context.Context, db *sql.DB, filter *domain.ListFilter) ([]*domain.Lecture, *response.ErrorMsg) {
+	var lectures []*domain.Lecture
+	l.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.lectures) {
+			if row := s.lectures[ID]; row.DeletedAt == nil && listed(filter, row.Name, row.Audit) && s.profiled(row.UserID, filter.Profile) {
+				lectures = append(lectures, &row)
+			}
+		}
+	})
+	sortListed(lectures, filter, func(row *domain.Lecture) (string, domain.Audit) { return row.Name, row.Audit })
+	return lectures, nil
+}
+
+// EachLecture reads the matching rows under the store lock and calls fn after
+// releasing it, so fn may take its time.
+func (l *LectureRepository) EachLecture(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(lecture *domain.Lecture) error) *response.ErrorMsg {
+	lectures, errMsg := l.FindAllLecture(ctx, db, filter)
+	if errMsg != nil {
+		return errMsg
+	}
+	for _, row := range lectures {
+		if err := fn(row); err != nil {
+			return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+	}
+	return nil
+}
+
+func (l *LectureRepository) FindLectureByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Lecture, *response.ErrorMsg) {
+	wanted := intSet(IDs)
+	var lectures []*domain.Lecture
+	l.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.lectures) {
+			if row := s.lectures[ID]; row.DeletedAt == nil && wanted[ID] {
+				lectures = append(lectures, &row)
+			}
+		}
+	})
+	return lectures, nil
+}
+
+func (l *LectureRepository) FindLectureByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (map[int][]*domain.Lecture, *response.ErrorMsg) {
+	wanted := intSet(classIDs)
+	lectures := map[int][]*domain.Lecture{}
+	l.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.lectures) {
+			row := s.lectures[ID]
+			if row.DeletedAt != nil {
+				continue
+			}
+			for _, classID := range s.classIDsOf(row.UserID) {
+				if wanted[classID] {
+					lecture := row
+					lectures[classID] = append(lectures[classID], &lecture)
+				}
+			}
+		}
+	})
+	return lectures, nil
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type MataKuliahRepository struct {
+	Store *Store
+}
+
+func NewMataKuliahRepository(store *Store) repository.MataKuliahRepository {
+	return &MataKuliahRepository{Store: store}
+}
+
+func (m *MataKuliahRepository) InsertMatkul(ctx context.Context, tx *sql.Tx, matkul *domain.Matkul) (bool, *response.ErrorMsg) {
+	row := *matkul
+	row.ID = m.Store.nextID("matakuliah")
+	row.Version = 1
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		s.matkuls[row.ID] = row
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	matkul.ID, matkul.Version, matkul.Audit = row.ID, row.Version, row.Audit
+	return true, nil
+}
+
+func (m *MataKuliahRepository) UpdateMatkul(ctx context.Context, tx *sql.Tx, matkul *domain.Matkul) (bool, *response.ErrorMsg) {
+	update := *matkul
+	var stale bool
+	m.Store.read(func(s *Store) { stale = staleVersion(update.Version, s.matkuls[update.ID].Version) })
+	if stale {
+		return false, helpers.ToVersionConflict()
+	}
+	at, by := repository.Stamp(ctx)
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.matkuls[update.ID]; ok && row.DeletedAt == nil {
+			update.Version, update.Audit = row.Version+1, row.Audit
+			update.Touch(at, by)
+			s.matkuls[update.ID] = update
+		}
+	})
+	if errMsg == nil && matkul.Version != 0 {
+		matkul.Version++
+	}
+	return errMsg == nil, errMsg
+}
+
+func (m *MataKuliahRepository) DeleteMatkulByID(ctx context.Context, tx *sql.Tx, ID, version int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	var stale bool
+	m.Store.read(func(s *Store) { stale = staleVersion(version, s.matkuls[ID].Version) })
+	if stale {
+		return false, helpers.ToVersionConflict()
+	}
+	at, by := repository.Stamp(ctx)
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.matkuls[ID]; ok && row.DeletedAt == nil {
+			row.DeletedAt = stamp(deletedAt)
+			row.Version++
+			row.Touch(at, by)
+			s.matkuls[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (m *MataKuliahRepository) RestoreMatkulByID(ctx context.Context, tx *sql.Tx, ID int) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.matkuls[ID]; ok {
+			row.DeletedAt = nil
+			row.Version++
+			row.Touch(at, by)
+			s.matkuls[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (m *MataKuliahRepository) FindDeletedMatkulByID(ctx context.Context, db *sql.DB, ID int) (*domain.Matkul, bool, *response.ErrorMsg) {
+	var matkul *domain.Matkul
+	m.Store.read(func(s *Store) {
+		if row, ok := s.matkuls[ID]; ok && row.DeletedAt != nil {
+			matkul = &row
+		}
+	})
+	if matkul == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Matkul terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	return matkul, true, nil
+}
+
+func (m *MataKuliahRepository) PurgeMatkul(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	var IDs []int
+	m.Store.read(func(s *Store) {
+		for ID, row := range s.matkuls {
+			if purgeable(row.DeletedAt, before) {
+				IDs = append(IDs, ID)
+			}
+		}
+	})
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		for _, ID := range IDs {
+			delete(s.matkuls, ID)
+		}
+	})
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	return int64(len(IDs)), nil
+}
+
+func (m *MataKuliahRepository) FindMatkulByID(ctx context.Context, db *sql.DB, ID int) (*domain.Matkul, bool, *response.ErrorMsg) {
+	var matkul *domain.Matkul
+	m.Store.read(func(s *Store) {
+		if row, ok := s.matkuls[ID]; ok && row.DeletedAt == nil {
+			matkul = &row
+		}
+	})
+	if matkul == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Matkul dengan ID tidak ditemukan.")
+	}
+	return matkul, true, nil
+}
+
+func (m *MataKuliahRepository) FindAllMatkul(ctx context.Context, db *sql.DB, filter *domain.ListFilter) ([]*domain.Matkul, *response.ErrorMsg) {
+	var matkuls []*domain.Matkul
+	m.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.matkuls) {
+			if row := s.matkuls[ID]; row.DeletedAt == nil && listed(filter, row.Name, row.Audit) {
+				matkuls = append(matkuls, &row)
+			}
+		}
+	})
+	sortListed(matkuls, filter, func(row *domain.Matkul) (string, domain.Audit) { return row.Name, row.Audit })
+	return matkuls, nil
+}
+
+// EachMatkul reads the matching rows under the store lock and calls fn after
+// releasing it, so fn may take its time.
+func (m *MataKuliahRepository) EachMatkul(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(matkul *domain.Matkul) error) *response.ErrorMsg {
+	matkuls, errMsg := m.FindAllMatkul(ctx, db, filter)
+	if errMsg != nil {
+		return errMsg
+	}
+	for _, row := range matkuls {
+		if err := fn(row); err != nil {
+			return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type MaterialRepository struct {
+	Store *Store
+}
+
+func NewMaterialRepository(store *Store) repository.MaterialRepository {
+	return &MaterialRepository{Store: store}
+}
+
+type enrolKey struct {
+	classID, matkulID int
+}
+
+// enrolment is a class_matkul row.
+type enrolment struct {
+	version   int
+	deletedAt *time.Time
+}
+
+// isEnrolled reports whether the class has a live enrolment in the matkul; call it under the store lock.
+func (s *Store) isEnrolled(classID, matkulID int) bool {
+	row, ok := s.enrolled[enrolKey{classID: classID, matkulID: matkulID}]
+	return ok && row.deletedAt == nil
+}
+
+func (m *MaterialRepository) EnrollClass(ctx context.Context, tx *sql.Tx, classID, matkulID int) (bool, *response.ErrorMsg) {
+	key := enrolKey{classID: classID, matkulID: matkulID}
+	var taken bool
+	m.Store.read(func(s *Store) { taken = s.isEnrolled(classID, matkulID) })
+	if taken {
+		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "Kelas sudah terdaftar di mata kuliah ini.")
+	}
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		row, ok := s.enrolled[key]
+		if !ok {
+			s.enrolled[key] = enrolment{version: 1}
+			return
+		}
+		s.enrolled[key] = enrolment{version: row.version + 1}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (m *MaterialRepository) UnenrollClass(ctx context.Context, tx *sql.Tx, classID, matkulID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	key := enrolKey{classID: classID, matkulID: matkulID}
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.enrolled[key]; ok && row.deletedAt == nil {
+			s.enrolled[key] = enrolment{version: row.version + 1, deletedAt: stamp(deletedAt)}
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (m *MaterialRepository) IsEnrolled(ctx context.Context, db *sql.DB, classID, matkulID int) (bool, *response.ErrorMsg) {
+	var isEnrolled bool
+	m.Store.read(func(s *Store) { isEnrolled = s.isEnrolled(classID, matkulID) })
+	return isEnrolled, nil
+}
+
+func (m *MaterialRepository) FindMatkulByClassID(ctx context.Context, db *sql.DB, classID int) ([]*domain.Matkul, *response.ErrorMsg) {
+	var matkuls []*domain.Matkul
+	m.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.matkuls) {
+			row := s.matkuls[ID]
+			if row.DeletedAt == nil && s.isEnrolled(classID, ID) {
+				matkuls = append(matkuls, &row)
+			}
+		}
+	})
+	return matkuls, nil
+}
+
+func (m *MaterialRepository) IsMatkulMember(ctx context.Context, db *sql.DB, matkulID, userID int) (bool, *response.ErrorMsg) {
+	var isMember bool
+	m.Store.read(func(s *Store) {
+		if user, ok := s.users[userID]; !ok || user.DeletedAt != nil {
+			return
+		}
+		for _, classID := range s.classIDsOf(userID) {
+			if class, ok := s.classes[classID]; ok && class.DeletedAt == nil && s.isEnrolled(classID, matkulID) {
+				isMember = true
+				return
+			}
+		}
+	})
+	return isMember, nil
+}
+
+func (m *MaterialRepository) InsertTopic(ctx context.Context, tx *sql.Tx, topic *domain.MaterialTopic) (bool, *response.ErrorMsg) {
+	var taken bool
+	m.Store.read(func(s *Store) {
+		for _, row := range s.topics {
+			taken = taken || (row.DeletedAt == nil && row.MatkulID == topic.MatkulID && row.Week == topic.Week)
+		}
+	})
+	if taken {
+		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "Topik untuk minggu tersebut sudah ada.")
+	}
+
+	row := *topic
+	row.ID = m.Store.nextID("material_topic")
+	row.Version = 1
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		s.topics[row.ID] = row
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	topic.ID, topic.Version, topic.Audit = row.ID, row.Version, row.Audit
+	return true, nil
+}
+
+func (m *MaterialRepository) DeleteTopic(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		for materialID, row := range s.material {
+			if row.TopicID == ID && row.DeletedAt == nil {
+				row.DeletedAt, row.Version = stamp(deletedAt), row.Version+1
+				row.Touch(at, by)
+				s.material[materialID] = row
+			}
+		}
+		if row, ok := s.topics[ID]; ok && row.DeletedAt == nil {
+			row.DeletedAt, row.Version = stamp(deletedAt), row.Version+1
+			row.Touch(at, by)
+			s.topics[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (m *MaterialRepository) FindTopicByID(ctx context.Context, db *sql.DB, ID int) (*domain.MaterialTopic, bool, *response.ErrorMsg) {
+	var topic *domain.MaterialTopic
+	m.Store.read(func(s *Store) {
+		if row, ok := s.topics[ID]; ok && row.DeletedAt == nil {
+			topic = &row
+		}
+	})
+	if topic == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Topik tidak ditemukan.")
+	}
+	return topic, true, nil
+}
+
+func (m *MaterialRepository) FindTopicsByMatkulID(ctx context.Context, db *sql.DB, matkulID int) ([]*domain.MaterialTopic, *response.ErrorMsg) {
+	var topics []*domain.MaterialTopic
+	m.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.topics) {
+			if row := s.topics[ID]; row.MatkulID == matkulID && row.DeletedAt == nil {
+				topics = append(topics, &row)
+			}
+		}
+	})
+	sort.SliceStable(topics, func(i, j int) bool { return topics[i].Week < topics[j].Week })
+	return topics, nil
+}
+
+func (m *MaterialRepository) InsertMaterial(ctx context.Context, tx *sql.Tx, material *domain.Material) (bool, *response.ErrorMsg) {
+	row := *material
+	row.ID = m.Store.nextID("material")
+	row.Version = 1
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		s.material[row.ID] = row
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	material.ID, material.Version, material.Audit = row.ID, row.Version, row.Audit
+	return true, nil
+}
+
+func (m *MaterialRepository) DeleteMaterial(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.material[ID]; ok && row.DeletedAt == nil {
+			row.DeletedAt, row.Version = stamp(deletedAt), row.Version+1
+			row.Touch(at, by)
+			s.material[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (m *MaterialRepository) FindMaterialByID(ctx context.Context, db *sql.DB, ID int) (*domain.Material, bool, *response.ErrorMsg) {
+	var material *domain.Material
+	m.Store.read(func(s *Store) {
+		if row, ok := s.material[ID]; ok && row.DeletedAt == nil {
+			material = &row
+		}
+	})
+	if material == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Materi tidak ditemukan.")
+	}
+	return material, true, nil
+}
+
+func (m *MaterialRepository) FindMaterialsByMatkulID(ctx context.Context, db *sql.DB, matkulID int) ([]*domain.Material, *response.ErrorMsg) {
+	var materials []*domain.Material
+	m.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.material) {
+			if row := s.material[ID]; row.DeletedAt == nil && s.topics[row.TopicID].MatkulID == matkulID && s.topics[row.TopicID].DeletedAt == nil {
+				materials = append(materials, &row)
+			}
+		}
+	})
+	return materials, nil
+}
+
+// purgedMaterial reports whether PurgeMaterials removes row: it was soft
+// deleted before before, on its own or with its topic or matkul; call it
+// under the store lock.
+func (s *Store) purgedMaterial(row domain.Material, before time.Time) bool {
+	topic := s.topics[row.TopicID]
+	return purgeable(row.DeletedAt, before) || purgeable(topic.DeletedAt, before) || purgeable(s.matkuls[topic.MatkulID].DeletedAt, before)
+}
+
+func (m *MaterialRepository) FindPurgedFileKeys(ctx context.Context, tx *sql.Tx, before time.Time) ([]string, *response.ErrorMsg) {
+	var fileKeys []string
+	m.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.material) {
+			row := s.material[ID]
+			if row.FileKey != "" && s.purgedMaterial(row, before) {
+				fileKeys = append(fileKeys, row.FileKey)
+			}
+		}
+	})
+	return fileKeys, nil
+}
+
+func (m *MaterialRepository) PurgeMaterials(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	var materialIDs, topicIDs []int
+	var keys []enrolKey
+	m.Store.read(func(s *Store) {
+		for ID, row := range s.material {
+			if s.purgedMaterial(row, before) {
+				materialIDs = append(materialIDs, ID)
+			}
+		}
+		for ID, row := range s.topics {
+			if purgeable(row.DeletedAt, before) || purgeable(s.matkuls[row.MatkulID].DeletedAt, before) {
+				topicIDs = append(topicIDs, ID)
+			}
+		}
+		for key, row := range s.enrolled {
+			if purgeable(row.deletedAt, before) || purgeable(s.matkuls[key.matkulID].DeletedAt, before) || purgeable(s.classes[key.classID].DeletedAt, before) {
+				keys = append(keys, key)
+			}
+		}
+	})
+	errMsg := m.Store.write(ctx, tx, func(s *Store) {
+		for _, ID := range materialIDs {
+			delete(s.material, ID)
+		}
+		for _, ID := range topicIDs {
+			delete(s.topics, ID)
+		}
+		for _, key := range keys {
+			delete(s.enrolled, key)
+		}
+	})
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	return int64(len(materialIDs) + len(topicIDs) + len(keys)), nil
+}
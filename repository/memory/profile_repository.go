@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type ProfileRepository struct {
+	Store *Store
+}
+
+func NewProfileRepository(store *Store) repository.ProfileRepository {
+	return &ProfileRepository{Store: store}
+}
+
+func (p *ProfileRepository) InsertProfile(ctx context.Context, tx *sql.Tx, profile *domain.Profile) (bool, *response.ErrorMsg) {
+	if p.taken(profile) {
+		return false, errProfileTaken()
+	}
+	row := *profile
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := p.Store.write(ctx, tx, func(s *Store) {
+		s.profiles[row.UserID] = row
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	profile.Audit = row.Audit
+	return true, nil
+}
+
+func (p *ProfileRepository) UpdateProfile(ctx context.Context, tx *sql.Tx, profile *domain.Profile) (bool, *response.ErrorMsg) {
+	if p.taken(profile) {
+		return false, errProfileTaken()
+	}
+	update := *profile
+	at, by := repository.Stamp(ctx)
+	errMsg := p.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.profiles[update.UserID]; ok {
+			update.Audit = row.Audit
+			update.Touch(at, by)
+			s.profiles[update.UserID] = update
+		}
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	profile.Touch(at, by)
+	return true, nil
+}
+
+// taken stands in for the unique indexes on npm and nidn.
+func (p *ProfileRepository) taken(profile *domain.Profile) (taken bool) {
+	p.Store.read(func(s *Store) {
+		for userID, row := range s.profiles {
+			if userID != profile.UserID && ((profile.NPM != "" && row.NPM == profile.NPM) || (profile.NIDN != "" && row.NIDN == profile.NIDN)) {
+				taken = true
+				return
+			}
+		}
+	})
+	return taken
+}
+
+func errProfileTaken() *response.ErrorMsg {
+	return helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "NPM atau NIDN sudah digunakan.")
+}
+
+func (p *ProfileRepository) FindProfileByUserID(ctx context.Context, db *sql.DB, userID int) (*domain.Profile, bool, *response.ErrorMsg) {
+	return p.find(func(row domain.Profile) bool { return row.UserID == userID })
+}
+
+func (p *ProfileRepository) FindProfileByNPM(ctx context.Context, db *sql.DB, npm string) (*domain.Profile, bool, *response.ErrorMsg) {
+	return p.find(func(row domain.Profile) bool { return row.NPM == npm })
+}
+
+func (p *ProfileRepository) FindProfileByNIDN(ctx context.Context, db *sql.DB, nidn string) (*domain.Profile, bool, *response.ErrorMsg) {
+	return p.find(func(row domain.Profile) bool { return row.NIDN == nidn })
+}
+
+func (p *ProfileRepository) find(match func(row domain.Profile) bool) (*domain.Profile, bool, *response.ErrorMsg) {
+	var profile *domain.Profile
+	p.Store.read(func(s *Store) {
+		for _, userID := range sortedIDs(s.profiles) {
+			if row := s.profiles[userID]; match(row) {
+				profile = &row
+				return
+			}
+		}
+	})
+	if profile == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Profil tidak ditemukan.")
+	}
+	return profile, true, nil
+}
+
+func (p *ProfileRepository) FindProfilesByUserIDs(ctx context.Context, db *sql.DB, userIDs []int) (map[int]*domain.Profile, *response.ErrorMsg) {
+	profiles := map[int]*domain.Profile{}
+	p.Store.read(func(s *Store) {
+		for _, userID := range userIDs {
+			if row, ok := s.profiles[userID]; ok {
+				profiles[userID] = &row
+			}
+		}
+	})
+	return profiles, nil
+}
+
+func (p *ProfileRepository) PurgeProfiles(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	var userIDs []int
+	p.Store.read(func(s *Store) {
+		for userID := range s.profiles {
+			if purgeable(s.users[userID].DeletedAt, before) {
+				userIDs = append(userIDs, userID)
+			}
+		}
+	})
+	errMsg := p.Store.write(ctx, tx, func(s *Store) {
+		for _, userID := range userIDs {
+			delete(s.profiles, userID)
+		}
+	})
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	return int64(len(userIDs)), nil
+}
@@ -0,0 +1,244 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type RoomRepository struct {
+	Store *Store
+}
+
+func NewRoomRepository(store *Store) repository.RoomRepository {
+	return &RoomRepository{Store: store}
+}
+
+func (r *RoomRepository) InsertRoom(ctx context.Context, tx *sql.Tx, room *domain.Room) (bool, *response.ErrorMsg) {
+	row := *room
+	row.ID = r.Store.nextID("room")
+	row.Version = 1
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := r.Store.write(ctx, tx, func(s *Store) {
+		s.rooms[row.ID] = row
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	room.ID, room.Version, room.Audit = row.ID, row.Version, row.Audit
+	return true, nil
+}
+
+func (r *RoomRepository) UpdateRoom(ctx context.Context, tx *sql.Tx, room *domain.Room) (bool, *response.ErrorMsg) {
+	update := *room
+	var stale bool
+	r.Store.read(func(s *Store) { stale = staleVersion(update.Version, s.rooms[update.ID].Version) })
+	if stale {
+		return false, helpers.ToVersionConflict()
+	}
+	at, by := repository.Stamp(ctx)
+	errMsg := r.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.rooms[update.ID]; ok && row.DeletedAt == nil {
+			row.Name, row.URL, row.LectureID, row.StartRoom, row.EndRoom = update.Name, update.URL, update.LectureID, update.StartRoom, update.EndRoom
+			row.Version++
+			row.Touch(at, by)
+			s.rooms[update.ID] = row
+		}
+	})
+	if errMsg == nil && room.Version != 0 {
+		room.Version++
+	}
+	return errMsg == nil, errMsg
+}
+
+func (r *RoomRepository) DeleteRoomByID(ctx context.Context, tx *sql.Tx, ID, version int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	var stale bool
+	r.Store.read(func(s *Store) { stale = staleVersion(version, s.rooms[ID].Version) })
+	if stale {
+		return false, helpers.ToVersionConflict()
+	}
+	return r.setDeletedAt(ctx, tx, func(row domain.Room) bool { return row.ID == ID && row.DeletedAt == nil }, stamp(deletedAt))
+}
+
+func (r *RoomRepository) DeleteRoomsByLectureID(ctx context.Context, tx *sql.Tx, lectureID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	return r.setDeletedAt(ctx, tx, func(row domain.Room) bool { return row.LectureID == lectureID && row.DeletedAt == nil }, stamp(deletedAt))
+}
+
+func (r *RoomRepository) ReassignRooms(ctx context.Context, tx *sql.Tx, fromLectureID, toLectureID int) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := r.Store.write(ctx, tx, func(s *Store) {
+		for ID, row := range s.rooms {
+			if row.LectureID == fromLectureID && row.DeletedAt == nil {
+				row.LectureID = toLectureID
+				row.Version++
+				row.Touch(at, by)
+				s.rooms[ID] = row
+			}
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (r *RoomRepository) RestoreRoomByID(ctx context.Context, tx *sql.Tx, ID int) (bool, *response.ErrorMsg) {
+	return r.setDeletedAt(ctx, tx, func(row domain.Room) bool { return row.ID == ID }, nil)
+}
+
+func (r *RoomRepository) RestoreRoomsByLectureID(ctx context.Context, tx *sql.Tx, lectureID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	return r.setDeletedAt(ctx, tx, func(row domain.Room) bool {
+		return row.LectureID == lectureID && row.DeletedAt != nil && row.DeletedAt.Equal(deletedAt)
+	}, nil)
+}
+
+// setDeletedAt stamps deletedAt on the rooms match accepts when the transaction commits.
+func (r *RoomRepository) setDeletedAt(ctx context.Context, tx *sql.Tx, match func(row domain.Room) bool, deletedAt *time.Time) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := r.Store.write(ctx, tx, func(s *Store) {
+		for ID, row := range s.rooms {
+			if match(row) {
+				row.DeletedAt = deletedAt
+				row.Version++
+				row.Touch(at, by)
+				s.rooms[ID] = row
+			}
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (r *RoomRepository) FindDeletedRoomByID(ctx context.Context, db *sql.DB, ID int) (*domain.Room, bool, *response.ErrorMsg) {
+	var room *domain.Room
+	r.Store.read(func(s *Store) {
+		if row, ok := s.rooms[ID]; ok && row.DeletedAt != nil {
+			room = &row
+		}
+	})
+	if room == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Room terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	return room, true, nil
+}
+
+func (r *RoomRepository) PurgeRoom(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	var IDs []int
+	r.Store.read(func(s *Store) {
+		for ID, row := range s.rooms {
+			if purgeable(row.DeletedAt, before) {
+				IDs = append(IDs, ID)
+			}
+		}
+	})
+	errMsg := r.Store.write(ctx, tx, func(s *Store) {
+		for _, ID := range IDs {
+			delete(s.rooms, ID)
+		}
+	})
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	return int64(len(IDs)), nil
+}
+
+func (r *RoomRepository) FindRoomByID(ctx context.Context, db *sql.DB, ID int) (*domain.Room, bool, *response.ErrorMsg) {
+	var room *domain.Room
+	r.Store.read(func(s *Store) {
+		if row, ok := s.rooms[ID]; ok && row.DeletedAt == nil {
+			room = &row
+		}
+	})
+	if room == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Room dengan ID tidak ditemukan.")
+	}
+	return room, true, nil
+}
+
+func (r *RoomRepository) FindAllRoom(ctx context.Context, db *sql.DB, filter *domain.ListFilter) ([]*domain.Room, *response.ErrorMsg) {
+	var rooms []*domain.Room
+	r.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.rooms) {
+			if row := s.rooms[ID]; row.DeletedAt == nil && listed(filter, row.Name, row.Audit) {
+				rooms = append(rooms, &row)
+			}
+		}
+	})
+	sortListed(rooms, filter, func(row *domain.Room) (string, domain.Audit) { return row.Name, row.Audit })
+	return rooms, nil
+}
+
+// EachRoom reads the matching rows under the store lock and calls fn after
+// releasing it, so fn may take its time.
+func (r *RoomRepository) EachRoom(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(room *domain.Room) error) *response.ErrorMsg {
+	rooms, errMsg := r.FindAllRoom(ctx, db, filter)
+	if errMsg != nil {
+		return errMsg
+	}
+	for _, row := range rooms {
+		if err := fn(row); err != nil {
+			return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+	}
+	return nil
+}
+
+func (r *RoomRepository) FindRoomByLectureIDs(ctx context.Context, db *sql.DB, lectureIDs []int) ([]*domain.Room, *response.ErrorMsg) {
+	wanted := intSet(lectureIDs)
+	var rooms []*domain.Room
+	r.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.rooms) {
+			if row := s.rooms[ID]; row.DeletedAt == nil && wanted[row.LectureID] {
+				rooms = append(rooms, &row)
+			}
+		}
+	})
+	sortByStartRoom(rooms)
+	return rooms, nil
+}
+
+func (r *RoomRepository) FindRoomByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (map[int][]*domain.Room, *response.ErrorMsg) {
+	wanted := intSet(classIDs)
+	rooms := map[int][]*domain.Room{}
+	r.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.rooms) {
+			row := s.rooms[ID]
+			lecture, ok := s.lectures[row.LectureID]
+			if !ok || row.DeletedAt != nil || lecture.DeletedAt != nil {
+				continue
+			}
+			for _, classID := range s.classIDsOf(lecture.UserID) {
+				if wanted[classID] {
+					room := row
+					rooms[classID] = append(rooms[classID], &room)
+				}
+			}
+		}
+	})
+	for _, classRooms := range rooms {
+		sortByStartRoom(classRooms)
+	}
+	return rooms, nil
+}
+
+// sortByStartRoom mirrors ORDER BY start_room, id; the rows are already in id order.
+func sortByStartRoom(rooms []*domain.Room) {
+	sort.SliceStable(rooms, func(i, j int) bool { return rooms[i].StartRoom < rooms[j].StartRoom })
+}
+
+func (r *RoomRepository) CountActiveRooms(ctx context.Context, db *sql.DB, at time.Time) (int, *response.ErrorMsg) {
+	now := at.Format("2006-01-02 15:04:05")
+	var count int
+	r.Store.read(func(s *Store) {
+		for _, row := range s.rooms {
+			if row.DeletedAt == nil && row.StartRoom <= now && row.EndRoom >= now {
+				count++
+			}
+		}
+	})
+	return count, nil
+}
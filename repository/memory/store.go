@@ -0,0 +1,329 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+)
+
+// Store keeps every table in memory. Writes issued through a *sql.Tx opened on
+// Store.DB are staged and only applied when that transaction commits, so the
+// services keep their Begin / RollbackOrCommit flow unchanged.
+type Store struct {
+	mu       sync.RWMutex
+	lastID   map[string]int
+	users    map[int]domain.Users
+	classes  map[int]domain.Class
+	lectures map[int]domain.Lecture
+	rooms    map[int]domain.Room
+	matkuls  map[int]domain.Matkul
+	members  map[memberKey]domain.ClassMember
+	profiles map[int]domain.Profile
+	enrolled map[enrolKey]enrolment
+	topics   map[int]domain.MaterialTopic
+	material map[int]domain.Material
+
+	db *sql.DB
+}
+
+func NewStore() *Store {
+	s := &Store{
+		lastID:   map[string]int{},
+		users:    map[int]domain.Users{},
+		classes:  map[int]domain.Class{},
+		lectures: map[int]domain.Lecture{},
+		rooms:    map[int]domain.Room{},
+		matkuls:  map[int]domain.Matkul{},
+		members:  map[memberKey]domain.ClassMember{},
+		profiles: map[int]domain.Profile{},
+		enrolled: map[enrolKey]enrolment{},
+		topics:   map[int]domain.MaterialTopic{},
+		material: map[int]domain.Material{},
+	}
+	s.db = sql.OpenDB(&connector{store: s})
+	return s
+}
+
+// DB returns the handle services must use to begin transactions against the store.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+func (s *Store) nextID(table string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// write stages op on the transaction; it runs under the store lock on commit.
+func (s *Store) write(ctx context.Context, tx *sql.Tx, op func(s *Store)) *response.ErrorMsg {
+	if tx == nil {
+		return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Transaksi tidak ditemukan.")
+	}
+
+	ref := &journalRef{}
+	if _, err := tx.ExecContext(ctx, enlistQuery, ref); err != nil {
+		return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	if ref.tx == nil || ref.tx.store != s {
+		return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Transaksi bukan milik memory store.")
+	}
+
+	ref.tx.ops = append(ref.tx.ops, op)
+	return nil
+}
+
+func (s *Store) read(fn func(s *Store)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s)
+}
+
+func sortedIDs[T any](rows map[int]T) []int {
+	IDs := make([]int, 0, len(rows))
+	for ID := range rows {
+		IDs = append(IDs, ID)
+	}
+	sort.Ints(IDs)
+	return IDs
+}
+
+type memberKey struct {
+	classID, userID int
+}
+
+// classIDsOf lists in order the classes userID is a member of; call it under the store lock.
+func (s *Store) classIDsOf(userID int) []int {
+	var classIDs []int
+	for key := range s.members {
+		if key.userID == userID {
+			classIDs = append(classIDs, key.classID)
+		}
+	}
+	sort.Ints(classIDs)
+	return classIDs
+}
+
+// stamp copies a soft delete timestamp the way the SQL repositories store it.
+func stamp(deletedAt time.Time) *time.Time {
+	deletedAt = deletedAt.UTC()
+	return &deletedAt
+}
+
+// purgeable reports whether a row soft deleted at deletedAt is older than before.
+func purgeable(deletedAt *time.Time, before time.Time) bool {
+	return deletedAt != nil && deletedAt.Before(before)
+}
+
+// staleVersion reports whether an update expecting version expected would find
+// the row at current instead; 0 expects any version.
+func staleVersion(expected, current int) bool {
+	return expected != 0 && expected != current
+}
+
+// listed reports whether a row with name and audit passes filter; the name
+// matches by part and case-insensitively, like LIKE in the SQL listings.
+func listed(filter *domain.ListFilter, name string, audit domain.Audit) bool {
+	switch {
+	case !strings.Contains(strings.ToLower(name), strings.ToLower(filter.Name)):
+		return false
+	case filter.CreatedBy != 0 && (audit.CreatedBy == nil || *audit.CreatedBy != filter.CreatedBy):
+		return false
+	case filter.UpdatedBy != 0 && (audit.UpdatedBy == nil || *audit.UpdatedBy != filter.UpdatedBy):
+		return false
+	}
+	return within(audit.CreatedAt, filter.CreatedAfter, filter.CreatedBefore) && within(audit.UpdatedAt, filter.UpdatedAfter, filter.UpdatedBefore)
+}
+
+func within(at, after, before *time.Time) bool {
+	if after == nil && before == nil {
+		return true
+	}
+	return at != nil && (after == nil || at.After(*after)) && (before == nil || at.Before(*before))
+}
+
+// sortListed orders rows, given in ID order, by filter.Sort the way the SQL
+// listings do: ties stay in ID order.
+func sortListed[T any](rows []T, filter *domain.ListFilter, key func(row T) (name string, audit domain.Audit)) {
+	desc := strings.HasPrefix(filter.Sort, "-")
+	var less func(a, b T) bool
+	switch strings.TrimPrefix(filter.Sort, "-") {
+	case "id":
+		if desc {
+			for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+				rows[i], rows[j] = rows[j], rows[i]
+			}
+		}
+		return
+	case "name":
+		less = func(a, b T) bool {
+			nameA, _ := key(a)
+			nameB, _ := key(b)
+			return nameA < nameB
+		}
+	case "created_at":
+		less = func(a, b T) bool {
+			_, auditA := key(a)
+			_, auditB := key(b)
+			return timeOf(auditA.CreatedAt).Before(timeOf(auditB.CreatedAt))
+		}
+	case "updated_at":
+		less = func(a, b T) bool {
+			_, auditA := key(a)
+			_, auditB := key(b)
+			return timeOf(auditA.UpdatedAt).Before(timeOf(auditB.UpdatedAt))
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if desc {
+			return less(rows[j], rows[i])
+		}
+		return less(rows[i], rows[j])
+	})
+}
+
+// profiled reports whether the profile of userID matches filter, like the
+// profile subquery of the SQL listings; call it under the store lock.
+func (s *Store) profiled(userID int, filter domain.ProfileFilter) bool {
+	if filter.IsZero() {
+		return true
+	}
+	profile, ok := s.profiles[userID]
+	switch {
+	case !ok:
+		return false
+	case filter.NPM != "" && profile.NPM != filter.NPM:
+		return false
+	case filter.NIDN != "" && profile.NIDN != filter.NIDN:
+		return false
+	case filter.ProgramStudi != "" && (profile.ProgramStudi == "" || !strings.Contains(strings.ToLower(profile.ProgramStudi), strings.ToLower(filter.ProgramStudi))):
+		return false
+	case filter.Angkatan != 0 && profile.Angkatan != filter.Angkatan:
+		return false
+	}
+	return true
+}
+
+func timeOf(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func intSet(values []int) map[int]bool {
+	set := make(map[int]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+const enlistQuery = "MEMORY ENLIST"
+
+type journalRef struct {
+	tx *memoryTx
+}
+
+type connector struct {
+	store *Store
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{store: c.store}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return memoryDriver{}
+}
+
+type memoryDriver struct{}
+
+func (memoryDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("memory: gunakan Store.DB")
+}
+
+type conn struct {
+	store *Store
+	tx    *memoryTx
+}
+
+func (c *conn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("memory: query SQL tidak didukung")
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, errors.New("memory: transaksi sudah berjalan")
+	}
+	c.tx = &memoryTx{store: c.store, conn: c}
+	return c.tx, nil
+}
+
+func (c *conn) Ping(context.Context) error {
+	return nil
+}
+
+func (c *conn) CheckNamedValue(value *driver.NamedValue) error {
+	if _, ok := value.Value.(*journalRef); ok {
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if query != enlistQuery || len(args) != 1 {
+		return nil, errors.New("memory: query SQL tidak didukung")
+	}
+	ref, ok := args[0].Value.(*journalRef)
+	if !ok || c.tx == nil {
+		return nil, errors.New("memory: enlist hanya dapat dilakukan di dalam transaksi")
+	}
+	ref.tx = c.tx
+	return driver.ResultNoRows, nil
+}
+
+type memoryTx struct {
+	store *Store
+	conn  *conn
+	ops   []func(s *Store)
+}
+
+func (t *memoryTx) Commit() error {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+	for _, op := range t.ops {
+		op(t.store)
+	}
+	t.conn.tx = nil
+	return nil
+}
+
+func (t *memoryTx) Rollback() error {
+	t.ops = nil
+	t.conn.tx = nil
+	return nil
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+func TestStoreTransactions(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	classes := NewClassRepository(store)
+
+	tx, err := store.DB().Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errMsg := classes.InsertClass(ctx, tx, &domain.Class{Name: "TI-2A", KodeKelas: "ABCDEFGHJK"}); errMsg != nil {
+		t.Fatal(errMsg.Msg)
+	}
+	if _, isRegistered, _ := classes.FindClassByID(ctx, store.DB(), 1); isRegistered {
+		t.Fatal("uncommitted insert must not be visible")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if _, isRegistered, _ := classes.FindClassByID(ctx, store.DB(), 1); isRegistered {
+		t.Fatal("rolled back insert must not be visible")
+	}
+
+	tx, err = store.DB().Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errMsg := classes.InsertClass(ctx, tx, &domain.Class{Name: "TI-2B", KodeKelas: "KLMNPQRSTU"}); errMsg != nil {
+		t.Fatal(errMsg.Msg)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	class, isRegistered, _ := classes.FindClassByID(ctx, store.DB(), 2)
+	if !isRegistered || class.Name != "TI-2B" {
+		t.Fatalf("expected committed class with id 2, got %+v", class)
+	}
+}
+
+func TestStoreWriteOutsideTransaction(t *testing.T) {
+	store := NewStore()
+	if _, errMsg := NewRoomRepository(store).DeleteRoomByID(context.Background(), nil, 1, 0, time.Now()); errMsg == nil {
+		t.Fatal("expected an error without a transaction")
+	}
+}
+
+func TestClassKodeKelasUnique(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	classes := NewClassRepository(store)
+
+	tx, _ := store.DB().Begin()
+	if _, errMsg := classes.InsertClass(ctx, tx, &domain.Class{Name: "TI-2A", KodeKelas: "ABCDEFGHJK"}); errMsg != nil {
+		t.Fatal(errMsg.Msg)
+	}
+	tx.Commit()
+
+	tx, _ = store.DB().Begin()
+	defer tx.Rollback()
+	if _, errMsg := classes.InsertClass(ctx, tx, &domain.Class{Name: "TI-2B", KodeKelas: "ABCDEFGHJK"}); errMsg == nil || errMsg.ErrorKey != exception.ERR_ALREADY_USE {
+		t.Fatalf("expected ERR_ALREADY_USE, got %+v", errMsg)
+	}
+	if _, errMsg := classes.UpdateKodeKelas(ctx, tx, &domain.Class{ID: 1, KodeKelas: "ABCDEFGHJK"}); errMsg != nil {
+		t.Fatalf("keeping its own kode kelas must be allowed: %v", errMsg.Msg)
+	}
+}
@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+)
+
+type UsersRepository struct {
+	Store *Store
+}
+
+func NewUsersRepository(store *Store) repository.UsersRepository {
+	return &UsersRepository{Store: store}
+}
+
+func (u *UsersRepository) InsertDataUser(ctx context.Context, tx *sql.Tx, user *domain.Users) (bool, *response.ErrorMsg) {
+	row := domain.Users{Name: user.Name, Email: user.Email, Password: user.Password, Role: user.Role, ClassID: user.ClassID}
+	row.ID = u.Store.nextID("users")
+	row.Version = 1
+	row.Audit = domain.NewAudit(repository.Stamp(ctx))
+	errMsg := u.Store.write(ctx, tx, func(s *Store) {
+		s.users[row.ID] = row
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	user.ID, user.Version, user.Audit = row.ID, row.Version, row.Audit
+	return true, nil
+}
+
+func (u *UsersRepository) UpdateDataUser(ctx context.Context, tx *sql.Tx, user *domain.Users) (bool, *response.ErrorMsg) {
+	update := *user
+	var stale bool
+	u.Store.read(func(s *Store) { stale = staleVersion(update.Version, s.users[update.ID].Version) })
+	if stale {
+		return false, helpers.ToVersionConflict()
+	}
+	at, by := repository.Stamp(ctx)
+	errMsg := u.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.users[update.ID]; ok && row.DeletedAt == nil {
+			row.Version++
+			row.Touch(at, by)
+			row.Name, row.Email, row.Role, row.ClassID = update.Name, update.Email, update.Role, update.ClassID
+			s.users[update.ID] = row
+		}
+	})
+	if errMsg == nil && user.Version != 0 {
+		user.Version++
+	}
+	return errMsg == nil, errMsg
+}
+
+func (u *UsersRepository) DeleteDataUser(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := u.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.users[ID]; ok && row.DeletedAt == nil {
+			row.DeletedAt = stamp(deletedAt)
+			row.Version++
+			row.Touch(at, by)
+			s.users[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (u *UsersRepository) RestoreDataUser(ctx context.Context, tx *sql.Tx, ID int) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := u.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.users[ID]; ok {
+			row.DeletedAt = nil
+			row.Version++
+			row.Touch(at, by)
+			s.users[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (u *UsersRepository) FindDeletedUserByID(ctx context.Context, db *sql.DB, ID int) (*domain.Users, bool, *response.ErrorMsg) {
+	var user *domain.Users
+	u.Store.read(func(s *Store) {
+		if row, ok := s.users[ID]; ok && row.DeletedAt != nil {
+			user = &domain.Users{ID: row.ID, Name: row.Name, Email: row.Email, Role: row.Role, ClassID: row.ClassID, DeletedAt: row.DeletedAt}
+		}
+	})
+	if user == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Data User terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	return user, true, nil
+}
+
+func (u *UsersRepository) PurgeUsers(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	var IDs []int
+	u.Store.read(func(s *Store) {
+		for ID, row := range s.users {
+			if purgeable(row.DeletedAt, before) {
+				IDs = append(IDs, ID)
+			}
+		}
+	})
+	errMsg := u.Store.write(ctx, tx, func(s *Store) {
+		for _, ID := range IDs {
+			delete(s.users, ID)
+		}
+	})
+	if errMsg != nil {
+		return 0, errMsg
+	}
+	return int64(len(IDs)), nil
+}
+
+func (u *UsersRepository) FindUserByID(ctx context.Context, db *sql.DB, ID int) (*domain.Users, bool, *response.ErrorMsg) {
+	var user *domain.Users
+	u.Store.read(func(s *Store) {
+		if row, ok := s.users[ID]; ok && row.DeletedAt == nil {
+			user = &domain.Users{ID: row.ID, Name: row.Name, Email: row.Email, Password: row.Password, Role: row.Role, ClassID: row.ClassID}
+		}
+	})
+	if user == nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Data User ID tidak ditemukan.")
+	}
+	return user, true, nil
+}
+
+func (u *UsersRepository) FindUserByEmail(ctx context.Context, db *sql.DB, email string) (*domain.Users, *response.ErrorMsg) {
+	row, ok := u.findByEmail(email)
+	if !ok {
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Email tidak ditemukan.")
+	}
+	return &domain.Users{ID: row.ID, Name: row.Name, Email: row.Email, Role: row.Role, ClassID: row.ClassID}, nil
+}
+
+func (u *UsersRepository) IsEmailRegistered(ctx context.Context, db *sql.DB, email string) (*domain.Users, bool, *response.ErrorMsg) {
+	row, ok := u.findByEmail(email)
+	if !ok {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, "ERR_NOT_FOUND", "Data tidak ditemukan")
+	}
+	return &domain.Users{ID: row.ID, Email: row.Email}, true, nil
+}
+
+func (u *UsersRepository) ChangePasswordUser(ctx context.Context, tx *sql.Tx, newPass string, ID int) (bool, *response.ErrorMsg) {
+	at, by := repository.Stamp(ctx)
+	errMsg := u.Store.write(ctx, tx, func(s *Store) {
+		if row, ok := s.users[ID]; ok && row.DeletedAt == nil {
+			row.Password = newPass
+			row.Version++
+			row.Touch(at, by)
+			s.users[ID] = row
+		}
+	})
+	return errMsg == nil, errMsg
+}
+
+func (u *UsersRepository) FindUserByClassID(ctx context.Context, db *sql.DB, classID int) ([]domain.Users, bool, *response.ErrorMsg) {
+	var users []domain.Users
+	for _, user := range u.findClassUsers([]int{classID}) {
+		users = append(users, domain.Users{ID: user.ID, Name: user.Name, ClassID: user.ClassID})
+	}
+	return users, len(users) > 0, nil
+}
+
+func (u *UsersRepository) findByEmail(email string) (row domain.Users, found bool) {
+	u.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.users) {
+			if user := s.users[ID]; user.DeletedAt == nil && user.Email == email {
+				row, found = user, true
+				return
+			}
+		}
+	})
+	return row, found
+}
+
+func (u *UsersRepository) FindUsersByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Users, *response.ErrorMsg) {
+	wanted := intSet(IDs)
+	return u.findUsers(func(row domain.Users) bool { return wanted[row.ID] }), nil
+}
+
+func (u *UsersRepository) FindUsersByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) ([]*domain.Users, *response.ErrorMsg) {
+	return u.findClassUsers(classIDs), nil
+}
+
+func (u *UsersRepository) findUsers(match func(row domain.Users) bool) []*domain.Users {
+	var users []*domain.Users
+	u.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.users) {
+			if row := s.users[ID]; row.DeletedAt == nil && match(row) {
+				users = append(users, &domain.Users{ID: row.ID, Name: row.Name, Email: row.Email, Role: row.Role, ClassID: row.ClassID})
+			}
+		}
+	})
+	return users
+}
+
+// findClassUsers lists the members of classIDs once per membership, with
+// ClassID set to the class they were found through, like the SQL join.
+func (u *UsersRepository) findClassUsers(classIDs []int) []*domain.Users {
+	wanted := intSet(classIDs)
+	var users []*domain.Users
+	u.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.users) {
+			row := s.users[ID]
+			if row.DeletedAt != nil {
+				continue
+			}
+			for _, classID := range s.classIDsOf(ID) {
+				if wanted[classID] {
+					users = append(users, &domain.Users{ID: row.ID, Name: row.Name, Email: row.Email, Role: row.Role, ClassID: classID})
+				}
+			}
+		}
+	})
+	return users
+}
+
+func (u *UsersRepository) CountUsers(ctx context.Context, db *sql.DB) (int, *response.ErrorMsg) {
+	var count int
+	u.Store.read(func(s *Store) {
+		for _, row := range s.users {
+			if row.DeletedAt == nil {
+				count++
+			}
+		}
+	})
+	return count, nil
+}
+
+func (u *UsersRepository) FindAllUsers(ctx context.Context, db *sql.DB, role string, filter *domain.ListFilter) ([]*domain.Users, *response.ErrorMsg) {
+	var users []*domain.Users
+	u.Store.read(func(s *Store) {
+		for _, ID := range sortedIDs(s.users) {
+			row := s.users[ID]
+			if row.DeletedAt == nil && (role == "" || row.Role == role) && listed(filter, row.Name, row.Audit) && s.profiled(ID, filter.Profile) {
+				users = append(users, &domain.Users{ID: row.ID, Name: row.Name, Email: row.Email, Role: row.Role, ClassID: row.ClassID, Version: row.Version, Audit: row.Audit})
+			}
+		}
+	})
+	sortListed(users, filter, func(row *domain.Users) (string, domain.Audit) { return row.Name, row.Audit })
+	return users, nil
+}
+
+// EachUser reads the matching rows under the store lock and calls fn after
+// releasing it, so fn may take its time.
+func (u *UsersRepository) EachUser(ctx context.Context, db *sql.DB, role string, filter *domain.ListFilter, fn func(user *domain.Users) error) *response.ErrorMsg {
+	users, errMsg := u.FindAllUsers(ctx, db, role, filter)
+	if errMsg != nil {
+		return errMsg
+	}
+	for _, row := range users {
+		if err := fn(row); err != nil {
+			return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+	}
+	return nil
+}
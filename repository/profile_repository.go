@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+)
+
+type ProfileRepository interface {
+	InsertProfile(ctx context.Context, tx *sql.Tx, profile *domain.Profile) (isSuccess bool, errMsg *response.ErrorMsg)
+	UpdateProfile(ctx context.Context, tx *sql.Tx, profile *domain.Profile) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindProfileByUserID(ctx context.Context, db *sql.DB, userID int) (profile *domain.Profile, isFound bool, errMsg *response.ErrorMsg)
+	FindProfilesByUserIDs(ctx context.Context, db *sql.DB, userIDs []int) (profiles map[int]*domain.Profile, errMsg *response.ErrorMsg)
+	FindProfileByNPM(ctx context.Context, db *sql.DB, npm string) (profile *domain.Profile, isFound bool, errMsg *response.ErrorMsg)
+	FindProfileByNIDN(ctx context.Context, db *sql.DB, nidn string) (profile *domain.Profile, isFound bool, errMsg *response.ErrorMsg)
+	PurgeProfiles(ctx context.Context, tx *sql.Tx, before time.Time) (purged int64, errMsg *response.ErrorMsg)
+}
+
+type ProfileRepositoryImplementation struct {
+	Dialect Dialect
+}
+
+func NewProfileRepositoryImplementation(dialect Dialect) ProfileRepository {
+	return &ProfileRepositoryImplementation{Dialect: dialect}
+}
+
+// profileColumns are the columns scanProfile reads, before the audit columns.
+const profileColumns = "user_id, npm, nidn, program_studi, angkatan, phone, avatar"
+
+func (p *ProfileRepositoryImplementation) InsertProfile(ctx context.Context, tx *sql.Tx, profile *domain.Profile) (bool, *response.ErrorMsg) {
+	querySql := "INSERT INTO profile(" + profileColumns + ", " + auditColumns + ") VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	args := append([]any{profile.UserID}, profileValues(profile)...)
+	_, err := tx.ExecContext(ctx, p.Dialect.Rebind(querySql), append(args, insertAudit(ctx, &profile.Audit)...)...)
+	if err != nil {
+		return false, p.writeError(ctx, err)
+	}
+	return true, nil
+}
+
+func (p *ProfileRepositoryImplementation) UpdateProfile(ctx context.Context, tx *sql.Tx, profile *domain.Profile) (bool, *response.ErrorMsg) {
+	at, by := Stamp(ctx)
+	querySql := "UPDATE profile SET updated_at = ?, updated_by = ?, npm = ?, nidn = ?, program_studi = ?, angkatan = ?, phone = ?, avatar = ? WHERE user_id = ?"
+	args := append([]any{at, intValue(by)}, profileValues(profile)...)
+	_, err := tx.ExecContext(ctx, p.Dialect.Rebind(querySql), append(args, profile.UserID)...)
+	if err != nil {
+		return false, p.writeError(ctx, err)
+	}
+	profile.Touch(at, by)
+	return true, nil
+}
+
+// profileValues are the arguments for the profile columns after user_id;
+// empty fields are stored as NULL so the unique indexes skip them.
+func profileValues(profile *domain.Profile) []any {
+	return []any{stringValue(profile.NPM), stringValue(profile.NIDN), stringValue(profile.ProgramStudi), intValue(nonZero(profile.Angkatan)), stringValue(profile.Phone), stringValue(profile.Avatar)}
+}
+
+func stringValue(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nonZero(n int) *int {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
+// writeError reports an NPM or NIDN another user took in the meantime as
+// ERR_ALREADY_USE, like the check the service makes before writing.
+func (p *ProfileRepositoryImplementation) writeError(ctx context.Context, err error) *response.ErrorMsg {
+	if p.Dialect.IsUniqueViolation(err) {
+		return helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "NPM atau NIDN sudah digunakan.")
+	}
+	return internalError(ctx, err)
+}
+
+func (p *ProfileRepositoryImplementation) FindProfileByUserID(ctx context.Context, db *sql.DB, userID int) (*domain.Profile, bool, *response.ErrorMsg) {
+	return p.findProfile(ctx, db, "user_id = ?", userID)
+}
+
+func (p *ProfileRepositoryImplementation) FindProfileByNPM(ctx context.Context, db *sql.DB, npm string) (*domain.Profile, bool, *response.ErrorMsg) {
+	return p.findProfile(ctx, db, "npm = ?", npm)
+}
+
+func (p *ProfileRepositoryImplementation) FindProfileByNIDN(ctx context.Context, db *sql.DB, nidn string) (*domain.Profile, bool, *response.ErrorMsg) {
+	return p.findProfile(ctx, db, "nidn = ?", nidn)
+}
+
+func (p *ProfileRepositoryImplementation) findProfile(ctx context.Context, db *sql.DB, where string, args ...any) (*domain.Profile, bool, *response.ErrorMsg) {
+	querySql := "SELECT " + profileColumns + ", " + auditColumns + " FROM profile WHERE " + where
+	rows, err := db.QueryContext(ctx, p.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Profil tidak ditemukan.")
+	}
+	profile, err := scanProfile(rows)
+	if err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	return profile, true, nil
+}
+
+// FindProfilesByUserIDs maps the users of userIDs that have a profile to it.
+func (p *ProfileRepositoryImplementation) FindProfilesByUserIDs(ctx context.Context, db *sql.DB, userIDs []int) (map[int]*domain.Profile, *response.ErrorMsg) {
+	profiles := map[int]*domain.Profile{}
+	if len(userIDs) == 0 {
+		return profiles, nil
+	}
+
+	querySql := "SELECT " + profileColumns + ", " + auditColumns + " FROM profile WHERE user_id IN " + inClause(len(userIDs))
+	rows, err := db.QueryContext(ctx, p.Dialect.Rebind(querySql), intArgs(userIDs)...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, internalError(ctx, err)
+		}
+		profiles[profile.UserID] = profile
+	}
+	if err := rows.Err(); err != nil {
+		return nil, internalError(ctx, err)
+	}
+
+	return profiles, nil
+}
+
+// PurgeProfiles removes the profiles of users soft deleted before before,
+// ahead of the users themselves.
+func (p *ProfileRepositoryImplementation) PurgeProfiles(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	querySql := "DELETE FROM profile WHERE user_id IN (SELECT id FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?)"
+	result, err := tx.ExecContext(ctx, p.Dialect.Rebind(querySql), before.UTC())
+	if err != nil {
+		return 0, internalError(ctx, err)
+	}
+	n, _ := result.RowsAffected()
+	return n, nil
+}
+
+// scanProfile reads the profile columns and the audit columns.
+func scanProfile(rows *sql.Rows) (*domain.Profile, error) {
+	var profile domain.Profile
+	var npm, nidn, programStudi, phone, avatar sql.NullString
+	var angkatan sql.NullInt64
+	if err := scanAudited(rows, &profile.Audit, &profile.UserID, &npm, &nidn, &programStudi, &angkatan, &phone, &avatar); err != nil {
+		return nil, err
+	}
+	profile.NPM, profile.NIDN, profile.ProgramStudi, profile.Phone, profile.Avatar = npm.String, nidn.String, programStudi.String, phone.String, avatar.String
+	profile.Angkatan = int(angkatan.Int64)
+	return &profile, nil
+}
+
+// profileWhere returns the condition, to add to a WHERE clause, that keeps
+// the rows whose column, a user ID, has a profile matching filter, and its
+// arguments. A zero filter adds nothing.
+func (d Dialect) profileWhere(column string, filter domain.ProfileFilter) (string, []any) {
+	if filter.IsZero() {
+		return "", nil
+	}
+
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	if filter.NPM != "" {
+		add("npm = ?", filter.NPM)
+	}
+	if filter.NIDN != "" {
+		add("nidn = ?", filter.NIDN)
+	}
+	if filter.ProgramStudi != "" {
+		add("program_studi "+d.Like()+" ?", "%"+filter.ProgramStudi+"%")
+	}
+	if filter.Angkatan != 0 {
+		add("angkatan = ?", filter.Angkatan)
+	}
+	return " AND " + column + " IN (SELECT user_id FROM profile WHERE " + strings.Join(conditions, " AND ") + ")", args
+}
@@ -0,0 +1,17 @@
+package repository
+
+import "strings"
+
+// inClause returns "(?, ?, ...)" with n placeholders for an IN filter; Rebind
+// turns them into the dialect's bind style like any other '?'.
+func inClause(n int) string {
+	return "(" + strings.TrimSuffix(strings.Repeat("?, ", n), ", ") + ")"
+}
+
+func intArgs(values []int) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
@@ -8,60 +8,135 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"net/http"
+	"time"
 )
 
 type RoomRepository interface {
 	InsertRoom(ctx context.Context, tx *sql.Tx, room *domain.Room) (isSuccess bool, errMsg *response.ErrorMsg)
 	UpdateRoom(ctx context.Context, tx *sql.Tx, room *domain.Room) (isSuccess bool, errMsg *response.ErrorMsg)
-	DeleteRoomByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteRoomByID(ctx context.Context, tx *sql.Tx, ID, version int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteRoomsByLectureID(ctx context.Context, tx *sql.Tx, lectureID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	ReassignRooms(ctx context.Context, tx *sql.Tx, fromLectureID, toLectureID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	RestoreRoomByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	RestoreRoomsByLectureID(ctx context.Context, tx *sql.Tx, lectureID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindDeletedRoomByID(ctx context.Context, db *sql.DB, ID int) (room *domain.Room, isRegistered bool, errMsg *response.ErrorMsg)
+	PurgeRoom(ctx context.Context, tx *sql.Tx, before time.Time) (purged int64, errMsg *response.ErrorMsg)
 	FindRoomByID(ctx context.Context, db *sql.DB, ID int) (room *domain.Room, isRegistered bool, errMsg *response.ErrorMsg)
+	FindRoomByLectureIDs(ctx context.Context, db *sql.DB, lectureIDs []int) (rooms []*domain.Room, errMsg *response.ErrorMsg)
+	FindRoomByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (rooms map[int][]*domain.Room, errMsg *response.ErrorMsg)
+	FindAllRoom(ctx context.Context, db *sql.DB, filter *domain.ListFilter) (rooms []*domain.Room, errMsg *response.ErrorMsg)
+	EachRoom(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(room *domain.Room) error) (errMsg *response.ErrorMsg)
+	CountActiveRooms(ctx context.Context, db *sql.DB, at time.Time) (count int, errMsg *response.ErrorMsg)
 }
 
 type RoomRepositoryImplementation struct {
+	Dialect Dialect
 }
 
-func NewRoomRepositoryImplementation() RoomRepository {
-	return &RoomRepositoryImplementation{}
+func NewRoomRepositoryImplementation(dialect Dialect) RoomRepository {
+	return &RoomRepositoryImplementation{Dialect: dialect}
 }
 
 func (r *RoomRepositoryImplementation) InsertRoom(ctx context.Context, tx *sql.Tx, room *domain.Room) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "INSERT INTO room(name, url, lecture_id, start_room, end_room) VALUES(?,?,?,?,?)"
-	_, err := tx.ExecContext(ctx, querySql, &room.Name, &room.URL, &room.LectureID, &room.StartRoom, &room.EndRoom)
+	querySql := "INSERT INTO room(name, url, lecture_id, start_room, end_room, " + auditColumns + ") VALUES(?,?,?,?,?,?,?,?,?)"
+	args := append([]any{&room.Name, &room.URL, &room.LectureID, &room.StartRoom, &room.EndRoom}, insertAudit(ctx, &room.Audit)...)
+	ID, err := r.Dialect.InsertID(ctx, tx, querySql, args...)
 	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return false, internalError(ctx, err)
 	}
+	room.ID = int(ID)
+	room.Version = 1
 	return true, nil
 }
 
 func (r *RoomRepositoryImplementation) UpdateRoom(ctx context.Context, tx *sql.Tx, room *domain.Room) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "UPDATE room SET name = ?, lecture_id = ?, start_room = ?, end_room = ? WHERE id = ?"
-	_, err := tx.ExecContext(ctx, querySql, &room.Name, &room.LectureID, &room.StartRoom, &room.EndRoom, &room.ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	errMsg = r.Dialect.updateVersioned(ctx, tx, "room", "name = ?, url = ?, lecture_id = ?, start_room = ?, end_room = ?", "id = ?", room.Version,
+		room.Name, room.URL, room.LectureID, room.StartRoom, room.EndRoom, room.ID)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	if room.Version != 0 {
+		room.Version++
 	}
 	return true, nil
 }
 
-func (r *RoomRepositoryImplementation) DeleteRoomByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "DELETE FROM room WHERE id = ?"
-	_, err := tx.ExecContext(ctx, querySql, ID)
+func (r *RoomRepositoryImplementation) DeleteRoomByID(ctx context.Context, tx *sql.Tx, ID, version int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := r.Dialect.softDeleteVersioned(ctx, tx, "room", "id = ?", deletedAt, version, ID); errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+// DeleteRoomsByLectureID soft deletes the live rooms of a lecture with the lecture's own stamp.
+func (r *RoomRepositoryImplementation) DeleteRoomsByLectureID(ctx context.Context, tx *sql.Tx, lectureID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := r.Dialect.softDelete(ctx, tx, "room", "lecture_id = ?", deletedAt, lectureID); errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+// ReassignRooms moves the live rooms of one lecture to another.
+func (r *RoomRepositoryImplementation) ReassignRooms(ctx context.Context, tx *sql.Tx, fromLectureID, toLectureID int) (isSuccess bool, errMsg *response.ErrorMsg) {
+	stamp, args := touch(ctx)
+	querySql := "UPDATE room SET " + stamp + ", lecture_id = ? WHERE lecture_id = ? AND deleted_at IS NULL"
+	_, err := tx.ExecContext(ctx, r.Dialect.Rebind(querySql), append(args, toLectureID, fromLectureID)...)
 	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return false, internalError(ctx, err)
+	}
+	return true, nil
+}
+
+func (r *RoomRepositoryImplementation) RestoreRoomByID(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := r.Dialect.restore(ctx, tx, "room", "id = ?", ID); errMsg != nil {
+		return false, errMsg
 	}
 	return true, nil
 }
 
+// RestoreRoomsByLectureID restores only the rooms deleted together with the lecture, i.e. stamped with deletedAt.
+func (r *RoomRepositoryImplementation) RestoreRoomsByLectureID(ctx context.Context, tx *sql.Tx, lectureID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := r.Dialect.restore(ctx, tx, "room", "lecture_id = ? AND deleted_at = ?", lectureID, deletedAt.UTC()); errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+func (r *RoomRepositoryImplementation) FindDeletedRoomByID(ctx context.Context, db *sql.DB, ID int) (*domain.Room, bool, *response.ErrorMsg) {
+	querySql := "SELECT id, name, url, lecture_id, start_room, end_room, version, deleted_at FROM room WHERE id = ? AND deleted_at IS NOT NULL"
+	rows, err := db.QueryContext(ctx, r.Dialect.Rebind(querySql), ID)
+	if err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Room terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	var room domain.Room
+	var deletedAt nullTime
+	if err := rows.Scan(&room.ID, &room.Name, &room.URL, &room.LectureID, &room.StartRoom, &room.EndRoom, &room.Version, &deletedAt); err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	room.DeletedAt = deletedAt.Ptr()
+	return &room, true, nil
+}
+
+func (r *RoomRepositoryImplementation) PurgeRoom(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	return r.Dialect.purge(ctx, tx, "room", before)
+}
+
 func (r *RoomRepositoryImplementation) FindRoomByID(ctx context.Context, db *sql.DB, ID int) (Room *domain.Room, isRegistered bool, errMsg *response.ErrorMsg) {
-	querySql := "SELECT id, name, url, lecture_id, start_room, end_room FROM room WHERE id = ?"
-	row, err := db.QueryContext(ctx, querySql, ID)
+	querySql := "SELECT id, name, url, lecture_id, start_room, end_room, version, " + auditColumns + " FROM room WHERE id = ? AND deleted_at IS NULL"
+	row, err := db.QueryContext(ctx, r.Dialect.Rebind(querySql), ID)
 	if err != nil {
-		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, false, internalError(ctx, err)
 	}
 	defer row.Close()
 
 	var room domain.Room
 	if row.Next() {
-		err := row.Scan(&room.ID, &room.Name, &room.URL, &room.LectureID, &room.StartRoom, &room.EndRoom)
+		err := scanAudited(row, &room.Audit, &room.ID, &room.Name, &room.URL, &room.LectureID, &room.StartRoom, &room.EndRoom, &room.Version)
 		if err != nil {
 			return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, err)
 		} else {
@@ -71,3 +146,107 @@ func (r *RoomRepositoryImplementation) FindRoomByID(ctx context.Context, db *sql
 		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Room dengan ID tidak ditemukan.")
 	}
 }
+
+func (r *RoomRepositoryImplementation) FindAllRoom(ctx context.Context, db *sql.DB, filter *domain.ListFilter) ([]*domain.Room, *response.ErrorMsg) {
+	var rooms []*domain.Room
+	errMsg := r.EachRoom(ctx, db, filter, func(room *domain.Room) error {
+		rooms = append(rooms, room)
+		return nil
+	})
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return rooms, nil
+}
+
+// EachRoom calls fn for every room matching filter while the rows are read,
+// stopping at the first error fn returns.
+func (r *RoomRepositoryImplementation) EachRoom(ctx context.Context, db *sql.DB, filter *domain.ListFilter, fn func(room *domain.Room) error) *response.ErrorMsg {
+	where, args := r.Dialect.listWhere(filter)
+	querySql := "SELECT id, name, url, lecture_id, start_room, end_room, version, " + auditColumns + " FROM room WHERE " + where + " AND deleted_at IS NULL " + listOrderBy(filter)
+	rows, err := db.QueryContext(ctx, r.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var room domain.Room
+		err := scanAudited(rows, &room.Audit, &room.ID, &room.Name, &room.URL, &room.LectureID, &room.StartRoom, &room.EndRoom, &room.Version)
+		if err != nil {
+			return internalError(ctx, err)
+		}
+		if err := fn(&room); err != nil {
+			return internalError(ctx, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return internalError(ctx, err)
+	}
+
+	return nil
+}
+
+func (r *RoomRepositoryImplementation) FindRoomByLectureIDs(ctx context.Context, db *sql.DB, lectureIDs []int) ([]*domain.Room, *response.ErrorMsg) {
+	if len(lectureIDs) == 0 {
+		return nil, nil
+	}
+
+	querySql := "SELECT id, name, url, lecture_id, start_room, end_room, version, " + auditColumns + " FROM room WHERE lecture_id IN " + inClause(len(lectureIDs)) + " AND deleted_at IS NULL ORDER BY start_room, id"
+	rows, err := db.QueryContext(ctx, r.Dialect.Rebind(querySql), intArgs(lectureIDs)...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var rooms []*domain.Room
+	for rows.Next() {
+		var room domain.Room
+		if err := scanAudited(rows, &room.Audit, &room.ID, &room.Name, &room.URL, &room.LectureID, &room.StartRoom, &room.EndRoom, &room.Version); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		rooms = append(rooms, &room)
+	}
+
+	return rooms, nil
+}
+
+// FindRoomByClassIDs groups by class the rooms held by a lecture whose user is a member of one of classIDs.
+func (r *RoomRepositoryImplementation) FindRoomByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (map[int][]*domain.Room, *response.ErrorMsg) {
+	rooms := map[int][]*domain.Room{}
+	if len(classIDs) == 0 {
+		return rooms, nil
+	}
+
+	querySql := "SELECT room.id, room.name, room.url, room.lecture_id, room.start_room, room.end_room, room.version, class_member.class_id FROM room " +
+		"JOIN lecture ON lecture.id = room.lecture_id JOIN class_member ON class_member.user_id = lecture.user_id " +
+		"WHERE class_member.class_id IN " + inClause(len(classIDs)) + " AND room.deleted_at IS NULL AND lecture.deleted_at IS NULL ORDER BY room.start_room, room.id"
+	rows, err := db.QueryContext(ctx, r.Dialect.Rebind(querySql), intArgs(classIDs)...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var room domain.Room
+		var classID int
+		if err := rows.Scan(&room.ID, &room.Name, &room.URL, &room.LectureID, &room.StartRoom, &room.EndRoom, &room.Version, &classID); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		rooms[classID] = append(rooms[classID], &room)
+	}
+
+	return rooms, nil
+}
+
+// CountActiveRooms counts the live rooms whose schedule includes at, compared
+// as the "yyyy-mm-dd HH:mm:ss" wall clock the rooms are stored with.
+func (r *RoomRepositoryImplementation) CountActiveRooms(ctx context.Context, db *sql.DB, at time.Time) (int, *response.ErrorMsg) {
+	querySql := "SELECT COUNT(*) FROM room WHERE start_room <= ? AND end_room >= ? AND deleted_at IS NULL"
+	now := at.Format("2006-01-02 15:04:05")
+	var count int
+	if err := db.QueryRowContext(ctx, r.Dialect.Rebind(querySql), now, now).Scan(&count); err != nil {
+		return 0, internalError(ctx, err)
+	}
+	return count, nil
+}
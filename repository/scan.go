@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+var timeLayouts = []string{"2006-01-02 15:04:05.999999999", time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00"}
+
+// nullTime scans a nullable DATETIME/TIMESTAMP column. Postgres and SQLite
+// hand over time.Time, MySQL without parseTime hands over the text.
+type nullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (n *nullTime) Scan(value any) error {
+	n.Time, n.Valid = time.Time{}, false
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		n.Time, n.Valid = value.UTC(), true
+		return nil
+	case []byte:
+		return n.parse(string(value))
+	case string:
+		return n.parse(value)
+	}
+	return fmt.Errorf("tipe waktu %T tidak didukung", value)
+}
+
+func (n *nullTime) parse(value string) error {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			n.Time, n.Valid = t.UTC(), true
+			return nil
+		}
+	}
+	return fmt.Errorf("format waktu %q tidak dikenali", value)
+}
+
+func (n nullTime) Ptr() *time.Time {
+	if !n.Valid {
+		return nil
+	}
+	t := n.Time
+	return &t
+}
+
+// timeValue is the argument for a nullable time column, stored in UTC.
+func timeValue(t *time.Time) driver.Value {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
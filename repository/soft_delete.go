@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+)
+
+// Rows are soft deleted by stamping deleted_at; every finder only reads rows
+// where it is NULL. Dependents deleted along with a row get the same stamp,
+// which is how a restore finds them again.
+
+// softDelete stamps deletedAt on the live rows of table matching where.
+func (d Dialect) softDelete(ctx context.Context, tx *sql.Tx, table, where string, deletedAt time.Time, args ...any) *response.ErrorMsg {
+	stamp, stampArgs := touch(ctx)
+	querySql := "UPDATE " + table + " SET deleted_at = ?, " + stamp + " WHERE " + where + " AND deleted_at IS NULL"
+	_, err := tx.ExecContext(ctx, d.Rebind(querySql), append(append([]any{deletedAt.UTC()}, stampArgs...), args...)...)
+	if err != nil {
+		return internalError(ctx, err)
+	}
+	return nil
+}
+
+// softDeleteVersioned stamps deletedAt on the live row of table matching where
+// while it is still at version, see updateVersioned. A version of 0 skips the
+// check.
+func (d Dialect) softDeleteVersioned(ctx context.Context, tx *sql.Tx, table, where string, deletedAt time.Time, version int, args ...any) *response.ErrorMsg {
+	return d.updateVersioned(ctx, tx, table, "deleted_at = ?", where, version, append([]any{deletedAt.UTC()}, args...)...)
+}
+
+// restore clears deleted_at on the deleted rows of table matching where.
+func (d Dialect) restore(ctx context.Context, tx *sql.Tx, table, where string, args ...any) *response.ErrorMsg {
+	stamp, stampArgs := touch(ctx)
+	querySql := "UPDATE " + table + " SET deleted_at = NULL, " + stamp + " WHERE " + where + " AND deleted_at IS NOT NULL"
+	_, err := tx.ExecContext(ctx, d.Rebind(querySql), append(stampArgs, args...)...)
+	if err != nil {
+		return internalError(ctx, err)
+	}
+	return nil
+}
+
+// purge removes for good the rows of table deleted before before.
+func (d Dialect) purge(ctx context.Context, tx *sql.Tx, table string, before time.Time) (int64, *response.ErrorMsg) {
+	querySql := "DELETE FROM " + table + " WHERE deleted_at IS NOT NULL AND deleted_at < ?"
+	result, err := tx.ExecContext(ctx, d.Rebind(querySql), before.UTC())
+	if err != nil {
+		return 0, internalError(ctx, err)
+	}
+	n, _ := result.RowsAffected()
+	return n, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"net/http"
+	"time"
 
 	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
 	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
@@ -14,67 +15,111 @@ import (
 type UsersRepository interface {
 	InsertDataUser(ctx context.Context, tx *sql.Tx, user *domain.Users) (isSuccess bool, errMsg *response.ErrorMsg)
 	UpdateDataUser(ctx context.Context, tx *sql.Tx, user *domain.Users) (isSuccess bool, errMsg *response.ErrorMsg)
-	DeleteDataUser(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteDataUser(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg)
+	RestoreDataUser(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindDeletedUserByID(ctx context.Context, db *sql.DB, ID int) (userResponse *domain.Users, isRegistered bool, errMsg *response.ErrorMsg)
+	PurgeUsers(ctx context.Context, tx *sql.Tx, before time.Time) (purged int64, errMsg *response.ErrorMsg)
 	FindUserByID(ctx context.Context, db *sql.DB, ID int) (userResponse *domain.Users, isRegistered bool, errMsg *response.ErrorMsg)
 	FindUserByEmail(ctx context.Context, db *sql.DB, email string) (userResponse *domain.Users, errMsg *response.ErrorMsg)
 	IsEmailRegistered(ctx context.Context, db *sql.DB, email string) (userResponse *domain.Users, isRegistered bool, errMsg *response.ErrorMsg)
 	ChangePasswordUser(ctx context.Context, tx *sql.Tx, newPass string, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
 	FindUserByClassID(ctx context.Context, db *sql.DB, classID int) (userResponse []domain.Users, isRegistered bool, errMsg *response.ErrorMsg)
+	FindUsersByIDs(ctx context.Context, db *sql.DB, IDs []int) (users []*domain.Users, errMsg *response.ErrorMsg)
+	FindUsersByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) (users []*domain.Users, errMsg *response.ErrorMsg)
+	CountUsers(ctx context.Context, db *sql.DB) (count int, errMsg *response.ErrorMsg)
+	FindAllUsers(ctx context.Context, db *sql.DB, role string, filter *domain.ListFilter) (users []*domain.Users, errMsg *response.ErrorMsg)
+	EachUser(ctx context.Context, db *sql.DB, role string, filter *domain.ListFilter, fn func(user *domain.Users) error) (errMsg *response.ErrorMsg)
 }
 type UsersRepositoryImplementations struct {
+	Dialect Dialect
 }
 
-func NewUsersRepositoryImplementations() UsersRepository {
-	return &UsersRepositoryImplementations{}
+func NewUsersRepositoryImplementations(dialect Dialect) UsersRepository {
+	return &UsersRepositoryImplementations{Dialect: dialect}
 }
 
 func (u *UsersRepositoryImplementations) InsertDataUser(ctx context.Context, tx *sql.Tx, user *domain.Users) (isSuccess bool, errMsg *response.ErrorMsg) {
-	sqlQuery := "INSERT INTO users(name, email, password, class_id, role) VALUES(?, ?, ?, ?, ?)"
-	_, err := tx.ExecContext(ctx, sqlQuery, &user.Name, &user.Email, &user.Password, &user.ClassID, &user.Role)
+	sqlQuery := "INSERT INTO users(name, email, password, class_id, role, " + auditColumns + ") VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	args := append([]any{&user.Name, &user.Email, &user.Password, &user.ClassID, &user.Role}, insertAudit(ctx, &user.Audit)...)
+	ID, err := u.Dialect.InsertID(ctx, tx, sqlQuery, args...)
 	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return false, internalError(ctx, err)
 	}
+	user.ID = int(ID)
+	user.Version = 1
 	return true, nil
 }
 
 func (u *UsersRepositoryImplementations) UpdateDataUser(ctx context.Context, tx *sql.Tx, user *domain.Users) (isSuccess bool, errMsg *response.ErrorMsg) {
-	sqlQuery := "UPDATE users SET name = ?, email = ?, role = ?, class_id = ? WHERE id = ?"
-	_, err := tx.ExecContext(ctx, sqlQuery, &user.Name, &user.Email, &user.Role, &user.ClassID, &user.ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	errMsg = u.Dialect.updateVersioned(ctx, tx, "users", "name = ?, email = ?, role = ?, class_id = ?", "id = ?", user.Version, user.Name, user.Email, user.Role, user.ClassID, user.ID)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	if user.Version != 0 {
+		user.Version++
 	}
 	return true, nil
 }
 
-func (u *UsersRepositoryImplementations) DeleteDataUser(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
-	sqlQuery := "DELETE FROM users WHERE id = ?"
-	_, err := tx.ExecContext(ctx, sqlQuery, ID)
-	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+func (u *UsersRepositoryImplementations) DeleteDataUser(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := u.Dialect.softDelete(ctx, tx, "users", "id = ?", deletedAt, ID); errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+func (u *UsersRepositoryImplementations) RestoreDataUser(ctx context.Context, tx *sql.Tx, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
+	if errMsg := u.Dialect.restore(ctx, tx, "users", "id = ?", ID); errMsg != nil {
+		return false, errMsg
 	}
 	return true, nil
 }
 
+func (u *UsersRepositoryImplementations) FindDeletedUserByID(ctx context.Context, db *sql.DB, ID int) (*domain.Users, bool, *response.ErrorMsg) {
+	querySql := "SELECT id, name, email, role, class_id, version, deleted_at FROM users WHERE id = ? AND deleted_at IS NOT NULL"
+	rows, err := db.QueryContext(ctx, u.Dialect.Rebind(querySql), ID)
+	if err != nil {
+		return nil, false, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Data User terhapus dengan ID tersebut tidak ditemukan.")
+	}
+	var user domain.Users
+	var deletedAt nullTime
+	if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.ClassID, &user.Version, &deletedAt); err != nil {
+		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_GET_DATA, err)
+	}
+	user.DeletedAt = deletedAt.Ptr()
+	return &user, true, nil
+}
+
+func (u *UsersRepositoryImplementations) PurgeUsers(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg) {
+	return u.Dialect.purge(ctx, tx, "users", before)
+}
+
 func (u *UsersRepositoryImplementations) UpdateEmailUser(ctx context.Context, tx *sql.Tx, recentEmail string, newEmail string) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "UPDATE users SET email = ? WHERE email = ?"
-	_, err := tx.ExecContext(ctx, querySql, newEmail, recentEmail)
+	stamp, args := touch(ctx)
+	querySql := "UPDATE users SET " + stamp + ", email = ? WHERE email = ? AND deleted_at IS NULL"
+	_, err := tx.ExecContext(ctx, u.Dialect.Rebind(querySql), append(args, newEmail, recentEmail)...)
 	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return false, internalError(ctx, err)
 	}
 	return true, nil
 }
 
 func (u *UsersRepositoryImplementations) FindUserByID(ctx context.Context, db *sql.DB, ID int) (userResponse *domain.Users, isRegistered bool, errMsg *response.ErrorMsg) {
-	querySql := "SELECT id, name, email, password, class_id FROM users WHERE id = ?"
-	row, err := db.QueryContext(ctx, querySql, ID)
+	querySql := "SELECT id, name, email, password, role, class_id, version, " + auditColumns + " FROM users WHERE id = ? AND deleted_at IS NULL"
+	row, err := db.QueryContext(ctx, u.Dialect.Rebind(querySql), ID)
 	if err != nil {
-		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, false, internalError(ctx, err)
 	}
 	defer row.Close()
 
 	var user domain.Users
 	if row.Next() {
-		err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.ClassID)
+		err := scanAudited(row, &user.Audit, &user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.ClassID, &user.Version)
 		if err != nil {
 			return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_GET_DATA, err)
 		} else {
@@ -86,18 +131,18 @@ func (u *UsersRepositoryImplementations) FindUserByID(ctx context.Context, db *s
 }
 
 func (u *UsersRepositoryImplementations) FindUserByEmail(ctx context.Context, db *sql.DB, email string) (userResponse *domain.Users, errMsg *response.ErrorMsg) {
-	querySql := "SELECT id, name, email, role, class_id FROM users WHERE email = ?"
-	rows, err := db.QueryContext(ctx, querySql, email)
+	querySql := "SELECT id, name, email, role, class_id, version, " + auditColumns + " FROM users WHERE email = ? AND deleted_at IS NULL"
+	rows, err := db.QueryContext(ctx, u.Dialect.Rebind(querySql), email)
 	if err != nil {
-		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, internalError(ctx, err)
 	}
 	defer rows.Close()
 
 	var user domain.Users
 	if rows.Next() {
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.ClassID)
+		err := scanAudited(rows, &user.Audit, &user.ID, &user.Name, &user.Email, &user.Role, &user.ClassID, &user.Version)
 		if err != nil {
-			return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+			return nil, internalError(ctx, err)
 		}
 		return &user, nil
 	} else {
@@ -107,8 +152,8 @@ func (u *UsersRepositoryImplementations) FindUserByEmail(ctx context.Context, db
 }
 
 func (u *UsersRepositoryImplementations) IsEmailRegistered(ctx context.Context, db *sql.DB, email string) (userResponse *domain.Users, isRegistered bool, errMsg *response.ErrorMsg) {
-	querySql := "SELECT id, email FROM users WHERE email = ?"
-	row, err := db.QueryContext(ctx, querySql, email)
+	querySql := "SELECT id, email FROM users WHERE email = ? AND deleted_at IS NULL"
+	row, err := db.QueryContext(ctx, u.Dialect.Rebind(querySql), email)
 	if err != nil {
 		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, "ERR_INTERNAL_SERVER_ERROR", err)
 	}
@@ -127,19 +172,20 @@ func (u *UsersRepositoryImplementations) IsEmailRegistered(ctx context.Context,
 }
 
 func (u *UsersRepositoryImplementations) ChangePasswordUser(ctx context.Context, tx *sql.Tx, newPass string, ID int) (isSuccess bool, errMsg *response.ErrorMsg) {
-	querySql := "UPDATE users SET password = ? WHERE id = ?"
-	_, err := tx.ExecContext(ctx, querySql, newPass, ID)
+	stamp, args := touch(ctx)
+	querySql := "UPDATE users SET " + stamp + ", password = ? WHERE id = ? AND deleted_at IS NULL"
+	_, err := tx.ExecContext(ctx, u.Dialect.Rebind(querySql), append(args, newPass, ID)...)
 	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return false, internalError(ctx, err)
 	}
 	return true, nil
 }
 
 func (u *UsersRepositoryImplementations) FindUserByClassID(ctx context.Context, db *sql.DB, classID int) (userResponse []domain.Users, isRegistered bool, errMsg *response.ErrorMsg) {
-	querySql := "SELECT id, name, class_id FROM users WHERE class_id = ? LIMIT 1"
-	rows, err := db.QueryContext(ctx, querySql, classID)
+	querySql := "SELECT users.id, users.name, class_member.class_id FROM users JOIN class_member ON class_member.user_id = users.id WHERE class_member.class_id = ? AND users.deleted_at IS NULL ORDER BY users.id"
+	rows, err := db.QueryContext(ctx, u.Dialect.Rebind(querySql), classID)
 	if err != nil {
-		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return nil, false, internalError(ctx, err)
 	}
 	defer rows.Close()
 
@@ -148,10 +194,110 @@ func (u *UsersRepositoryImplementations) FindUserByClassID(ctx context.Context,
 		var user domain.Users
 		err := rows.Scan(&user.ID, &user.Name, &user.ClassID)
 		if err != nil {
-			return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+			return nil, false, internalError(ctx, err)
 		} else {
 			users = append(users, user)
 		}
 	}
-	return users, true, nil
+	return users, len(users) > 0, nil
+}
+
+func (u *UsersRepositoryImplementations) FindUsersByIDs(ctx context.Context, db *sql.DB, IDs []int) ([]*domain.Users, *response.ErrorMsg) {
+	return u.findUsersIn(ctx, db, "id", IDs)
+}
+
+func (u *UsersRepositoryImplementations) FindUsersByClassIDs(ctx context.Context, db *sql.DB, classIDs []int) ([]*domain.Users, *response.ErrorMsg) {
+	if len(classIDs) == 0 {
+		return nil, nil
+	}
+
+	// ClassID is the class the user was found through, so a member of two
+	// requested classes comes back once for each.
+	querySql := "SELECT users.id, users.name, users.email, users.role, class_member.class_id FROM users JOIN class_member ON class_member.user_id = users.id " +
+		"WHERE class_member.class_id IN " + inClause(len(classIDs)) + " AND users.deleted_at IS NULL ORDER BY users.id, class_member.class_id"
+	return u.queryUsers(ctx, db, querySql, intArgs(classIDs)...)
+}
+
+// findUsersIn loads the users whose column is one of values, without the password.
+func (u *UsersRepositoryImplementations) findUsersIn(ctx context.Context, db *sql.DB, column string, values []int) ([]*domain.Users, *response.ErrorMsg) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	querySql := "SELECT id, name, email, role, class_id FROM users WHERE " + column + " IN " + inClause(len(values)) + " AND deleted_at IS NULL ORDER BY id"
+	return u.queryUsers(ctx, db, querySql, intArgs(values)...)
+}
+
+func (u *UsersRepositoryImplementations) queryUsers(ctx context.Context, db *sql.DB, querySql string, args ...any) ([]*domain.Users, *response.ErrorMsg) {
+	rows, err := db.QueryContext(ctx, u.Dialect.Rebind(querySql), args...)
+	if err != nil {
+		return nil, internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	var users []*domain.Users
+	for rows.Next() {
+		var user domain.Users
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.Role, &user.ClassID); err != nil {
+			return nil, internalError(ctx, err)
+		}
+		users = append(users, &user)
+	}
+
+	return users, nil
+}
+
+// CountUsers counts the users that are not soft deleted.
+func (u *UsersRepositoryImplementations) CountUsers(ctx context.Context, db *sql.DB) (int, *response.ErrorMsg) {
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE deleted_at IS NULL").Scan(&count); err != nil {
+		return 0, internalError(ctx, err)
+	}
+	return count, nil
+}
+
+// FindAllUsers lists the users matching filter, without the password; an
+// empty role lists every role.
+func (u *UsersRepositoryImplementations) FindAllUsers(ctx context.Context, db *sql.DB, role string, filter *domain.ListFilter) ([]*domain.Users, *response.ErrorMsg) {
+	var users []*domain.Users
+	errMsg := u.EachUser(ctx, db, role, filter, func(user *domain.Users) error {
+		users = append(users, user)
+		return nil
+	})
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return users, nil
+}
+
+// EachUser calls fn for every user FindAllUsers lists while the rows are
+// read, stopping at the first error fn returns.
+func (u *UsersRepositoryImplementations) EachUser(ctx context.Context, db *sql.DB, role string, filter *domain.ListFilter, fn func(user *domain.Users) error) *response.ErrorMsg {
+	where, args := u.Dialect.listWhere(filter)
+	if role != "" {
+		where += " AND role = ?"
+		args = append(args, role)
+	}
+	profileWhere, profileArgs := u.Dialect.profileWhere("id", filter.Profile)
+	querySql := "SELECT id, name, email, role, class_id, version, " + auditColumns + " FROM users WHERE " + where + profileWhere + " AND deleted_at IS NULL " + listOrderBy(filter)
+	rows, err := db.QueryContext(ctx, u.Dialect.Rebind(querySql), append(args, profileArgs...)...)
+	if err != nil {
+		return internalError(ctx, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user domain.Users
+		if err := scanAudited(rows, &user.Audit, &user.ID, &user.Name, &user.Email, &user.Role, &user.ClassID, &user.Version); err != nil {
+			return internalError(ctx, err)
+		}
+		if err := fn(&user); err != nil {
+			return internalError(ctx, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return internalError(ctx, err)
+	}
+
+	return nil
 }
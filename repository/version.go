@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+)
+
+// Every write to a row bumps its version. Updates that carry the version the
+// caller read only match the row while it is still at that version, so a
+// concurrent write is reported instead of silently overwritten. A successful
+// checked update leaves the new version on the entity.
+
+// updateVersioned sets set on the live row of table matching where. A version
+// of 0 skips the check.
+func (d Dialect) updateVersioned(ctx context.Context, tx *sql.Tx, table, set, where string, version int, args ...any) *response.ErrorMsg {
+	stamp, stampArgs := touch(ctx)
+	querySql := "UPDATE " + table + " SET " + stamp + ", " + set + " WHERE " + where + " AND deleted_at IS NULL"
+	args = append(stampArgs, args...)
+	if version != 0 {
+		querySql += " AND version = ?"
+		args = append(args, version)
+	}
+
+	result, err := tx.ExecContext(ctx, d.Rebind(querySql), args...)
+	if err != nil {
+		return internalError(ctx, err)
+	}
+	if version != 0 {
+		if n, _ := result.RowsAffected(); n == 0 {
+			return helpers.ToVersionConflict()
+		}
+	}
+	return nil
+}
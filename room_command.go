@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+)
+
+// runRoom implements "sinaustudio room list".
+func runRoom(args []string) {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintln(os.Stderr, "Usage: sinaustudio room list [flags]")
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("room list", flag.ExitOnError)
+	r := &requests.ListRequest{}
+	flags.StringVar(&r.Name, "name", "", "hanya room yang namanya memuat teks ini")
+	flags.StringVar(&r.Sort, "sort", "", "urutan: id, name, created_at atau updated_at, awali dengan - untuk menurun")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio room list [-name NAME] [-sort SORT]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args[1:])
+
+	config, db := openDatabase()
+	defer db.Close()
+
+	roomService := services.NewRoomServiceImplementation(db, api.NewSQLMicroService(config.Dialect))
+	rooms, errMsg := roomService.FindAllRoom(systemContext(), r)
+	if errMsg != nil {
+		fatalMsg(errMsg)
+	}
+	printJSON(rooms)
+}
@@ -0,0 +1,38 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func TestAdminRole(t *testing.T) {
+	h := routertest.New(t)
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "register as admin", Method: http.MethodPost, Path: "/api/v.1/auth/register",
+			Body:       map[string]any{"name": "Admin Palsu", "email": "palsu@sinaustudio.test", "password": "rahasia123", "role": "admin", "class_id": h.ClassID},
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "lecturer creates admin", Method: http.MethodPost, Path: "/api/v2/users", User: "dosen",
+			Body:       map[string]any{"name": "Admin Palsu", "email": "palsu@sinaustudio.test", "password": "rahasia123", "role": "admin", "class_id": h.ClassID},
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "student makes self admin", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "mahasiswa",
+			Header:     ifMatch(1),
+			Body:       map[string]any{"role": "admin"},
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "admin makes student admin", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "admin",
+			Header:     ifMatch(1),
+			Body:       map[string]any{"role": "admin"},
+			WantStatus: http.StatusOK, Check: expectData("role", "admin"),
+		},
+	})
+}
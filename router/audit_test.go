@@ -0,0 +1,95 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func expectIDs(want ...int) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		data, _ := res.JSON(t)["data"].([]any)
+		var got []int
+		for _, item := range data {
+			ID, _ := item.(map[string]any)["id"].(float64)
+			got = append(got, int(ID))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("expected IDs %v, got %v: %s", want, got, res.Body)
+		}
+	}
+}
+
+func TestAuditMetadata(t *testing.T) {
+	h := routertest.New(t)
+	h.Login("dosen")
+	h.Login("mahasiswa")
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "create stamps creator", Method: http.MethodPost, Path: "/api/v2/matkul", User: "dosen",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Algoritma Pemrograman"},
+			WantStatus: http.StatusCreated,
+			Check: func(t *testing.T, res *routertest.Response) {
+				expectData("created_by", 1)(t, res)
+				expectData("updated_by", 1)(t, res)
+				if data, _ := res.JSON(t)["data"].(map[string]any); data["created_at"] == nil || data["created_at"] != data["updated_at"] {
+					t.Fatalf("expected equal created_at and updated_at, got %s", res.Body)
+				}
+			},
+		},
+		{
+			Name: "create by another user", Method: http.MethodPost, Path: "/api/v2/matkul", User: "mahasiswa",
+			Body:       map[string]any{"kode_matkul": "IF102", "name": "Basis Data"},
+			WantStatus: http.StatusCreated, Check: expectData("created_by", 2),
+		},
+		{
+			Name: "patch stamps updater", Method: http.MethodPatch, Path: "/api/v2/matkul/1", User: "mahasiswa",
+			Header:     ifMatch(1),
+			Body:       map[string]any{"name": "Struktur Data"},
+			WantStatus: http.StatusOK,
+			Check: func(t *testing.T, res *routertest.Response) {
+				expectData("created_by", 1)(t, res)
+				expectData("updated_by", 2)(t, res)
+			},
+		},
+		{
+			Name: "filter by creator", Method: http.MethodGet, Path: "/api/v2/matkul?created_by=2", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectIDs(2),
+		},
+		{
+			Name: "filter by updater", Method: http.MethodGet, Path: "/api/v2/matkul?updated_by=2", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectIDs(1, 2),
+		},
+		{
+			Name: "filter by creation time", Method: http.MethodGet, Path: "/api/v2/matkul?created_after=2999-01-01T00:00:00Z", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(0),
+		},
+		{
+			Name: "sort by name", Method: http.MethodGet, Path: "/api/v2/matkul?sort=name", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectIDs(2, 1),
+		},
+		{
+			Name: "sort by id descending", Method: http.MethodGet, Path: "/api/v2/matkul?sort=-id&created_before=2999-01-01T00:00:00Z", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectIDs(2, 1),
+		},
+		{
+			Name: "unknown sort column", Method: http.MethodGet, Path: "/api/v2/matkul?sort=password", User: "dosen",
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "malformed time", Method: http.MethodGet, Path: "/api/v2/rooms?updated_after=kemarin", User: "dosen",
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "user stamps", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "dosen",
+			Header:     ifMatch(1),
+			Body:       map[string]any{"name": "Siti Aminah"},
+			WantStatus: http.StatusOK, Check: expectData("updated_by", 1),
+		},
+	})
+}
@@ -0,0 +1,158 @@
+package router_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+// picture encodes a w by h gradient with encode.
+func picture(t *testing.T, w, h int, encode func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func avatarURL(userID int, content []byte) string {
+	sum := sha256.Sum256(content)
+	return fmt.Sprintf("/api/v2/avatars/%d/%s.jpg", userID, hex.EncodeToString(sum[:8]))
+}
+
+func expectThumbnail(size int) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		if got := res.Header.Get("Content-Type"); got != "image/jpeg" {
+			t.Fatalf("expected Content-Type image/jpeg, got %q", got)
+		}
+		if got := res.Header.Get("Cache-Control"); !strings.Contains(got, "immutable") {
+			t.Fatalf("expected an immutable Cache-Control, got %q", got)
+		}
+		if bytes.Contains(res.Body, []byte("Exif")) {
+			t.Fatal("expected the thumbnail without EXIF")
+		}
+		config, err := jpeg.DecodeConfig(bytes.NewReader(res.Body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Width != size || config.Height != size {
+			t.Fatalf("expected a %dx%d thumbnail, got %dx%d", size, size, config.Width, config.Height)
+		}
+	}
+}
+
+func TestAvatar(t *testing.T) {
+	h := routertest.New(t)
+	// Fixtures are registered on first login, which fixes their IDs.
+	for _, name := range []string{"dosen", "mahasiswa", "admin"} {
+		h.Login(name)
+	}
+
+	first := picture(t, 600, 400, func(buf *bytes.Buffer, img image.Image) error { return png.Encode(buf, img) })
+	second := picture(t, 300, 300, func(buf *bytes.Buffer, img image.Image) error { return jpeg.Encode(buf, img, nil) })
+	firstURL, secondURL := avatarURL(2, first), avatarURL(2, second)
+	upload := func(content []byte) routertest.Upload {
+		return routertest.Upload{Field: "avatar", Filename: "foto.png", Content: content}
+	}
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "student uploads avatar", Method: http.MethodPut, Path: "/api/v2/users/2/avatar", User: "mahasiswa",
+			Header: ifMatch(1), Body: upload(first),
+			WantStatus: http.StatusOK, Check: expectProfile("avatar", firstURL),
+		},
+		{
+			Name: "avatar served without token", Method: http.MethodGet, Path: firstURL,
+			WantStatus: http.StatusOK, Check: expectThumbnail(256),
+		},
+		{
+			Name: "avatar thumbnail", Method: http.MethodGet, Path: firstURL + "?size=64",
+			WantStatus: http.StatusOK, Check: expectThumbnail(64),
+		},
+		{
+			Name: "avatar revalidated", Method: http.MethodGet, Path: firstURL + "?size=64",
+			Header:     map[string]string{"If-None-Match": fmt.Sprintf("%q", strings.TrimSuffix(firstURL[len("/api/v2/avatars/2/"):], ".jpg")+"-64")},
+			WantStatus: http.StatusNotModified,
+		},
+		{
+			Name: "unknown avatar size", Method: http.MethodGet, Path: firstURL + "?size=100",
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "avatar of another user", Method: http.MethodGet, Path: strings.Replace(firstURL, "/2/", "/1/", 1),
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "lecturer uploads student avatar", Method: http.MethodPut, Path: "/api/v2/users/2/avatar", User: "dosen",
+			Header: ifMatch(2), Body: upload(second),
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "avatar is not a picture", Method: http.MethodPut, Path: "/api/v2/users/2/avatar", User: "mahasiswa",
+			Header: ifMatch(2), Body: upload([]byte("bukan gambar")),
+			WantStatus: http.StatusUnsupportedMediaType, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "avatar too large", Method: http.MethodPut, Path: "/api/v2/users/2/avatar", User: "mahasiswa",
+			Header: ifMatch(2), Body: upload(append(first, make([]byte, 5<<20)...)),
+			WantStatus: http.StatusRequestEntityTooLarge, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "avatar upload on stale version", Method: http.MethodPut, Path: "/api/v2/users/2/avatar", User: "mahasiswa",
+			Header: ifMatch(1), Body: upload(second),
+			WantStatus: http.StatusPreconditionFailed, WantKey: exception.ERR_PRECONDITION_FAILED,
+		},
+		{
+			Name: "admin replaces avatar", Method: http.MethodPut, Path: "/api/v2/users/2/avatar", User: "admin",
+			Header: ifMatch(2), Body: upload(second),
+			WantStatus: http.StatusOK, Check: expectProfile("avatar", secondURL),
+		},
+		{
+			Name: "replaced avatar removed", Method: http.MethodGet, Path: firstURL,
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "profile sent back keeps avatar", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "mahasiswa",
+			Header:     ifMatch(3),
+			Body:       map[string]any{"profile": map[string]any{"avatar": secondURL, "angkatan": 2024}},
+			WantStatus: http.StatusOK, Check: expectProfile("avatar", secondURL),
+		},
+		{
+			Name: "student deletes avatar", Method: http.MethodDelete, Path: "/api/v2/users/2/avatar", User: "mahasiswa",
+			Header:     ifMatch(4),
+			WantStatus: http.StatusNoContent,
+		},
+		{
+			Name: "deleted avatar removed", Method: http.MethodGet, Path: secondURL,
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "profile without avatar", Method: http.MethodGet, Path: "/api/v2/users/2", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectProfile("avatar", nil),
+		},
+		{
+			Name: "delete missing avatar", Method: http.MethodDelete, Path: "/api/v2/users/2/avatar", User: "mahasiswa",
+			Header:     ifMatch(5),
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+	})
+}
@@ -0,0 +1,122 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func TestClassMembership(t *testing.T) {
+	h := routertest.New(t)
+	h.Login("dosen")
+	h.Login("mahasiswa")
+	classID := h.SeedClass("TI-3A", "JOIN0001")
+	if classID != 2 {
+		t.Fatalf("expected class 2, got %d", classID)
+	}
+
+	var newKode string
+	h.Run([]routertest.Scenario{
+		{
+			Name: "join by kode", Method: http.MethodPost, Path: "/api/v2/classes/join", User: "mahasiswa",
+			Body:       map[string]any{"kode_kelas": "JOIN0001"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/classes/2/members/2"),
+		},
+		{
+			Name: "join twice", Method: http.MethodPost, Path: "/api/v2/classes/join", User: "mahasiswa",
+			Body:       map[string]any{"kode_kelas": "JOIN0001"},
+			WantStatus: http.StatusConflict, WantKey: exception.ERR_CONFLICT,
+		},
+		{
+			Name: "unknown kode", Method: http.MethodPost, Path: "/api/v2/classes/join", User: "mahasiswa",
+			Body:       map[string]any{"kode_kelas": "TIDAKADA"},
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "lecturer outside class", Method: http.MethodGet, Path: "/api/v2/classes/2/members", User: "dosen",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "lecturer joins", Method: http.MethodPost, Path: "/api/v2/classes/join", User: "dosen",
+			Body:       map[string]any{"kode_kelas": "JOIN0001"},
+			WantStatus: http.StatusCreated, Check: expectData("kode_kelas", "JOIN0001"),
+		},
+		{
+			Name: "list members", Method: http.MethodGet, Path: "/api/v2/classes/2/members", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(2),
+		},
+		{
+			Name: "list members by role", Method: http.MethodGet, Path: "/api/v2/classes/2/members?role=mahasiswa", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(1),
+		},
+		{
+			Name: "class users from membership", Method: http.MethodGet, Path: "/api/v2/classes/2/users", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(2),
+		},
+		{
+			Name: "student cannot list members", Method: http.MethodGet, Path: "/api/v2/classes/2/members", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "student cannot see kode", Method: http.MethodGet, Path: "/api/v2/classes/2", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectData("kode_kelas", nil),
+		},
+		{
+			Name: "regenerate kode", Method: http.MethodPost, Path: "/api/v2/classes/2/code", User: "dosen",
+			Body:       map[string]any{"expires_in_hours": 24},
+			WantStatus: http.StatusOK,
+			Check: func(t *testing.T, res *routertest.Response) {
+				data, _ := res.JSON(t)["data"].(map[string]any)
+				newKode, _ = data["kode_kelas"].(string)
+				if newKode == "" || newKode == "JOIN0001" || data["kode_kelas_expires_at"] == nil {
+					t.Fatalf("expected a new expiring kode kelas: %s", res.Body)
+				}
+			},
+		},
+		{
+			Name: "expiry survives reload", Method: http.MethodGet, Path: "/api/v2/classes/2", User: "dosen",
+			WantStatus: http.StatusOK,
+			Check: func(t *testing.T, res *routertest.Response) {
+				data, _ := res.JSON(t)["data"].(map[string]any)
+				if data["kode_kelas"] != newKode || data["kode_kelas_expires_at"] == nil {
+					t.Fatalf("expected kode %s with expiry: %s", newKode, res.Body)
+				}
+			},
+		},
+		{
+			Name: "remove member", Method: http.MethodDelete, Path: "/api/v2/classes/2/members/2", User: "dosen",
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+		{
+			Name: "remove missing member", Method: http.MethodDelete, Path: "/api/v2/classes/2/members/2", User: "dosen",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "old kode rejected", Method: http.MethodPost, Path: "/api/v2/classes/join", User: "mahasiswa",
+			Body:       map[string]any{"kode_kelas": "JOIN0001"},
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+	})
+}
+
+func TestKodeKelasUnique(t *testing.T) {
+	h := routertest.New(t)
+	ctx := context.Background()
+	classes := repository.NewClassRepositoryImplementation(h.Dialect)
+
+	tx, err := h.DB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	_, errMsg := classes.InsertClass(ctx, tx, &domain.Class{Name: "TI-3B", KodeKelas: "FIXTURE01"})
+	if errMsg == nil || errMsg.ErrorKey != exception.ERR_ALREADY_USE || errMsg.StatusCode != http.StatusConflict {
+		t.Fatalf("expected ERR_ALREADY_USE for a taken kode kelas, got %+v", errMsg)
+	}
+}
@@ -0,0 +1,83 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func expectDependents(resource string, IDs ...int) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+
+		dependents, _ := res.JSON(t)["dependents"].([]any)
+		if len(dependents) != 1 {
+			t.Fatalf("expected one dependent resource, got %s", res.Body)
+		}
+		dependent, _ := dependents[0].(map[string]any)
+		got, _ := dependent["ids"].([]any)
+		if dependent["resource"] != resource || len(got) != len(IDs) {
+			t.Fatalf("expected %s %v, got %s", resource, IDs, res.Body)
+		}
+		for i, ID := range IDs {
+			if got[i] != float64(ID) {
+				t.Fatalf("expected %s %v, got %s", resource, IDs, res.Body)
+			}
+		}
+	}
+}
+
+func TestDeletePolicies(t *testing.T) {
+	h := routertest.New(t)
+	h.Login("dosen")
+	h.Login("mahasiswa")
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "room needs existing lecture", Method: http.MethodPost, Path: "/api/v2/rooms", User: "dosen",
+			Body:       map[string]any{"name": "Algoritma", "url": "https://meet.example.com/algo", "lecture_id": 9, "start_room": "2023-02-19 20:25:50", "end_room": "2023-02-19 22:25:50"},
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "create room", Method: http.MethodPost, Path: "/api/v2/rooms", User: "dosen",
+			Body:       map[string]any{"name": "Algoritma", "url": "https://meet.example.com/algo", "lecture_id": 1, "start_room": "2023-02-19 20:25:50", "end_room": "2023-02-19 22:25:50"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/rooms/1"),
+		},
+		{
+			Name: "class with users is restricted", Method: http.MethodDelete, Path: "/api/v2/classes/1", User: "dosen",
+			Header:     ifMatch(1),
+			WantStatus: http.StatusConflict, WantKey: exception.ERR_CONFLICT, Check: expectDependents("users", 1, 2),
+		},
+		{
+			Name: "lecture with rooms is restricted", Method: http.MethodDelete, Path: "/api/v2/lectures/1?rooms=restrict", User: "dosen",
+			Header:     ifMatch(1),
+			WantStatus: http.StatusConflict, WantKey: exception.ERR_CONFLICT, Check: expectDependents("rooms", 1),
+		},
+		{
+			Name: "unknown policy", Method: http.MethodDelete, Path: "/api/v2/lectures/1?rooms=orphan", User: "dosen",
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "reassign needs target", Method: http.MethodDelete, Path: "/api/v2/lectures/1?rooms=reassign", User: "dosen",
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "cascade by default", Method: http.MethodDelete, Path: "/api/v2/lectures/1", User: "dosen",
+			Header:     ifMatch(1),
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+		{
+			Name: "room deleted with lecture", Method: http.MethodGet, Path: "/api/v2/rooms/1", User: "dosen",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+	})
+
+	_, err := h.DB.ExecContext(context.Background(), "INSERT INTO room(name, url, lecture_id, start_room, end_room) VALUES('Liar', 'https://meet.example.com/x', 99, '2023-02-19 20:25:50', '2023-02-19 22:25:50')")
+	if err == nil || !strings.Contains(err.Error(), "FOREIGN KEY") {
+		t.Fatalf("expected the foreign key to reject a room without lecture, got %v", err)
+	}
+}
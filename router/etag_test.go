@@ -0,0 +1,120 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+)
+
+func expectETag(want string) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		if got := res.Header.Get("ETag"); got != want {
+			t.Fatalf("expected ETag %s, got %q", want, got)
+		}
+	}
+}
+
+func TestOptimisticConcurrency(t *testing.T) {
+	h := routertest.New(t)
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "create matkul", Method: http.MethodPost, Path: "/api/v2/matkul", User: "dosen",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Algoritma Pemrograman"},
+			WantStatus: http.StatusCreated, Check: expectETag(`"1"`),
+		},
+		{
+			Name: "get answers etag", Method: http.MethodGet, Path: "/api/v2/matkul/1", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectETag(`"1"`),
+		},
+		{
+			Name: "patch without if-match", Method: http.MethodPatch, Path: "/api/v2/matkul/1", User: "dosen",
+			Body:       map[string]any{"name": "Algoritma dan Pemrograman"},
+			WantStatus: http.StatusPreconditionRequired, WantKey: exception.ERR_PRECONDITION_REQUIRED,
+		},
+		{
+			Name: "patch current version", Method: http.MethodPatch, Path: "/api/v2/matkul/1", User: "dosen",
+			Header:     ifMatch(1),
+			Body:       map[string]any{"name": "Algoritma dan Pemrograman"},
+			WantStatus: http.StatusOK, Check: expectETag(`"2"`),
+		},
+		{
+			Name: "patch stale version", Method: http.MethodPatch, Path: "/api/v2/matkul/1", User: "dosen",
+			Header:     ifMatch(1),
+			Body:       map[string]any{"name": "Pemrograman Dasar"},
+			WantStatus: http.StatusPreconditionFailed, WantKey: exception.ERR_PRECONDITION_FAILED,
+			Check: func(t *testing.T, res *routertest.Response) {
+				expectETag(`"2"`)(t, res)
+				current, _ := res.JSON(t)["current"].(map[string]any)
+				if current["name"] != "Algoritma dan Pemrograman" || current["version"] != float64(2) {
+					t.Fatalf("expected current representation at version 2, got %s", res.Body)
+				}
+			},
+		},
+		{
+			Name: "weak etag never matches", Method: http.MethodPatch, Path: "/api/v2/matkul/1", User: "dosen",
+			Header:     map[string]string{"If-Match": `W/"2"`},
+			Body:       map[string]any{"name": "Pemrograman Dasar"},
+			WantStatus: http.StatusPreconditionFailed, WantKey: exception.ERR_PRECONDITION_FAILED,
+		},
+		{
+			Name: "delete stale version", Method: http.MethodDelete, Path: "/api/v2/matkul/1", User: "dosen",
+			Header:     ifMatch(1),
+			WantStatus: http.StatusPreconditionFailed, WantKey: exception.ERR_PRECONDITION_FAILED,
+		},
+	})
+
+	// A write that read version 2 before the PATCH above landed must not win.
+	matkul := services.NewMataKuliahServiceImplementation(h.DB, api.NewSQLMicroService(h.Dialect))
+	_, errMsg := matkul.UpdateMatkul(context.Background(), &requests.UpdateMatkulRequest{ID: 1, Name: "Pemrograman Dasar", KodeMatkul: "IF101", Version: 1})
+	if errMsg == nil || errMsg.ErrorKey != exception.ERR_PRECONDITION_FAILED {
+		t.Fatalf("expected %s from a stale update, got %+v", exception.ERR_PRECONDITION_FAILED, errMsg)
+	}
+	// Nor may a delete that checked version 1 before it was written.
+	_, errMsg = matkul.DeleteMatkulByID(context.Background(), 1, 1)
+	if errMsg == nil || errMsg.ErrorKey != exception.ERR_PRECONDITION_FAILED {
+		t.Fatalf("expected %s from a stale delete, got %+v", exception.ERR_PRECONDITION_FAILED, errMsg)
+	}
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "v1 get answers etag", Method: http.MethodGet, Path: "/api/v.1/matkul/?id=1", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectETag(`"2"`),
+		},
+		{
+			Name: "v1 update without version", Method: http.MethodPut, Path: "/api/v.1/matkul/update?id=1", User: "dosen",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Pemrograman Dasar"},
+			WantStatus: http.StatusPreconditionRequired, WantKey: exception.ERR_PRECONDITION_REQUIRED,
+		},
+		{
+			Name: "v1 update current version", Method: http.MethodPut, Path: "/api/v.1/matkul/update?id=1", User: "dosen",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Pemrograman Dasar", "version": 2},
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name: "v1 update stale if-match", Method: http.MethodPut, Path: "/api/v.1/matkul/update?id=1", User: "dosen",
+			Header:     ifMatch(2),
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Algoritma Lanjut"},
+			WantStatus: http.StatusPreconditionFailed, WantKey: exception.ERR_PRECONDITION_FAILED,
+			Check: func(t *testing.T, res *routertest.Response) {
+				expectETag(`"3"`)(t, res)
+				current, _ := res.JSON(t)["current"].(map[string]any)
+				if current["name"] != "Pemrograman Dasar" {
+					t.Fatalf("expected current representation at version 3, got %s", res.Body)
+				}
+			},
+		},
+		{
+			Name: "delete any version", Method: http.MethodDelete, Path: "/api/v2/matkul/1", User: "dosen",
+			Header:     map[string]string{"If-Match": "*"},
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+	})
+}
@@ -0,0 +1,86 @@
+package router_test
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+)
+
+func expectExport(format spreadsheet.Format, filename string, body string) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		if got := res.Header.Get("Content-Type"); got != format.ContentType() {
+			t.Fatalf("expected Content-Type %s, got %q", format.ContentType(), got)
+		}
+		if got := res.Header.Get("Content-Disposition"); got != "attachment; filename="+filename {
+			t.Fatalf("expected attachment %s, got %q", filename, got)
+		}
+		if body != "" && !bytes.Equal(res.Body, []byte(body)) {
+			t.Fatalf("expected body %q, got %q", body, res.Body)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	h := routertest.New(t)
+	h.Login("mahasiswa")
+	h.Login("admin")
+
+	h.Run([]routertest.Scenario{
+		{
+			Name:   "without token",
+			Method: http.MethodGet, Path: "/api/v2/export/classes",
+			WantStatus: http.StatusUnauthorized, WantKey: exception.ERR_UNAUTHORIZED_BEARER,
+		},
+		{
+			Name:   "student exports users",
+			Method: http.MethodGet, Path: "/api/v2/export/users", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name:   "student exports classes",
+			Method: http.MethodGet, Path: "/api/v2/export/classes", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name:   "csv by default",
+			Method: http.MethodGet, Path: "/api/v2/export/classes", User: "admin",
+			WantStatus: http.StatusOK,
+			Check:      expectExport(spreadsheet.CSV, "classes.csv", "id,name,kode_kelas\n1,TI-1A,FIXTURE01\n"),
+		},
+		{
+			Name:   "ndjson by Accept",
+			Method: http.MethodGet, Path: "/api/v2/export/users?role=mahasiswa", User: "admin",
+			Header:     map[string]string{"Accept": "application/json;q=0.9, application/x-ndjson"},
+			WantStatus: http.StatusOK,
+			Check:      expectExport(spreadsheet.NDJSON, "users.ndjson", `{"class_id":1,"email":"siti@sinaustudio.test","id":1,"name":"Siti Rahmawati","role":"mahasiswa"}`+"\n"),
+		},
+		{
+			Name:   "format query wins over Accept",
+			Method: http.MethodGet, Path: "/api/v2/export/rooms?format=xlsx", User: "admin",
+			Header:     map[string]string{"Accept": "text/csv"},
+			WantStatus: http.StatusOK,
+			Check:      expectExport(spreadsheet.XLSX, "rooms.xlsx", ""),
+		},
+		{
+			Name:   "unknown format",
+			Method: http.MethodGet, Path: "/api/v2/export/rooms?format=pdf", User: "admin",
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name:   "users sorted like the listing",
+			Method: http.MethodGet, Path: "/api/v2/export/users?sort=-name", User: "admin",
+			WantStatus: http.StatusOK,
+			Check:      expectExport(spreadsheet.CSV, "users.csv", "id,name,email,role,class_id\n1,Siti Rahmawati,siti@sinaustudio.test,mahasiswa,1\n2,Admin Sinau,admin@sinaustudio.test,admin,0\n"),
+		},
+		{
+			Name:   "unknown resource",
+			Method: http.MethodGet, Path: "/api/v2/export/secrets", User: "admin",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+	})
+}
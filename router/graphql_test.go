@@ -0,0 +1,45 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func TestGraphQL(t *testing.T) {
+	h := routertest.New(t)
+
+	h.Run([]routertest.Scenario{
+		{
+			Name:   "without token",
+			Method: http.MethodPost, Path: "/graphql",
+			Body:       map[string]any{"query": "{ classes { name } }"},
+			WantStatus: http.StatusUnauthorized, WantKey: exception.ERR_UNAUTHORIZED_BEARER,
+		},
+		{
+			Name:   "query with token",
+			Method: http.MethodPost, Path: "/graphql", User: "mahasiswa",
+			Body:       map[string]any{"query": "{ classes { name students { name } } }"},
+			WantStatus: http.StatusOK,
+			Check: func(t *testing.T, res *routertest.Response) {
+				classes, _ := res.JSON(t)["data"].(map[string]any)["classes"].([]any)
+				if len(classes) != 1 {
+					t.Fatalf("expected the seeded class, got %s", res.Body)
+				}
+			},
+		},
+		{
+			Name:   "query by GET",
+			Method: http.MethodGet, Path: "/graphql?query=%7B%20user(id%3A%201)%20%7B%20name%20%7D%20%7D", User: "mahasiswa",
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name:   "missing query",
+			Method: http.MethodPost, Path: "/graphql", User: "mahasiswa",
+			Body:       map[string]any{},
+			WantStatus: http.StatusBadRequest,
+		},
+	})
+}
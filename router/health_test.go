@@ -0,0 +1,69 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func expectHealth(status string, checks map[string]string) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		body := res.JSON(t)
+		if body["status"] != status {
+			t.Fatalf("expected status %q, got %v: %s", status, body["status"], res.Body)
+		}
+		got, _ := body["checks"].(map[string]any)
+		for check, want := range checks {
+			if !strings.Contains(fmt.Sprint(got[check]), want) {
+				t.Errorf("expected checks.%s to contain %q, got %v", check, want, got[check])
+			}
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	h := routertest.New(t)
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "live", Method: http.MethodGet, Path: "/healthz",
+			WantStatus: http.StatusOK,
+			Check:      expectHealth("ok", nil),
+		},
+		{
+			Name: "ready", Method: http.MethodGet, Path: "/readyz",
+			WantStatus: http.StatusOK,
+			Check:      expectHealth("ok", map[string]string{"database": "ok", "migrations": "ok"}),
+		},
+	})
+
+	if _, err := h.DB.Exec("DELETE FROM schema_migrations WHERE version = '0007_audit_columns.sql'"); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "pending migration", Method: http.MethodGet, Path: "/readyz",
+			WantStatus: http.StatusServiceUnavailable,
+			Check:      expectHealth("unavailable", map[string]string{"database": "ok", "migrations": "0007_audit_columns.sql"}),
+		},
+		{
+			Name: "still live", Method: http.MethodGet, Path: "/healthz",
+			WantStatus: http.StatusOK,
+		},
+	})
+
+	h.DB.Close()
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "database closed", Method: http.MethodGet, Path: "/readyz",
+			WantStatus: http.StatusServiceUnavailable,
+			Check:      expectHealth("unavailable", map[string]string{"database": "closed"}),
+		},
+	})
+}
@@ -0,0 +1,29 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func TestImport(t *testing.T) {
+	h := routertest.New(t)
+	file := routertest.Upload{Field: "file", Filename: "users.csv", Content: []byte("name,email,role,class_name,password\nBudi Santoso,budi@sinaustudio.test,dosen,TI-1A,\n")}
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "student imports users", Method: http.MethodPost, Path: "/api/v2/import/users", User: "mahasiswa",
+			Body: file, WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "lecturer imports users", Method: http.MethodPost, Path: "/api/v2/import/users", User: "dosen",
+			Body: file, WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "admin imports users", Method: http.MethodPost, Path: "/api/v2/import/users", User: "admin",
+			Body: file, WantStatus: http.StatusOK, Check: expectData("created", 1),
+		},
+	})
+}
@@ -0,0 +1,16 @@
+package router_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+)
+
+// TestMain keeps the access log out of the test output; tests that check log
+// lines install their own logger.
+func TestMain(m *testing.M) {
+	logging.SetDefault(logging.New(io.Discard, nil))
+	os.Exit(m.Run())
+}
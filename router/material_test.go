@@ -0,0 +1,189 @@
+package router_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+)
+
+func expectRedirect(prefix string, location *string) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		got := res.Header.Get("Location")
+		if !strings.HasPrefix(got, prefix) {
+			t.Fatalf("expected a redirect to %s, got %q", prefix, got)
+		}
+		if location != nil {
+			*location = got
+		}
+	}
+}
+
+func TestMaterials(t *testing.T) {
+	h := routertest.New(t)
+	for _, name := range []string{"dosen", "mahasiswa", "admin"} {
+		h.Login(name)
+	}
+	file := routertest.Upload{Field: "file", Filename: "pertemuan 1.txt", Content: []byte("materi minggu 1")}
+
+	var signed string
+	h.Run([]routertest.Scenario{
+		{
+			Name: "create matkul", Method: http.MethodPost, Path: "/api/v2/matkul", User: "admin",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Algoritma Pemrograman"},
+			WantStatus: http.StatusCreated,
+		},
+		{
+			Name: "topics before enrolment", Method: http.MethodGet, Path: "/api/v2/matkul/1/topics", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "student enrols class", Method: http.MethodPut, Path: "/api/v2/classes/1/matkul/1", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "lecturer enrols class", Method: http.MethodPut, Path: "/api/v2/classes/1/matkul/1", User: "dosen",
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+		{
+			Name: "enrol again", Method: http.MethodPut, Path: "/api/v2/classes/1/matkul/1", User: "dosen",
+			WantStatus: http.StatusNoContent,
+		},
+		{
+			Name: "enrol unknown matkul", Method: http.MethodPut, Path: "/api/v2/classes/1/matkul/99", User: "dosen",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "class matkul", Method: http.MethodGet, Path: "/api/v2/classes/1/matkul", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectLen(1),
+		},
+		{
+			Name: "create topic", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics", User: "dosen",
+			Body:       map[string]any{"week": 1, "title": "Pengenalan Algoritma"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/matkul/1/topics"),
+		},
+		{
+			Name: "topic of same week", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics", User: "dosen",
+			Body:       map[string]any{"week": 1, "title": "Pengenalan Lagi"},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_ALREADY_USE,
+		},
+		{
+			Name: "topic out of semester", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics", User: "dosen",
+			Body:       map[string]any{"week": 60, "title": "Minggu Tambahan"},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "student creates topic", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics", User: "mahasiswa",
+			Body:       map[string]any{"week": 2, "title": "Variabel"},
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "share link", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics/1/links", User: "dosen",
+			Body:       map[string]any{"title": "Slide", "url": "https://example.com/slide"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/matkul/1/materials/1"),
+		},
+		{
+			Name: "share script link", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics/1/links", User: "dosen",
+			Body:       map[string]any{"title": "Slide", "url": "javascript:alert(1)"},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "link under unknown topic", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics/9/links", User: "dosen",
+			Body:       map[string]any{"title": "Slide", "url": "https://example.com/slide"},
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "upload file", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics/1/files", User: "dosen",
+			Body: file, WantStatus: http.StatusCreated, Check: expectData("file_name", "pertemuan_1.txt"),
+		},
+		{
+			Name: "upload empty file", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics/1/files", User: "dosen",
+			Body:       routertest.Upload{Field: "file", Filename: "kosong.txt"},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "student uploads file", Method: http.MethodPost, Path: "/api/v2/matkul/1/topics/1/files", User: "mahasiswa",
+			Body: file, WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "student lists topics", Method: http.MethodGet, Path: "/api/v2/matkul/1/topics", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectLen(1),
+		},
+		{
+			Name: "student reads material", Method: http.MethodGet, Path: "/api/v2/matkul/1/materials/2", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectData("download_url", "/api/v2/matkul/1/materials/2/download"),
+		},
+		{
+			Name: "material of another matkul", Method: http.MethodGet, Path: "/api/v2/matkul/2/materials/2", User: "admin",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "open link", Method: http.MethodGet, Path: "/api/v2/matkul/1/materials/1/download", User: "mahasiswa",
+			WantStatus: http.StatusFound, Check: expectRedirect("https://example.com/slide", nil),
+		},
+		{
+			Name: "download file", Method: http.MethodGet, Path: "/api/v2/matkul/1/materials/2/download", User: "mahasiswa",
+			WantStatus: http.StatusFound, Check: expectRedirect(storage.LocalPath+"/users/1/materials/", &signed),
+		},
+	})
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "follow signed URL", Method: http.MethodGet, Path: signed,
+			WantStatus: http.StatusOK, Check: expectDownload("text/plain; charset=utf-8", "materi minggu 1"),
+		},
+		{
+			Name: "lecturer counts upload", Method: http.MethodGet, Path: "/api/v2/users/1/storage", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectData("used", 15),
+		},
+		{
+			Name: "student deletes material", Method: http.MethodDelete, Path: "/api/v2/matkul/1/materials/2", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "delete material", Method: http.MethodDelete, Path: "/api/v2/matkul/1/materials/2", User: "dosen",
+			WantStatus: http.StatusNoContent,
+		},
+		{
+			// The file stays until the purge job removes it.
+			Name: "deleted file", Method: http.MethodGet, Path: signed,
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name: "student leaves class", Method: http.MethodDelete, Path: "/api/v2/classes/1/members/2", User: "dosen",
+			WantStatus: http.StatusNoContent,
+		},
+		{
+			Name: "former student lists topics", Method: http.MethodGet, Path: "/api/v2/matkul/1/topics", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "former student downloads", Method: http.MethodGet, Path: "/api/v2/matkul/1/materials/1/download", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "delete topic", Method: http.MethodDelete, Path: "/api/v2/matkul/1/topics/1", User: "dosen",
+			WantStatus: http.StatusNoContent,
+		},
+		{
+			Name: "topics after delete", Method: http.MethodGet, Path: "/api/v2/matkul/1/topics", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(0),
+		},
+		{
+			Name: "unenrol class", Method: http.MethodDelete, Path: "/api/v2/classes/1/matkul/1", User: "dosen",
+			WantStatus: http.StatusNoContent,
+		},
+		{
+			Name: "unenrol again", Method: http.MethodDelete, Path: "/api/v2/classes/1/matkul/1", User: "dosen",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "lecturer after unenrol", Method: http.MethodGet, Path: "/api/v2/matkul/1/topics", User: "dosen",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package router_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func expectMetrics(lines ...string) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		for _, line := range lines {
+			if !strings.Contains(string(res.Body), line) {
+				t.Errorf("expected %q in:\n%s", line, res.Body)
+			}
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	h := routertest.New(t)
+	now := time.Now()
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "failed login", Method: http.MethodPost, Path: "/api/v.1/auth/login",
+			Body:       map[string]any{"email": "tidak.ada@sinaustudio.test", "password": "rahasia123"},
+			WantStatus: http.StatusNotFound,
+		},
+		{
+			Name: "running room", Method: http.MethodPost, Path: "/api/v2/rooms", User: "dosen",
+			Body: map[string]any{"name": "Algoritma", "url": "https://meet.example.com/algo", "lecture_id": 1,
+				"start_room": now.Add(-time.Hour).Format("2006-01-02 15:04:05"), "end_room": now.Add(time.Hour).Format("2006-01-02 15:04:05")},
+			WantStatus: http.StatusCreated,
+		},
+		{
+			Name: "finished room", Method: http.MethodPost, Path: "/api/v2/rooms", User: "mahasiswa",
+			Body:       map[string]any{"name": "Basis Data", "url": "https://meet.example.com/bd", "lecture_id": 1, "start_room": "2023-02-19 20:25:50", "end_room": "2023-02-19 22:25:50"},
+			WantStatus: http.StatusCreated,
+		},
+		{
+			Name: "room by id", Method: http.MethodGet, Path: "/api/v2/rooms/2", User: "dosen",
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name: "scrape", Method: http.MethodGet, Path: "/metrics",
+			WantStatus: http.StatusOK,
+			Check: expectMetrics(
+				`sinaustudio_http_requests_total{method="GET",route="/api/v2/rooms/:id",status="200"}`,
+				`sinaustudio_http_request_duration_seconds_bucket{method="POST",route="/api/v2/rooms",le="+Inf"}`,
+				`sinaustudio_logins_total{result="success"}`,
+				`sinaustudio_logins_total{result="failure"}`,
+				"sinaustudio_active_rooms 1\n",
+				"sinaustudio_registered_users 2\n",
+				`go_sql_max_open_connections{db_name="sinaustudio"}`,
+				`go_sql_in_use_connections{db_name="sinaustudio"}`,
+			),
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/docs"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	h := routertest.New(t)
+
+	for _, route := range h.Engine.Routes() {
+		if !docs.HasOperation(route.Method, route.Path) {
+			t.Errorf("route %s %s belum didokumentasikan di docs.Operations", route.Method, route.Path)
+		}
+	}
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	h := routertest.New(t)
+
+	res := h.Do(http.MethodGet, docs.SpecPath, nil, "")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+	spec := res.JSON(t)
+	if spec["openapi"] != "3.0.3" {
+		t.Fatalf("unexpected openapi version %v", spec["openapi"])
+	}
+	paths, _ := spec["paths"].(map[string]any)
+	if _, ok := paths["/api/v.1/auth/register"]; !ok {
+		t.Fatal("expected /api/v.1/auth/register in paths")
+	}
+
+	if res := h.Do(http.MethodGet, docs.UIPath, nil, ""); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected swagger UI page, got %d", res.StatusCode)
+	}
+}
@@ -0,0 +1,102 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func expectProfile(key string, want any) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		data, _ := res.JSON(t)["data"].(map[string]any)
+		profile, _ := data["profile"].(map[string]any)
+		if got := profile[key]; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("expected data.profile.%s = %v, got %v: %s", key, want, got, res.Body)
+		}
+	}
+}
+
+func TestProfile(t *testing.T) {
+	h := routertest.New(t)
+	// Fixtures are registered on first login, which fixes their IDs.
+	for _, name := range []string{"dosen", "mahasiswa", "admin"} {
+		h.Login(name)
+	}
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "student sets own profile", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "mahasiswa",
+			Header:     ifMatch(1),
+			Body:       map[string]any{"profile": map[string]any{"npm": "2405100001", "program_studi": "Teknik Informatika", "angkatan": 2024, "phone": "0812-3456-7890"}},
+			WantStatus: http.StatusOK, Check: expectProfile("phone", "+6281234567890"),
+		},
+		{
+			Name: "profile in user", Method: http.MethodGet, Path: "/api/v2/users/2", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectProfile("npm", "2405100001"),
+		},
+		{
+			Name: "lecturer edits student profile", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "dosen",
+			Header:     ifMatch(2),
+			Body:       map[string]any{"profile": map[string]any{"angkatan": 2023}},
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "admin edits student profile", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "admin",
+			Header:     ifMatch(2),
+			Body:       map[string]any{"profile": map[string]any{"angkatan": 2023}},
+			WantStatus: http.StatusOK, Check: expectProfile("angkatan", 2023),
+		},
+		{
+			Name: "malformed npm", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "mahasiswa",
+			Header:     ifMatch(3),
+			Body:       map[string]any{"profile": map[string]any{"npm": "24-051"}},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "student with nidn", Method: http.MethodPatch, Path: "/api/v2/users/2", User: "mahasiswa",
+			Header:     ifMatch(3),
+			Body:       map[string]any{"profile": map[string]any{"nidn": "0412345678"}},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "lecturer sets nidn", Method: http.MethodPatch, Path: "/api/v2/users/1", User: "dosen",
+			Header:     ifMatch(1),
+			Body:       map[string]any{"profile": map[string]any{"nidn": "0412345678", "program_studi": "Teknik Informatika"}},
+			WantStatus: http.StatusOK, Check: expectProfile("nidn", "0412345678"),
+		},
+		{
+			Name: "new student with taken npm", Method: http.MethodPost, Path: "/api/v2/users", User: "dosen",
+			Body:       map[string]any{"name": "Rina Lestari", "email": "rina@sinaustudio.test", "password": "rahasia123", "role": "mahasiswa", "class_id": h.ClassID, "profile": map[string]any{"npm": "2405100001"}},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_ALREADY_USE,
+		},
+		{
+			Name: "new student", Method: http.MethodPost, Path: "/api/v2/users", User: "dosen",
+			Body:       map[string]any{"name": "Rina Lestari", "email": "rina@sinaustudio.test", "password": "rahasia123", "role": "mahasiswa", "class_id": h.ClassID, "profile": map[string]any{"npm": "2405100002", "angkatan": 2024}},
+			WantStatus: http.StatusCreated, Check: expectProfile("npm", "2405100002"),
+		},
+		{
+			Name: "users by npm", Method: http.MethodGet, Path: "/api/v2/users?npm=2405100002", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectIDs(4),
+		},
+		{
+			Name: "users by angkatan and role", Method: http.MethodGet, Path: "/api/v2/users?angkatan=2024&role=mahasiswa", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectIDs(4),
+		},
+		{
+			Name: "users by program studi", Method: http.MethodGet, Path: "/api/v2/users?program_studi=informatika&sort=-id", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectIDs(2, 1),
+		},
+		{
+			Name: "lectures by nidn", Method: http.MethodGet, Path: "/api/v2/lectures?nidn=0412345678", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectIDs(1),
+		},
+		{
+			Name: "lectures by unknown nidn", Method: http.MethodGet, Path: "/api/v2/lectures?nidn=0499999999", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectLen(0),
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package router_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func expectRequestID(want string) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		got := res.Header.Get("X-Request-ID")
+		if want == "" && len(got) != 20 || want != "" && got != want {
+			t.Fatalf("expected X-Request-ID %q, got %q", want, got)
+		}
+		if res.StatusCode >= 400 {
+			if ID, _ := res.JSON(t)["request_id"].(string); ID != got {
+				t.Fatalf("expected request_id %q in the error body, got %s", got, res.Body)
+			}
+		}
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := logging.Default()
+	logging.SetDefault(logging.New(&logs, nil))
+	t.Cleanup(func() { logging.SetDefault(defaultLogger) })
+
+	h := routertest.New(t)
+	h.Run([]routertest.Scenario{
+		{
+			Name: "generated for success", Method: http.MethodGet, Path: "/api/v2/matkul", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectRequestID(""),
+		},
+		{
+			Name: "kept from the client", Method: http.MethodGet, Path: "/api/v2/matkul/99", User: "dosen",
+			Header:     map[string]string{"X-Request-ID": "req-42"},
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND, Check: expectRequestID("req-42"),
+		},
+		{
+			Name: "replaced when malformed", Method: http.MethodGet, Path: "/api/v2/matkul", Header: map[string]string{"X-Request-ID": "bukan id\nvalid"},
+			WantStatus: http.StatusUnauthorized, Check: expectRequestID(""),
+		},
+	})
+
+	var found bool
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if entry["request_id"] == "req-42" {
+			found = true
+			if entry["package"] != "http" || entry["level"] != "WARN" || entry["status"] != float64(http.StatusNotFound) || entry["error_key"] != exception.ERR_NOT_FOUND {
+				t.Errorf("unexpected access log line %v", entry)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("no log line with request_id req-42 in:\n%s", logs.String())
+	}
+}
@@ -0,0 +1,81 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func TestSoftDeleteRestore(t *testing.T) {
+	h := routertest.New(t)
+	h.Login("dosen")
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "create room", Method: http.MethodPost, Path: "/api/v2/rooms", User: "dosen",
+			Body:       map[string]any{"name": "Algoritma", "url": "https://meet.example.com/algo", "lecture_id": 1, "start_room": "2023-02-19 20:25:50", "end_room": "2023-02-19 22:25:50"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/rooms/1"),
+		},
+		{
+			Name: "delete lecture", Method: http.MethodDelete, Path: "/api/v2/lectures/1", User: "dosen",
+			Header:     ifMatch(1),
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+		{
+			Name: "room deleted with lecture", Method: http.MethodGet, Path: "/api/v2/rooms/1", User: "dosen",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "room list hides deleted", Method: http.MethodGet, Path: "/api/v2/rooms", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(0),
+		},
+		{
+			Name: "restore room before lecture", Method: http.MethodPost, Path: "/api/v2/admin/rooms/1/restore", User: "admin",
+			WantStatus: http.StatusConflict, WantKey: exception.ERR_CONFLICT,
+		},
+		{
+			Name: "student cannot restore", Method: http.MethodPost, Path: "/api/v2/admin/lectures/1/restore", User: "mahasiswa",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "lecturer cannot restore", Method: http.MethodPost, Path: "/api/v2/admin/lectures/1/restore", User: "dosen",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "restore lecture", Method: http.MethodPost, Path: "/api/v2/admin/lectures/1/restore", User: "admin",
+			WantStatus: http.StatusOK, Check: expectData("resource", "lectures"),
+		},
+		{
+			Name: "room restored with lecture", Method: http.MethodGet, Path: "/api/v2/rooms/1", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectData("name", "Algoritma"),
+		},
+		{
+			Name: "restore live lecture", Method: http.MethodPost, Path: "/api/v2/admin/lectures/1/restore", User: "admin",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "create matkul", Method: http.MethodPost, Path: "/api/v2/matkul", User: "dosen",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Algoritma Pemrograman"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/matkul/1"),
+		},
+		{
+			Name: "delete matkul", Method: http.MethodDelete, Path: "/api/v2/matkul/1", User: "dosen",
+			Header:     ifMatch(1),
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+		{
+			Name: "restore matkul", Method: http.MethodPost, Path: "/api/v2/admin/matkul/1/restore", User: "admin",
+			WantStatus: http.StatusOK, Check: expectData("id", 1),
+		},
+		{
+			Name: "matkul restored", Method: http.MethodGet, Path: "/api/v2/matkul/1", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectData("kode_matkul", "IF101"),
+		},
+		{
+			Name: "unknown resource", Method: http.MethodPost, Path: "/api/v2/admin/kursi/1/restore", User: "admin",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+	})
+}
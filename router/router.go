@@ -0,0 +1,216 @@
+package router
+
+import (
+	"database/sql"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/controllers"
+	controllersV2 "github.com/dimassfeb-09/sinaustudio.git/controllers/v2"
+	"github.com/dimassfeb-09/sinaustudio.git/docs"
+	"github.com/dimassfeb-09/sinaustudio.git/graphqlapi"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/metrics"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+	"github.com/gin-gonic/gin"
+)
+
+// NewRouter builds the gin engine with every route registered, without
+// serving it. Uploads such as avatars are kept in store.
+func NewRouter(db *sql.DB, dialect repository.Dialect, store *storage.Quota) *gin.Engine {
+	route := gin.New()
+	route.Use(api.RequestLogger(logging.Default()), api.Recovery(), tracing.Middleware(), metrics.Middleware(), api.ControllAccessAllow())
+
+	Router(route, db, dialect, store)
+	return route
+}
+
+func Router(route *gin.Engine, db *sql.DB, dialect repository.Dialect, store *storage.Quota) {
+	docs.Register(route)
+
+	v1 := route.Group("/api/v.1/")
+
+	microServices := api.NewSQLMicroService(dialect)
+
+	usersService := services.NewUserServiceImplementation(db, microServices)
+	authService := services.NewAuthServiceImplementation(db, microServices)
+	classService := services.NewClassServiceImplementation(db, microServices)
+	lectureService := services.NewLectureServiceImplementation(db, microServices)
+	roomService := services.NewRoomServiceImplementation(db, microServices)
+	matkulService := services.NewMataKuliahServiceImplementation(db, microServices)
+
+	usersController := controllers.NewUsersControllerImplementation(usersService)
+	authController := controllers.NewAuthControllerImplementation(authService)
+	classController := controllers.NewClassControllerImplementation(classService)
+	lectureController := controllers.NewLectureController(lectureService)
+	roomController := controllers.NewRoomController(roomService)
+	matkulController := controllers.NewMatkulController(matkulService)
+
+	auth := v1.Group("/auth")
+	auth.POST("/register", authController.AuthRegisterUser)
+	auth.POST("/login", authController.AuthLoginUser)
+
+	v1.Use(api.MiddlewareAuthorization)
+	user := v1.Group("/user")
+	user.POST("/create", usersController.InsertDataUser)
+	user.PUT("/update", usersController.UpdateDataUser)
+	user.DELETE("/delete", usersController.DeleteDataUser)
+	user.PUT("/changepassword", usersController.ChangePasswordUser)
+
+	class := v1.Group("/class")
+	class.POST("/create", classController.AddClass)
+	class.PUT("/update", classController.UpdateClass)
+	class.DELETE("/delete", classController.DeleteClassByID)
+	class.GET("/", func(c *gin.Context) {
+		if ID := c.Query("id"); ID != "" {
+			classController.FindClassByID(c)
+			return
+		} else if name := c.Query("name"); name != "" {
+			classController.FindClassByName(c)
+			return
+		}
+	})
+
+	lecture := v1.Group("/lecture")
+	lecture.POST("/create", lectureController.InsertLecture)
+	lecture.PUT("/update", lectureController.UpdateLecture)
+	lecture.DELETE("/delete", lectureController.DeleteLecture)
+	lecture.GET("/", func(c *gin.Context) {
+		if id := c.Query("id"); id != "" {
+			lectureController.FindLectureByID(c)
+			return
+		} else if name := c.Query("name"); name != "" {
+			lectureController.FindLectureByName(c)
+			return
+		}
+	})
+
+	room := v1.Group("/room")
+	room.POST("/create", roomController.InsertRoom)
+	room.PUT("/update", roomController.UpdateRoom)
+	room.DELETE("/delete", roomController.DeleteRoom)
+	room.GET("/", func(c *gin.Context) {
+		if id := c.Query("id"); id != "" {
+			roomController.FindRoomByID(c)
+			return
+		}
+	})
+
+	matkul := v1.Group("/matkul")
+	matkul.POST("/create", matkulController.InsertMatkul)
+	matkul.PUT("/update", matkulController.UpdateMatkul)
+	matkul.DELETE("/delete", matkulController.DeleteMatkul)
+	matkul.GET("/", func(c *gin.Context) {
+		if id := c.Query("id"); id != "" {
+			matkulController.FindMatkulByID(c)
+			return
+		} else if name := c.Query("name"); name != "" {
+			matkulController.FindMatkulByName(c)
+			return
+		}
+	})
+
+	usersControllerV2 := controllersV2.NewUsersController(usersService)
+	classControllerV2 := controllersV2.NewClassController(classService)
+	lectureControllerV2 := controllersV2.NewLectureController(lectureService)
+	roomControllerV2 := controllersV2.NewRoomController(roomService)
+	matkulControllerV2 := controllersV2.NewMatkulController(matkulService)
+
+	v2 := route.Group(controllersV2.BasePath)
+	v2.Use(api.MiddlewareAuthorization)
+
+	users := v2.Group("/users")
+	users.GET("", usersControllerV2.ListUser)
+	users.POST("", usersControllerV2.CreateUser)
+	users.GET("/:id", usersControllerV2.GetUser)
+	users.PATCH("/:id", usersControllerV2.PatchUser)
+	users.DELETE("/:id", usersControllerV2.DeleteUser)
+	users.PUT("/:id/password", usersControllerV2.ChangePasswordUser)
+
+	avatarControllerV2 := controllersV2.NewAvatarController(services.NewAvatarServiceImplementation(db, microServices, store), usersService)
+	users.PUT("/:id/avatar", avatarControllerV2.UploadAvatar)
+	users.DELETE("/:id/avatar", avatarControllerV2.DeleteAvatar)
+	// Avatars are served without a token, so that their URL works in an img tag.
+	route.GET(services.AvatarPath+"/:id/:name", avatarControllerV2.GetAvatar)
+
+	fileControllerV2 := controllersV2.NewFileController(services.NewFileServiceImplementation(db, microServices, store))
+	users.GET("/:id/storage", fileControllerV2.Usage)
+	// Signed URLs carry their own credential, like a presigned S3 URL.
+	route.GET(storage.LocalPath+"/*key", fileControllerV2.Download)
+
+	classes := v2.Group("/classes")
+	classes.GET("", classControllerV2.ListClass)
+	classes.POST("", classControllerV2.CreateClass)
+	classes.GET("/:id", classControllerV2.GetClass)
+	classes.PATCH("/:id", classControllerV2.PatchClass)
+	classes.DELETE("/:id", classControllerV2.DeleteClass)
+	classes.GET("/:id/users", classControllerV2.ListClassUsers)
+	classes.POST("/join", classControllerV2.JoinClass)
+	classes.GET("/:id/members", classControllerV2.ListClassMembers)
+	classes.DELETE("/:id/members/:user_id", classControllerV2.RemoveClassMember)
+	classes.POST("/:id/code", classControllerV2.RegenerateKodeKelas)
+
+	lectures := v2.Group("/lectures")
+	lectures.GET("", lectureControllerV2.ListLecture)
+	lectures.POST("", lectureControllerV2.CreateLecture)
+	lectures.GET("/:id", lectureControllerV2.GetLecture)
+	lectures.PATCH("/:id", lectureControllerV2.PatchLecture)
+	lectures.DELETE("/:id", lectureControllerV2.DeleteLecture)
+
+	rooms := v2.Group("/rooms")
+	rooms.GET("", roomControllerV2.ListRoom)
+	rooms.POST("", roomControllerV2.CreateRoom)
+	rooms.GET("/:id", roomControllerV2.GetRoom)
+	rooms.PATCH("/:id", roomControllerV2.PatchRoom)
+	rooms.DELETE("/:id", roomControllerV2.DeleteRoom)
+
+	matkuls := v2.Group("/matkul")
+	matkuls.GET("", matkulControllerV2.ListMatkul)
+	matkuls.POST("", matkulControllerV2.CreateMatkul)
+	matkuls.GET("/:id", matkulControllerV2.GetMatkul)
+	matkuls.PATCH("/:id", matkulControllerV2.PatchMatkul)
+	matkuls.DELETE("/:id", matkulControllerV2.DeleteMatkul)
+
+	// Access to the materials follows the classes enrolled in the matkul and
+	// is checked by MaterialService.
+	materialControllerV2 := controllersV2.NewMaterialController(services.NewMaterialServiceImplementation(db, microServices, store))
+	classes.GET("/:id/matkul", materialControllerV2.ListClassMatkul)
+	classes.PUT("/:id/matkul/:matkul_id", materialControllerV2.EnrollClass)
+	classes.DELETE("/:id/matkul/:matkul_id", materialControllerV2.UnenrollClass)
+	matkuls.GET("/:id/topics", materialControllerV2.ListTopics)
+	matkuls.POST("/:id/topics", materialControllerV2.CreateTopic)
+	matkuls.DELETE("/:id/topics/:topic_id", materialControllerV2.DeleteTopic)
+	matkuls.POST("/:id/topics/:topic_id/links", materialControllerV2.CreateLink)
+	matkuls.POST("/:id/topics/:topic_id/files", materialControllerV2.UploadFile)
+	matkuls.GET("/:id/materials/:material_id", materialControllerV2.GetMaterial)
+	matkuls.DELETE("/:id/materials/:material_id", materialControllerV2.DeleteMaterial)
+	matkuls.GET("/:id/materials/:material_id/download", materialControllerV2.DownloadMaterial)
+
+	importControllerV2 := controllersV2.NewImportController(services.NewImportServiceImplementation(db, microServices))
+	v2.POST("/import/:resource", importControllerV2.Import)
+
+	exportControllerV2 := controllersV2.NewExportController(services.NewExportServiceImplementation(db, microServices))
+	v2.GET("/export/:resource", exportControllerV2.Export)
+
+	// Restoring is limited to admins by RestoreService.
+	restoreControllerV2 := controllersV2.NewRestoreController(services.NewRestoreServiceImplementation(db, microServices))
+	admin := v2.Group("/admin")
+	admin.POST("/:resource/:id/restore", restoreControllerV2.Restore)
+
+	graphqlHandler, err := graphqlapi.NewHandler(graphqlapi.Services{Class: classService, Users: usersService, Lecture: lectureService, Room: roomService, Matkul: matkulService}, graphqlapi.DefaultLimits)
+	if err != nil {
+		panic(err)
+	}
+	route.GET(graphqlapi.Path, api.MiddlewareAuthorization, graphqlHandler.Serve)
+	route.POST(graphqlapi.Path, api.MiddlewareAuthorization, graphqlHandler.Serve)
+
+	healthController := controllers.NewHealthController(services.NewHealthServiceImplementation(db, dialect))
+	route.GET(controllers.HealthPath, healthController.Health)
+	route.GET(controllers.ReadyPath, healthController.Ready)
+
+	// Like the docs, /metrics is left unauthenticated for the Prometheus scraper;
+	// keep it off the public listener in production.
+	route.GET(metrics.Path, gin.WrapH(metrics.Handler(db, services.NewStatsServiceImplementation(db, microServices))))
+}
@@ -0,0 +1,139 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func expectData(key string, want any) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		data, _ := res.JSON(t)["data"].(map[string]any)
+		if got := data[key]; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("expected data.%s = %v, got %v: %s", key, want, got, res.Body)
+		}
+	}
+}
+
+func TestAuthFlow(t *testing.T) {
+	h := routertest.New(t)
+
+	h.Run([]routertest.Scenario{
+		{
+			Name:   "register dosen",
+			Method: http.MethodPost, Path: "/api/v.1/auth/register",
+			Body:       map[string]any{"email": "dimas@gmail.com", "password": "jahgdasadaadsasd", "name": "Dimas", "role": "dosen", "class_id": h.ClassID},
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name:   "register same email",
+			Method: http.MethodPost, Path: "/api/v.1/auth/register",
+			Body:       map[string]any{"email": "dimas@gmail.com", "password": "jahgdasadaadsasd", "name": "Dimas", "role": "dosen", "class_id": h.ClassID},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name:   "register unknown class",
+			Method: http.MethodPost, Path: "/api/v.1/auth/register",
+			Body:       map[string]any{"email": "budi@gmail.com", "password": "jahgdasadaadsasd", "name": "Budi Santoso", "role": "mahasiswa", "class_id": 99},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name:   "login",
+			Method: http.MethodPost, Path: "/api/v.1/auth/login",
+			Body:       map[string]any{"email": "dimas@gmail.com", "password": "jahgdasadaadsasd"},
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name:   "login wrong password",
+			Method: http.MethodPost, Path: "/api/v.1/auth/login",
+			Body:       map[string]any{"email": "dimas@gmail.com", "password": "salahpassword"},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name:   "class without token",
+			Method: http.MethodGet, Path: fmt.Sprintf("/api/v.1/class/?id=%d", h.ClassID),
+			WantStatus: http.StatusUnauthorized, WantKey: exception.ERR_UNAUTHORIZED_BEARER,
+		},
+		{
+			Name:   "class by id",
+			Method: http.MethodGet, Path: fmt.Sprintf("/api/v.1/class/?id=%d", h.ClassID), User: "dosen",
+			WantStatus: http.StatusOK, Check: expectData("kode_kelas", "FIXTURE01"),
+		},
+	})
+}
+
+func TestResourceFlows(t *testing.T) {
+	h := routertest.New(t)
+
+	h.Run([]routertest.Scenario{
+		{
+			Name:   "create class",
+			Method: http.MethodPost, Path: "/api/v.1/class/create", User: "dosen",
+			Body:       map[string]any{"name": "TI-2B"},
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name:   "find class by name",
+			Method: http.MethodGet, Path: "/api/v.1/class/?name=TI-2B", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectData("name", "TI-2B"),
+		},
+		{
+			Name:   "delete class with members",
+			Method: http.MethodDelete, Path: fmt.Sprintf("/api/v.1/class/delete?id=%d", h.ClassID), User: "dosen",
+			WantStatus: http.StatusConflict, WantKey: exception.ERR_CONFLICT,
+		},
+		{
+			Name:   "fixture dosen has a lecture",
+			Method: http.MethodGet, Path: "/api/v.1/lecture/?name=Dimas%20Saputra", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectData("id", 1),
+		},
+		{
+			Name:   "create room",
+			Method: http.MethodPost, Path: "/api/v.1/room/create", User: "dosen",
+			Body:       map[string]any{"name": "Algoritma", "url": "https://meet.example.com/algo", "lecture_id": 1, "start_room": "2023-02-19 20:25:50", "end_room": "2023-02-19 22:25:50"},
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name:   "create room bad time",
+			Method: http.MethodPost, Path: "/api/v.1/room/create", User: "dosen",
+			Body:       map[string]any{"name": "Algoritma", "url": "https://meet.example.com/algo", "lecture_id": 1, "start_room": "19/02/2023", "end_room": "2023-02-19 22:25:50"},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name:   "find room",
+			Method: http.MethodGet, Path: "/api/v.1/room/?id=1", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectData("start_room", "2023-02-19 20:25:50"),
+		},
+		{
+			Name:   "create matkul",
+			Method: http.MethodPost, Path: "/api/v.1/matkul/create", User: "dosen",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Algoritma Pemrograman"},
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name:   "update matkul",
+			Method: http.MethodPut, Path: "/api/v.1/matkul/update?id=1", User: "dosen",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Algoritma dan Pemrograman", "version": 1},
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name:   "find matkul",
+			Method: http.MethodGet, Path: "/api/v.1/matkul/?id=1", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectData("name", "Algoritma dan Pemrograman"),
+		},
+		{
+			Name:   "delete matkul",
+			Method: http.MethodDelete, Path: "/api/v.1/matkul/delete?id=1", User: "dosen",
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name:   "find deleted matkul",
+			Method: http.MethodGet, Path: "/api/v.1/matkul/?id=1", User: "dosen",
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_NOT_FOUND,
+		},
+	})
+}
@@ -0,0 +1,291 @@
+// Package routertest runs the full gin engine from package router against a
+// throwaway SQLite database so API flows can be exercised with net/http/httptest.
+package routertest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/router"
+	"github.com/dimassfeb-09/sinaustudio.git/seeder"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Fixture struct {
+	Name     string
+	Email    string
+	Password string
+	Role     string
+}
+
+// Fixtures are the users registered by Harness.Login on first use, keyed by the
+// name scenarios refer to them with. Admins cannot register, so the admin is
+// created through services the way the CLI creates one.
+var Fixtures = map[string]Fixture{
+	"dosen":     {Name: "Dimas Saputra", Email: "dimas@sinaustudio.test", Password: "rahasia123", Role: "dosen"},
+	"mahasiswa": {Name: "Siti Rahmawati", Email: "siti@sinaustudio.test", Password: "rahasia123", Role: "mahasiswa"},
+	"admin":     {Name: "Admin Sinau", Email: "admin@sinaustudio.test", Password: "rahasia123", Role: "admin"},
+}
+
+type Harness struct {
+	T       *testing.T
+	DB      *sql.DB
+	Dialect repository.Dialect
+	Engine  *gin.Engine
+	ClassID int
+	Storage *storage.Quota
+
+	tokens map[string]string
+}
+
+// New migrates a fresh SQLite database in the test's temp dir, opened like the
+// server opens its own, seeds one class for fixture users and builds the
+// engine through router.NewRouter, keeping uploads in the temp dir as well.
+func New(t *testing.T) *Harness {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", filepath.Join(t.TempDir(), "sinaustudio.db"))
+	db, err := api.ConnectionDatabases(&api.Config{DBDriver: repository.SQLite.DriverName(), DBDSN: dsn, DBMigrate: true, Dialect: repository.SQLite})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	h := &Harness{T: t, DB: db, Dialect: repository.SQLite, tokens: map[string]string{}}
+	h.ClassID = h.SeedClass("TI-1A", "FIXTURE01")
+	h.Storage = storage.NewQuota(storage.NewLocal(filepath.Join(t.TempDir(), "storage"), storage.LocalPath, []byte("rahasia")), 0)
+	h.Engine = router.NewRouter(db, repository.SQLite, h.Storage)
+	return h
+}
+
+// Seed writes the demo dataset of config through the services, for tests that
+// need more than the fixture users.
+func (h *Harness) Seed(config seeder.Config) *seeder.Report {
+	h.T.Helper()
+
+	report, errMsg := seeder.New(h.DB, api.NewSQLMicroService(h.Dialect)).Run(api.ContextWithSystemActor(context.Background()), config)
+	if errMsg != nil {
+		h.T.Fatalf("seed: %v", errMsg.Msg)
+	}
+	return report
+}
+
+// SeedClass inserts a class directly, since creating one through the API already needs a token.
+func (h *Harness) SeedClass(name, kodeKelas string) int {
+	h.T.Helper()
+
+	tx, err := h.DB.Begin()
+	if err != nil {
+		h.T.Fatal(err)
+	}
+	ID, err := h.Dialect.InsertID(context.Background(), tx, "INSERT INTO class(name, kode_kelas) VALUES(?, ?)", name, kodeKelas)
+	if err != nil {
+		tx.Rollback()
+		h.T.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		h.T.Fatal(err)
+	}
+	return int(ID)
+}
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// JSON decodes the body into a generic map; it fails the test on invalid JSON.
+func (r *Response) JSON(t *testing.T) map[string]any {
+	t.Helper()
+
+	var body map[string]any
+	if err := json.Unmarshal(r.Body, &body); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", r.Body, err)
+	}
+	return body
+}
+
+// Do sends a request through the engine. body is encoded as JSON unless it is
+// nil; an empty token sends no Authorization header.
+func (h *Harness) Do(method, path string, body any, token string) *Response {
+	h.T.Helper()
+	return h.Serve(h.NewRequest(method, path, body), token)
+}
+
+// Upload is a request body sent as multipart/form-data with one file field.
+type Upload struct {
+	Field    string
+	Filename string
+	Content  []byte
+}
+
+// NewRequest builds a request for Serve, encoding body as JSON unless it is
+// nil or an Upload.
+func (h *Harness) NewRequest(method, path string, body any) *http.Request {
+	h.T.Helper()
+
+	if upload, ok := body.(Upload); ok {
+		var payload bytes.Buffer
+		form := multipart.NewWriter(&payload)
+		part, err := form.CreateFormFile(upload.Field, upload.Filename)
+		if err == nil {
+			_, err = part.Write(upload.Content)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		if err != nil {
+			h.T.Fatal(err)
+		}
+		req := httptest.NewRequest(method, path, &payload)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return req
+	}
+
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			h.T.Fatal(err)
+		}
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+// Serve sends req through the engine; an empty token sends no Authorization header.
+func (h *Harness) Serve(req *http.Request, token string) *Response {
+	h.T.Helper()
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	h.Engine.ServeHTTP(recorder, req)
+	return &Response{StatusCode: recorder.Code, Header: recorder.Header(), Body: recorder.Body.Bytes()}
+}
+
+// Login registers the named fixture on first use and returns a bearer token for it.
+func (h *Harness) Login(name string) string {
+	h.T.Helper()
+
+	if token, ok := h.tokens[name]; ok {
+		return token
+	}
+
+	fixture, ok := Fixtures[name]
+	if !ok {
+		h.T.Fatalf("fixture user %q tidak ditemukan", name)
+	}
+
+	if fixture.Role == domain.RoleAdmin {
+		usersService := services.NewUserServiceImplementation(h.DB, api.NewSQLMicroService(h.Dialect))
+		r := &requests.UserInsertRequest{Name: fixture.Name, Email: fixture.Email, Password: fixture.Password, Role: fixture.Role}
+		if _, errMsg := usersService.InsertDataUser(api.ContextWithSystemActor(context.Background()), r); errMsg != nil {
+			h.T.Fatalf("create %s: %v", name, errMsg.Msg)
+		}
+	} else {
+		res := h.Do(http.MethodPost, "/api/v.1/auth/register", map[string]any{
+			"name":     fixture.Name,
+			"email":    fixture.Email,
+			"password": fixture.Password,
+			"role":     fixture.Role,
+			"class_id": h.ClassID,
+		}, "")
+		if res.StatusCode != http.StatusOK {
+			h.T.Fatalf("register %s: %d %s", name, res.StatusCode, res.Body)
+		}
+	}
+
+	res := h.Do(http.MethodPost, "/api/v.1/auth/login", map[string]any{
+		"email":    fixture.Email,
+		"password": fixture.Password,
+	}, "")
+	if res.StatusCode != http.StatusOK {
+		h.T.Fatalf("login %s: %d %s", name, res.StatusCode, res.Body)
+	}
+
+	data, _ := res.JSON(h.T)["data"].(map[string]any)
+	token, _ := data["token"].(string)
+	if token == "" {
+		h.T.Fatalf("login %s: token kosong: %s", name, res.Body)
+	}
+
+	h.tokens[name] = token
+	return token
+}
+
+// Scenario is one API call and its expected outcome. User names a fixture to
+// authenticate as; leave it empty for anonymous calls.
+type Scenario struct {
+	Name       string
+	Method     string
+	Path       string
+	Body       any
+	Header     map[string]string
+	User       string
+	WantStatus int
+	WantKey    string
+	Check      func(t *testing.T, res *Response)
+}
+
+// Run executes the scenarios in order as subtests; later scenarios see the
+// data written by earlier ones.
+func (h *Harness) Run(scenarios []Scenario) {
+	h.T.Helper()
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		h.T.Run(scenario.Name, func(t *testing.T) {
+			sub := *h
+			sub.T = t
+
+			var token string
+			if scenario.User != "" {
+				token = sub.Login(scenario.User)
+			}
+
+			req := sub.NewRequest(scenario.Method, scenario.Path, scenario.Body)
+			for key, value := range scenario.Header {
+				req.Header.Set(key, value)
+			}
+			res := sub.Serve(req, token)
+			if res.StatusCode != scenario.WantStatus {
+				t.Fatalf("%s %s: expected status %d, got %d: %s", scenario.Method, scenario.Path, scenario.WantStatus, res.StatusCode, res.Body)
+			}
+			if scenario.WantKey != "" {
+				if key, _ := res.JSON(t)["error_key"].(string); key != scenario.WantKey {
+					t.Fatalf("%s %s: expected error_key %s, got %q", scenario.Method, scenario.Path, scenario.WantKey, key)
+				}
+			}
+			if scenario.Check != nil {
+				scenario.Check(t, res)
+			}
+		})
+	}
+}
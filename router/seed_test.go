@@ -0,0 +1,39 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+	"github.com/dimassfeb-09/sinaustudio.git/seeder"
+)
+
+func TestSeededData(t *testing.T) {
+	h := routertest.New(t)
+
+	config := seeder.Scales["small"]
+	config.Seed, config.Password, config.Start = 1, "rahasia123", time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)
+	h.Seed(config)
+	student := seeder.Plan(config).Classes[0].Students[0]
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "seeded student logs in", Method: http.MethodPost, Path: "/api/v.1/auth/login",
+			Body:       map[string]any{"email": student.Email, "password": "rahasia123"},
+			WantStatus: http.StatusOK,
+		},
+		{
+			Name: "seeded student by npm", Method: http.MethodGet, Path: "/api/v2/users?npm=" + student.NPM, User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(1),
+		},
+		{
+			Name: "seeded classes", Method: http.MethodGet, Path: "/api/v2/classes", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(3),
+		},
+		{
+			Name: "seeded rooms", Method: http.MethodGet, Path: "/api/v2/rooms", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(4),
+		},
+	})
+}
@@ -0,0 +1,101 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+)
+
+func expectDownload(contentType, body string) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		if got := res.Header.Get("Content-Type"); got != contentType {
+			t.Fatalf("expected Content-Type %q, got %q", contentType, got)
+		}
+		if got := res.Header.Get("Content-Disposition"); !strings.HasPrefix(got, "attachment") {
+			t.Fatalf("expected an attachment, got %q", got)
+		}
+		if got := res.Header.Get("Content-Security-Policy"); got != "sandbox" {
+			t.Fatalf("expected a sandboxed download, got %q", got)
+		}
+		if string(res.Body) != body {
+			t.Fatalf("expected body %q, got %q", body, res.Body)
+		}
+	}
+}
+
+func TestStorage(t *testing.T) {
+	h := routertest.New(t)
+	for _, name := range []string{"dosen", "mahasiswa", "admin"} {
+		h.Login(name)
+	}
+
+	ctx := context.Background()
+	notes := storage.UserKey(2, "catatan.txt")
+	if err := h.Storage.Put(ctx, notes, strings.NewReader("catatan kuliah minggu 1"), storage.Info{}); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := h.Storage.SignedURL(ctx, notes, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := h.Storage.SignedURL(ctx, notes, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Storage.Limit = 30
+	avatar := picture(t, 64, 64, func(buf *bytes.Buffer, img image.Image) error { return png.Encode(buf, img) })
+
+	h.Run([]routertest.Scenario{
+		{
+			Name: "student reads storage usage", Method: http.MethodGet, Path: "/api/v2/users/2/storage", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectData("used", 23),
+		},
+		{
+			Name: "admin reads storage quota", Method: http.MethodGet, Path: "/api/v2/users/2/storage", User: "admin",
+			WantStatus: http.StatusOK, Check: expectData("quota", 30),
+		},
+		{
+			Name: "lecturer reads student storage", Method: http.MethodGet, Path: "/api/v2/users/2/storage", User: "dosen",
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "storage of unknown user", Method: http.MethodGet, Path: "/api/v2/users/99/storage", User: "admin",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name: "signed download without token", Method: http.MethodGet, Path: signed,
+			WantStatus: http.StatusOK, Check: expectDownload("text/plain; charset=utf-8", "catatan kuliah minggu 1"),
+		},
+		{
+			Name: "signed URL for another file", Method: http.MethodGet, Path: strings.Replace(signed, "catatan", "lain", 1),
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "expired signed URL", Method: http.MethodGet, Path: expired,
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "download without signature", Method: http.MethodGet, Path: storage.LocalPath + "/" + notes,
+			WantStatus: http.StatusForbidden, WantKey: exception.ERR_FORBIDDEN,
+		},
+		{
+			Name: "avatar over quota", Method: http.MethodPut, Path: "/api/v2/users/2/avatar", User: "mahasiswa",
+			Header: ifMatch(1), Body: routertest.Upload{Field: "avatar", Filename: "foto.png", Content: avatar},
+			WantStatus: http.StatusRequestEntityTooLarge, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name: "rejected avatar leaves usage", Method: http.MethodGet, Path: "/api/v2/users/2/storage", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectData("used", 23),
+		},
+	})
+}
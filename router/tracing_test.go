@@ -0,0 +1,56 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingFollowsRegister(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defaultProvider, defaultPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(defaultProvider)
+		otel.SetTextMapPropagator(defaultPropagator)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	h := routertest.New(t)
+	h.Run([]routertest.Scenario{{
+		Name: "register", Method: http.MethodPost, Path: "/api/v.1/auth/register",
+		Header:     map[string]string{"traceparent": "00-" + traceID + "-00f067aa0ba902b7-01"},
+		Body:       map[string]any{"name": "Dimas Saputra", "email": "dimas@sinaustudio.test", "password": "rahasia123", "role": "dosen", "class_id": h.ClassID},
+		WantStatus: http.StatusOK,
+	}})
+
+	// Migrating the database and logging in also leave spans, in traces of their own.
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID().String() == traceID {
+			spans[span.Name] = span
+		}
+	}
+
+	parents := map[string]string{
+		"AuthService.AuthRegisterUser": "POST /api/v.1/auth/register",
+		"bcrypt.GenerateFromPassword":  "AuthService.AuthRegisterUser",
+	}
+	for child, parent := range parents {
+		if spans[child].Parent.SpanID() != spans[parent].SpanContext.SpanID() || !spans[parent].SpanContext.IsValid() {
+			t.Errorf("expected span %q under %q, got %v", child, parent, exporter.GetSpans())
+		}
+	}
+	for _, name := range []string{"sql.conn.query", "sql.conn.exec"} {
+		if span, ok := spans[name]; !ok || span.Parent.SpanID() != spans["AuthService.AuthRegisterUser"].SpanContext.SpanID() {
+			t.Errorf("expected a %s span under AuthService.AuthRegisterUser", name)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/router/routertest"
+)
+
+func expectLocation(want string) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		if got := res.Header.Get("Location"); got != want {
+			t.Fatalf("expected Location %s, got %q", want, got)
+		}
+	}
+}
+
+func expectLen(want int) func(t *testing.T, res *routertest.Response) {
+	return func(t *testing.T, res *routertest.Response) {
+		t.Helper()
+		data, _ := res.JSON(t)["data"].([]any)
+		if len(data) != want {
+			t.Fatalf("expected %d items, got %d: %s", want, len(data), res.Body)
+		}
+	}
+}
+
+func ifMatch(version int) map[string]string {
+	return map[string]string{"If-Match": fmt.Sprintf("%q", fmt.Sprint(version))}
+}
+
+func expectEmptyBody(t *testing.T, res *routertest.Response) {
+	t.Helper()
+	if len(res.Body) != 0 {
+		t.Fatalf("expected empty body, got %s", res.Body)
+	}
+}
+
+func TestV2Resources(t *testing.T) {
+	h := routertest.New(t)
+	h.Login("dosen")
+	h.Login("mahasiswa")
+
+	h.Run([]routertest.Scenario{
+		{
+			Name:   "without token",
+			Method: http.MethodGet, Path: "/api/v2/classes",
+			WantStatus: http.StatusUnauthorized, WantKey: exception.ERR_UNAUTHORIZED_BEARER,
+		},
+		{
+			Name:   "create class",
+			Method: http.MethodPost, Path: "/api/v2/classes", User: "dosen",
+			Body:       map[string]any{"name": "TI-2B"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/classes/2"),
+		},
+		{
+			Name:   "list classes",
+			Method: http.MethodGet, Path: "/api/v2/classes", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectLen(2),
+		},
+		{
+			Name:   "list classes by name",
+			Method: http.MethodGet, Path: "/api/v2/classes?name=2b", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectLen(1),
+		},
+		{
+			Name:   "patch class",
+			Header: ifMatch(1),
+			Method: http.MethodPatch, Path: "/api/v2/classes/2", User: "dosen",
+			Body:       map[string]any{"name": "TI-2C"},
+			WantStatus: http.StatusOK, Check: expectData("name", "TI-2C"),
+		},
+		{
+			Name:   "class users",
+			Method: http.MethodGet, Path: fmt.Sprintf("/api/v2/classes/%d/users", h.ClassID), User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(2),
+		},
+		{
+			Name:   "empty class users",
+			Method: http.MethodGet, Path: "/api/v2/classes/2/users", User: "dosen",
+			WantStatus: http.StatusOK, Check: expectLen(0),
+		},
+		{
+			Name:   "delete class",
+			Header: ifMatch(2),
+			Method: http.MethodDelete, Path: "/api/v2/classes/2", User: "dosen",
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+		{
+			Name:   "get deleted class",
+			Method: http.MethodGet, Path: "/api/v2/classes/2", User: "dosen",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+		{
+			Name:   "invalid id",
+			Method: http.MethodGet, Path: "/api/v2/classes/abc", User: "dosen",
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_BAD_REQUEST_FIELD,
+		},
+		{
+			Name:   "create room",
+			Method: http.MethodPost, Path: "/api/v2/rooms", User: "dosen",
+			Body:       map[string]any{"name": "Algoritma", "url": "https://meet.example.com/algo", "lecture_id": 1, "start_room": "2023-02-19 20:25:50", "end_room": "2023-02-19 22:25:50"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/rooms/1"),
+		},
+		{
+			Name:   "patch room url only",
+			Header: ifMatch(1),
+			Method: http.MethodPatch, Path: "/api/v2/rooms/1", User: "dosen",
+			Body:       map[string]any{"url": "https://meet.example.com/algo-2"},
+			WantStatus: http.StatusOK, Check: expectData("url", "https://meet.example.com/algo-2"),
+		},
+		{
+			Name:   "patched room keeps name",
+			Method: http.MethodGet, Path: "/api/v2/rooms/1", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectData("name", "Algoritma"),
+		},
+		{
+			Name:   "delete room",
+			Header: ifMatch(2),
+			Method: http.MethodDelete, Path: "/api/v2/rooms/1", User: "dosen",
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+		{
+			Name:   "create matkul",
+			Method: http.MethodPost, Path: "/api/v2/matkul", User: "dosen",
+			Body:       map[string]any{"kode_matkul": "IF101", "name": "Algoritma Pemrograman"},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/matkul/1"),
+		},
+		{
+			Name:   "patch matkul",
+			Header: ifMatch(1),
+			Method: http.MethodPatch, Path: "/api/v2/matkul/1", User: "dosen",
+			Body:       map[string]any{"name": "Algoritma dan Pemrograman"},
+			WantStatus: http.StatusOK, Check: expectData("kode_matkul", "IF101"),
+		},
+		{
+			Name:   "list lectures",
+			Method: http.MethodGet, Path: "/api/v2/lectures?name=dimas", User: "mahasiswa",
+			WantStatus: http.StatusOK, Check: expectLen(1),
+		},
+		{
+			Name:   "create user",
+			Method: http.MethodPost, Path: "/api/v2/users", User: "dosen",
+			Body:       map[string]any{"name": "Budi Santoso", "email": "budi@sinaustudio.test", "password": "rahasia123", "role": "mahasiswa", "class_id": h.ClassID},
+			WantStatus: http.StatusCreated, Check: expectLocation("/api/v2/users/3"),
+		},
+		{
+			Name:   "get user hides password",
+			Method: http.MethodGet, Path: "/api/v2/users/3", User: "dosen",
+			WantStatus: http.StatusOK, Check: func(t *testing.T, res *routertest.Response) {
+				data, _ := res.JSON(t)["data"].(map[string]any)
+				if _, ok := data["password"]; ok {
+					t.Fatalf("password must not be returned: %s", res.Body)
+				}
+			},
+		},
+		{
+			Name:   "patch user email taken",
+			Header: ifMatch(1),
+			Method: http.MethodPatch, Path: "/api/v2/users/3", User: "dosen",
+			Body:       map[string]any{"email": "siti@sinaustudio.test"},
+			WantStatus: http.StatusBadRequest, WantKey: exception.ERR_ALREADY_USE,
+		},
+		{
+			Name:   "patch user name",
+			Header: ifMatch(1),
+			Method: http.MethodPatch, Path: "/api/v2/users/3", User: "dosen",
+			Body:       map[string]any{"name": "Budi Prasetyo"},
+			WantStatus: http.StatusOK, Check: expectData("email", "budi@sinaustudio.test"),
+		},
+		{
+			Name:   "delete user",
+			Header: ifMatch(2),
+			Method: http.MethodDelete, Path: "/api/v2/users/3", User: "dosen",
+			Body:       map[string]any{"confirmpassword": "rahasia123"},
+			WantStatus: http.StatusNoContent, Check: expectEmptyBody,
+		},
+		{
+			Name:   "get deleted user",
+			Method: http.MethodGet, Path: "/api/v2/users/3", User: "dosen",
+			WantStatus: http.StatusNotFound, WantKey: exception.ERR_NOT_FOUND,
+		},
+	})
+}
@@ -0,0 +1,64 @@
+package seeder
+
+type prodi struct {
+	Code   string
+	Number string
+	Name   string
+}
+
+// prodis are the study programs classes are spread over; Number is the
+// program's part of an NPM.
+var prodis = []prodi{
+	{"TI", "0510", "Teknik Informatika"},
+	{"SI", "0520", "Sistem Informasi"},
+	{"TE", "0310", "Teknik Elektro"},
+	{"MN", "0210", "Manajemen"},
+	{"AK", "0220", "Akuntansi"},
+}
+
+var firstNames = []string{
+	"Adi", "Agus", "Ahmad", "Andi", "Anisa", "Ayu", "Bayu", "Bambang", "Budi", "Citra",
+	"Dewi", "Dian", "Dimas", "Eko", "Fajar", "Fitri", "Gilang", "Hendra", "Indah", "Intan",
+	"Joko", "Kartika", "Lestari", "Lina", "Made", "Maya", "Nur", "Nyoman", "Putri", "Rahmat",
+	"Rina", "Rizky", "Sari", "Siti", "Slamet", "Taufik", "Teguh", "Wahyu", "Wulan", "Yusuf",
+}
+
+var lastNames = []string{
+	"Anggraini", "Firmansyah", "Gunawan", "Hakim", "Handayani", "Hidayat", "Irawan", "Kurniawan", "Kusuma", "Lubis",
+	"Maharani", "Nasution", "Nugroho", "Pangaribuan", "Permana", "Pratama", "Purnomo", "Putra", "Rahayu", "Ramadhan",
+	"Saputra", "Setiawan", "Simanjuntak", "Siregar", "Suharto", "Sulistyo", "Susanto", "Syahputra", "Tanjung", "Utami",
+	"Wahyudi", "Wibowo", "Widodo", "Wijaya", "Yulianti",
+}
+
+// lecturerTitles are appended to lecturer names, as on a faculty roster.
+var lecturerTitles = []string{"S.Kom., M.Kom.", "S.T., M.T.", "M.Sc.", "S.E., M.M.", "M.Kom., Ph.D."}
+
+type matkul struct {
+	Kode string
+	Name string
+}
+
+var matkuls = []matkul{
+	{"IF101", "Algoritma dan Pemrograman"},
+	{"IF102", "Matematika Diskrit"},
+	{"IF103", "Pengantar Teknologi Informasi"},
+	{"IF201", "Struktur Data"},
+	{"IF202", "Basis Data"},
+	{"IF203", "Sistem Operasi"},
+	{"IF204", "Jaringan Komputer"},
+	{"IF205", "Pemrograman Berorientasi Objek"},
+	{"IF301", "Rekayasa Perangkat Lunak"},
+	{"IF302", "Pemrograman Web"},
+	{"IF303", "Kecerdasan Buatan"},
+	{"IF304", "Interaksi Manusia dan Komputer"},
+	{"IF401", "Keamanan Informasi"},
+	{"IF402", "Komputasi Awan"},
+	{"SI201", "Analisis dan Perancangan Sistem"},
+	{"SI301", "Manajemen Proyek Sistem Informasi"},
+	{"TE101", "Rangkaian Listrik"},
+	{"MN101", "Pengantar Manajemen"},
+	{"AK101", "Pengantar Akuntansi"},
+	{"UM101", "Pendidikan Pancasila"},
+	{"UM102", "Bahasa Indonesia"},
+	{"UM103", "Bahasa Inggris"},
+}
@@ -0,0 +1,196 @@
+// Package seeder fills a database with demo and fixture data: classes per
+// program studi, lecturers with their rooms, students with NPMs and a
+// catalogue of matakuliah. Plan draws the data from a seed, so the same
+// Config always yields the same dataset, and Seeder.Run writes it through the
+// services, skipping what an earlier run already created.
+package seeder
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// Seed picks the names and schedules; the same seed gives the same data.
+	Seed              int64
+	Classes           int
+	LecturersPerClass int
+	StudentsPerClass  int
+	Matkul            int
+	RoomsPerLecturer  int
+	// Password is the password of every seeded user.
+	Password string
+	// Start is the Monday the room schedule begins; each lecturer has one
+	// room per week from then on, Monday to Friday.
+	Start time.Time
+}
+
+// Scales are the preset sizes of the CLI's -scale flag; Seed, Password and
+// Start are left to the caller.
+var Scales = map[string]Config{
+	"small":  {Classes: 2, LecturersPerClass: 1, StudentsPerClass: 5, Matkul: 4, RoomsPerLecturer: 2},
+	"medium": {Classes: 6, LecturersPerClass: 2, StudentsPerClass: 25, Matkul: 10, RoomsPerLecturer: 4},
+	"large":  {Classes: 20, LecturersPerClass: 3, StudentsPerClass: 40, Matkul: len(matkuls), RoomsPerLecturer: 8},
+}
+
+// MaxStudentsPerClass is the block of NPMs each class letter takes within a
+// program studi and angkatan.
+const MaxStudentsPerClass = 300
+
+// MaxClasses is how many distinct class names Plan can draw: one letter A to
+// Z per program studi and angkatan, over four angkatan.
+var MaxClasses = len(prodis) * 4 * 26
+
+func (c Config) Validate() error {
+	switch {
+	case c.Classes < 0 || c.LecturersPerClass < 0 || c.StudentsPerClass < 0 || c.Matkul < 0 || c.RoomsPerLecturer < 0:
+		return fmt.Errorf("jumlah data seed tidak boleh negatif")
+	case c.Classes > MaxClasses:
+		return fmt.Errorf("jumlah kelas maksimal %d", MaxClasses)
+	case c.StudentsPerClass > MaxStudentsPerClass:
+		return fmt.Errorf("jumlah mahasiswa per kelas maksimal %d", MaxStudentsPerClass)
+	case c.Matkul > len(matkuls):
+		return fmt.Errorf("jumlah matakuliah maksimal %d", len(matkuls))
+	case c.RoomsPerLecturer > 0 && c.Matkul == 0:
+		return fmt.Errorf("room membutuhkan paling sedikit satu matakuliah")
+	case len(c.Password) < 6:
+		return fmt.Errorf("password seed minimal 6 karakter")
+	}
+	return nil
+}
+
+type Dataset struct {
+	Matkul  []Matkul
+	Classes []Class
+}
+
+type Matkul struct {
+	Kode string
+	Name string
+}
+
+type Class struct {
+	Name      string
+	Lecturers []User
+	Students  []User
+}
+
+// User is a lecturer or a student; only students have an NPM and angkatan
+// and only lecturers have rooms. Both belong to the program studi of their
+// class.
+type User struct {
+	Name         string
+	Email        string
+	Role         string
+	NPM          string
+	ProgramStudi string
+	Angkatan     int
+	Rooms        []Room
+}
+
+type Room struct {
+	Name  string
+	URL   string
+	Start time.Time
+	End   time.Time
+}
+
+// Streams of random numbers per class, so that changing how many students a
+// class has does not rename its lecturers or move their rooms.
+const (
+	streamLecturers = iota
+	streamStudents
+	streamRooms
+)
+
+func newRand(seed int64, class, stream int) *rand.Rand {
+	return rand.New(rand.NewSource(seed*1_000_003 + int64(class)*3 + int64(stream)))
+}
+
+// Plan draws the dataset of config. Class names, NPMs and student emails
+// follow from the position of the class and student and the year of Start
+// alone, so a run with a larger config extends the data of a smaller one.
+func Plan(config Config) *Dataset {
+	dataset := &Dataset{}
+	for _, m := range matkuls[:config.Matkul] {
+		dataset.Matkul = append(dataset.Matkul, Matkul{Kode: m.Kode, Name: m.Name})
+	}
+
+	emails := map[string]bool{}
+	for i := 0; i < config.Classes; i++ {
+		p := prodis[i%len(prodis)]
+		group := i / len(prodis)
+		angkatan := config.Start.Year() - group%4
+		class := Class{Name: fmt.Sprintf("%s-%02d%c", p.Code, angkatan%100, 'A'+rune(group/4))}
+
+		lecturerRand := newRand(config.Seed, i, streamLecturers)
+		roomRand := newRand(config.Seed, i, streamRooms)
+		for j := 0; j < config.LecturersPerClass; j++ {
+			first, last := drawName(lecturerRand)
+			lecturer := User{
+				Name:         first + " " + last + ", " + lecturerTitles[lecturerRand.Intn(len(lecturerTitles))],
+				Email:        uniqueEmail(emails, strings.ToLower(first+"."+last), "sinaustudio.test"),
+				Role:         "dosen",
+				ProgramStudi: p.Name,
+			}
+			for k := 0; k < config.RoomsPerLecturer; k++ {
+				lecturer.Rooms = append(lecturer.Rooms, drawRoom(roomRand, dataset.Matkul, config.Start, k))
+			}
+			class.Lecturers = append(class.Lecturers, lecturer)
+		}
+
+		studentRand := newRand(config.Seed, i, streamStudents)
+		for j := 0; j < config.StudentsPerClass; j++ {
+			first, last := drawName(studentRand)
+			// Classes of the same program and angkatan share the NPM range,
+			// each letter taking the next block of numbers.
+			npm := fmt.Sprintf("%02d%s%04d", angkatan%100, p.Number, (group/4)*MaxStudentsPerClass+j+1)
+			class.Students = append(class.Students, User{
+				Name:         first + " " + last,
+				Email:        npm + "@student.sinaustudio.test",
+				Role:         "mahasiswa",
+				NPM:          npm,
+				ProgramStudi: p.Name,
+				Angkatan:     angkatan,
+			})
+		}
+
+		dataset.Classes = append(dataset.Classes, class)
+	}
+	return dataset
+}
+
+func drawName(r *rand.Rand) (first, last string) {
+	return firstNames[r.Intn(len(firstNames))], lastNames[r.Intn(len(lastNames))]
+}
+
+// uniqueEmail numbers the local part when an earlier user already took it.
+func uniqueEmail(emails map[string]bool, local, domain string) string {
+	email := local + "@" + domain
+	for n := 2; emails[email]; n++ {
+		email = fmt.Sprintf("%s%d@%s", local, n, domain)
+	}
+	emails[email] = true
+	return email
+}
+
+// drawRoom schedules meeting k of a lecturer in week k after start, on a
+// weekday between 07:00 and 15:00, for 100 minutes, given that start is a
+// Monday.
+func drawRoom(r *rand.Rand, matkul []Matkul, start time.Time, k int) Room {
+	m := matkul[r.Intn(len(matkul))]
+	day := time.Date(start.Year(), start.Month(), start.Day(), 7+r.Intn(9), 0, 0, 0, start.Location()).AddDate(0, 0, 7*k+r.Intn(5))
+
+	code := make([]byte, 6)
+	for i := range code {
+		code[i] = "abcdefghijkmnpqrstuvwxyz"[r.Intn(24)]
+	}
+	return Room{
+		Name:  fmt.Sprintf("%s - Pertemuan %d", m.Name, k+1),
+		URL:   "https://meet.sinaustudio.test/" + strings.ToLower(m.Kode) + "-" + string(code),
+		Start: day,
+		End:   day.Add(100 * time.Minute),
+	}
+}
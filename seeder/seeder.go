@@ -0,0 +1,187 @@
+package seeder
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+)
+
+// Count is how many rows of a kind a run created and how many it found
+// already there.
+type Count struct {
+	Created  int `json:"created"`
+	Existing int `json:"existing"`
+}
+
+func (c *Count) add(created bool) {
+	if created {
+		c.Created++
+	} else {
+		c.Existing++
+	}
+}
+
+type Report struct {
+	Matkul    Count `json:"matkul"`
+	Classes   Count `json:"classes"`
+	Lecturers Count `json:"lecturers"`
+	Students  Count `json:"students"`
+	Rooms     Count `json:"rooms"`
+}
+
+// Seeder writes a Dataset through the services, as the API would. Rows are
+// recognised by their natural key on a re-run: kode matkul, class name, email
+// and, within a lecture, room name.
+type Seeder struct {
+	Auth    services.AuthService
+	Users   services.UsersService
+	Class   services.ClassService
+	Lecture services.LectureService
+	Room    services.RoomService
+	Matkul  services.MataKuliahService
+}
+
+func New(db *sql.DB, m api.MicroServiceServer) *Seeder {
+	return &Seeder{
+		Auth:    services.NewAuthServiceImplementation(db, m),
+		Users:   services.NewUserServiceImplementation(db, m),
+		Class:   services.NewClassServiceImplementation(db, m),
+		Lecture: services.NewLectureServiceImplementation(db, m),
+		Room:    services.NewRoomServiceImplementation(db, m),
+		Matkul:  services.NewMataKuliahServiceImplementation(db, m),
+	}
+}
+
+// Run plans the dataset of config and creates whatever of it is missing.
+func (s *Seeder) Run(ctx context.Context, config Config) (*Report, *response.ErrorMsg) {
+	if err := config.Validate(); err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, err.Error())
+	}
+
+	dataset := Plan(config)
+	report := &Report{}
+	for _, m := range dataset.Matkul {
+		created, errMsg := s.matkul(ctx, m)
+		if errMsg != nil {
+			return report, errMsg
+		}
+		report.Matkul.add(created)
+	}
+
+	for _, c := range dataset.Classes {
+		classID, created, errMsg := s.class(ctx, c)
+		if errMsg != nil {
+			return report, errMsg
+		}
+		report.Classes.add(created)
+
+		for _, lecturer := range c.Lecturers {
+			userID, created, errMsg := s.user(ctx, lecturer, classID, config.Password)
+			if errMsg != nil {
+				return report, errMsg
+			}
+			report.Lecturers.add(created)
+
+			if errMsg := s.rooms(ctx, userID, lecturer.Rooms, &report.Rooms); errMsg != nil {
+				return report, errMsg
+			}
+		}
+		for _, student := range c.Students {
+			_, created, errMsg := s.user(ctx, student, classID, config.Password)
+			if errMsg != nil {
+				return report, errMsg
+			}
+			report.Students.add(created)
+		}
+	}
+	return report, nil
+}
+
+func (s *Seeder) matkul(ctx context.Context, m Matkul) (bool, *response.ErrorMsg) {
+	found, errMsg := s.Matkul.FindMatkulByName(ctx, m.Name)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	for _, matkul := range found {
+		if matkul.KodeMatkul == m.Kode {
+			return false, nil
+		}
+	}
+	_, errMsg = s.Matkul.InsertMatkul(ctx, &requests.InsertMatkulRequest{KodeMatkul: m.Kode, Name: m.Name})
+	return errMsg == nil, errMsg
+}
+
+func (s *Seeder) class(ctx context.Context, c Class) (ID int, created bool, errMsg *response.ErrorMsg) {
+	if class, isRegistered, _ := s.Class.FindClassByName(ctx, c.Name); isRegistered {
+		return class.ID, false, nil
+	}
+	r := &requests.InsertClassRequest{Name: c.Name}
+	if _, errMsg := s.Class.AddClass(ctx, r); errMsg != nil {
+		return 0, false, errMsg
+	}
+	return r.ID, true, nil
+}
+
+// user registers u in classID like a self-registration, which also gives a
+// lecturer their lecture.
+func (s *Seeder) user(ctx context.Context, u User, classID int, password string) (ID int, created bool, errMsg *response.ErrorMsg) {
+	if user, errMsg := s.Users.FindUserByEmail(ctx, u.Email); errMsg == nil {
+		return user.ID, false, nil
+	} else if errMsg.StatusCode != http.StatusNotFound {
+		return 0, false, errMsg
+	}
+
+	r := &requests.AuthRegisterRequest{
+		Name: u.Name, Email: u.Email, Password: password, Role: u.Role, ClassID: classID,
+		Profile: &requests.ProfileRequest{NPM: u.NPM, ProgramStudi: u.ProgramStudi, Angkatan: u.Angkatan},
+	}
+	if _, errMsg := s.Auth.AuthRegisterUser(ctx, r); errMsg != nil {
+		return 0, false, errMsg
+	}
+	user, errMsg := s.Users.FindUserByEmail(ctx, u.Email)
+	if errMsg != nil {
+		return 0, false, errMsg
+	}
+	return user.ID, true, nil
+}
+
+func (s *Seeder) rooms(ctx context.Context, userID int, rooms []Room, count *Count) *response.ErrorMsg {
+	if len(rooms) == 0 {
+		return nil
+	}
+	lecture, isValid, errMsg := s.Lecture.FindLectureByUserID(ctx, userID)
+	if !isValid {
+		return errMsg
+	}
+	existing, errMsg := s.Room.FindRoomByLectureIDs(ctx, []int{lecture.ID})
+	if errMsg != nil {
+		return errMsg
+	}
+	names := map[string]bool{}
+	for _, room := range existing {
+		names[room.Name] = true
+	}
+
+	for _, room := range rooms {
+		if names[room.Name] {
+			count.add(false)
+			continue
+		}
+		r := &requests.InsertRoomRequest{
+			Name: room.Name, URL: room.URL, LectureID: lecture.ID,
+			StartRoom: room.Start.Format("2006-01-02 15:04:05"), EndRoom: room.End.Format("2006-01-02 15:04:05"),
+		}
+		if _, errMsg := s.Room.InsertRoom(ctx, r); errMsg != nil {
+			return errMsg
+		}
+		count.add(true)
+	}
+	return nil
+}
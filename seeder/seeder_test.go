@@ -0,0 +1,98 @@
+package seeder_test
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/repository/memory"
+	"github.com/dimassfeb-09/sinaustudio.git/seeder"
+)
+
+var monday = time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)
+
+func smallConfig(seed int64) seeder.Config {
+	config := seeder.Scales["small"]
+	config.Seed, config.Password, config.Start = seed, "rahasia123", monday
+	return config
+}
+
+func TestPlanIsDeterministic(t *testing.T) {
+	a, b := seeder.Plan(smallConfig(7)), seeder.Plan(smallConfig(7))
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("expected the same dataset for the same seed")
+	}
+	if reflect.DeepEqual(a, seeder.Plan(smallConfig(8))) {
+		t.Fatal("expected another dataset for another seed")
+	}
+
+	if got := []string{a.Classes[0].Name, a.Classes[1].Name}; !reflect.DeepEqual(got, []string{"TI-24A", "SI-24A"}) {
+		t.Errorf("unexpected class names %v", got)
+	}
+
+	npm := regexp.MustCompile(`^\d{10}$`)
+	seen := map[string]bool{}
+	for _, class := range a.Classes {
+		for _, student := range class.Students {
+			if !npm.MatchString(student.NPM) || seen[student.NPM] {
+				t.Errorf("NPM %q is malformed or taken twice", student.NPM)
+			}
+			seen[student.NPM] = true
+		}
+		for _, lecturer := range class.Lecturers {
+			for _, room := range lecturer.Rooms {
+				if room.Start.Before(monday) || room.Start.Weekday() == time.Saturday || room.Start.Weekday() == time.Sunday {
+					t.Errorf("room %q is not on a weekday after start: %v", room.Name, room.Start)
+				}
+			}
+		}
+	}
+
+	more := smallConfig(7)
+	more.StudentsPerClass = 8
+	if got := seeder.Plan(more); !reflect.DeepEqual(got.Classes[0].Lecturers, a.Classes[0].Lecturers) || !reflect.DeepEqual(got.Classes[0].Students[:5], a.Classes[0].Students) {
+		t.Error("expected more students to keep the existing lecturers and students")
+	}
+}
+
+func TestRunIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	t.Cleanup(func() { store.DB().Close() })
+	s := seeder.New(store.DB(), api.NewMemoryMicroService(store))
+
+	config := smallConfig(1)
+	report, errMsg := s.Run(ctx, config)
+	if errMsg != nil {
+		t.Fatalf("Run: %v", errMsg.Msg)
+	}
+	want := &seeder.Report{
+		Matkul:    seeder.Count{Created: 4},
+		Classes:   seeder.Count{Created: 2},
+		Lecturers: seeder.Count{Created: 2},
+		Students:  seeder.Count{Created: 10},
+		Rooms:     seeder.Count{Created: 4},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("first run: expected %+v, got %+v", want, report)
+	}
+
+	config.StudentsPerClass = 6
+	report, errMsg = s.Run(ctx, config)
+	if errMsg != nil {
+		t.Fatalf("second Run: %v", errMsg.Msg)
+	}
+	want = &seeder.Report{
+		Matkul:    seeder.Count{Existing: 4},
+		Classes:   seeder.Count{Existing: 2},
+		Lecturers: seeder.Count{Existing: 2},
+		Students:  seeder.Count{Created: 2, Existing: 10},
+		Rooms:     seeder.Count{Existing: 4},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("second run: expected %+v, got %+v", want, report)
+	}
+}
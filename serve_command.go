@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/grpcapi"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/router"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+// runServe implements "sinaustudio serve": the HTTP and gRPC servers and the
+// purge job, until SIGINT or SIGTERM drains them.
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio serve")
+		fmt.Fprintln(flags.Output(), "Konfigurasi dibaca dari environment, lihat api.LoadConfig.")
+	}
+	flags.Parse(args)
+
+	config, err := api.LoadConfig()
+	if err != nil {
+		fatal(err)
+	}
+	logging.SetDefault(logging.New(os.Stdout, config.LogLevels))
+	log := logging.Default().Named("main")
+
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing)
+	if err != nil {
+		log.Error("tracing gagal disiapkan", "error", err)
+		os.Exit(1)
+	}
+
+	db, err := api.ConnectionDatabases(config)
+	if err != nil {
+		log.Error("koneksi database gagal", "error", err)
+		os.Exit(1)
+	}
+
+	store, err := storage.Open(config.Storage)
+	if err != nil {
+		log.Error("storage gagal disiapkan", "backend", config.Storage.Backend, "error", err)
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", config.GRPCAddr)
+	if err != nil {
+		log.Error("listen gRPC gagal", "addr", config.GRPCAddr, "error", err)
+		os.Exit(1)
+	}
+
+	// ctx is cancelled by SIGINT or SIGTERM; everything below drains in
+	// reverse order of starting once it is.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 2)
+
+	grpcServer := grpcapi.NewServer(db, api.NewSQLMicroService(config.Dialect))
+	go func() {
+		log.Info("server gRPC berjalan", "addr", config.GRPCAddr)
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	purgeService := services.NewPurgeServiceImplementation(db, api.NewSQLMicroService(config.Dialect), store)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		services.RunPurgeJob(workerCtx, purgeService, config.PurgeRetention, config.PurgeInterval)
+	}()
+
+	server := &http.Server{
+		Addr:              config.HTTPAddr,
+		Handler:           router.NewRouter(db, config.Dialect, store),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		log.Info("server HTTP berjalan", "addr", config.HTTPAddr)
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			serveErr <- err
+		}
+	}()
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		log.Info("sinyal berhenti diterima, menunggu request selesai", "timeout", config.ShutdownTimeout)
+	case err := <-serveErr:
+		log.Error("server berhenti", "error", err)
+		exitCode = 1
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error("server HTTP tidak berhenti dengan bersih", "error", err)
+		exitCode = 1
+	}
+
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		log.Warn("server gRPC dihentikan paksa", "error", shutdownCtx.Err())
+		grpcServer.Stop()
+	}
+
+	stopWorkers()
+	workers.Wait()
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("span tracing gagal dikirim", "error", err)
+	}
+	if err := db.Close(); err != nil {
+		log.Error("database gagal ditutup", "error", err)
+	}
+
+	log.Info("server berhenti")
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
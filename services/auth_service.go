@@ -9,7 +9,9 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/metrics"
 	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
 	"golang.org/x/crypto/bcrypt"
 	"net/http"
 )
@@ -37,14 +39,23 @@ func NewAuthServiceImplementation(DB *sql.DB, M api.MicroServiceServer) AuthServ
 	}
 }
 
-func (a *AuthRepositoryImplementation) AuthRegisterUser(ctx context.Context, r *requests.AuthRegisterRequest) (bool, *response.ErrorMsg) {
+func (a *AuthRepositoryImplementation) AuthRegisterUser(ctx context.Context, r *requests.AuthRegisterRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "AuthService.AuthRegisterUser")
+	defer span.End()
+
+	if r.Role == domain.RoleAdmin {
+		return false, helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, "Role admin tidak dapat didaftarkan.")
+	}
+
 	tx, err := a.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
+	_, hashSpan := tracing.Start(ctx, "bcrypt.GenerateFromPassword")
 	hashPassword, err := helpers.HashAndSaltPassword([]byte(r.Password))
+	hashSpan.End()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
@@ -59,6 +70,14 @@ func (a *AuthRepositoryImplementation) AuthRegisterUser(ctx context.Context, r *
 		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Email telah digunakan")
 	}
 
+	var profile *domain.Profile
+	if patch := profilePatch(r.Profile); patch != nil {
+		var errMsg *response.ErrorMsg
+		if profile, _, errMsg = prepareProfile(ctx, a.DB, a.M, 0, r.Role, patch); errMsg != nil {
+			return false, errMsg
+		}
+	}
+
 	user := &domain.AuthRegisterUser{
 		Name:     r.Name,
 		Email:    r.Email,
@@ -72,6 +91,16 @@ func (a *AuthRepositoryImplementation) AuthRegisterUser(ctx context.Context, r *
 		return false, errMsg
 	}
 
+	if errMsg := addClassMember(ctx, tx, a.M, r.ClassID, lastID); errMsg != nil {
+		return false, errMsg
+	}
+	if profile != nil {
+		profile.UserID = lastID
+		if errMsg := saveProfile(ctx, tx, a.M, profile, false); errMsg != nil {
+			return false, errMsg
+		}
+	}
+
 	if r.Role == "dosen" || r.Role == "guru" {
 		if isRegisterSuccess {
 			lecture := &domain.Lecture{
@@ -88,8 +117,17 @@ func (a *AuthRepositoryImplementation) AuthRegisterUser(ctx context.Context, r *
 	return true, nil
 }
 
+// AuthLoginUser checks the credentials and counts the attempt for /metrics.
 func (a *AuthRepositoryImplementation) AuthLoginUser(ctx context.Context, email string, password string) (*response.UserInfoLogin, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "AuthService.AuthLoginUser")
+	defer span.End()
+
+	userInfo, errMsg := a.authLoginUser(ctx, email, password)
+	metrics.LoginResult(errMsg == nil)
+	return userInfo, errMsg
+}
 
+func (a *AuthRepositoryImplementation) authLoginUser(ctx context.Context, email string, password string) (*response.UserInfoLogin, *response.ErrorMsg) {
 	_, isEmailRegistered, _ := a.M.UserRepository().IsEmailRegistered(ctx, a.DB, email)
 	if !isEmailRegistered {
 		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Email tidak ditemukan.")
@@ -100,7 +138,9 @@ func (a *AuthRepositoryImplementation) AuthLoginUser(ctx context.Context, email
 		return nil, errMsg
 	}
 
+	_, compareSpan := tracing.Start(ctx, "bcrypt.CompareHashAndPassword")
 	err := bcrypt.CompareHashAndPassword([]byte(result.Password), []byte(password))
+	compareSpan.End()
 	if err != nil {
 		return nil, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Password anda salah. Silahkan periksa kembali.")
 	}
@@ -0,0 +1,58 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+func TestAuthRegisterUser(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+
+	t.Run("class not found", func(t *testing.T) {
+		r := &requests.AuthRegisterRequest{Name: "Budi Santoso", Email: "budi@mail.com", Password: "rahasia123", Role: "mahasiswa", ClassID: 99}
+		_, errMsg := ts.Auth.AuthRegisterUser(ctx, r)
+		assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+	})
+
+	t.Run("lecturer gets a lecture record", func(t *testing.T) {
+		userID := ts.seedUser(t, "Dimas Febriyanto", "dimas@mail.com", "dosen", classID)
+
+		lecture, isRegistered, _ := ts.M.LectureRepository().FindLectureByUserID(ctx, ts.Store.DB(), userID)
+		if !isRegistered || lecture.Name != "Dimas Febriyanto" {
+			t.Fatalf("expected lecture for user %d, got %+v", userID, lecture)
+		}
+	})
+
+	t.Run("email already used", func(t *testing.T) {
+		r := &requests.AuthRegisterRequest{Name: "Dimas Lain", Email: "dimas@mail.com", Password: "rahasia123", Role: "mahasiswa", ClassID: classID}
+		_, errMsg := ts.Auth.AuthRegisterUser(ctx, r)
+		assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+	})
+}
+
+func TestAuthLoginUser(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+	userID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", classID)
+
+	userInfo, errMsg := ts.Auth.AuthLoginUser(ctx, "siti@mail.com", "rahasia123")
+	if errMsg != nil {
+		t.Fatalf("AuthLoginUser: %v", errMsg.Msg)
+	}
+	if userInfo.ID != userID || userInfo.Role != "mahasiswa" || userInfo.ClassID != classID {
+		t.Fatalf("unexpected user info %+v", userInfo)
+	}
+
+	_, errMsg = ts.Auth.AuthLoginUser(ctx, "siti@mail.com", "salah12345")
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+
+	_, errMsg = ts.Auth.AuthLoginUser(ctx, "tidakada@mail.com", "rahasia123")
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/imaging"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+// An uploaded avatar is kept as square JPEG thumbnails, one per AvatarSizes,
+// under a name taken from the hash of the upload. The profile's avatar is
+// then the URL of the avatar under AvatarPath, which serves the thumbnail of
+// the size query; since a new picture gets a new name, the files behind a
+// URL never change and can be cached for good.
+
+const (
+	AvatarPath = "/api/v2/avatars"
+
+	MaxAvatarBytes    = 5 << 20
+	MaxAvatarSide     = 5000
+	DefaultAvatarSize = 256
+)
+
+// AvatarSizes are the sides, in pixels, of the thumbnails kept of an avatar.
+var AvatarSizes = []int{64, 128, 256, 512}
+
+var avatarName = regexp.MustCompile(`^[0-9a-f]{16}\.jpg$`)
+
+type AvatarService interface {
+	UploadAvatar(ctx context.Context, userID int, version int, content []byte) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteAvatar(ctx context.Context, userID int, version int) (isSuccess bool, errMsg *response.ErrorMsg)
+	OpenAvatar(ctx context.Context, userID int, name string, size int) (file io.ReadCloser, info *storage.Info, errMsg *response.ErrorMsg)
+}
+
+type AvatarServiceImplementation struct {
+	DB      *sql.DB
+	M       api.MicroServiceServer
+	Storage storage.Storage
+}
+
+func NewAvatarServiceImplementation(DB *sql.DB, M api.MicroServiceServer, store storage.Storage) AvatarService {
+	return &AvatarServiceImplementation{DB: DB, M: M, Storage: store}
+}
+
+// AvatarURL is the URL of the avatar stored under hash for userID.
+func AvatarURL(userID int, hash string) string {
+	return fmt.Sprintf("%s/%d/%s.jpg", AvatarPath, userID, hash)
+}
+
+// avatarHash returns the hash of the uploaded avatar avatar, the URL in the
+// profile of userID, and false when it is not one.
+func avatarHash(userID int, avatar string) (string, bool) {
+	prefix := fmt.Sprintf("%s/%d/", AvatarPath, userID)
+	if !strings.HasPrefix(avatar, prefix) || !avatarName.MatchString(avatar[len(prefix):]) {
+		return "", false
+	}
+	return strings.TrimSuffix(avatar[len(prefix):], ".jpg"), true
+}
+
+// avatarKey keeps the thumbnails among the files of userID, so that they
+// count against the user's quota.
+func avatarKey(userID int, hash string, size int) string {
+	return storage.UserKey(userID, fmt.Sprintf("avatars/%s-%d.jpg", hash, size))
+}
+
+// UploadAvatar makes content, a JPEG, PNG, GIF or WebP picture, the avatar of
+// userID, at the version the client expects, and removes the files of the
+// avatar it replaces.
+func (a *AvatarServiceImplementation) UploadAvatar(ctx context.Context, userID int, version int, content []byte) (bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "AvatarService.UploadAvatar")
+	defer span.End()
+
+	if errMsg := authorizeOwnerOrAdmin(ctx, userID, "Hanya pemilik akun atau admin yang dapat mengubah avatar."); errMsg != nil {
+		return false, errMsg
+	}
+	if len(content) > MaxAvatarBytes {
+		return false, helpers.ToErrorMsg(http.StatusRequestEntityTooLarge, exception.ERR_BAD_REQUEST_FIELD, "Ukuran avatar maksimal 5 MB.")
+	}
+	img, err := imaging.Decode(content, MaxAvatarSide)
+	switch {
+	case errors.Is(err, imaging.ErrUnsupported):
+		return false, helpers.ToErrorMsg(http.StatusUnsupportedMediaType, exception.ERR_BAD_REQUEST_FIELD, "Avatar harus gambar JPEG, PNG, GIF atau WebP.")
+	case err != nil:
+		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Avatar tidak valid: "+err.Error()+".")
+	}
+
+	user, profile, isStored, errMsg := a.findOwner(ctx, userID)
+	if errMsg != nil {
+		return false, errMsg
+	}
+
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:8])
+	previous := profile.Avatar
+	profile.Avatar = AvatarURL(userID, hash)
+
+	// The files are stored before the profile points at them; if storing one
+	// or saving the profile fails they are removed again, unless they are the
+	// current avatar.
+	if errMsg := a.storeThumbnails(ctx, userID, hash, img); errMsg != nil {
+		if previous != profile.Avatar {
+			a.removeAvatar(ctx, userID, profile.Avatar)
+		}
+		return false, errMsg
+	}
+
+	user.Version = version
+	if errMsg := a.saveAvatar(ctx, user, profile, isStored); errMsg != nil {
+		if previous != profile.Avatar {
+			a.removeAvatar(ctx, userID, profile.Avatar)
+		}
+		return false, errMsg
+	}
+	if previous != profile.Avatar {
+		a.removeAvatar(ctx, userID, previous)
+	}
+	return true, nil
+}
+
+// DeleteAvatar clears the avatar of userID, at the version the client
+// expects, and removes its files when it was uploaded.
+func (a *AvatarServiceImplementation) DeleteAvatar(ctx context.Context, userID int, version int) (bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "AvatarService.DeleteAvatar")
+	defer span.End()
+
+	if errMsg := authorizeOwnerOrAdmin(ctx, userID, "Hanya pemilik akun atau admin yang dapat mengubah avatar."); errMsg != nil {
+		return false, errMsg
+	}
+	user, profile, isStored, errMsg := a.findOwner(ctx, userID)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	if profile.Avatar == "" {
+		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Avatar tidak ditemukan.")
+	}
+
+	previous := profile.Avatar
+	profile.Avatar = ""
+	user.Version = version
+	if errMsg := a.saveAvatar(ctx, user, profile, isStored); errMsg != nil {
+		return false, errMsg
+	}
+	a.removeAvatar(ctx, userID, previous)
+	return true, nil
+}
+
+// OpenAvatar opens the thumbnail of size of the avatar name, as in the last
+// part of its URL, of userID. The caller closes file.
+func (a *AvatarServiceImplementation) OpenAvatar(ctx context.Context, userID int, name string, size int) (io.ReadCloser, *storage.Info, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "AvatarService.OpenAvatar")
+	defer span.End()
+
+	if !isAvatarSize(size) {
+		return nil, nil, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, fmt.Sprintf("Ukuran avatar harus salah satu dari %v.", AvatarSizes))
+	}
+	if !avatarName.MatchString(name) {
+		return nil, nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Avatar tidak ditemukan.")
+	}
+
+	file, info, err := a.Storage.Open(ctx, avatarKey(userID, strings.TrimSuffix(name, ".jpg"), size))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Avatar tidak ditemukan.")
+	}
+	if err != nil {
+		return nil, nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	return file, info, nil
+}
+
+// storeThumbnails stores a thumbnail of img for each of AvatarSizes.
+func (a *AvatarServiceImplementation) storeThumbnails(ctx context.Context, userID int, hash string, img *imaging.Image) *response.ErrorMsg {
+	for _, size := range AvatarSizes {
+		var thumbnail bytes.Buffer
+		if err := imaging.EncodeJPEG(&thumbnail, img.Thumbnail(size)); err != nil {
+			return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+		info := storage.Info{ContentType: "image/jpeg", Size: int64(thumbnail.Len())}
+		err := a.Storage.Put(ctx, avatarKey(userID, hash, size), &thumbnail, info)
+		if errors.Is(err, storage.ErrQuotaExceeded) {
+			return helpers.ToErrorMsg(http.StatusRequestEntityTooLarge, exception.ERR_BAD_REQUEST_FIELD, "Kuota penyimpanan sudah penuh.")
+		}
+		if err != nil {
+			return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+	}
+	return nil
+}
+
+func isAvatarSize(size int) bool {
+	for _, s := range AvatarSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// findOwner reads the user whose avatar changes and their profile.
+func (a *AvatarServiceImplementation) findOwner(ctx context.Context, userID int) (*domain.Users, *domain.Profile, bool, *response.ErrorMsg) {
+	user, isRegistered, _ := a.M.UserRepository().FindUserByID(ctx, a.DB, userID)
+	if !isRegistered {
+		return nil, nil, false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "ID tidak ditemukan.")
+	}
+	profile, isStored, errMsg := findProfile(ctx, a.DB, a.M, userID)
+	if errMsg != nil {
+		return nil, nil, false, errMsg
+	}
+	return user, profile, isStored, nil
+}
+
+// saveAvatar writes the profile with its new avatar and bumps the version of
+// its user, which the profile shares.
+func (a *AvatarServiceImplementation) saveAvatar(ctx context.Context, user *domain.Users, profile *domain.Profile, isStored bool) (errMsg *response.ErrorMsg) {
+	tx, err := a.DB.Begin()
+	if err != nil {
+		return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	if _, errMsg := a.M.UserRepository().UpdateDataUser(ctx, tx, user); errMsg != nil {
+		return errMsg
+	}
+	return saveProfile(ctx, tx, a.M, profile, isStored)
+}
+
+// removeAvatar deletes the files of avatar, when it is an uploaded avatar of
+// userID. The profile no longer points at them, so a failure is only logged.
+func (a *AvatarServiceImplementation) removeAvatar(ctx context.Context, userID int, avatar string) {
+	hash, ok := avatarHash(userID, avatar)
+	if !ok {
+		return
+	}
+	for _, size := range AvatarSizes {
+		if err := a.Storage.Delete(ctx, avatarKey(userID, hash, size)); err != nil {
+			logging.FromContext(ctx).Named("services").Warn("file avatar gagal dihapus", "user_id", userID, "size", size, "error", err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+)
+
+func TestAvatarService(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	dir := t.TempDir()
+	avatars := services.NewAvatarServiceImplementation(ts.Store.DB(), ts.M, storage.NewQuota(storage.NewLocal(dir, storage.LocalPath, nil), 0))
+	userID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", ts.seedClass(t, "TI-2A"))
+	owner := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: userID, Role: "mahasiswa"})
+
+	var picture bytes.Buffer
+	png.Encode(&picture, image.NewGray(image.Rect(0, 0, 40, 30)))
+	stored := func() int {
+		files, _ := filepath.Glob(filepath.Join(dir, "users", "*", "avatars", "*.jpg"))
+		return len(files)
+	}
+
+	// A stale version leaves neither the profile nor the files changed.
+	_, errMsg := avatars.UploadAvatar(owner, userID, 7, picture.Bytes())
+	assertErrorKey(t, errMsg, http.StatusPreconditionFailed, exception.ERR_PRECONDITION_FAILED)
+	if n := stored(); n != 0 {
+		t.Fatalf("expected no files after a failed upload, got %d", n)
+	}
+
+	if _, errMsg := avatars.UploadAvatar(owner, userID, 1, picture.Bytes()); errMsg != nil {
+		t.Fatalf("UploadAvatar: %v", errMsg.Msg)
+	}
+	if n := stored(); n != len(services.AvatarSizes) {
+		t.Fatalf("expected %d thumbnails, got %d", len(services.AvatarSizes), n)
+	}
+	profile, errMsg := ts.Users.FindUserProfile(ctx, userID)
+	if errMsg != nil {
+		t.Fatalf("FindUserProfile: %v", errMsg.Msg)
+	}
+
+	// Uploading the same picture again keeps its files.
+	if _, errMsg := avatars.UploadAvatar(owner, userID, 2, picture.Bytes()); errMsg != nil {
+		t.Fatalf("UploadAvatar again: %v", errMsg.Msg)
+	}
+	name := filepath.Base(profile.Avatar)
+	file, info, errMsg := avatars.OpenAvatar(ctx, userID, name, 64)
+	if errMsg != nil {
+		t.Fatalf("OpenAvatar: %v", errMsg.Msg)
+	}
+	file.Close()
+	if info.ContentType != "image/jpeg" {
+		t.Fatalf("expected image/jpeg, got %q", info.ContentType)
+	}
+
+	if _, errMsg := avatars.DeleteAvatar(owner, userID, 3); errMsg != nil {
+		t.Fatalf("DeleteAvatar: %v", errMsg.Msg)
+	}
+	if n := stored(); n != 0 {
+		t.Fatalf("expected the files removed with the avatar, got %d", n)
+	}
+	_, _, errMsg = avatars.OpenAvatar(ctx, userID, name, 64)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
@@ -3,14 +3,18 @@ package services
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/codegen"
 	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
 	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
 	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
 	"net/http"
+	"time"
 )
 
 type ClassService interface {
@@ -19,6 +23,13 @@ type ClassService interface {
 	DeleteClassByID(ctx context.Context, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
 	FindClassByID(ctx context.Context, ID int) (*domain.Class, bool, *response.ErrorMsg)
 	FindClassByName(ctx context.Context, name string) (*domain.Class, bool, *response.ErrorMsg)
+	FindAllClass(ctx context.Context, r *requests.ListRequest) (classes []*domain.Class, errMsg *response.ErrorMsg)
+	FindUsersByClassID(ctx context.Context, ID int) (users []*response.UserResponse, errMsg *response.ErrorMsg)
+	FindClassByIDs(ctx context.Context, IDs []int) (classes []*domain.Class, errMsg *response.ErrorMsg)
+	JoinClass(ctx context.Context, userID int, r *requests.JoinClassRequest) (*domain.Class, *response.ErrorMsg)
+	FindClassMembers(ctx context.Context, classID int, role string) (members []*response.ClassMemberResponse, errMsg *response.ErrorMsg)
+	RemoveClassMember(ctx context.Context, classID int, userID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	RegenerateKodeKelas(ctx context.Context, classID int, r *requests.RegenerateKodeKelasRequest) (*domain.Class, *response.ErrorMsg)
 }
 
 type ClassServiceImplementation struct {
@@ -32,31 +43,49 @@ func NewClassServiceImplementation(DB *sql.DB, M api.MicroServiceServer) ClassSe
 }
 
 func (c *ClassServiceImplementation) AddClass(ctx context.Context, r *requests.InsertClassRequest) (bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.AddClass")
+	defer span.End()
+
+	errMsg := retryKodeKelas(func() *response.ErrorMsg {
+		return c.addClass(ctx, r)
+	})
+	return errMsg == nil, errMsg
+}
+
+func (c *ClassServiceImplementation) addClass(ctx context.Context, r *requests.InsertClassRequest) (errMsg *response.ErrorMsg) {
 	tx, err := c.DB.Begin()
 	if err != nil {
-		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
-	r.KodeKelas = helpers.RandStr(10)
+	kodeKelas, errMsg := newKodeKelas(ctx, c.DB, c.ClassRepository)
+	if errMsg != nil {
+		return errMsg
+	}
+	r.KodeKelas = kodeKelas
 	class := &domain.Class{
 		Name:      r.Name,
 		KodeKelas: r.KodeKelas,
 	}
 	isSuccess, errMsg := c.ClassRepository.InsertClass(ctx, tx, class)
 	if !isSuccess && errMsg != nil {
-		return false, errMsg
+		return errMsg
 	}
+	r.ID = class.ID
 
-	return true, nil
+	return nil
 }
 
-func (c *ClassServiceImplementation) UpdateClass(ctx context.Context, r *requests.UpdateClassRequest) (bool, *response.ErrorMsg) {
+func (c *ClassServiceImplementation) UpdateClass(ctx context.Context, r *requests.UpdateClassRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.UpdateClass")
+	defer span.End()
+
 	tx, err := c.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	_, isIDRegistered, errMsg := c.ClassRepository.FindClassByID(ctx, c.DB, r.ID)
 	if !isIDRegistered {
@@ -64,8 +93,9 @@ func (c *ClassServiceImplementation) UpdateClass(ctx context.Context, r *request
 	}
 
 	class := &domain.Class{
-		ID:   r.ID,
-		Name: r.Name,
+		ID:      r.ID,
+		Name:    r.Name,
+		Version: r.Version,
 	}
 
 	isSuccess, errMsg := c.ClassRepository.UpdateClass(ctx, tx, class)
@@ -76,16 +106,23 @@ func (c *ClassServiceImplementation) UpdateClass(ctx context.Context, r *request
 	return true, nil
 }
 
-func (c *ClassServiceImplementation) DeleteClassByID(ctx context.Context, ID int) (bool, *response.ErrorMsg) {
+func (c *ClassServiceImplementation) DeleteClassByID(ctx context.Context, ID int) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.DeleteClassByID")
+	defer span.End()
+
 	tx, err := c.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
-	_, isClassIDAlreadyUse, _ := c.M.UserRepository().FindUserByClassID(ctx, c.DB, ID)
+	users, isClassIDAlreadyUse, _ := c.M.UserRepository().FindUserByClassID(ctx, c.DB, ID)
 	if isClassIDAlreadyUse {
-		return false, helpers.ToErrorMsg(http.StatusConflict, exception.ERR_CONFLICT, "Kelas tidak dapat dihapus, karena berelasi dengan data user.")
+		userIDs := make([]int, 0, len(users))
+		for _, user := range users {
+			userIDs = append(userIDs, user.ID)
+		}
+		return false, dependentsConflict("Kelas tidak dapat dihapus, karena berelasi dengan data user.", &response.Dependent{Resource: "users", IDs: userIDs})
 	}
 
 	_, isIDRegistered, _ := c.ClassRepository.FindClassByID(ctx, c.DB, ID)
@@ -93,7 +130,7 @@ func (c *ClassServiceImplementation) DeleteClassByID(ctx context.Context, ID int
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_NOT_FOUND, "Class by ID tidak ditemukan.")
 	}
 
-	isSuccess, errMsg := c.ClassRepository.DeleteClassByID(ctx, tx, ID)
+	isSuccess, errMsg := c.ClassRepository.DeleteClassByID(ctx, tx, ID, deletionTime())
 	if !isSuccess && errMsg != nil {
 		return false, errMsg
 	}
@@ -102,17 +139,279 @@ func (c *ClassServiceImplementation) DeleteClassByID(ctx context.Context, ID int
 }
 
 func (c *ClassServiceImplementation) FindClassByID(ctx context.Context, ID int) (*domain.Class, bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.FindClassByID")
+	defer span.End()
+
 	r, isIDValid, _ := c.ClassRepository.FindClassByID(ctx, c.DB, ID)
 	if !isIDValid {
 		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_NOT_FOUND, "Kelas by ID tidak ditemukan.")
 	}
-	return r, true, nil
+	return c.hideKodeKelas(ctx, r), true, nil
 }
 
 func (c *ClassServiceImplementation) FindClassByName(ctx context.Context, name string) (*domain.Class, bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.FindClassByName")
+	defer span.End()
+
 	r, isNameValid, _ := c.ClassRepository.FindClassByName(ctx, c.DB, name)
 	if !isNameValid {
 		return nil, false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_NOT_FOUND, "Kelas by Name tidak ditemukan.")
 	}
-	return r, true, nil
+	return c.hideKodeKelas(ctx, r), true, nil
+}
+
+func (c *ClassServiceImplementation) FindAllClass(ctx context.Context, r *requests.ListRequest) ([]*domain.Class, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.FindAllClass")
+	defer span.End()
+
+	classes, errMsg := c.ClassRepository.FindAllClass(ctx, c.DB, listFilter(r))
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	for _, class := range classes {
+		c.hideKodeKelas(ctx, class)
+	}
+	return classes, nil
+}
+
+func (c *ClassServiceImplementation) FindUsersByClassID(ctx context.Context, ID int) ([]*response.UserResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.FindUsersByClassID")
+	defer span.End()
+
+	_, isIDValid, _ := c.ClassRepository.FindClassByID(ctx, c.DB, ID)
+	if !isIDValid {
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Kelas by ID tidak ditemukan.")
+	}
+
+	users, _, errMsg := c.M.UserRepository().FindUserByClassID(ctx, c.DB, ID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	var userResponses []*response.UserResponse
+	for _, user := range users {
+		userResponses = append(userResponses, &response.UserResponse{ID: user.ID, Name: user.Name, ClassID: user.ClassID})
+	}
+	return userResponses, nil
+}
+
+func (c *ClassServiceImplementation) FindClassByIDs(ctx context.Context, IDs []int) ([]*domain.Class, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.FindClassByIDs")
+	defer span.End()
+
+	classes, errMsg := c.ClassRepository.FindClassByIDs(ctx, c.DB, IDs)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	for _, class := range classes {
+		c.hideKodeKelas(ctx, class)
+	}
+	return classes, nil
+}
+
+func (c *ClassServiceImplementation) JoinClass(ctx context.Context, userID int, r *requests.JoinClassRequest) (_ *domain.Class, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.JoinClass")
+	defer span.End()
+
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	class, isKodeValid, _ := c.ClassRepository.FindClassByKode(ctx, c.DB, r.KodeKelas)
+	if !isKodeValid {
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Kode kelas tidak ditemukan.")
+	}
+	if class.KodeKelasExpired(time.Now()) {
+		return nil, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Kode kelas sudah kedaluwarsa.")
+	}
+
+	_, isUserRegistered, _ := c.M.UserRepository().FindUserByID(ctx, c.DB, userID)
+	if !isUserRegistered {
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "User ID tidak ditemukan.")
+	}
+
+	isMember, errMsg := c.M.ClassMemberRepository().IsClassMember(ctx, c.DB, class.ID, userID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	if isMember {
+		return nil, helpers.ToErrorMsg(http.StatusConflict, exception.ERR_CONFLICT, "Anda sudah terdaftar di kelas ini.")
+	}
+
+	if errMsg := addClassMember(ctx, tx, c.M, class.ID, userID); errMsg != nil {
+		return nil, errMsg
+	}
+
+	// The membership is not committed yet, so hideKodeKelas would not find a
+	// lecturer who joins for themselves.
+	if info, _ := caller(ctx); info != nil && info.ID == userID && info.IsLecturer() {
+		return class, nil
+	}
+	return c.hideKodeKelas(ctx, class), nil
+}
+
+// FindClassMembers lists the members of the class; an empty role lists every role.
+func (c *ClassServiceImplementation) FindClassMembers(ctx context.Context, classID int, role string) ([]*response.ClassMemberResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.FindClassMembers")
+	defer span.End()
+
+	if errMsg := authorizeClassLecturer(ctx, c.DB, c.M, classID); errMsg != nil {
+		return nil, errMsg
+	}
+
+	members, errMsg := c.M.ClassMemberRepository().FindClassMembers(ctx, c.DB, classID, role)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	var memberResponses []*response.ClassMemberResponse
+	for _, member := range members {
+		memberResponses = append(memberResponses, &response.ClassMemberResponse{UserID: member.UserID, Name: member.Name, Email: member.Email, Role: member.Role, JoinedAt: member.JoinedAt})
+	}
+	return memberResponses, nil
+}
+
+func (c *ClassServiceImplementation) RemoveClassMember(ctx context.Context, classID int, userID int) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.RemoveClassMember")
+	defer span.End()
+
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	if errMsg := authorizeClassLecturer(ctx, c.DB, c.M, classID); errMsg != nil {
+		return false, errMsg
+	}
+
+	isMember, errMsg := c.M.ClassMemberRepository().IsClassMember(ctx, c.DB, classID, userID)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	if !isMember {
+		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "User bukan anggota kelas ini.")
+	}
+
+	isSuccess, errMsg := c.M.ClassMemberRepository().DeleteClassMember(ctx, tx, classID, userID)
+	if !isSuccess && errMsg != nil {
+		return false, errMsg
+	}
+
+	return true, nil
+}
+
+// RegenerateKodeKelas replaces the kode kelas so the previous one can no
+// longer be used to join; members who already joined stay.
+func (c *ClassServiceImplementation) RegenerateKodeKelas(ctx context.Context, classID int, r *requests.RegenerateKodeKelasRequest) (*domain.Class, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ClassService.RegenerateKodeKelas")
+	defer span.End()
+
+	if errMsg := authorizeClassLecturer(ctx, c.DB, c.M, classID); errMsg != nil {
+		return nil, errMsg
+	}
+
+	class, _, errMsg := c.ClassRepository.FindClassByID(ctx, c.DB, classID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	class.KodeKelasExpiresAt = nil
+	if r.ExpiresInHours > 0 {
+		expiresAt := time.Now().UTC().Add(time.Duration(r.ExpiresInHours) * time.Hour).Truncate(time.Second)
+		class.KodeKelasExpiresAt = &expiresAt
+	}
+
+	errMsg = retryKodeKelas(func() *response.ErrorMsg {
+		return c.updateKodeKelas(ctx, class)
+	})
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return class, nil
+}
+
+func (c *ClassServiceImplementation) updateKodeKelas(ctx context.Context, class *domain.Class) (errMsg *response.ErrorMsg) {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	kodeKelas, errMsg := newKodeKelas(ctx, c.DB, c.ClassRepository)
+	if errMsg != nil {
+		return errMsg
+	}
+	class.KodeKelas = kodeKelas
+
+	isSuccess, errMsg := c.ClassRepository.UpdateKodeKelas(ctx, tx, class)
+	if !isSuccess && errMsg != nil {
+		return errMsg
+	}
+	return nil
+}
+
+// newKodeKelas draws a kode kelas no class uses yet. Another writer can still
+// take it before the insert; the unique index then rejects the write with
+// ERR_ALREADY_USE and retryKodeKelas starts over with a new code.
+func newKodeKelas(ctx context.Context, db *sql.DB, classRepository repository.ClassRepository) (string, *response.ErrorMsg) {
+	kodeKelas, err := codegen.ClassCode.Unique(func(code string) (bool, error) {
+		_, isTaken, errMsg := classRepository.FindClassByKode(ctx, db, code)
+		if errMsg != nil && errMsg.ErrorKey != exception.ERR_NOT_FOUND {
+			return false, fmt.Errorf("%v", errMsg.Msg)
+		}
+		return isTaken, nil
+	})
+	if err != nil {
+		return "", helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err.Error())
+	}
+	return kodeKelas, nil
+}
+
+// retryKodeKelas runs write, each time in a new transaction, until it no
+// longer fails on a kode kelas taken by another class.
+func retryKodeKelas(write func() *response.ErrorMsg) *response.ErrorMsg {
+	var errMsg *response.ErrorMsg
+	for attempt := 0; attempt < codegen.MaxAttempts; attempt++ {
+		errMsg = write()
+		if errMsg == nil || errMsg.ErrorKey != exception.ERR_ALREADY_USE {
+			return errMsg
+		}
+	}
+	return errMsg
+}
+
+// hideKodeKelas clears the join code for callers who do not teach the class,
+// so a student can only join with a code handed out by the lecturer.
+func (c *ClassServiceImplementation) hideKodeKelas(ctx context.Context, class *domain.Class) *domain.Class {
+	if isLecturer, _ := isClassLecturer(ctx, c.DB, c.M, class.ID); !isLecturer {
+		class.KodeKelas, class.KodeKelasExpiresAt = "", nil
+	}
+	return class
+}
+
+// addClassMember records userID as a member of classID from now on.
+func addClassMember(ctx context.Context, tx *sql.Tx, M api.MicroServiceServer, classID, userID int) *response.ErrorMsg {
+	member := &domain.ClassMember{ClassID: classID, UserID: userID, JoinedAt: time.Now().UTC()}
+	_, errMsg := M.ClassMemberRepository().AddClassMember(ctx, tx, member)
+	return errMsg
+}
+
+// moveClassMember follows a change of users.class_id: the membership of the
+// previous class is replaced by one of the new class. Other classes the user
+// joined by code are kept.
+func moveClassMember(ctx context.Context, tx *sql.Tx, db *sql.DB, M api.MicroServiceServer, userID, fromClassID, toClassID int) *response.ErrorMsg {
+	if fromClassID == toClassID {
+		return nil
+	}
+	if _, errMsg := M.ClassMemberRepository().DeleteClassMember(ctx, tx, fromClassID, userID); errMsg != nil {
+		return errMsg
+	}
+	isMember, errMsg := M.ClassMemberRepository().IsClassMember(ctx, db, toClassID, userID)
+	if errMsg != nil || isMember {
+		return errMsg
+	}
+	return addClassMember(ctx, tx, M, toClassID, userID)
 }
@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+func TestClassService(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+
+	class, _, errMsg := ts.Class.FindClassByID(ctx, classID)
+	if errMsg != nil {
+		t.Fatalf("FindClassByID: %v", errMsg.Msg)
+	}
+	if len(class.KodeKelas) != 10 {
+		t.Fatalf("expected a 10 character kode kelas, got %q", class.KodeKelas)
+	}
+
+	if _, errMsg := ts.Class.UpdateClass(ctx, &requests.UpdateClassRequest{ID: classID, Name: "TI-2B"}); errMsg != nil {
+		t.Fatalf("UpdateClass: %v", errMsg.Msg)
+	}
+	if class, _, _ := ts.Class.FindClassByID(ctx, classID); class.Name != "TI-2B" {
+		t.Fatalf("expected renamed class, got %q", class.Name)
+	}
+
+	_, errMsg = ts.Class.UpdateClass(ctx, &requests.UpdateClassRequest{ID: 99, Name: "TI-9Z"})
+	assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_NOT_FOUND)
+
+	_, _, errMsg = ts.Class.FindClassByName(ctx, "TI-2A")
+	assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_NOT_FOUND)
+}
+
+func TestClassServiceDeleteClassByID(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	usedClassID := ts.seedClass(t, "TI-2A")
+	emptyClassID := ts.seedClass(t, "TI-2B")
+	ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", usedClassID)
+
+	_, errMsg := ts.Class.DeleteClassByID(ctx, usedClassID)
+	assertErrorKey(t, errMsg, http.StatusConflict, exception.ERR_CONFLICT)
+
+	if _, errMsg := ts.Class.DeleteClassByID(ctx, emptyClassID); errMsg != nil {
+		t.Fatalf("DeleteClassByID: %v", errMsg.Msg)
+	}
+	_, _, errMsg = ts.Class.FindClassByID(ctx, emptyClassID)
+	assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_NOT_FOUND)
+}
+
+func TestClassServiceMembership(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	homeClassID := ts.seedClass(t, "TI-2A")
+	classID := ts.seedClass(t, "TI-2B")
+	dosenID := ts.seedUser(t, "Budi Santoso", "budi@mail.com", "dosen", classID)
+	siswaID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", homeClassID)
+	dosenCtx := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: dosenID, Role: "dosen"})
+	siswaCtx := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: siswaID, Role: "mahasiswa"})
+
+	adminCtx := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: 99, Role: "admin"})
+
+	class, _, _ := ts.Class.FindClassByID(dosenCtx, classID)
+	if hidden, _, _ := ts.Class.FindClassByID(siswaCtx, classID); hidden.KodeKelas != "" {
+		t.Fatalf("expected kode kelas hidden from mahasiswa, got %q", hidden.KodeKelas)
+	}
+	if hidden, _, _ := ts.Class.FindClassByID(dosenCtx, homeClassID); hidden.KodeKelas != "" {
+		t.Fatalf("expected kode kelas hidden from a dosen outside the class, got %q", hidden.KodeKelas)
+	}
+	if shown, _, _ := ts.Class.FindClassByID(adminCtx, homeClassID); shown.KodeKelas == "" {
+		t.Fatal("expected kode kelas shown to admin")
+	}
+	if _, errMsg := ts.Class.FindClassMembers(adminCtx, homeClassID, ""); errMsg != nil {
+		t.Fatalf("FindClassMembers by admin: %v", errMsg.Msg)
+	}
+
+	_, errMsg := ts.Class.JoinClass(siswaCtx, siswaID, &requests.JoinClassRequest{KodeKelas: "SALAH"})
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+	if _, errMsg := ts.Class.JoinClass(siswaCtx, siswaID, &requests.JoinClassRequest{KodeKelas: class.KodeKelas}); errMsg != nil {
+		t.Fatalf("JoinClass: %v", errMsg.Msg)
+	}
+	_, errMsg = ts.Class.JoinClass(siswaCtx, siswaID, &requests.JoinClassRequest{KodeKelas: class.KodeKelas})
+	assertErrorKey(t, errMsg, http.StatusConflict, exception.ERR_CONFLICT)
+
+	members, errMsg := ts.Class.FindClassMembers(dosenCtx, classID, "mahasiswa")
+	if errMsg != nil {
+		t.Fatalf("FindClassMembers: %v", errMsg.Msg)
+	}
+	if len(members) != 1 || members[0].UserID != siswaID {
+		t.Fatalf("expected siti as the only mahasiswa member, got %+v", members)
+	}
+	_, errMsg = ts.Class.FindClassMembers(siswaCtx, classID, "")
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+	_, errMsg = ts.Class.FindClassMembers(dosenCtx, homeClassID, "")
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+
+	users, _ := ts.Users.FindUsersByClassIDs(ctx, []int{homeClassID, classID})
+	if len(users) != 3 {
+		t.Fatalf("expected siti in both classes and budi in one, got %+v", users)
+	}
+
+	regenerated, errMsg := ts.Class.RegenerateKodeKelas(dosenCtx, classID, &requests.RegenerateKodeKelasRequest{ExpiresInHours: 2})
+	if errMsg != nil {
+		t.Fatalf("RegenerateKodeKelas: %v", errMsg.Msg)
+	}
+	if regenerated.KodeKelas == class.KodeKelas || regenerated.KodeKelasExpiresAt == nil {
+		t.Fatalf("expected a new expiring kode kelas, got %+v", regenerated)
+	}
+
+	if _, errMsg := ts.Class.RemoveClassMember(dosenCtx, classID, siswaID); errMsg != nil {
+		t.Fatalf("RemoveClassMember: %v", errMsg.Msg)
+	}
+	_, errMsg = ts.Class.RemoveClassMember(dosenCtx, classID, siswaID)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+
+	_, errMsg = ts.Class.JoinClass(siswaCtx, siswaID, &requests.JoinClassRequest{KodeKelas: class.KodeKelas})
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+
+	expired := time.Now().Add(-time.Minute)
+	regenerated.KodeKelasExpiresAt = &expired
+	tx, _ := ts.Store.DB().Begin()
+	ts.M.ClassRepository().UpdateKodeKelas(ctx, tx, regenerated)
+	tx.Commit()
+	_, errMsg = ts.Class.JoinClass(siswaCtx, siswaID, &requests.JoinClassRequest{KodeKelas: regenerated.KodeKelas})
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+}
+
+func TestUsersServiceMovesClassMember(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	fromClassID := ts.seedClass(t, "TI-2A")
+	toClassID := ts.seedClass(t, "TI-2B")
+	userID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", fromClassID)
+
+	classID := toClassID
+	if _, errMsg := ts.Users.PatchDataUser(ctx, userID, &requests.UserPatchRequest{ClassID: &classID}); errMsg != nil {
+		t.Fatalf("PatchDataUser: %v", errMsg.Msg)
+	}
+
+	if isMember, _ := ts.M.ClassMemberRepository().IsClassMember(ctx, ts.Store.DB(), fromClassID, userID); isMember {
+		t.Fatal("expected membership of the previous class removed")
+	}
+	if isMember, _ := ts.M.ClassMemberRepository().IsClassMember(ctx, ts.Store.DB(), toClassID, userID); !isMember {
+		t.Fatal("expected membership of the new class")
+	}
+
+	if _, errMsg := ts.Class.DeleteClassByID(ctx, fromClassID); errMsg != nil {
+		t.Fatalf("DeleteClassByID: %v", errMsg.Msg)
+	}
+}
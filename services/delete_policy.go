@@ -0,0 +1,33 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+)
+
+// Each relationship has a delete policy, enforced here on top of the foreign
+// keys so the caller gets a useful error instead of a constraint violation:
+//
+//	class   <- users (class_member)  restrict
+//	users   <- lecture               cascade, the lecture takes its rooms along
+//	lecture <- room                  cascade by default, restrict or reassign per request
+//
+// A restricted delete answers 409 with the blocking rows in ErrorMsg.Dependents.
+
+func dependentsConflict(msg string, dependents ...*response.Dependent) *response.ErrorMsg {
+	errMsg := helpers.ToErrorMsg(http.StatusConflict, exception.ERR_CONFLICT, msg)
+	errMsg.Dependents = dependents
+	return errMsg
+}
+
+func roomIDs(rooms []*domain.Room) []int {
+	IDs := make([]int, 0, len(rooms))
+	for _, room := range rooms {
+		IDs = append(IDs, room.ID)
+	}
+	return IDs
+}
@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+)
+
+func TestDeleteLecturePolicies(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-1A")
+	ts.seedUser(t, "Agus Salim", "agus@example.com", "dosen", classID)
+	ts.seedUser(t, "Budi Santoso", "budi@example.com", "dosen", classID)
+	ts.seedUser(t, "Citra Lestari", "citra@example.com", "dosen", classID)
+
+	room := &requests.InsertRoomRequest{Name: "Algoritma", URL: "https://meet.example.com/algo", LectureID: 1, StartRoom: "2023-02-19 20:25:00", EndRoom: "2023-02-19 21:25:00"}
+	if _, errMsg := ts.Room.InsertRoom(ctx, room); errMsg != nil {
+		t.Fatalf("InsertRoom: %v", errMsg.Msg)
+	}
+
+	_, errMsg := ts.Lecture.DeleteLecture(ctx, &requests.DeleteLectureRequest{ID: 1, Rooms: requests.DeleteRestrict})
+	assertErrorKey(t, errMsg, http.StatusConflict, exception.ERR_CONFLICT)
+	if len(errMsg.Dependents) != 1 || errMsg.Dependents[0].Resource != "rooms" || len(errMsg.Dependents[0].IDs) != 1 || errMsg.Dependents[0].IDs[0] != 1 {
+		t.Fatalf("expected room 1 as dependent, got %+v", errMsg.Dependents)
+	}
+
+	_, errMsg = ts.Lecture.DeleteLecture(ctx, &requests.DeleteLectureRequest{ID: 1, Rooms: requests.DeleteReassign, ReassignTo: 1})
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+	_, errMsg = ts.Lecture.DeleteLecture(ctx, &requests.DeleteLectureRequest{ID: 1, Rooms: requests.DeleteReassign, ReassignTo: 99})
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+
+	if _, errMsg := ts.Lecture.DeleteLecture(ctx, &requests.DeleteLectureRequest{ID: 1, Rooms: requests.DeleteReassign, ReassignTo: 2}); errMsg != nil {
+		t.Fatalf("DeleteLecture reassign: %v", errMsg.Msg)
+	}
+	moved, _, errMsg := ts.Room.FindRoomByID(ctx, 1)
+	if errMsg != nil {
+		t.Fatalf("FindRoomByID: %v", errMsg.Msg)
+	}
+	if moved.LectureID != 2 {
+		t.Fatalf("expected room on lecture 2, got %d", moved.LectureID)
+	}
+
+	if _, errMsg := ts.Lecture.DeleteLecture(ctx, &requests.DeleteLectureRequest{ID: 2, Rooms: requests.DeleteCascade}); errMsg != nil {
+		t.Fatalf("DeleteLecture cascade: %v", errMsg.Msg)
+	}
+	_, _, errMsg = ts.Room.FindRoomByID(ctx, 1)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+
+	if _, errMsg := ts.Lecture.DeleteLecture(ctx, &requests.DeleteLectureRequest{ID: 3, Rooms: requests.DeleteRestrict}); errMsg != nil {
+		t.Fatalf("DeleteLecture restrict without rooms: %v", errMsg.Msg)
+	}
+}
+
+// failingLectures fails the lecture delete, which runs after the rooms of the
+// lecture are written.
+type failingLectures struct{ repository.LectureRepository }
+
+func (failingLectures) DeleteLectureByID(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Hapus dosen gagal.")
+}
+
+// failingUsers fails the user delete, which runs after the user's lecture is deleted.
+type failingUsers struct{ repository.UsersRepository }
+
+func (failingUsers) DeleteDataUser(ctx context.Context, tx *sql.Tx, ID int, deletedAt time.Time) (bool, *response.ErrorMsg) {
+	return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Hapus user gagal.")
+}
+
+func TestDeleteRollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-1A")
+	ts.seedUser(t, "Agus Salim", "agus@example.com", "dosen", classID)
+	ts.seedUser(t, "Budi Santoso", "budi@example.com", "dosen", classID)
+
+	room := &requests.InsertRoomRequest{Name: "Algoritma", URL: "https://meet.example.com/algo", LectureID: 1, StartRoom: "2023-02-19 20:25:00", EndRoom: "2023-02-19 21:25:00"}
+	if _, errMsg := ts.Room.InsertRoom(ctx, room); errMsg != nil {
+		t.Fatalf("InsertRoom: %v", errMsg.Msg)
+	}
+	assertRoomOnLecture := func(t *testing.T, lectureID int) {
+		t.Helper()
+		got, _, errMsg := ts.Room.FindRoomByID(ctx, room.ID)
+		if errMsg != nil {
+			t.Fatalf("FindRoomByID: %v", errMsg.Msg)
+		}
+		if got.LectureID != lectureID || got.Version != 1 {
+			t.Fatalf("expected the room untouched on lecture %d, got %+v", lectureID, got)
+		}
+	}
+
+	failingLecture := *ts.M.(*api.MicroService)
+	failingLecture.Lecture = failingLectures{failingLecture.Lecture}
+	lectures := services.NewLectureServiceImplementation(ts.Store.DB(), &failingLecture)
+	for _, policy := range []string{requests.DeleteReassign, requests.DeleteCascade} {
+		_, errMsg := lectures.DeleteLecture(ctx, &requests.DeleteLectureRequest{ID: 1, Rooms: policy, ReassignTo: 2})
+		assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER)
+		assertRoomOnLecture(t, 1)
+	}
+
+	failingUser := *ts.M.(*api.MicroService)
+	failingUser.User = failingUsers{failingUser.User}
+	users := services.NewUserServiceImplementation(ts.Store.DB(), &failingUser)
+	_, errMsg := users.DeleteDataUser(ctx, "rahasia123", 1)
+	assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER)
+	assertRoomOnLecture(t, 1)
+	if _, _, errMsg := ts.Lecture.FindLectureByID(ctx, 1); errMsg != nil {
+		t.Fatalf("expected lecture 1 kept, got %v", errMsg.Msg)
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+// ExportResources lists the resources accepted by ExportService.Export.
+var ExportResources = []string{"classes", "users", "lectures", "rooms", "matkul"}
+
+type ExportService interface {
+	// Validate checks the caller, who must be an admin, and the request before
+	// anything is written, so errors can still be answered with a status code.
+	Validate(ctx context.Context, r *requests.ExportRequest) (errMsg *response.ErrorMsg)
+	// Export writes the header and then every row as it is read from the
+	// repository.
+	Export(ctx context.Context, r *requests.ExportRequest, w spreadsheet.Writer) (errMsg *response.ErrorMsg)
+}
+
+type ExportServiceImplementation struct {
+	DB *sql.DB
+	M  api.MicroServiceServer
+}
+
+func NewExportServiceImplementation(DB *sql.DB, M api.MicroServiceServer) ExportService {
+	return &ExportServiceImplementation{DB: DB, M: M}
+}
+
+func (e *ExportServiceImplementation) Validate(ctx context.Context, r *requests.ExportRequest) *response.ErrorMsg {
+	ctx, span := tracing.Start(ctx, "ExportService.Validate")
+	defer span.End()
+
+	if errMsg := authorizeAdmin(ctx, "Hanya admin yang dapat mengekspor data."); errMsg != nil {
+		return errMsg
+	}
+	switch r.Resource {
+	case "classes", "users", "lectures", "rooms", "matkul":
+		return nil
+	}
+	return helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Resource export harus salah satu dari "+strings.Join(ExportResources, ", ")+".")
+}
+
+func (e *ExportServiceImplementation) Export(ctx context.Context, r *requests.ExportRequest, w spreadsheet.Writer) *response.ErrorMsg {
+	ctx, span := tracing.Start(ctx, "ExportService.Export")
+	defer span.End()
+
+	if errMsg := e.Validate(ctx, r); errMsg != nil {
+		return errMsg
+	}
+
+	header := func(columns ...string) *response.ErrorMsg {
+		if err := w.WriteHeader(columns); err != nil {
+			return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+		return nil
+	}
+
+	switch r.Resource {
+	case "classes":
+		if errMsg := header("id", "name", "kode_kelas"); errMsg != nil {
+			return errMsg
+		}
+		return e.M.ClassRepository().EachClass(ctx, e.DB, listFilter(&r.ListRequest), func(class *domain.Class) error {
+			return w.WriteRow([]any{class.ID, class.Name, class.KodeKelas})
+		})
+	case "users":
+		if errMsg := header("id", "name", "email", "role", "class_id"); errMsg != nil {
+			return errMsg
+		}
+		filter := listFilter(&r.ListRequest)
+		filter.Profile = profileFilter(&r.ProfileListRequest)
+		return e.M.UserRepository().EachUser(ctx, e.DB, r.Role, filter, func(user *domain.Users) error {
+			return w.WriteRow([]any{user.ID, user.Name, user.Email, user.Role, user.ClassID})
+		})
+	case "lectures":
+		if errMsg := header("id", "name", "user_id"); errMsg != nil {
+			return errMsg
+		}
+		filter := listFilter(&r.ListRequest)
+		filter.Profile = profileFilter(&r.ProfileListRequest)
+		return e.M.LectureRepository().EachLecture(ctx, e.DB, filter, func(lecture *domain.Lecture) error {
+			return w.WriteRow([]any{lecture.ID, lecture.Name, lecture.UserID})
+		})
+	case "rooms":
+		if errMsg := header("id", "name", "url", "lecture_id", "start_room", "end_room"); errMsg != nil {
+			return errMsg
+		}
+		return e.M.RoomRepository().EachRoom(ctx, e.DB, listFilter(&r.ListRequest), func(room *domain.Room) error {
+			return w.WriteRow([]any{room.ID, room.Name, room.URL, room.LectureID, room.StartRoom, room.EndRoom})
+		})
+	default:
+		if errMsg := header("id", "kode_matkul", "name"); errMsg != nil {
+			return errMsg
+		}
+		return e.M.MatkulRepository().EachMatkul(ctx, e.DB, listFilter(&r.ListRequest), func(matkul *domain.Matkul) error {
+			return w.WriteRow([]any{matkul.ID, matkul.KodeMatkul, matkul.Name})
+		})
+	}
+}
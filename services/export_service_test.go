@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+)
+
+func export(t *testing.T, ts *testServices, r *requests.ExportRequest, format spreadsheet.Format) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := spreadsheet.NewWriter(&buf, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errMsg := ts.Export.Export(api.ContextWithSystemActor(context.Background()), r, w); errMsg != nil {
+		t.Fatalf("Export: %v", errMsg.Msg)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestExportService(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+	ts.seedClass(t, "SI-1B")
+	ts.seedUser(t, "Dimas Febriyanto", "dimas@mail.com", "dosen", classID)
+	ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", classID)
+
+	t.Run("classes honor the name filter", func(t *testing.T) {
+		out := export(t, ts, &requests.ExportRequest{Resource: "classes", UserListRequest: requests.UserListRequest{ListRequest: requests.ListRequest{Name: "ti"}}}, spreadsheet.CSV)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		if len(lines) != 2 || lines[0] != "id,name,kode_kelas" || !strings.HasPrefix(lines[1], "1,TI-2A,") {
+			t.Fatalf("unexpected csv %q", out)
+		}
+	})
+
+	t.Run("roster as ndjson", func(t *testing.T) {
+		out := export(t, ts, &requests.ExportRequest{Resource: "users", UserListRequest: requests.UserListRequest{Role: "mahasiswa"}}, spreadsheet.NDJSON)
+		want := `{"class_id":1,"email":"siti@mail.com","id":2,"name":"Siti Aminah","role":"mahasiswa"}` + "\n"
+		if out != want {
+			t.Fatalf("expected %q, got %q", want, out)
+		}
+	})
+
+	t.Run("users sorted like the listing", func(t *testing.T) {
+		r := &requests.ExportRequest{Resource: "users", UserListRequest: requests.UserListRequest{ListRequest: requests.ListRequest{Sort: "-name"}}}
+		out := export(t, ts, r, spreadsheet.CSV)
+		want := "id,name,email,role,class_id\n2,Siti Aminah,siti@mail.com,mahasiswa,1\n1,Dimas Febriyanto,dimas@mail.com,dosen,1\n"
+		if out != want {
+			t.Fatalf("expected %q, got %q", want, out)
+		}
+	})
+
+	t.Run("exported matkul can be imported again", func(t *testing.T) {
+		if _, errMsg := ts.Matkul.InsertMatkul(ctx, &requests.InsertMatkulRequest{KodeMatkul: "IF101", Name: "Algoritma"}); errMsg != nil {
+			t.Fatalf("InsertMatkul: %v", errMsg.Msg)
+		}
+		out := export(t, ts, &requests.ExportRequest{Resource: "matkul"}, spreadsheet.XLSX)
+
+		records, err := spreadsheet.Read(strings.NewReader(out), spreadsheet.XLSX, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || records[0].Get("kode_matkul") != "IF101" || records[0].Get("name") != "Algoritma" {
+			t.Fatalf("unexpected records %+v", records)
+		}
+	})
+
+	t.Run("only admins export", func(t *testing.T) {
+		lecturer := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: 1, Role: "dosen"})
+		errMsg := ts.Export.Validate(lecturer, &requests.ExportRequest{Resource: "users"})
+		assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+
+		admin := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: 3, Role: "admin"})
+		if errMsg := ts.Export.Validate(admin, &requests.ExportRequest{Resource: "users"}); errMsg != nil {
+			t.Fatalf("Validate by admin: %v", errMsg.Msg)
+		}
+	})
+
+	t.Run("unknown resource", func(t *testing.T) {
+		errMsg := ts.Export.Validate(ctx, &requests.ExportRequest{Resource: "secrets"})
+		assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+	})
+}
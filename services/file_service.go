@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+// SignedURLTTL is how long a signed download URL handed to a client works.
+const SignedURLTTL = 15 * time.Minute
+
+type FileService interface {
+	Usage(ctx context.Context, userID int) (usage *response.StorageUsageResponse, errMsg *response.ErrorMsg)
+	OpenSigned(ctx context.Context, key string, expires string, signature string) (file io.ReadCloser, info *storage.Info, errMsg *response.ErrorMsg)
+}
+
+type FileServiceImplementation struct {
+	DB      *sql.DB
+	M       api.MicroServiceServer
+	Storage *storage.Quota
+}
+
+func NewFileServiceImplementation(DB *sql.DB, M api.MicroServiceServer, store *storage.Quota) FileService {
+	return &FileServiceImplementation{DB: DB, M: M, Storage: store}
+}
+
+// Usage reports how much of their quota the files of userID take.
+func (f *FileServiceImplementation) Usage(ctx context.Context, userID int) (*response.StorageUsageResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "FileService.Usage")
+	defer span.End()
+
+	if errMsg := authorizeOwnerOrAdmin(ctx, userID, "Hanya pemilik akun atau admin yang dapat melihat penyimpanan."); errMsg != nil {
+		return nil, errMsg
+	}
+	if _, isRegistered, _ := f.M.UserRepository().FindUserByID(ctx, f.DB, userID); !isRegistered {
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "ID tidak ditemukan.")
+	}
+
+	used, err := f.Storage.Usage(ctx, userID)
+	if err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	return &response.StorageUsageResponse{Used: used, Quota: f.Storage.Limit}, nil
+}
+
+// OpenSigned opens key for a signed URL of a backend the API serves itself.
+// The caller closes file.
+func (f *FileServiceImplementation) OpenSigned(ctx context.Context, key string, expires string, signature string) (io.ReadCloser, *storage.Info, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "FileService.OpenSigned")
+	defer span.End()
+
+	switch err := f.Storage.VerifyURL(key, expires, signature, time.Now()); {
+	case errors.Is(err, storage.ErrExpired):
+		return nil, nil, helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, "URL unduhan sudah kedaluwarsa.")
+	case err != nil:
+		return nil, nil, helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, "URL unduhan tidak valid.")
+	}
+
+	file, info, err := f.Storage.Open(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "File tidak ditemukan.")
+	}
+	if err != nil {
+		return nil, nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	return file, info, nil
+}
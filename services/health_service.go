@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/migrations"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// readyTimeout bounds each readiness check, so a hanging database fails the
+// probe instead of stalling it.
+const readyTimeout = 2 * time.Second
+
+type HealthService interface {
+	Ready(ctx context.Context) (health *response.HealthResponse, isReady bool)
+}
+
+type HealthServiceImplementation struct {
+	DB      *sql.DB
+	Dialect repository.Dialect
+}
+
+func NewHealthServiceImplementation(DB *sql.DB, dialect repository.Dialect) HealthService {
+	return &HealthServiceImplementation{DB: DB, Dialect: dialect}
+}
+
+// Ready pings the database and checks that every migration of the dialect
+// has been applied.
+func (h *HealthServiceImplementation) Ready(ctx context.Context) (*response.HealthResponse, bool) {
+	ctx, span := tracing.Start(ctx, "HealthService.Ready")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	health := &response.HealthResponse{Status: HealthOK, Checks: map[string]string{"database": HealthOK, "migrations": HealthOK}}
+	fail := func(check string, err error) {
+		health.Status = HealthUnavailable
+		health.Checks[check] = err.Error()
+	}
+
+	if err := h.DB.PingContext(ctx); err != nil {
+		fail("database", err)
+		fail("migrations", fmt.Errorf("database tidak dapat dihubungi"))
+		return health, false
+	}
+
+	pending, err := migrations.Pending(ctx, h.DB, h.Dialect)
+	if err != nil {
+		fail("migrations", err)
+	} else if len(pending) > 0 {
+		fail("migrations", fmt.Errorf("belum dijalankan: %s", strings.Join(pending, ", ")))
+	}
+	return health, health.Status == HealthOK
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/codegen"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ImportResources lists the resources accepted by ImportService.Import.
+var ImportResources = []string{"users", "classes", "matkul"}
+
+type ImportService interface {
+	Authorize(ctx context.Context) *response.ErrorMsg
+	Import(ctx context.Context, r *requests.ImportRequest, records []spreadsheet.Record) (report *response.ImportReport, errMsg *response.ErrorMsg)
+}
+
+type ImportServiceImplementation struct {
+	DB *sql.DB
+	M  api.MicroServiceServer
+}
+
+func NewImportServiceImplementation(DB *sql.DB, M api.MicroServiceServer) ImportService {
+	return &ImportServiceImplementation{DB: DB, M: M}
+}
+
+// Authorize lets through the admins, who alone may import, so that a
+// transport can refuse the caller before reading the upload.
+func (i *ImportServiceImplementation) Authorize(ctx context.Context) *response.ErrorMsg {
+	return authorizeAdmin(ctx, "Hanya admin yang dapat mengimpor data.")
+}
+
+// importRow is a validated row waiting to be written with insert.
+type importRow struct {
+	result *response.ImportRowResult
+	insert func(ctx context.Context, tx *sql.Tx) *response.ErrorMsg
+}
+
+// Import validates every record and, unless r.DryRun is set, writes them. In
+// atomic mode all rows share one transaction and nothing is written when any
+// row is invalid or fails; in best_effort mode every valid row is written in
+// its own transaction. An atomic import that is aborted answers with 422 and
+// the report as message.
+func (i *ImportServiceImplementation) Import(ctx context.Context, r *requests.ImportRequest, records []spreadsheet.Record) (*response.ImportReport, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "ImportService.Import")
+	defer span.End()
+
+	if errMsg := i.Authorize(ctx); errMsg != nil {
+		return nil, errMsg
+	}
+	if r.Mode == "" {
+		r.Mode = requests.ImportAtomic
+	}
+	if r.Mode != requests.ImportAtomic && r.Mode != requests.ImportBestEffort {
+		return nil, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Mode import harus atomic atau best_effort.")
+	}
+
+	var prepare func(ctx context.Context, record spreadsheet.Record) *importRow
+	switch r.Resource {
+	case "users":
+		prepare = i.userRows()
+	case "classes":
+		prepare = i.classRow
+	case "matkul":
+		prepare = i.matkulRow
+	default:
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Resource import harus salah satu dari "+strings.Join(ImportResources, ", ")+".")
+	}
+	if len(records) == 0 {
+		return nil, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "File tidak berisi baris data.")
+	}
+
+	report := &response.ImportReport{Resource: r.Resource, Mode: r.Mode, DryRun: r.DryRun, Total: len(records)}
+	var valid []*importRow
+	for _, record := range records {
+		row := prepare(ctx, record)
+		report.Rows = append(report.Rows, row.result)
+		if len(row.result.Errors) > 0 {
+			row.result.Status = response.ImportRowInvalid
+			report.Failed++
+			continue
+		}
+		row.result.Status = response.ImportRowValid
+		valid = append(valid, row)
+	}
+
+	if r.DryRun {
+		return report, nil
+	}
+	if r.Mode == requests.ImportBestEffort {
+		for _, row := range valid {
+			i.insertBestEffort(ctx, row, report)
+		}
+		report.Committed = report.Created > 0
+		return report, nil
+	}
+	return i.insertAtomic(ctx, valid, report)
+}
+
+func (i *ImportServiceImplementation) insertAtomic(ctx context.Context, rows []*importRow, report *response.ImportReport) (*response.ImportReport, *response.ErrorMsg) {
+	abort := func() (*response.ImportReport, *response.ErrorMsg) {
+		for _, row := range rows {
+			if row.result.Status != response.ImportRowFailed {
+				row.result.Status = response.ImportRowSkipped
+				row.result.ID = 0
+				row.result.Password = ""
+			}
+		}
+		report.Created = 0
+		return report, helpers.ToErrorMsg(http.StatusUnprocessableEntity, exception.ERR_BAD_REQUEST_FIELD, report)
+	}
+	if report.Failed > 0 {
+		return abort()
+	}
+
+	tx, err := i.DB.Begin()
+	if err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	for _, row := range rows {
+		if errMsg := row.insert(ctx, tx); errMsg != nil {
+			tx.Rollback()
+			failRow(row, errMsg, report)
+			return abort()
+		}
+		row.result.Status = response.ImportRowCreated
+		report.Created++
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	report.Committed = true
+	return report, nil
+}
+
+func (i *ImportServiceImplementation) insertBestEffort(ctx context.Context, row *importRow, report *response.ImportReport) {
+	tx, err := i.DB.Begin()
+	if err != nil {
+		failRow(row, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err), report)
+		return
+	}
+	if errMsg := row.insert(ctx, tx); errMsg != nil {
+		tx.Rollback()
+		failRow(row, errMsg, report)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		failRow(row, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err), report)
+		return
+	}
+	row.result.Status = response.ImportRowCreated
+	report.Created++
+}
+
+func failRow(row *importRow, errMsg *response.ErrorMsg, report *response.ImportReport) {
+	row.result.Status = response.ImportRowFailed
+	row.result.ID = 0
+	row.result.Password = ""
+	row.result.Errors = append(row.result.Errors, fmt.Sprint(errMsg.Msg))
+	report.Failed++
+}
+
+// userRows prepares user rows. Columns: name, email, role, class_id or
+// class_name, and an optional password; a random initial password is
+// generated when it is empty. Lecturers also get their lecture record, as on
+// registration.
+func (i *ImportServiceImplementation) userRows() func(ctx context.Context, record spreadsheet.Record) *importRow {
+	emails := map[string]int{}
+	classIDs := map[string]int{}
+
+	return func(ctx context.Context, record spreadsheet.Record) *importRow {
+		result := &response.ImportRowResult{Line: record.Line}
+		r := &requests.ImportUserRow{
+			Name:     record.Get("name"),
+			Email:    strings.ToLower(record.Get("email")),
+			Password: record.Get("password"),
+			Role:     strings.ToLower(record.Get("role")),
+		}
+
+		generated := r.Password == ""
+		if generated {
+			password, err := codegen.Password.Generate()
+			if err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			}
+			r.Password = password
+		}
+
+		if classID := record.Get("class_id"); classID != "" {
+			ID, err := strconv.Atoi(classID)
+			if err != nil {
+				result.Errors = append(result.Errors, "class_id harus berupa angka.")
+			} else if _, isRegistered, _ := i.M.ClassRepository().FindClassByID(ctx, i.DB, ID); !isRegistered {
+				result.Errors = append(result.Errors, "Kelas dengan class_id "+classID+" tidak ditemukan.")
+			} else {
+				r.ClassID = ID
+			}
+		} else if className := record.Get("class_name"); className != "" {
+			ID, ok := classIDs[className]
+			if !ok {
+				if class, isRegistered, _ := i.M.ClassRepository().FindClassByName(ctx, i.DB, className); isRegistered {
+					ID = class.ID
+				}
+				classIDs[className] = ID
+			}
+			if ID == 0 {
+				result.Errors = append(result.Errors, "Kelas "+className+" tidak ditemukan.")
+			}
+			r.ClassID = ID
+		} else {
+			result.Errors = append(result.Errors, "class_id atau class_name wajib diisi.")
+		}
+
+		result.Errors = append(result.Errors, validateRow(r)...)
+		if r.Role == domain.RoleAdmin {
+			result.Errors = append(result.Errors, "Role admin tidak dapat diimport.")
+		}
+
+		if r.Email != "" {
+			if line, ok := emails[r.Email]; ok {
+				result.Errors = append(result.Errors, fmt.Sprintf("Email sama dengan baris %d.", line))
+			} else {
+				emails[r.Email] = record.Line
+				if _, isRegistered, _ := i.M.UserRepository().IsEmailRegistered(ctx, i.DB, r.Email); isRegistered {
+					result.Errors = append(result.Errors, "Email sudah digunakan")
+				}
+			}
+		}
+
+		return &importRow{result: result, insert: func(ctx context.Context, tx *sql.Tx) *response.ErrorMsg {
+			hashPassword, err := helpers.HashAndSaltPassword([]byte(r.Password))
+			if err != nil {
+				return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+			}
+
+			user := &domain.Users{Name: r.Name, Email: r.Email, Password: hashPassword, Role: r.Role, ClassID: r.ClassID}
+			if _, errMsg := i.M.UserRepository().InsertDataUser(ctx, tx, user); errMsg != nil {
+				return errMsg
+			}
+			if errMsg := addClassMember(ctx, tx, i.M, user.ClassID, user.ID); errMsg != nil {
+				return errMsg
+			}
+			if r.Role == "dosen" || r.Role == "guru" {
+				lecture := &domain.Lecture{Name: user.Name, UserID: user.ID}
+				if _, errMsg := i.M.LectureRepository().InsertLecture(ctx, tx, lecture); errMsg != nil {
+					return errMsg
+				}
+			}
+
+			result.ID = user.ID
+			if generated {
+				result.Password = r.Password
+			}
+			return nil
+		}}
+	}
+}
+
+// classRow prepares a class row with a name column; kode_kelas is generated
+// as in ClassService.AddClass.
+func (i *ImportServiceImplementation) classRow(ctx context.Context, record spreadsheet.Record) *importRow {
+	result := &response.ImportRowResult{Line: record.Line}
+	r := &requests.ImportClassRow{Name: record.Get("name")}
+	result.Errors = validateRow(r)
+
+	return &importRow{result: result, insert: func(ctx context.Context, tx *sql.Tx) *response.ErrorMsg {
+		kodeKelas, errMsg := newKodeKelas(ctx, i.DB, i.M.ClassRepository())
+		if errMsg != nil {
+			return errMsg
+		}
+		class := &domain.Class{Name: r.Name, KodeKelas: kodeKelas}
+		if _, errMsg := i.M.ClassRepository().InsertClass(ctx, tx, class); errMsg != nil {
+			return errMsg
+		}
+		result.ID = class.ID
+		return nil
+	}}
+}
+
+// matkulRow prepares a mata kuliah row with kode_matkul and name columns.
+func (i *ImportServiceImplementation) matkulRow(ctx context.Context, record spreadsheet.Record) *importRow {
+	result := &response.ImportRowResult{Line: record.Line}
+	r := &requests.ImportMatkulRow{KodeMatkul: record.Get("kode_matkul"), Name: record.Get("name")}
+	result.Errors = validateRow(r)
+
+	return &importRow{result: result, insert: func(ctx context.Context, tx *sql.Tx) *response.ErrorMsg {
+		matkul := &domain.Matkul{KodeMatkul: r.KodeMatkul, Name: r.Name}
+		if _, errMsg := i.M.MatkulRepository().InsertMatkul(ctx, tx, matkul); errMsg != nil {
+			return errMsg
+		}
+		result.ID = matkul.ID
+		return nil
+	}}
+}
+
+// validateRow reports every failed binding rule of r, not only the first.
+func validateRow(r any) []string {
+	err := binding.Validator.ValidateStruct(r)
+	if err == nil {
+		return nil
+	}
+
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []string{helpers.ErrorValidateHandler(err)}
+	}
+	var errs []string
+	for _, fieldErr := range validationErrors {
+		errs = append(errs, helpers.ErrorValidateHandler(validator.ValidationErrors{fieldErr}))
+	}
+	return errs
+}
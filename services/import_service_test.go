@@ -0,0 +1,142 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/spreadsheet"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func readCSV(t *testing.T, csv string) []spreadsheet.Record {
+	t.Helper()
+
+	records, err := spreadsheet.Read(strings.NewReader(csv), spreadsheet.CSV, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func assertRowStatuses(t *testing.T, report *response.ImportReport, statuses ...string) {
+	t.Helper()
+
+	if len(report.Rows) != len(statuses) {
+		t.Fatalf("expected %d rows, got %d", len(statuses), len(report.Rows))
+	}
+	for i, status := range statuses {
+		if row := report.Rows[i]; row.Status != status {
+			t.Fatalf("row on line %d: expected %s, got %s %v", row.Line, status, row.Status, row.Errors)
+		}
+	}
+}
+
+func TestImportUsers(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+	ts.seedClass(t, "TI-2A")
+	ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", 1)
+
+	csv := "name,email,role,class_name,password\n" +
+		"Budi Santoso,budi@mail.com,mahasiswa,TI-2A,\n" +
+		"Dimas Febriyanto,dimas@mail.com,dosen,TI-2A,rahasia123\n" +
+		"Siti Lain,SITI@mail.com,mahasiswa,TI-2A,\n" +
+		"Budi Lain,budi@mail.com,mahasiswa,TI-9Z,\n"
+
+	t.Run("dry run reports row errors", func(t *testing.T) {
+		report, errMsg := ts.Import.Import(ctx, &requests.ImportRequest{Resource: "users", DryRun: true}, readCSV(t, csv))
+		if errMsg != nil {
+			t.Fatalf("Import: %v", errMsg.Msg)
+		}
+		assertRowStatuses(t, report, response.ImportRowValid, response.ImportRowValid, response.ImportRowInvalid, response.ImportRowInvalid)
+		if errs := report.Rows[3].Errors; len(errs) != 2 {
+			t.Fatalf("expected unknown class and duplicate email errors, got %v", errs)
+		}
+		if _, errMsg := ts.Users.IsEmailRegistered(ctx, "budi@mail.com"); errMsg == nil {
+			t.Fatal("dry run must not write users")
+		}
+	})
+
+	t.Run("atomic import is aborted", func(t *testing.T) {
+		report, errMsg := ts.Import.Import(ctx, &requests.ImportRequest{Resource: "users"}, readCSV(t, csv))
+		assertErrorKey(t, errMsg, http.StatusUnprocessableEntity, exception.ERR_BAD_REQUEST_FIELD)
+		assertRowStatuses(t, report, response.ImportRowSkipped, response.ImportRowSkipped, response.ImportRowInvalid, response.ImportRowInvalid)
+		if report.Committed || report.Created != 0 {
+			t.Fatalf("expected nothing committed, got %+v", report)
+		}
+	})
+
+	t.Run("best effort imports the valid rows", func(t *testing.T) {
+		report, errMsg := ts.Import.Import(ctx, &requests.ImportRequest{Resource: "users", Mode: requests.ImportBestEffort}, readCSV(t, csv))
+		if errMsg != nil {
+			t.Fatalf("Import: %v", errMsg.Msg)
+		}
+		assertRowStatuses(t, report, response.ImportRowCreated, response.ImportRowCreated, response.ImportRowInvalid, response.ImportRowInvalid)
+		if report.Created != 2 || report.Failed != 2 {
+			t.Fatalf("expected 2 created and 2 failed, got %+v", report)
+		}
+
+		budi := report.Rows[0]
+		if len(budi.Password) != 12 {
+			t.Fatalf("expected a generated password, got %q", budi.Password)
+		}
+		user, errMsg := ts.Users.FindUserByID(ctx, budi.ID)
+		if errMsg != nil {
+			t.Fatalf("FindUserByID: %v", errMsg.Msg)
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(budi.Password)); err != nil {
+			t.Fatal("generated password does not match the stored hash")
+		}
+		if report.Rows[1].Password != "" {
+			t.Fatal("a given password must not be reported")
+		}
+		if _, isRegistered, _ := ts.M.LectureRepository().FindLectureByUserID(ctx, ts.Store.DB(), report.Rows[1].ID); !isRegistered {
+			t.Fatal("expected a lecture record for the imported dosen")
+		}
+	})
+}
+
+func TestImportClassesAndMatkul(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+
+	report, errMsg := ts.Import.Import(ctx, &requests.ImportRequest{Resource: "classes"}, readCSV(t, "name\nTI-1A\nTI-1B\n\nTI-1C\n"))
+	if errMsg != nil {
+		t.Fatalf("Import: %v", errMsg.Msg)
+	}
+	assertRowStatuses(t, report, response.ImportRowCreated, response.ImportRowCreated, response.ImportRowCreated)
+	if report.Rows[2].Line != 5 {
+		t.Fatalf("expected line numbers to count blank rows, got %d", report.Rows[2].Line)
+	}
+	if classes, _ := ts.Class.FindAllClass(ctx, &requests.ListRequest{}); len(classes) != 3 {
+		t.Fatalf("expected 3 classes, got %d", len(classes))
+	}
+
+	report, errMsg = ts.Import.Import(ctx, &requests.ImportRequest{Resource: "matkul"}, readCSV(t, "kode_matkul,name\nIF101,Algoritma\nIF102,\n"))
+	assertErrorKey(t, errMsg, http.StatusUnprocessableEntity, exception.ERR_BAD_REQUEST_FIELD)
+	assertRowStatuses(t, report, response.ImportRowSkipped, response.ImportRowInvalid)
+
+	_, errMsg = ts.Import.Import(ctx, &requests.ImportRequest{Resource: "rooms"}, readCSV(t, "name\nA\n"))
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
+
+func TestImportRequiresAdmin(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	lecturer := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: 1, Role: "dosen"})
+
+	csv := "name,email,role,class_name,password\nBudi Santoso,budi@mail.com,dosen,,\n"
+	_, errMsg := ts.Import.Import(lecturer, &requests.ImportRequest{Resource: "users"}, readCSV(t, csv))
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+	_, errMsg = ts.Import.Import(ctx, &requests.ImportRequest{Resource: "users"}, readCSV(t, csv))
+	assertErrorKey(t, errMsg, http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER)
+	if _, isRegistered, _ := ts.M.UserRepository().IsEmailRegistered(ctx, ts.Store.DB(), "budi@mail.com"); isRegistered {
+		t.Fatal("expected no user imported by a non-admin")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
 	"net/http"
 )
 
@@ -17,8 +18,13 @@ type LectureService interface {
 	InsertLecture(ctx context.Context, r *requests.InsertLectureRequest) (isSuccess bool, errMsg *response.ErrorMsg)
 	UpdateLecture(ctx context.Context, r *requests.UpdateLectureRequest) (isSuccess bool, errMsg *response.ErrorMsg)
 	DeleteLectureByID(ctx context.Context, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteLecture(ctx context.Context, r *requests.DeleteLectureRequest) (isSuccess bool, errMsg *response.ErrorMsg)
 	FindLectureByID(ctx context.Context, ID int) (r *response.LectureResponse, isValid bool, errMsg *response.ErrorMsg)
 	FindLectureByName(ctx context.Context, name string) (r *response.LectureResponse, isValid bool, errMsg *response.ErrorMsg)
+	FindLectureByUserID(ctx context.Context, userID int) (r *response.LectureResponse, isValid bool, errMsg *response.ErrorMsg)
+	FindAllLecture(ctx context.Context, r *requests.LectureListRequest) (lectures []*response.LectureResponse, errMsg *response.ErrorMsg)
+	FindLectureByIDs(ctx context.Context, IDs []int) (lectures []*domain.Lecture, errMsg *response.ErrorMsg)
+	FindLectureByClassIDs(ctx context.Context, classIDs []int) (lectures map[int][]*domain.Lecture, errMsg *response.ErrorMsg)
 }
 
 type LectureServiceImplementation struct {
@@ -31,12 +37,19 @@ func NewLectureServiceImplementation(DB *sql.DB, m api.MicroServiceServer) Lectu
 	return &LectureServiceImplementation{DB: DB, LectureRepository: m.LectureRepository(), M: m}
 }
 
-func (l *LectureServiceImplementation) InsertLecture(ctx context.Context, r *requests.InsertLectureRequest) (bool, *response.ErrorMsg) {
+func (l *LectureServiceImplementation) InsertLecture(ctx context.Context, r *requests.InsertLectureRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.InsertLecture")
+	defer span.End()
+
 	tx, err := l.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	if _, isUserRegistered, _ := l.M.UserRepository().FindUserByID(ctx, l.DB, r.UserID); !isUserRegistered {
+		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "User dengan ID tersebut tidak ditemukan.")
+	}
 
 	_, isUserIDRegistered, _ := l.LectureRepository.FindLectureByUserID(ctx, l.DB, r.UserID)
 	if isUserIDRegistered {
@@ -52,16 +65,20 @@ func (l *LectureServiceImplementation) InsertLecture(ctx context.Context, r *req
 	if errMsg != nil && !isSuccess {
 		return false, errMsg
 	}
+	r.ID = lecture.ID
 
 	return true, nil
 }
 
-func (l *LectureServiceImplementation) UpdateLecture(ctx context.Context, r *requests.UpdateLectureRequest) (bool, *response.ErrorMsg) {
+func (l *LectureServiceImplementation) UpdateLecture(ctx context.Context, r *requests.UpdateLectureRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.UpdateLecture")
+	defer span.End()
+
 	tx, err := l.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	_, isIDValid, errMsg := l.LectureRepository.FindLectureByID(ctx, l.DB, r.ID)
 	if errMsg != nil && !isIDValid {
@@ -69,8 +86,9 @@ func (l *LectureServiceImplementation) UpdateLecture(ctx context.Context, r *req
 	}
 
 	lecture := &domain.Lecture{
-		ID:   r.ID,
-		Name: r.Name,
+		ID:      r.ID,
+		Name:    r.Name,
+		Version: r.Version,
 	}
 
 	isSuccess, errMsg := l.LectureRepository.UpdateLecture(ctx, tx, lecture)
@@ -81,32 +99,79 @@ func (l *LectureServiceImplementation) UpdateLecture(ctx context.Context, r *req
 	return true, nil
 }
 
+// DeleteLectureByID deletes the lecture together with its rooms.
 func (l *LectureServiceImplementation) DeleteLectureByID(ctx context.Context, ID int) (bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.DeleteLectureByID")
+	defer span.End()
+
+	return l.DeleteLecture(ctx, &requests.DeleteLectureRequest{ID: ID, Rooms: requests.DeleteCascade})
+}
+
+// DeleteLecture deletes the lecture and applies r.Rooms to its rooms.
+func (l *LectureServiceImplementation) DeleteLecture(ctx context.Context, r *requests.DeleteLectureRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.DeleteLecture")
+	defer span.End()
+
 	tx, err := l.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
-	_, isIDValid, _ := l.LectureRepository.FindLectureByID(ctx, l.DB, ID)
+	_, isIDValid, _ := l.LectureRepository.FindLectureByID(ctx, l.DB, r.ID)
 	if !isIDValid {
 		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen dengan ID tidak ditemukan")
 	}
 
-	isSuccess, errMsg := l.LectureRepository.DeleteLectureByID(ctx, tx, ID)
-	if errMsg != nil && !isSuccess {
+	rooms, errMsg := l.M.RoomRepository().FindRoomByLectureIDs(ctx, l.DB, []int{r.ID})
+	if errMsg != nil {
+		return false, errMsg
+	}
+
+	switch r.Rooms {
+	case requests.DeleteRestrict:
+		if len(rooms) > 0 {
+			return false, dependentsConflict("Dosen tidak dapat dihapus, karena masih memiliki room.", &response.Dependent{Resource: "rooms", IDs: roomIDs(rooms)})
+		}
+	case requests.DeleteReassign:
+		if r.ReassignTo == r.ID {
+			return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Dosen pengganti tidak boleh dosen yang dihapus.")
+		}
+		if _, isTargetValid, _ := l.LectureRepository.FindLectureByID(ctx, l.DB, r.ReassignTo); !isTargetValid {
+			return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen pengganti tidak ditemukan.")
+		}
+		if _, errMsg := l.M.RoomRepository().ReassignRooms(ctx, tx, r.ID, r.ReassignTo); errMsg != nil {
+			return false, errMsg
+		}
+	}
+
+	// Whatever rooms are still on the lecture go with it.
+	if _, errMsg := deleteLecture(ctx, tx, l.DB, l.M, r.ID, deletionTime()); errMsg != nil {
 		return false, errMsg
 	}
 
+	if r.Rooms != requests.DeleteReassign {
+		publishRoomsDeleted(roomEvents, rooms)
+		return true, nil
+	}
+	for _, room := range rooms {
+		room.LectureID = r.ReassignTo
+		roomEvents.Publish(RoomEvent{Type: RoomUpdated, Room: toRoomResponse(room)})
+	}
 	return true, nil
 }
 
 func (l *LectureServiceImplementation) FindLectureByID(ctx context.Context, ID int) (r *response.LectureResponse, isValid bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.FindLectureByID")
+	defer span.End()
+
 	lecture, isIDValid, errMsg := l.LectureRepository.FindLectureByID(ctx, l.DB, ID)
 	if isIDValid {
 		lectureResponse := &response.LectureResponse{
-			ID:   lecture.ID,
-			Name: lecture.Name,
+			ID:      lecture.ID,
+			Name:    lecture.Name,
+			Version: lecture.Version,
+			Audit:   lecture.Audit,
 		}
 		return lectureResponse, true, nil
 	} else {
@@ -114,15 +179,64 @@ func (l *LectureServiceImplementation) FindLectureByID(ctx context.Context, ID i
 	}
 }
 
+// FindLectureByUserID returns the lecture of a dosen.
+func (l *LectureServiceImplementation) FindLectureByUserID(ctx context.Context, userID int) (r *response.LectureResponse, isValid bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.FindLectureByUserID")
+	defer span.End()
+
+	lecture, isValid, errMsg := l.LectureRepository.FindLectureByUserID(ctx, l.DB, userID)
+	if !isValid {
+		return nil, false, errMsg
+	}
+	return &response.LectureResponse{ID: lecture.ID, Name: lecture.Name, Version: lecture.Version, Audit: lecture.Audit}, true, nil
+}
+
 func (l *LectureServiceImplementation) FindLectureByName(ctx context.Context, name string) (r *response.LectureResponse, isValid bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.FindLectureByName")
+	defer span.End()
+
 	lecture, isIDValid, errMsg := l.LectureRepository.FindLectureByName(ctx, l.DB, name)
 	if isIDValid {
 		lectureResponse := &response.LectureResponse{
-			ID:   lecture.ID,
-			Name: lecture.Name,
+			ID:      lecture.ID,
+			Name:    lecture.Name,
+			Version: lecture.Version,
+			Audit:   lecture.Audit,
 		}
 		return lectureResponse, true, nil
 	} else {
 		return nil, false, errMsg
 	}
 }
+
+func (l *LectureServiceImplementation) FindAllLecture(ctx context.Context, r *requests.LectureListRequest) ([]*response.LectureResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.FindAllLecture")
+	defer span.End()
+
+	filter := listFilter(&r.ListRequest)
+	filter.Profile = profileFilter(&r.ProfileListRequest)
+	lectures, errMsg := l.LectureRepository.FindAllLecture(ctx, l.DB, filter)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	var lectureResponses []*response.LectureResponse
+	for _, lecture := range lectures {
+		lectureResponses = append(lectureResponses, &response.LectureResponse{ID: lecture.ID, Name: lecture.Name, Version: lecture.Version, Audit: lecture.Audit})
+	}
+	return lectureResponses, nil
+}
+
+func (l *LectureServiceImplementation) FindLectureByIDs(ctx context.Context, IDs []int) ([]*domain.Lecture, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.FindLectureByIDs")
+	defer span.End()
+
+	return l.LectureRepository.FindLectureByIDs(ctx, l.DB, IDs)
+}
+
+func (l *LectureServiceImplementation) FindLectureByClassIDs(ctx context.Context, classIDs []int) (map[int][]*domain.Lecture, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "LectureService.FindLectureByClassIDs")
+	defer span.End()
+
+	return l.LectureRepository.FindLectureByClassIDs(ctx, l.DB, classIDs)
+}
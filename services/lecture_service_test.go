@@ -0,0 +1,43 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+func TestLectureService(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	userID := ts.seedUser(t, "Agus Salim", "agus@example.com", "mahasiswa", ts.seedClass(t, "TI-1A"))
+
+	_, errMsg := ts.Lecture.InsertLecture(ctx, &requests.InsertLectureRequest{Name: "Tanpa User", UserID: 99})
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+
+	if _, errMsg := ts.Lecture.InsertLecture(ctx, &requests.InsertLectureRequest{Name: "Agus Salim", UserID: userID}); errMsg != nil {
+		t.Fatalf("InsertLecture: %v", errMsg.Msg)
+	}
+	_, errMsg = ts.Lecture.InsertLecture(ctx, &requests.InsertLectureRequest{Name: "Agus Lain", UserID: userID})
+	assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER)
+
+	lecture, _, errMsg := ts.Lecture.FindLectureByName(ctx, "Agus Salim")
+	if errMsg != nil {
+		t.Fatalf("FindLectureByName: %v", errMsg.Msg)
+	}
+
+	if _, errMsg := ts.Lecture.UpdateLecture(ctx, &requests.UpdateLectureRequest{ID: lecture.ID, Name: "Agus Salim, M.Kom"}); errMsg != nil {
+		t.Fatalf("UpdateLecture: %v", errMsg.Msg)
+	}
+	if lecture, _, _ := ts.Lecture.FindLectureByID(ctx, lecture.ID); lecture.Name != "Agus Salim, M.Kom" {
+		t.Fatalf("expected renamed lecture, got %q", lecture.Name)
+	}
+
+	if _, errMsg := ts.Lecture.DeleteLectureByID(ctx, lecture.ID); errMsg != nil {
+		t.Fatalf("DeleteLectureByID: %v", errMsg.Msg)
+	}
+	_, errMsg = ts.Lecture.DeleteLectureByID(ctx, lecture.ID)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+)
+
+// listFilter converts the query of a listing route for the repositories.
+func listFilter(r *requests.ListRequest) *domain.ListFilter {
+	return &domain.ListFilter{
+		Name:          r.Name,
+		CreatedBy:     r.CreatedBy,
+		UpdatedBy:     r.UpdatedBy,
+		CreatedAfter:  r.CreatedAfter,
+		CreatedBefore: r.CreatedBefore,
+		UpdatedAfter:  r.UpdatedAfter,
+		UpdatedBefore: r.UpdatedBefore,
+		Sort:          r.Sort,
+	}
+}
+
+// profileFilter converts the profile filters of the user and lecture listings.
+func profileFilter(r *requests.ProfileListRequest) domain.ProfileFilter {
+	return domain.ProfileFilter{NPM: r.NPM, NIDN: r.NIDN, ProgramStudi: r.ProgramStudi, Angkatan: r.Angkatan}
+}
@@ -10,15 +10,17 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
 	"net/http"
 )
 
 type MataKuliahService interface {
 	InsertMatkul(ctx context.Context, r *requests.InsertMatkulRequest) (isSuccess bool, errMsg *response.ErrorMsg)
 	UpdateMatkul(ctx context.Context, r *requests.UpdateMatkulRequest) (isSuccess bool, errMsg *response.ErrorMsg)
-	DeleteMatkulByID(ctx context.Context, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteMatkulByID(ctx context.Context, ID, version int) (isSuccess bool, errMsg *response.ErrorMsg)
 	FindMatkulByID(ctx context.Context, ID int) (response *response.MatkulResponse, isRegistered bool, errMsg *response.ErrorMsg)
 	FindMatkulByName(ctx context.Context, name string) (matkuls []*response.MatkulResponse, errMsg *response.ErrorMsg)
+	FindAllMatkul(ctx context.Context, r *requests.ListRequest) (matkuls []*response.MatkulResponse, errMsg *response.ErrorMsg)
 }
 
 type MataKuliahServiceImplementation struct {
@@ -31,12 +33,15 @@ func NewMataKuliahServiceImplementation(DB *sql.DB, M api.MicroServiceServer) Ma
 	return &MataKuliahServiceImplementation{DB: DB, MatkulRepository: M.MatkulRepository(), M: M}
 }
 
-func (m *MataKuliahServiceImplementation) InsertMatkul(ctx context.Context, r *requests.InsertMatkulRequest) (bool, *response.ErrorMsg) {
+func (m *MataKuliahServiceImplementation) InsertMatkul(ctx context.Context, r *requests.InsertMatkulRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MataKuliahService.InsertMatkul")
+	defer span.End()
+
 	tx, err := m.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	matkul := &domain.Matkul{
 		Name:       r.Name,
@@ -46,21 +51,26 @@ func (m *MataKuliahServiceImplementation) InsertMatkul(ctx context.Context, r *r
 	if errMsg != nil && !isSuccess {
 		return false, errMsg
 	}
+	r.ID = matkul.ID
 
 	return isSuccess, nil
 }
 
-func (m *MataKuliahServiceImplementation) UpdateMatkul(ctx context.Context, r *requests.UpdateMatkulRequest) (bool, *response.ErrorMsg) {
+func (m *MataKuliahServiceImplementation) UpdateMatkul(ctx context.Context, r *requests.UpdateMatkulRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MataKuliahService.UpdateMatkul")
+	defer span.End()
+
 	tx, err := m.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	matkul := &domain.Matkul{
 		ID:         r.ID,
 		Name:       r.Name,
 		KodeMatkul: r.KodeMatkul,
+		Version:    r.Version,
 	}
 	isSuccess, errMsg := m.MatkulRepository.UpdateMatkul(ctx, tx, matkul)
 	if errMsg != nil && !isSuccess {
@@ -70,19 +80,22 @@ func (m *MataKuliahServiceImplementation) UpdateMatkul(ctx context.Context, r *r
 	return isSuccess, nil
 }
 
-func (m *MataKuliahServiceImplementation) DeleteMatkulByID(ctx context.Context, ID int) (bool, *response.ErrorMsg) {
+func (m *MataKuliahServiceImplementation) DeleteMatkulByID(ctx context.Context, ID, version int) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MataKuliahService.DeleteMatkulByID")
+	defer span.End()
+
 	tx, err := m.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	_, isRegistered, _ := m.MatkulRepository.FindMatkulByID(ctx, m.DB, ID)
 	if !isRegistered {
 		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Matkul ID tidak ditemukan")
 	}
 
-	isSuccess, errMsg := m.MatkulRepository.DeleteMatkulByID(ctx, tx, ID)
+	isSuccess, errMsg := m.MatkulRepository.DeleteMatkulByID(ctx, tx, ID, version, deletionTime())
 	if errMsg != nil && !isSuccess {
 		return false, errMsg
 	}
@@ -91,6 +104,9 @@ func (m *MataKuliahServiceImplementation) DeleteMatkulByID(ctx context.Context,
 }
 
 func (m *MataKuliahServiceImplementation) FindMatkulByID(ctx context.Context, ID int) (*response.MatkulResponse, bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MataKuliahService.FindMatkulByID")
+	defer span.End()
+
 	matkul, isRegistered, errMsg := m.MatkulRepository.FindMatkulByID(ctx, m.DB, ID)
 	if errMsg != nil && !isRegistered {
 		return nil, false, errMsg
@@ -100,13 +116,25 @@ func (m *MataKuliahServiceImplementation) FindMatkulByID(ctx context.Context, ID
 		ID:         matkul.ID,
 		Name:       matkul.Name,
 		KodeMatkul: matkul.KodeMatkul,
+		Version:    matkul.Version,
+		Audit:      matkul.Audit,
 	}
 
 	return matkulResponse, true, nil
 }
 
 func (m *MataKuliahServiceImplementation) FindMatkulByName(ctx context.Context, name string) ([]*response.MatkulResponse, *response.ErrorMsg) {
-	responseName, errMsg := m.MatkulRepository.FindMatkulByName(ctx, m.DB, name)
+	ctx, span := tracing.Start(ctx, "MataKuliahService.FindMatkulByName")
+	defer span.End()
+
+	return m.FindAllMatkul(ctx, &requests.ListRequest{Name: name})
+}
+
+func (m *MataKuliahServiceImplementation) FindAllMatkul(ctx context.Context, r *requests.ListRequest) ([]*response.MatkulResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MataKuliahService.FindAllMatkul")
+	defer span.End()
+
+	responseName, errMsg := m.MatkulRepository.FindAllMatkul(ctx, m.DB, listFilter(r))
 	if errMsg != nil {
 		return nil, errMsg
 
@@ -118,6 +146,8 @@ func (m *MataKuliahServiceImplementation) FindMatkulByName(ctx context.Context,
 			ID:         responseName[i].ID,
 			Name:       responseName[i].Name,
 			KodeMatkul: responseName[i].KodeMatkul,
+			Version:    responseName[i].Version,
+			Audit:      responseName[i].Audit,
 		}
 		matkuls = append(matkuls, &matkul)
 	}
@@ -0,0 +1,46 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+func TestMataKuliahService(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+
+	for _, r := range []*requests.InsertMatkulRequest{
+		{KodeMatkul: "IF101", Name: "Algoritma Pemrograman"},
+		{KodeMatkul: "IF102", Name: "Basis Data"},
+		{KodeMatkul: "IF201", Name: "Algoritma Lanjut"},
+	} {
+		if _, errMsg := ts.Matkul.InsertMatkul(ctx, r); errMsg != nil {
+			t.Fatalf("InsertMatkul(%s): %v", r.KodeMatkul, errMsg.Msg)
+		}
+	}
+
+	matkuls, errMsg := ts.Matkul.FindMatkulByName(ctx, "algoritma")
+	if errMsg != nil {
+		t.Fatalf("FindMatkulByName: %v", errMsg.Msg)
+	}
+	if len(matkuls) != 2 || matkuls[0].KodeMatkul != "IF101" || matkuls[1].KodeMatkul != "IF201" {
+		t.Fatalf("unexpected matkuls %+v", matkuls)
+	}
+
+	if _, errMsg := ts.Matkul.UpdateMatkul(ctx, &requests.UpdateMatkulRequest{ID: 2, Name: "Sistem Basis Data", KodeMatkul: "IF102"}); errMsg != nil {
+		t.Fatalf("UpdateMatkul: %v", errMsg.Msg)
+	}
+	if matkul, _, _ := ts.Matkul.FindMatkulByID(ctx, 2); matkul.Name != "Sistem Basis Data" {
+		t.Fatalf("expected renamed matkul, got %q", matkul.Name)
+	}
+
+	if _, errMsg := ts.Matkul.DeleteMatkulByID(ctx, 2, 0); errMsg != nil {
+		t.Fatalf("DeleteMatkulByID: %v", errMsg.Msg)
+	}
+	_, errMsg = ts.Matkul.DeleteMatkulByID(ctx, 2, 0)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
@@ -0,0 +1,492 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/codegen"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+// The materials library of a matkul is open to the members of the classes
+// enrolled in it: their lecturers manage the weekly topics and materials,
+// everyone else in those classes reads and downloads them. Admins may do
+// both for any matkul.
+
+// MaxMaterialBytes is the largest file a lecturer may upload as a material.
+const MaxMaterialBytes = 50 << 20
+
+type MaterialService interface {
+	EnrollClass(ctx context.Context, classID int, matkulID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	UnenrollClass(ctx context.Context, classID int, matkulID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindMatkulByClassID(ctx context.Context, classID int) (matkuls []*response.MatkulResponse, errMsg *response.ErrorMsg)
+	FindTopics(ctx context.Context, matkulID int) (topics []*response.MaterialTopicResponse, errMsg *response.ErrorMsg)
+	InsertTopic(ctx context.Context, r *requests.InsertMaterialTopicRequest) (topic *response.MaterialTopicResponse, errMsg *response.ErrorMsg)
+	DeleteTopic(ctx context.Context, matkulID int, topicID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	InsertLink(ctx context.Context, matkulID int, topicID int, r *requests.InsertMaterialLinkRequest) (isSuccess bool, errMsg *response.ErrorMsg)
+	InsertFile(ctx context.Context, matkulID int, topicID int, r *requests.InsertMaterialFileRequest, content io.Reader) (isSuccess bool, errMsg *response.ErrorMsg)
+	FindMaterialByID(ctx context.Context, matkulID int, materialID int) (material *response.MaterialResponse, errMsg *response.ErrorMsg)
+	DeleteMaterial(ctx context.Context, matkulID int, materialID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DownloadURL(ctx context.Context, matkulID int, materialID int) (location string, errMsg *response.ErrorMsg)
+}
+
+type MaterialServiceImplementation struct {
+	DB      *sql.DB
+	M       api.MicroServiceServer
+	Storage storage.Storage
+}
+
+func NewMaterialServiceImplementation(DB *sql.DB, M api.MicroServiceServer, store storage.Storage) MaterialService {
+	return &MaterialServiceImplementation{DB: DB, M: M, Storage: store}
+}
+
+// MaterialDownloadURL is where the members of the matkul download a material.
+func MaterialDownloadURL(matkulID, materialID int) string {
+	return fmt.Sprintf("/api/v2/matkul/%d/materials/%d/download", matkulID, materialID)
+}
+
+// EnrollClass makes the matkul part of the class, opening its materials to the
+// members. Enrolling a class twice changes nothing.
+func (m *MaterialServiceImplementation) EnrollClass(ctx context.Context, classID int, matkulID int) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.EnrollClass")
+	defer span.End()
+
+	if errMsg := authorizeClassLecturer(ctx, m.DB, m.M, classID); errMsg != nil {
+		return false, errMsg
+	}
+	if _, isFound, _ := m.M.MatkulRepository().FindMatkulByID(ctx, m.DB, matkulID); !isFound {
+		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Matkul dengan ID tidak ditemukan.")
+	}
+	isEnrolled, errMsg := m.M.MaterialRepository().IsEnrolled(ctx, m.DB, classID, matkulID)
+	if errMsg != nil || isEnrolled {
+		return errMsg == nil, errMsg
+	}
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	return m.M.MaterialRepository().EnrollClass(ctx, tx, classID, matkulID)
+}
+
+func (m *MaterialServiceImplementation) UnenrollClass(ctx context.Context, classID int, matkulID int) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.UnenrollClass")
+	defer span.End()
+
+	if errMsg := authorizeClassLecturer(ctx, m.DB, m.M, classID); errMsg != nil {
+		return false, errMsg
+	}
+	isEnrolled, errMsg := m.M.MaterialRepository().IsEnrolled(ctx, m.DB, classID, matkulID)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	if !isEnrolled {
+		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Kelas tidak terdaftar di mata kuliah ini.")
+	}
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	return m.M.MaterialRepository().UnenrollClass(ctx, tx, classID, matkulID, deletionTime())
+}
+
+// FindMatkulByClassID lists the mata kuliah of the class to its members.
+func (m *MaterialServiceImplementation) FindMatkulByClassID(ctx context.Context, classID int) ([]*response.MatkulResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.FindMatkulByClassID")
+	defer span.End()
+
+	if _, isFound, _ := m.M.ClassRepository().FindClassByID(ctx, m.DB, classID); !isFound {
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Kelas by ID tidak ditemukan.")
+	}
+	info, errMsg := caller(ctx)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	if info != nil && !info.IsAdmin() {
+		isMember, errMsg := m.M.ClassMemberRepository().IsClassMember(ctx, m.DB, classID, info.ID)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+		if !isMember {
+			return nil, helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, "Anda bukan anggota kelas ini.")
+		}
+	}
+
+	matkuls, errMsg := m.M.MaterialRepository().FindMatkulByClassID(ctx, m.DB, classID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	var matkulResponses []*response.MatkulResponse
+	for _, matkul := range matkuls {
+		matkulResponses = append(matkulResponses, &response.MatkulResponse{ID: matkul.ID, Name: matkul.Name, KodeMatkul: matkul.KodeMatkul, Version: matkul.Version, Audit: matkul.Audit})
+	}
+	return matkulResponses, nil
+}
+
+// FindTopics lists the topics of the matkul by week, each with its materials.
+func (m *MaterialServiceImplementation) FindTopics(ctx context.Context, matkulID int) ([]*response.MaterialTopicResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.FindTopics")
+	defer span.End()
+
+	if errMsg := m.authorizeMatkul(ctx, matkulID, false); errMsg != nil {
+		return nil, errMsg
+	}
+
+	topics, errMsg := m.M.MaterialRepository().FindTopicsByMatkulID(ctx, m.DB, matkulID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	materials, errMsg := m.M.MaterialRepository().FindMaterialsByMatkulID(ctx, m.DB, matkulID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	topicResponses := make([]*response.MaterialTopicResponse, 0, len(topics))
+	byID := map[int]*response.MaterialTopicResponse{}
+	for _, topic := range topics {
+		topicResponse := &response.MaterialTopicResponse{MaterialTopic: *topic, Materials: []*response.MaterialResponse{}}
+		topicResponses = append(topicResponses, topicResponse)
+		byID[topic.ID] = topicResponse
+	}
+	for _, material := range materials {
+		if topicResponse, ok := byID[material.TopicID]; ok {
+			topicResponse.Materials = append(topicResponse.Materials, materialResponse(matkulID, material))
+		}
+	}
+	return topicResponses, nil
+}
+
+// InsertTopic adds the topic of a week, still without materials.
+func (m *MaterialServiceImplementation) InsertTopic(ctx context.Context, r *requests.InsertMaterialTopicRequest) (*response.MaterialTopicResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.InsertTopic")
+	defer span.End()
+
+	if errMsg := m.authorizeMatkul(ctx, r.MatkulID, true); errMsg != nil {
+		return nil, errMsg
+	}
+
+	topic := &domain.MaterialTopic{MatkulID: r.MatkulID, Week: r.Week, Title: strings.TrimSpace(r.Title), Description: strings.TrimSpace(r.Description)}
+	errMsg := m.write(ctx, func(tx *sql.Tx) *response.ErrorMsg {
+		_, errMsg := m.M.MaterialRepository().InsertTopic(ctx, tx, topic)
+		return errMsg
+	})
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	r.ID = topic.ID
+	return &response.MaterialTopicResponse{MaterialTopic: *topic, Materials: []*response.MaterialResponse{}}, nil
+}
+
+// DeleteTopic soft deletes the topic with its materials; the purge job
+// removes their files.
+func (m *MaterialServiceImplementation) DeleteTopic(ctx context.Context, matkulID int, topicID int) (bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.DeleteTopic")
+	defer span.End()
+
+	if errMsg := m.authorizeMatkul(ctx, matkulID, true); errMsg != nil {
+		return false, errMsg
+	}
+	if _, errMsg := m.findTopic(ctx, matkulID, topicID); errMsg != nil {
+		return false, errMsg
+	}
+
+	errMsg := m.write(ctx, func(tx *sql.Tx) *response.ErrorMsg {
+		_, errMsg := m.M.MaterialRepository().DeleteTopic(ctx, tx, topicID, deletionTime())
+		return errMsg
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+// InsertLink shares a web page, such as a video or a shared document, under
+// the topic.
+func (m *MaterialServiceImplementation) InsertLink(ctx context.Context, matkulID int, topicID int, r *requests.InsertMaterialLinkRequest) (bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.InsertLink")
+	defer span.End()
+
+	if errMsg := m.authorizeMatkul(ctx, matkulID, true); errMsg != nil {
+		return false, errMsg
+	}
+	if _, errMsg := m.findTopic(ctx, matkulID, topicID); errMsg != nil {
+		return false, errMsg
+	}
+	link, err := url.Parse(r.URL)
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Link materi harus URL http atau https.")
+	}
+
+	material := &domain.Material{TopicID: topicID, Kind: domain.MaterialLink, Title: strings.TrimSpace(r.Title), URL: r.URL}
+	if errMsg := m.insertMaterial(ctx, material); errMsg != nil {
+		return false, errMsg
+	}
+	r.ID = material.ID
+	return true, nil
+}
+
+// InsertFile stores content, of r.Size bytes, as a file of the topic. The
+// file counts against the quota of the lecturer uploading it.
+func (m *MaterialServiceImplementation) InsertFile(ctx context.Context, matkulID int, topicID int, r *requests.InsertMaterialFileRequest, content io.Reader) (bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.InsertFile")
+	defer span.End()
+
+	if errMsg := m.authorizeMatkul(ctx, matkulID, true); errMsg != nil {
+		return false, errMsg
+	}
+	if _, errMsg := m.findTopic(ctx, matkulID, topicID); errMsg != nil {
+		return false, errMsg
+	}
+	if r.Size > MaxMaterialBytes {
+		return false, helpers.ToErrorMsg(http.StatusRequestEntityTooLarge, exception.ERR_BAD_REQUEST_FIELD, "Ukuran file materi maksimal 50 MB.")
+	}
+	if r.Size <= 0 {
+		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "File materi kosong.")
+	}
+
+	name := materialFileName(r.FileName)
+	key, errMsg := materialKey(ctx, name)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		var err error
+		if contentType, content, err = storage.Sniff(content); err != nil {
+			return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+		}
+	}
+
+	err := m.Storage.Put(ctx, key, io.LimitReader(content, r.Size), storage.Info{ContentType: contentType, Size: r.Size})
+	if errors.Is(err, storage.ErrQuotaExceeded) {
+		return false, helpers.ToErrorMsg(http.StatusRequestEntityTooLarge, exception.ERR_BAD_REQUEST_FIELD, "Kuota penyimpanan sudah penuh.")
+	}
+	if err != nil {
+		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		title = name
+	}
+	material := &domain.Material{TopicID: topicID, Kind: domain.MaterialFile, Title: title, FileKey: key, FileName: name, ContentType: contentType, Size: r.Size}
+	if errMsg := m.insertMaterial(ctx, material); errMsg != nil {
+		m.removeFile(ctx, key)
+		return false, errMsg
+	}
+	r.ID = material.ID
+	return true, nil
+}
+
+func (m *MaterialServiceImplementation) FindMaterialByID(ctx context.Context, matkulID int, materialID int) (*response.MaterialResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.FindMaterialByID")
+	defer span.End()
+
+	if errMsg := m.authorizeMatkul(ctx, matkulID, false); errMsg != nil {
+		return nil, errMsg
+	}
+	material, errMsg := m.findMaterial(ctx, matkulID, materialID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return materialResponse(matkulID, material), nil
+}
+
+// DeleteMaterial soft deletes the material; the purge job removes its file.
+func (m *MaterialServiceImplementation) DeleteMaterial(ctx context.Context, matkulID int, materialID int) (bool, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.DeleteMaterial")
+	defer span.End()
+
+	if errMsg := m.authorizeMatkul(ctx, matkulID, true); errMsg != nil {
+		return false, errMsg
+	}
+	if _, errMsg := m.findMaterial(ctx, matkulID, materialID); errMsg != nil {
+		return false, errMsg
+	}
+
+	errMsg := m.write(ctx, func(tx *sql.Tx) *response.ErrorMsg {
+		_, errMsg := m.M.MaterialRepository().DeleteMaterial(ctx, tx, materialID, deletionTime())
+		return errMsg
+	})
+	if errMsg != nil {
+		return false, errMsg
+	}
+	return true, nil
+}
+
+// DownloadURL returns where a member gets the material: the link itself, or
+// a signed URL of the file that expires after SignedURLTTL.
+func (m *MaterialServiceImplementation) DownloadURL(ctx context.Context, matkulID int, materialID int) (string, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "MaterialService.DownloadURL")
+	defer span.End()
+
+	if errMsg := m.authorizeMatkul(ctx, matkulID, false); errMsg != nil {
+		return "", errMsg
+	}
+	material, errMsg := m.findMaterial(ctx, matkulID, materialID)
+	if errMsg != nil {
+		return "", errMsg
+	}
+	if material.Kind == domain.MaterialLink {
+		return material.URL, nil
+	}
+
+	location, err := m.Storage.SignedURL(ctx, material.FileKey, SignedURLTTL)
+	if err != nil {
+		return "", helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	return location, nil
+}
+
+// authorizeMatkul lets through admins and the members of a class enrolled in
+// the matkul, and the system actor; when manage is set, only the dosen among
+// them.
+func (m *MaterialServiceImplementation) authorizeMatkul(ctx context.Context, matkulID int, manage bool) *response.ErrorMsg {
+	if _, isFound, _ := m.M.MatkulRepository().FindMatkulByID(ctx, m.DB, matkulID); !isFound {
+		return helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Matkul dengan ID tidak ditemukan.")
+	}
+
+	info, errMsg := caller(ctx)
+	if errMsg != nil || info == nil || info.IsAdmin() {
+		return errMsg
+	}
+	if manage && !info.IsLecturer() {
+		return helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, "Hanya dosen pengampu yang dapat mengelola materi.")
+	}
+	isMember, errMsg := m.M.MaterialRepository().IsMatkulMember(ctx, m.DB, matkulID, info.ID)
+	if errMsg != nil {
+		return errMsg
+	}
+	if !isMember {
+		return helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, "Anda tidak terdaftar di kelas yang mengambil mata kuliah ini.")
+	}
+	return nil
+}
+
+// findTopic reads the topic, which must belong to the matkul.
+func (m *MaterialServiceImplementation) findTopic(ctx context.Context, matkulID int, topicID int) (*domain.MaterialTopic, *response.ErrorMsg) {
+	topic, isFound, errMsg := m.M.MaterialRepository().FindTopicByID(ctx, m.DB, topicID)
+	if !isFound || topic.MatkulID != matkulID {
+		if errMsg == nil || errMsg.ErrorKey == exception.ERR_NOT_FOUND {
+			errMsg = helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Topik tidak ditemukan.")
+		}
+		return nil, errMsg
+	}
+	return topic, nil
+}
+
+// findMaterial reads the material, which must belong to a topic of the matkul.
+func (m *MaterialServiceImplementation) findMaterial(ctx context.Context, matkulID int, materialID int) (*domain.Material, *response.ErrorMsg) {
+	material, isFound, errMsg := m.M.MaterialRepository().FindMaterialByID(ctx, m.DB, materialID)
+	if !isFound {
+		return nil, errMsg
+	}
+	if _, errMsg := m.findTopic(ctx, matkulID, material.TopicID); errMsg != nil {
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Materi tidak ditemukan.")
+	}
+	return material, nil
+}
+
+func (m *MaterialServiceImplementation) insertMaterial(ctx context.Context, material *domain.Material) *response.ErrorMsg {
+	return m.write(ctx, func(tx *sql.Tx) *response.ErrorMsg {
+		_, errMsg := m.M.MaterialRepository().InsertMaterial(ctx, tx, material)
+		return errMsg
+	})
+}
+
+// write runs fn in a transaction that is over once write returns, so that
+// the file of a failed insert is only removed after its row is rolled back.
+func (m *MaterialServiceImplementation) write(ctx context.Context, fn func(tx *sql.Tx) *response.ErrorMsg) (errMsg *response.ErrorMsg) {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	return fn(tx)
+}
+
+// removeFile deletes the stored file of a material, if it has one. No row
+// points at it anymore, so a failure is only logged.
+func (m *MaterialServiceImplementation) removeFile(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := m.Storage.Delete(ctx, key); err != nil {
+		logging.FromContext(ctx).Named("services").Warn("file materi gagal dihapus", "key", key, "error", err)
+	}
+}
+
+func materialResponse(matkulID int, material *domain.Material) *response.MaterialResponse {
+	return &response.MaterialResponse{
+		ID:          material.ID,
+		TopicID:     material.TopicID,
+		Kind:        material.Kind,
+		Title:       material.Title,
+		URL:         material.URL,
+		FileName:    material.FileName,
+		ContentType: material.ContentType,
+		Size:        material.Size,
+		DownloadURL: MaterialDownloadURL(matkulID, material.ID),
+		Version:     material.Version,
+		Audit:       material.Audit,
+	}
+}
+
+// materialFileName keeps the last element of an uploaded file name, with
+// anything but letters, digits, dots, dashes and underscores replaced, so
+// that it is safe in a storage key and a Content-Disposition header.
+func materialFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	safe := []byte(name)
+	for i, c := range safe {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '.' || c == '-' || c == '_') {
+			safe[i] = '_'
+		}
+	}
+	name = strings.TrimLeft(string(safe), ".")
+	if len(name) > 100 {
+		name = name[len(name)-100:]
+	}
+	if name == "" {
+		return "materi"
+	}
+	return name
+}
+
+// materialKey stores a material among the files of the lecturer uploading it,
+// in a directory of its own so that the key ends in the file name. A file is
+// always charged to a user: without one, such as from the system actor, it
+// would escape every quota.
+func materialKey(ctx context.Context, name string) (string, *response.ErrorMsg) {
+	info, ok := api.UserInfoFromContext(ctx)
+	if !ok {
+		return "", helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, "File materi hanya dapat diunggah oleh pengguna.")
+	}
+	token, err := codegen.FileToken.Generate()
+	if err != nil {
+		return "", helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err.Error())
+	}
+	return storage.UserKey(info.ID, "materials/"+token+"/"+name), nil
+}
@@ -0,0 +1,172 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+)
+
+func TestMaterialService(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	store := storage.NewQuota(storage.NewLocal(t.TempDir(), storage.LocalPath, nil), 20)
+	materials := services.NewMaterialServiceImplementation(ts.Store.DB(), ts.M, store)
+
+	classID := ts.seedClass(t, "TI-2A")
+	otherClassID := ts.seedClass(t, "TI-2B")
+	dosenID := ts.seedUser(t, "Budi Santoso", "budi@mail.com", "dosen", classID)
+	siswaID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", classID)
+	otherID := ts.seedUser(t, "Andi Wijaya", "andi@mail.com", "mahasiswa", otherClassID)
+	dosen := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: dosenID, Role: "dosen"})
+	siswa := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: siswaID, Role: "mahasiswa"})
+	other := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: otherID, Role: "mahasiswa"})
+
+	if _, errMsg := ts.Matkul.InsertMatkul(ctx, &requests.InsertMatkulRequest{KodeMatkul: "IF101", Name: "Algoritma Pemrograman"}); errMsg != nil {
+		t.Fatalf("InsertMatkul: %v", errMsg.Msg)
+	}
+
+	// A lecturer only enrols the classes they teach.
+	_, errMsg := materials.EnrollClass(dosen, otherClassID, 1)
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+	if _, errMsg := materials.EnrollClass(dosen, classID, 1); errMsg != nil {
+		t.Fatalf("EnrollClass: %v", errMsg.Msg)
+	}
+
+	for _, week := range []int{3, 1} {
+		r := &requests.InsertMaterialTopicRequest{MatkulID: 1, Week: week, Title: "Minggu"}
+		if _, errMsg := materials.InsertTopic(dosen, r); errMsg != nil {
+			t.Fatalf("InsertTopic(%d): %v", week, errMsg.Msg)
+		}
+	}
+	_, errMsg = materials.InsertTopic(siswa, &requests.InsertMaterialTopicRequest{MatkulID: 1, Week: 2, Title: "Minggu"})
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+
+	link := &requests.InsertMaterialLinkRequest{Title: "Slide", URL: "https://example.com/slide"}
+	if _, errMsg := materials.InsertLink(dosen, 1, 2, link); errMsg != nil {
+		t.Fatalf("InsertLink: %v", errMsg.Msg)
+	}
+
+	// Files count against the uploader's quota and are kept only when stored.
+	file := &requests.InsertMaterialFileRequest{FileName: "../catatan.md", Size: 15}
+	if _, errMsg := materials.InsertFile(dosen, 1, 2, file, strings.NewReader("materi minggu 1")); errMsg != nil {
+		t.Fatalf("InsertFile: %v", errMsg.Msg)
+	}
+	_, errMsg = materials.InsertFile(dosen, 1, 2, &requests.InsertMaterialFileRequest{FileName: "besar.md", Size: 15}, strings.NewReader("materi minggu 2"))
+	assertErrorKey(t, errMsg, http.StatusRequestEntityTooLarge, exception.ERR_BAD_REQUEST_FIELD)
+	// The system actor passes the role checks but has no quota to charge.
+	_, errMsg = materials.InsertFile(api.ContextWithSystemActor(ctx), 1, 2, &requests.InsertMaterialFileRequest{FileName: "sistem.md", Size: 1}, strings.NewReader("x"))
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+
+	topics, errMsg := materials.FindTopics(siswa, 1)
+	if errMsg != nil {
+		t.Fatalf("FindTopics: %v", errMsg.Msg)
+	}
+	if len(topics) != 2 || topics[0].Week != 1 || topics[1].Week != 3 {
+		t.Fatalf("expected topics ordered by week, got %+v", topics)
+	}
+	if got := topics[0].Materials; len(got) != 2 || got[0].URL != link.URL || got[1].FileName != "catatan.md" || got[1].Title != "catatan.md" {
+		t.Fatalf("unexpected materials %+v", got)
+	}
+	_, errMsg = materials.FindTopics(other, 1)
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+
+	signed, errMsg := materials.DownloadURL(siswa, 1, file.ID)
+	if errMsg != nil {
+		t.Fatalf("DownloadURL: %v", errMsg.Msg)
+	}
+	if !strings.HasPrefix(signed, storage.LocalPath+"/") || !strings.Contains(signed, "signature=") {
+		t.Fatalf("expected a signed URL, got %q", signed)
+	}
+
+	// Purging the deleted matkul drops its topics, materials and enrolments,
+	// and the stored file with them.
+	if _, errMsg := ts.Matkul.DeleteMatkulByID(ctx, 1, 0); errMsg != nil {
+		t.Fatalf("DeleteMatkulByID: %v", errMsg.Msg)
+	}
+	purge := services.NewPurgeServiceImplementation(ts.Store.DB(), ts.M, store)
+	report, errMsg := purge.Purge(ctx, time.Now().Add(time.Hour))
+	if errMsg != nil {
+		t.Fatalf("Purge: %v", errMsg.Msg)
+	}
+	if report.Materials != 5 || report.Matkul != 1 {
+		t.Fatalf("expected 2 materials, 2 topics and 1 enrolment purged, got %+v", report)
+	}
+	if used, err := store.Usage(ctx, dosenID); err != nil || used != 0 {
+		t.Fatalf("expected the purged file deleted, %d bytes still used (%v)", used, err)
+	}
+}
+
+func TestMaterialSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	store := storage.NewQuota(storage.NewLocal(t.TempDir(), storage.LocalPath, nil), 100)
+	materials := services.NewMaterialServiceImplementation(ts.Store.DB(), ts.M, store)
+	purge := services.NewPurgeServiceImplementation(ts.Store.DB(), ts.M, store)
+
+	classID := ts.seedClass(t, "TI-2A")
+	dosenID := ts.seedUser(t, "Budi Santoso", "budi@mail.com", "dosen", classID)
+	dosen := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: dosenID, Role: "dosen"})
+
+	if _, errMsg := ts.Matkul.InsertMatkul(ctx, &requests.InsertMatkulRequest{KodeMatkul: "IF101", Name: "Algoritma Pemrograman"}); errMsg != nil {
+		t.Fatalf("InsertMatkul: %v", errMsg.Msg)
+	}
+	if _, errMsg := materials.EnrollClass(dosen, classID, 1); errMsg != nil {
+		t.Fatalf("EnrollClass: %v", errMsg.Msg)
+	}
+	topic := &requests.InsertMaterialTopicRequest{MatkulID: 1, Week: 1, Title: "Pengenalan"}
+	if _, errMsg := materials.InsertTopic(dosen, topic); errMsg != nil {
+		t.Fatalf("InsertTopic: %v", errMsg.Msg)
+	}
+	file := &requests.InsertMaterialFileRequest{FileName: "catatan.md", Size: 15}
+	if _, errMsg := materials.InsertFile(dosen, 1, topic.ID, file, strings.NewReader("materi minggu 1")); errMsg != nil {
+		t.Fatalf("InsertFile: %v", errMsg.Msg)
+	}
+	assertUsage := func(t *testing.T, want int64) {
+		t.Helper()
+		if used, err := store.Usage(ctx, dosenID); err != nil || used != want {
+			t.Fatalf("expected %d bytes stored, got %d (%v)", want, used, err)
+		}
+	}
+
+	// Deleting hides the material but keeps its file until it is purged.
+	if _, errMsg := materials.DeleteMaterial(dosen, 1, file.ID); errMsg != nil {
+		t.Fatalf("DeleteMaterial: %v", errMsg.Msg)
+	}
+	_, errMsg := materials.FindMaterialByID(dosen, 1, file.ID)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+	assertUsage(t, 15)
+
+	// A deleted topic frees its week for a new one.
+	if _, errMsg := materials.DeleteTopic(dosen, 1, topic.ID); errMsg != nil {
+		t.Fatalf("DeleteTopic: %v", errMsg.Msg)
+	}
+	if _, errMsg := materials.InsertTopic(dosen, &requests.InsertMaterialTopicRequest{MatkulID: 1, Week: 1, Title: "Pengenalan ulang"}); errMsg != nil {
+		t.Fatalf("InsertTopic after delete: %v", errMsg.Msg)
+	}
+	_, errMsg = materials.InsertTopic(dosen, &requests.InsertMaterialTopicRequest{MatkulID: 1, Week: 1, Title: "Duplikat"})
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_ALREADY_USE)
+
+	report, errMsg := purge.Purge(ctx, time.Now().Add(time.Hour))
+	if errMsg != nil {
+		t.Fatalf("Purge: %v", errMsg.Msg)
+	}
+	if report.Materials != 2 {
+		t.Fatalf("expected the material and its topic purged, got %+v", report)
+	}
+	assertUsage(t, 0)
+	topics, errMsg := materials.FindTopics(dosen, 1)
+	if errMsg != nil {
+		t.Fatalf("FindTopics: %v", errMsg.Msg)
+	}
+	if len(topics) != 1 || topics[0].Title != "Pengenalan ulang" {
+		t.Fatalf("expected only the new topic left, got %+v", topics)
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+)
+
+// Profiles are written along with their user by UsersService and
+// AuthService. Only the fields a request sets are checked, so a user whose
+// role changed keeps an NPM or NIDN until it is edited.
+
+var (
+	npmPattern   = regexp.MustCompile(`^[0-9]{8,15}$`)
+	nidnPattern  = regexp.MustCompile(`^[0-9]{10}$`)
+	phonePattern = regexp.MustCompile(`^\+628[0-9]{7,11}$`)
+)
+
+// MinAngkatan is the earliest angkatan a profile accepts; the latest is next year.
+const MinAngkatan = 1950
+
+// profilePatch turns the profile of a new user into the changes it makes to
+// an empty profile.
+func profilePatch(r *requests.ProfileRequest) *requests.ProfilePatchRequest {
+	if r == nil {
+		return nil
+	}
+	patch := &requests.ProfilePatchRequest{}
+	set := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	patch.NPM, patch.NIDN, patch.ProgramStudi, patch.Phone = set(r.NPM), set(r.NIDN), set(r.ProgramStudi), set(r.Phone)
+	if r.Angkatan != 0 {
+		patch.Angkatan = &r.Angkatan
+	}
+	return patch
+}
+
+// validateProfilePatch checks the fields r sets for a user of role and
+// writes the phone number in its +62 form.
+func validateProfilePatch(role string, r *requests.ProfilePatchRequest) *response.ErrorMsg {
+	invalid := func(msg string) *response.ErrorMsg {
+		return helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, msg)
+	}
+
+	if r.NPM != nil && *r.NPM != "" {
+		switch {
+		case role != domain.RoleMahasiswa:
+			return invalid("NPM hanya untuk mahasiswa.")
+		case !npmPattern.MatchString(*r.NPM):
+			return invalid("NPM harus berupa 8 sampai 15 digit angka.")
+		}
+	}
+	if r.NIDN != nil && *r.NIDN != "" {
+		switch {
+		case role != domain.RoleDosen:
+			return invalid("NIDN hanya untuk dosen.")
+		case !nidnPattern.MatchString(*r.NIDN):
+			return invalid("NIDN harus berupa 10 digit angka.")
+		}
+	}
+	if r.ProgramStudi != nil {
+		*r.ProgramStudi = strings.TrimSpace(*r.ProgramStudi)
+		if len(*r.ProgramStudi) > 100 {
+			return invalid("Program studi maksimal 100 karakter.")
+		}
+	}
+	if r.Angkatan != nil && *r.Angkatan != 0 {
+		if latest := time.Now().Year() + 1; *r.Angkatan < MinAngkatan || *r.Angkatan > latest {
+			return invalid(fmt.Sprintf("Angkatan harus tahun antara %d dan %d.", MinAngkatan, latest))
+		}
+	}
+	if r.Phone != nil && *r.Phone != "" {
+		*r.Phone = normalizePhone(*r.Phone)
+		if !phonePattern.MatchString(*r.Phone) {
+			return invalid("Nomor telepon harus nomor seluler Indonesia, misalnya 081234567890.")
+		}
+	}
+	if r.Avatar != nil && *r.Avatar != "" {
+		avatar, err := url.Parse(*r.Avatar)
+		if err != nil || (avatar.Scheme != "http" && avatar.Scheme != "https") || avatar.Host == "" || len(*r.Avatar) > 255 {
+			return invalid("Avatar harus URL http atau https, maksimal 255 karakter.")
+		}
+	}
+	return nil
+}
+
+// normalizePhone drops the spaces and dashes of an Indonesian number and
+// writes its prefix as +62.
+func normalizePhone(phone string) string {
+	phone = strings.NewReplacer(" ", "", "-", "").Replace(phone)
+	switch {
+	case strings.HasPrefix(phone, "0"):
+		return "+62" + phone[1:]
+	case strings.HasPrefix(phone, "62"):
+		return "+" + phone
+	}
+	return phone
+}
+
+// applyProfilePatch copies the fields r sets onto profile.
+func applyProfilePatch(profile *domain.Profile, r *requests.ProfilePatchRequest) {
+	if r.NPM != nil {
+		profile.NPM = *r.NPM
+	}
+	if r.NIDN != nil {
+		profile.NIDN = *r.NIDN
+	}
+	if r.ProgramStudi != nil {
+		profile.ProgramStudi = *r.ProgramStudi
+	}
+	if r.Angkatan != nil {
+		profile.Angkatan = *r.Angkatan
+	}
+	if r.Phone != nil {
+		profile.Phone = *r.Phone
+	}
+	if r.Avatar != nil {
+		profile.Avatar = *r.Avatar
+	}
+}
+
+// checkProfileUnique reports an NPM or NIDN of profile that another user already has.
+func checkProfileUnique(ctx context.Context, db *sql.DB, m api.MicroServiceServer, profile *domain.Profile) *response.ErrorMsg {
+	if profile.NPM != "" {
+		other, found, errMsg := m.ProfileRepository().FindProfileByNPM(ctx, db, profile.NPM)
+		if !found && errMsg != nil && errMsg.StatusCode != http.StatusNotFound {
+			return errMsg
+		}
+		if found && other.UserID != profile.UserID {
+			return helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "NPM sudah digunakan.")
+		}
+	}
+	if profile.NIDN != "" {
+		other, found, errMsg := m.ProfileRepository().FindProfileByNIDN(ctx, db, profile.NIDN)
+		if !found && errMsg != nil && errMsg.StatusCode != http.StatusNotFound {
+			return errMsg
+		}
+		if found && other.UserID != profile.UserID {
+			return helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "NIDN sudah digunakan.")
+		}
+	}
+	return nil
+}
+
+// findProfile returns the profile of userID, an empty one when the user has
+// none yet, and whether it is stored.
+func findProfile(ctx context.Context, db *sql.DB, m api.MicroServiceServer, userID int) (*domain.Profile, bool, *response.ErrorMsg) {
+	profile, found, errMsg := m.ProfileRepository().FindProfileByUserID(ctx, db, userID)
+	if found {
+		return profile, true, nil
+	}
+	if errMsg != nil && errMsg.StatusCode != http.StatusNotFound {
+		return nil, false, errMsg
+	}
+	return &domain.Profile{UserID: userID}, false, nil
+}
+
+// prepareProfile loads the profile of userID with r applied, after checking
+// r for a user of role and that no other user has its NPM or NIDN. Call it
+// before the first write of the transaction that saves the profile.
+func prepareProfile(ctx context.Context, db *sql.DB, m api.MicroServiceServer, userID int, role string, r *requests.ProfilePatchRequest) (profile *domain.Profile, isStored bool, errMsg *response.ErrorMsg) {
+	profile, isStored, errMsg = findProfile(ctx, db, m, userID)
+	if errMsg != nil {
+		return nil, false, errMsg
+	}
+	// An uploaded avatar is no http URL; a client sending back the profile
+	// it read leaves it as it is.
+	if r.Avatar != nil && *r.Avatar == profile.Avatar {
+		r.Avatar = nil
+	}
+	if errMsg := validateProfilePatch(role, r); errMsg != nil {
+		return nil, false, errMsg
+	}
+	applyProfilePatch(profile, r)
+	if errMsg := checkProfileUnique(ctx, db, m, profile); errMsg != nil {
+		return nil, false, errMsg
+	}
+	return profile, isStored, nil
+}
+
+// saveProfile writes profile for its user, given a user ID once the user is inserted.
+func saveProfile(ctx context.Context, tx *sql.Tx, m api.MicroServiceServer, profile *domain.Profile, isStored bool) *response.ErrorMsg {
+	var errMsg *response.ErrorMsg
+	if isStored {
+		_, errMsg = m.ProfileRepository().UpdateProfile(ctx, tx, profile)
+	} else {
+		_, errMsg = m.ProfileRepository().InsertProfile(ctx, tx, profile)
+	}
+	return errMsg
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/logging"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+// PurgeReport counts the rows removed for good by one purge.
+type PurgeReport struct {
+	Rooms        int64
+	Lectures     int64
+	ClassMembers int64
+	Profiles     int64
+	Materials    int64
+	Users        int64
+	Classes      int64
+	Matkul       int64
+}
+
+func (p *PurgeReport) Total() int64 {
+	return p.Rooms + p.Lectures + p.ClassMembers + p.Profiles + p.Materials + p.Users + p.Classes + p.Matkul
+}
+
+type PurgeService interface {
+	Purge(ctx context.Context, before time.Time) (report *PurgeReport, errMsg *response.ErrorMsg)
+}
+
+type PurgeServiceImplementation struct {
+	DB      *sql.DB
+	M       api.MicroServiceServer
+	Storage storage.Storage
+}
+
+func NewPurgeServiceImplementation(DB *sql.DB, m api.MicroServiceServer, store storage.Storage) PurgeService {
+	return &PurgeServiceImplementation{DB: DB, M: m, Storage: store}
+}
+
+// Purge deletes for good every row soft deleted before before, dependents
+// first, in one transaction. The stored files of the purged materials are
+// deleted once it commits.
+func (p *PurgeServiceImplementation) Purge(ctx context.Context, before time.Time) (*PurgeReport, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "PurgeService.Purge")
+	defer span.End()
+
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+
+	fileKeys, errMsg := p.M.MaterialRepository().FindPurgedFileKeys(ctx, tx, before)
+	if errMsg != nil {
+		tx.Rollback()
+		return nil, errMsg
+	}
+
+	report := &PurgeReport{}
+	steps := []struct {
+		purged *int64
+		purge  func(ctx context.Context, tx *sql.Tx, before time.Time) (int64, *response.ErrorMsg)
+	}{
+		{&report.Rooms, p.M.RoomRepository().PurgeRoom},
+		{&report.Lectures, p.M.LectureRepository().PurgeLecture},
+		{&report.ClassMembers, p.M.ClassMemberRepository().PurgeClassMembers},
+		{&report.Profiles, p.M.ProfileRepository().PurgeProfiles},
+		{&report.Materials, p.M.MaterialRepository().PurgeMaterials},
+		{&report.Users, p.M.UserRepository().PurgeUsers},
+		{&report.Classes, p.M.ClassRepository().PurgeClass},
+		{&report.Matkul, p.M.MatkulRepository().PurgeMatkul},
+	}
+	for _, step := range steps {
+		purged, errMsg := step.purge(ctx, tx, before)
+		if errMsg != nil {
+			tx.Rollback()
+			return nil, errMsg
+		}
+		*step.purged = purged
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	for _, key := range fileKeys {
+		if err := p.Storage.Delete(ctx, key); err != nil {
+			logging.FromContext(ctx).Named("services").Warn("file materi gagal dihapus", "key", key, "error", err)
+		}
+	}
+	return report, nil
+}
+
+// RunPurgeJob purges the rows soft deleted more than retention ago, once at
+// start and then every interval, until ctx is done. A retention of zero or
+// less disables it.
+func RunPurgeJob(ctx context.Context, purgeService PurgeService, retention, interval time.Duration) {
+	if retention <= 0 || interval <= 0 {
+		return
+	}
+
+	log := logging.FromContext(ctx).Named("services")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, errMsg := purgeService.Purge(ctx, time.Now().Add(-retention))
+		if errMsg != nil {
+			log.Error("purge soft delete gagal", "error_key", errMsg.ErrorKey, "error", errMsg.Msg)
+		} else if report.Total() > 0 {
+			log.Info("purge soft delete selesai", "rooms", report.Rooms, "lectures", report.Lectures, "class_members", report.ClassMembers,
+				"profiles", report.Profiles, "materials", report.Materials, "users", report.Users, "classes", report.Classes, "matkul", report.Matkul)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
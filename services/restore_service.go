@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+// RestoreResources lists the resources accepted by RestoreService.Restore.
+var RestoreResources = []string{"users", "classes", "lectures", "rooms", "matkul"}
+
+type RestoreService interface {
+	Restore(ctx context.Context, resource string, ID int) (restored *response.RestoreResponse, errMsg *response.ErrorMsg)
+}
+
+type RestoreServiceImplementation struct {
+	DB     *sql.DB
+	M      api.MicroServiceServer
+	Events *RoomEvents
+}
+
+func NewRestoreServiceImplementation(DB *sql.DB, m api.MicroServiceServer) RestoreService {
+	return &RestoreServiceImplementation{DB: DB, M: m, Events: roomEvents}
+}
+
+// Restore brings back a soft deleted row of resource along with the
+// dependents that were deleted with it. Every check runs before the first
+// write, since the transaction is committed on any return.
+func (r *RestoreServiceImplementation) Restore(ctx context.Context, resource string, ID int) (_ *response.RestoreResponse, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "RestoreService.Restore")
+	defer span.End()
+
+	if errMsg := authorizeAdmin(ctx, "Hanya admin yang dapat memulihkan data terhapus."); errMsg != nil {
+		return nil, errMsg
+	}
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	switch resource {
+	case "users":
+		errMsg = r.restoreUser(ctx, tx, ID)
+	case "classes":
+		errMsg = r.restoreClass(ctx, tx, ID)
+	case "lectures":
+		errMsg = r.restoreLecture(ctx, tx, ID)
+	case "rooms":
+		errMsg = r.restoreRoom(ctx, tx, ID)
+	case "matkul":
+		errMsg = r.restoreMatkul(ctx, tx, ID)
+	default:
+		errMsg = helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Resource restore harus salah satu dari "+strings.Join(RestoreResources, ", ")+".")
+	}
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	return &response.RestoreResponse{Resource: resource, ID: ID}, nil
+}
+
+// restoreUser also restores the user's lecture and its rooms when they were
+// deleted together with the user.
+func (r *RestoreServiceImplementation) restoreUser(ctx context.Context, tx *sql.Tx, ID int) *response.ErrorMsg {
+	user, _, errMsg := r.M.UserRepository().FindDeletedUserByID(ctx, r.DB, ID)
+	if errMsg != nil {
+		return errMsg
+	}
+	if _, isEmailRegistered, _ := r.M.UserRepository().IsEmailRegistered(ctx, r.DB, user.Email); isEmailRegistered {
+		return helpers.ToErrorMsg(http.StatusConflict, exception.ERR_ALREADY_USE, "User tidak dapat dipulihkan, email sudah digunakan oleh user lain.")
+	}
+
+	if _, errMsg := r.M.UserRepository().RestoreDataUser(ctx, tx, ID); errMsg != nil {
+		return errMsg
+	}
+
+	lecture, isLecture, _ := r.M.LectureRepository().FindDeletedLectureByUserID(ctx, r.DB, ID)
+	if isLecture && lecture.DeletedAt.Equal(*user.DeletedAt) {
+		return restoreLecture(ctx, tx, r.M, lecture)
+	}
+	return nil
+}
+
+func (r *RestoreServiceImplementation) restoreClass(ctx context.Context, tx *sql.Tx, ID int) *response.ErrorMsg {
+	if _, _, errMsg := r.M.ClassRepository().FindDeletedClassByID(ctx, r.DB, ID); errMsg != nil {
+		return errMsg
+	}
+	_, errMsg := r.M.ClassRepository().RestoreClassByID(ctx, tx, ID)
+	return errMsg
+}
+
+func (r *RestoreServiceImplementation) restoreLecture(ctx context.Context, tx *sql.Tx, ID int) *response.ErrorMsg {
+	lecture, _, errMsg := r.M.LectureRepository().FindDeletedLectureByID(ctx, r.DB, ID)
+	if errMsg != nil {
+		return errMsg
+	}
+	if _, isUserRegistered, _ := r.M.UserRepository().FindUserByID(ctx, r.DB, lecture.UserID); !isUserRegistered {
+		return helpers.ToErrorMsg(http.StatusConflict, exception.ERR_CONFLICT, "Dosen tidak dapat dipulihkan, karena data user-nya terhapus.")
+	}
+	if _, isUserIDUsed, _ := r.M.LectureRepository().FindLectureByUserID(ctx, r.DB, lecture.UserID); isUserIDUsed {
+		return helpers.ToErrorMsg(http.StatusConflict, exception.ERR_ALREADY_USE, "Dosen tidak dapat dipulihkan, User ID telah digunakan.")
+	}
+
+	return restoreLecture(ctx, tx, r.M, lecture)
+}
+
+func (r *RestoreServiceImplementation) restoreRoom(ctx context.Context, tx *sql.Tx, ID int) *response.ErrorMsg {
+	room, _, errMsg := r.M.RoomRepository().FindDeletedRoomByID(ctx, r.DB, ID)
+	if errMsg != nil {
+		return errMsg
+	}
+	if _, isLecture, _ := r.M.LectureRepository().FindLectureByID(ctx, r.DB, room.LectureID); !isLecture {
+		return helpers.ToErrorMsg(http.StatusConflict, exception.ERR_CONFLICT, "Room tidak dapat dipulihkan, karena data dosen-nya terhapus.")
+	}
+
+	if _, errMsg := r.M.RoomRepository().RestoreRoomByID(ctx, tx, ID); errMsg != nil {
+		return errMsg
+	}
+
+	room.DeletedAt = nil
+	r.Events.Publish(RoomEvent{Type: RoomCreated, Room: toRoomResponse(room)})
+	return nil
+}
+
+func (r *RestoreServiceImplementation) restoreMatkul(ctx context.Context, tx *sql.Tx, ID int) *response.ErrorMsg {
+	if _, _, errMsg := r.M.MatkulRepository().FindDeletedMatkulByID(ctx, r.DB, ID); errMsg != nil {
+		return errMsg
+	}
+	_, errMsg := r.M.MatkulRepository().RestoreMatkulByID(ctx, tx, ID)
+	return errMsg
+}
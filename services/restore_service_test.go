@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+func TestRestoreUserCascade(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-4A")
+	userID := ts.seedUser(t, "Budi Santoso", "budi@mail.com", "dosen", classID)
+
+	lecture, _, errMsg := ts.M.LectureRepository().FindLectureByUserID(ctx, ts.Store.DB(), userID)
+	if errMsg != nil {
+		t.Fatalf("FindLectureByUserID: %v", errMsg.Msg)
+	}
+	insert := &requests.InsertRoomRequest{Name: "Basis Data", URL: "https://meet.example.com/bd", LectureID: lecture.ID, StartRoom: "2023-02-19 20:25:00", EndRoom: "2023-02-19 21:25:00"}
+	if _, errMsg := ts.Room.InsertRoom(ctx, insert); errMsg != nil {
+		t.Fatalf("InsertRoom: %v", errMsg.Msg)
+	}
+
+	if _, errMsg := ts.Users.DeleteDataUser(ctx, "rahasia123", userID); errMsg != nil {
+		t.Fatalf("DeleteDataUser: %v", errMsg.Msg)
+	}
+	if _, isValid, _ := ts.Lecture.FindLectureByID(ctx, lecture.ID); isValid {
+		t.Fatal("expected the lecture to be deleted with its user")
+	}
+	if _, isValid, _ := ts.Room.FindRoomByID(ctx, insert.ID); isValid {
+		t.Fatal("expected the room to be deleted with its lecture")
+	}
+	if _, errMsg := ts.Auth.AuthLoginUser(ctx, "budi@mail.com", "rahasia123"); errMsg == nil {
+		t.Fatal("expected a deleted user to be unable to log in")
+	}
+
+	_, errMsg = ts.Restore.Restore(ctx, "rooms", insert.ID)
+	assertErrorKey(t, errMsg, http.StatusConflict, exception.ERR_CONFLICT)
+
+	if _, errMsg := ts.Restore.Restore(ctx, "users", userID); errMsg != nil {
+		t.Fatalf("Restore users: %v", errMsg.Msg)
+	}
+	if _, isValid, _ := ts.Room.FindRoomByID(ctx, insert.ID); !isValid {
+		t.Fatal("expected the room to be restored with its user")
+	}
+	if members, _ := ts.Class.FindClassMembers(ctx, classID, ""); len(members) != 1 {
+		t.Fatalf("expected the restored user back in the class, got %d members", len(members))
+	}
+
+	_, errMsg = ts.Restore.Restore(ctx, "users", userID)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+	_, errMsg = ts.Restore.Restore(ctx, "kursi", 1)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
+
+func TestRestoreUserEmailTaken(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-4B")
+	userID := ts.seedUser(t, "Rina Wati", "rina@mail.com", "mahasiswa", classID)
+
+	if _, errMsg := ts.Users.DeleteDataUser(ctx, "rahasia123", userID); errMsg != nil {
+		t.Fatalf("DeleteDataUser: %v", errMsg.Msg)
+	}
+	ts.seedUser(t, "Rina Baru", "rina@mail.com", "mahasiswa", classID)
+
+	_, errMsg := ts.Restore.Restore(ctx, "users", userID)
+	assertErrorKey(t, errMsg, http.StatusConflict, exception.ERR_ALREADY_USE)
+}
+
+func TestPurgeService(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+
+	if _, errMsg := ts.Matkul.InsertMatkul(ctx, &requests.InsertMatkulRequest{Name: "Kalkulus", KodeMatkul: "MK001"}); errMsg != nil {
+		t.Fatalf("InsertMatkul: %v", errMsg.Msg)
+	}
+	if _, errMsg := ts.Matkul.DeleteMatkulByID(ctx, 1, 0); errMsg != nil {
+		t.Fatalf("DeleteMatkulByID: %v", errMsg.Msg)
+	}
+
+	report, errMsg := ts.Purge.Purge(ctx, time.Now().Add(-time.Hour))
+	if errMsg != nil {
+		t.Fatalf("Purge: %v", errMsg.Msg)
+	}
+	if report.Total() != 0 {
+		t.Fatalf("expected nothing older than the retention, got %+v", report)
+	}
+
+	report, errMsg = ts.Purge.Purge(ctx, time.Now().Add(time.Hour))
+	if errMsg != nil {
+		t.Fatalf("Purge: %v", errMsg.Msg)
+	}
+	if report.Matkul != 1 {
+		t.Fatalf("expected 1 purged matkul, got %+v", report)
+	}
+
+	_, errMsg = ts.Restore.Restore(ctx, "matkul", 1)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
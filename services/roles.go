@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+)
+
+// Roles lists the values accepted for users.role.
+var Roles = []string{domain.RoleDosen, domain.RoleMahasiswa, domain.RoleAdmin}
+
+// IsRole reports whether role is one of Roles.
+func IsRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// caller returns the user ctx acts for, or nil for the system actor, which
+// passes every role check. A ctx with neither is refused, so a request that
+// lost its user on the way is never taken for the CLI.
+func caller(ctx context.Context) (*api.UserInfo, *response.ErrorMsg) {
+	if info, ok := api.UserInfoFromContext(ctx); ok {
+		return info, nil
+	}
+	if api.IsSystemActor(ctx) {
+		return nil, nil
+	}
+	return nil, helpers.ToErrorMsg(http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER, "Token tidak valid!")
+}
+
+// authorizeAdmin lets through admins and the system actor.
+func authorizeAdmin(ctx context.Context, msg string) *response.ErrorMsg {
+	info, errMsg := caller(ctx)
+	if errMsg != nil {
+		return errMsg
+	}
+	if info != nil && !info.IsAdmin() {
+		return helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, msg)
+	}
+	return nil
+}
+
+// authorizeRoleChange keeps non-admins from making a user admin or from
+// changing the role of an admin.
+func authorizeRoleChange(ctx context.Context, from, to string) *response.ErrorMsg {
+	if from == to || (from != domain.RoleAdmin && to != domain.RoleAdmin) {
+		return nil
+	}
+	return authorizeAdmin(ctx, "Hanya admin yang dapat mengubah role admin.")
+}
+
+// authorizeOwnerOrAdmin lets through the user userID themselves, admins and
+// the system actor.
+func authorizeOwnerOrAdmin(ctx context.Context, userID int, msg string) *response.ErrorMsg {
+	info, errMsg := caller(ctx)
+	if errMsg != nil {
+		return errMsg
+	}
+	if info != nil && info.ID != userID && !info.IsAdmin() {
+		return helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, msg)
+	}
+	return nil
+}
+
+// isClassLecturer reports whether the caller teaches classID: admins and the
+// system actor always do, otherwise only a dosen or guru who is a member of
+// the class. It decides both who manages the class and who sees its kode_kelas.
+func isClassLecturer(ctx context.Context, db *sql.DB, m api.MicroServiceServer, classID int) (bool, *response.ErrorMsg) {
+	info, errMsg := caller(ctx)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	if info == nil || info.IsAdmin() {
+		return true, nil
+	}
+	if !info.IsLecturer() {
+		return false, nil
+	}
+	return m.ClassMemberRepository().IsClassMember(ctx, db, classID, info.ID)
+}
+
+// authorizeClassLecturer lets through the callers isClassLecturer accepts for
+// an existing class.
+func authorizeClassLecturer(ctx context.Context, db *sql.DB, m api.MicroServiceServer, classID int) *response.ErrorMsg {
+	if _, isFound, _ := m.ClassRepository().FindClassByID(ctx, db, classID); !isFound {
+		return helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Kelas by ID tidak ditemukan.")
+	}
+	isLecturer, errMsg := isClassLecturer(ctx, db, m, classID)
+	if errMsg != nil {
+		return errMsg
+	}
+	if !isLecturer {
+		return helpers.ToErrorMsg(http.StatusForbidden, exception.ERR_FORBIDDEN, "Anda bukan pengajar di kelas ini.")
+	}
+	return nil
+}
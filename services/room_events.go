@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+)
+
+type RoomEventType string
+
+const (
+	RoomCreated RoomEventType = "created"
+	RoomUpdated RoomEventType = "updated"
+	RoomDeleted RoomEventType = "deleted"
+)
+
+// RoomEvent is published after a room write. Room only carries the ID for RoomDeleted.
+type RoomEvent struct {
+	Type RoomEventType
+	Room *response.RoomResponse
+}
+
+// RoomEvents fans room writes out to every subscriber. A subscriber that does
+// not keep up loses events instead of blocking the write that published them.
+type RoomEvents struct {
+	mu          sync.Mutex
+	subscribers map[chan RoomEvent]struct{}
+}
+
+func NewRoomEvents() *RoomEvents {
+	return &RoomEvents{subscribers: map[chan RoomEvent]struct{}{}}
+}
+
+// roomEvents is shared by every RoomService of the process, so writes made
+// through REST reach gRPC watchers as well.
+var roomEvents = NewRoomEvents()
+
+// Subscribe returns a channel of events that is closed once ctx is done.
+func (e *RoomEvents) Subscribe(ctx context.Context) <-chan RoomEvent {
+	events := make(chan RoomEvent, 16)
+
+	e.mu.Lock()
+	e.subscribers[events] = struct{}{}
+	e.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		e.mu.Lock()
+		delete(e.subscribers, events)
+		close(events)
+		e.mu.Unlock()
+	}()
+
+	return events
+}
+
+func (e *RoomEvents) Publish(event RoomEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for subscriber := range e.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
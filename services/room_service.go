@@ -10,6 +10,7 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
 	"net/http"
 	"time"
 )
@@ -17,26 +18,34 @@ import (
 type RoomService interface {
 	InsertRoom(ctx context.Context, r *requests.InsertRoomRequest) (isSuccess bool, errMsg *response.ErrorMsg)
 	UpdateRoom(ctx context.Context, r *requests.UpdateRoomRequest) (isSuccess bool, errMsg *response.ErrorMsg)
-	DeleteRoomByID(ctx context.Context, ID int) (isSuccess bool, errMsg *response.ErrorMsg)
+	DeleteRoomByID(ctx context.Context, ID, version int) (isSuccess bool, errMsg *response.ErrorMsg)
 	FindRoomByID(ctx context.Context, ID int) (r *response.RoomResponse, isValid bool, errMsg *response.ErrorMsg)
+	FindAllRoom(ctx context.Context, r *requests.ListRequest) (rooms []*response.RoomResponse, errMsg *response.ErrorMsg)
+	WatchRoom(ctx context.Context) <-chan RoomEvent
+	FindRoomByLectureIDs(ctx context.Context, lectureIDs []int) (rooms []*response.RoomResponse, errMsg *response.ErrorMsg)
+	FindRoomByClassIDs(ctx context.Context, classIDs []int) (rooms map[int][]*response.RoomResponse, errMsg *response.ErrorMsg)
 }
 
 type RoomServiceImplementation struct {
 	DB             *sql.DB
 	RoomRepository repository.RoomRepository
 	M              api.MicroServiceServer
+	Events         *RoomEvents
 }
 
 func NewRoomServiceImplementation(DB *sql.DB, m api.MicroServiceServer) RoomService {
-	return &RoomServiceImplementation{DB: DB, RoomRepository: m.RoomRepository(), M: m}
+	return &RoomServiceImplementation{DB: DB, RoomRepository: m.RoomRepository(), M: m, Events: roomEvents}
 }
 
-func (l *RoomServiceImplementation) InsertRoom(ctx context.Context, r *requests.InsertRoomRequest) (bool, *response.ErrorMsg) {
+func (l *RoomServiceImplementation) InsertRoom(ctx context.Context, r *requests.InsertRoomRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "RoomService.InsertRoom")
+	defer span.End()
+
 	tx, err := l.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	startRoom, err := time.Parse("2006-01-02 15:04:05", r.StartRoom)
 	if err != nil {
@@ -50,6 +59,10 @@ func (l *RoomServiceImplementation) InsertRoom(ctx context.Context, r *requests.
 	r.StartRoom = startRoom.Format("2006-01-02 15:04:05")
 	r.EndRoom = endRoom.Format("2006-01-02 15:04:05")
 
+	if errMsg := l.requireLecture(ctx, r.LectureID); errMsg != nil {
+		return false, errMsg
+	}
+
 	room := &domain.Room{
 		ID:        r.ID,
 		Name:      r.Name,
@@ -63,21 +76,29 @@ func (l *RoomServiceImplementation) InsertRoom(ctx context.Context, r *requests.
 	if errMsg != nil && !isSuccess {
 		return false, errMsg
 	}
+	r.ID = room.ID
 
+	l.Events.Publish(RoomEvent{Type: RoomCreated, Room: toRoomResponse(room)})
 	return true, nil
 }
 
-func (l *RoomServiceImplementation) UpdateRoom(ctx context.Context, r *requests.UpdateRoomRequest) (bool, *response.ErrorMsg) {
+func (l *RoomServiceImplementation) UpdateRoom(ctx context.Context, r *requests.UpdateRoomRequest) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "RoomService.UpdateRoom")
+	defer span.End()
+
 	tx, err := l.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	_, isIDValid, errMsg := l.RoomRepository.FindRoomByID(ctx, l.DB, r.ID)
 	if errMsg != nil && !isIDValid {
 		return false, errMsg
 	}
+	if errMsg := l.requireLecture(ctx, r.LectureID); errMsg != nil {
+		return false, errMsg
+	}
 
 	room := &domain.Room{
 		ID:        r.ID,
@@ -86,6 +107,7 @@ func (l *RoomServiceImplementation) UpdateRoom(ctx context.Context, r *requests.
 		LectureID: r.LectureID,
 		StartRoom: r.StartRoom,
 		EndRoom:   r.EndRoom,
+		Version:   r.Version,
 	}
 
 	isSuccess, errMsg := l.RoomRepository.UpdateRoom(ctx, tx, room)
@@ -93,37 +115,115 @@ func (l *RoomServiceImplementation) UpdateRoom(ctx context.Context, r *requests.
 		return false, errMsg
 	}
 
+	l.Events.Publish(RoomEvent{Type: RoomUpdated, Room: toRoomResponse(room)})
 	return true, nil
 }
 
-func (l *RoomServiceImplementation) DeleteRoomByID(ctx context.Context, ID int) (bool, *response.ErrorMsg) {
+// requireLecture checks the room's lecture exists, as room.lecture_id must reference a lecture.
+func (l *RoomServiceImplementation) requireLecture(ctx context.Context, lectureID int) *response.ErrorMsg {
+	if _, isLecture, _ := l.M.LectureRepository().FindLectureByID(ctx, l.DB, lectureID); !isLecture {
+		return helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "Dosen dengan ID tersebut tidak ditemukan.")
+	}
+	return nil
+}
+
+func (l *RoomServiceImplementation) DeleteRoomByID(ctx context.Context, ID, version int) (_ bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "RoomService.DeleteRoomByID")
+	defer span.End()
+
 	tx, err := l.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
-	isSuccess, errMsg := l.RoomRepository.DeleteRoomByID(ctx, tx, ID)
+	isSuccess, errMsg := l.RoomRepository.DeleteRoomByID(ctx, tx, ID, version, deletionTime())
 	if errMsg != nil && !isSuccess {
 		return false, errMsg
 	}
 
+	l.Events.Publish(RoomEvent{Type: RoomDeleted, Room: &response.RoomResponse{ID: ID}})
 	return true, nil
 }
 
 func (l *RoomServiceImplementation) FindRoomByID(ctx context.Context, ID int) (r *response.RoomResponse, isValid bool, errMsg *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "RoomService.FindRoomByID")
+	defer span.End()
+
 	room, isIDValid, errMsg := l.RoomRepository.FindRoomByID(ctx, l.DB, ID)
 	if isIDValid {
-		roomResponse := &response.RoomResponse{
-			ID:        room.ID,
-			Name:      room.Name,
-			URL:       room.URL,
-			LectureID: room.LectureID,
-			StartRoom: room.StartRoom,
-			EndRoom:   room.EndRoom,
-		}
-		return roomResponse, true, nil
+		return toRoomResponse(room), true, nil
 	} else {
 		return nil, false, errMsg
 	}
 }
+
+func (l *RoomServiceImplementation) FindAllRoom(ctx context.Context, r *requests.ListRequest) ([]*response.RoomResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "RoomService.FindAllRoom")
+	defer span.End()
+
+	rooms, errMsg := l.RoomRepository.FindAllRoom(ctx, l.DB, listFilter(r))
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	var roomResponses []*response.RoomResponse
+	for _, room := range rooms {
+		roomResponses = append(roomResponses, toRoomResponse(room))
+	}
+	return roomResponses, nil
+}
+
+func (l *RoomServiceImplementation) WatchRoom(ctx context.Context) <-chan RoomEvent {
+	ctx, span := tracing.Start(ctx, "RoomService.WatchRoom")
+	defer span.End()
+
+	return l.Events.Subscribe(ctx)
+}
+
+func (l *RoomServiceImplementation) FindRoomByLectureIDs(ctx context.Context, lectureIDs []int) ([]*response.RoomResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "RoomService.FindRoomByLectureIDs")
+	defer span.End()
+
+	rooms, errMsg := l.RoomRepository.FindRoomByLectureIDs(ctx, l.DB, lectureIDs)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	var roomResponses []*response.RoomResponse
+	for _, room := range rooms {
+		roomResponses = append(roomResponses, toRoomResponse(room))
+	}
+	return roomResponses, nil
+}
+
+func (l *RoomServiceImplementation) FindRoomByClassIDs(ctx context.Context, classIDs []int) (map[int][]*response.RoomResponse, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "RoomService.FindRoomByClassIDs")
+	defer span.End()
+
+	rooms, errMsg := l.RoomRepository.FindRoomByClassIDs(ctx, l.DB, classIDs)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	roomResponses := map[int][]*response.RoomResponse{}
+	for classID, classRooms := range rooms {
+		for _, room := range classRooms {
+			roomResponses[classID] = append(roomResponses[classID], toRoomResponse(room))
+		}
+	}
+	return roomResponses, nil
+}
+
+func toRoomResponse(room *domain.Room) *response.RoomResponse {
+	return &response.RoomResponse{
+		ID:        room.ID,
+		Name:      room.Name,
+		URL:       room.URL,
+		LectureID: room.LectureID,
+		StartRoom: room.StartRoom,
+		EndRoom:   room.EndRoom,
+		Version:   room.Version,
+		Audit:     room.Audit,
+	}
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+func TestRoomService(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	ts.seedUser(t, "Agus Salim", "agus@example.com", "dosen", ts.seedClass(t, "TI-1A"))
+
+	insert := &requests.InsertRoomRequest{Name: "Algoritma", URL: "https://meet.example.com/algo", LectureID: 1, StartRoom: "19-02-2023 20:25", EndRoom: "2023-02-19 21:25:00"}
+	_, errMsg := ts.Room.InsertRoom(ctx, insert)
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+
+	insert.StartRoom = "2023-02-19 20:25:00"
+	insert.LectureID = 99
+	_, errMsg = ts.Room.InsertRoom(ctx, insert)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+
+	insert.LectureID = 1
+	if _, errMsg := ts.Room.InsertRoom(ctx, insert); errMsg != nil {
+		t.Fatalf("InsertRoom: %v", errMsg.Msg)
+	}
+
+	room, _, errMsg := ts.Room.FindRoomByID(ctx, 1)
+	if errMsg != nil {
+		t.Fatalf("FindRoomByID: %v", errMsg.Msg)
+	}
+	if room.URL != insert.URL || room.StartRoom != insert.StartRoom {
+		t.Fatalf("unexpected room %+v", room)
+	}
+
+	update := &requests.UpdateRoomRequest{ID: room.ID, Name: "Struktur Data", URL: room.URL, LectureID: 1, StartRoom: room.StartRoom, EndRoom: room.EndRoom}
+	if _, errMsg := ts.Room.UpdateRoom(ctx, update); errMsg != nil {
+		t.Fatalf("UpdateRoom: %v", errMsg.Msg)
+	}
+	if room, _, _ := ts.Room.FindRoomByID(ctx, room.ID); room.Name != "Struktur Data" || room.Version != 2 {
+		t.Fatalf("expected renamed room at version 2, got %q at %d", room.Name, room.Version)
+	}
+
+	update.Name, update.Version = "Basis Data", 1
+	_, errMsg = ts.Room.UpdateRoom(ctx, update)
+	assertErrorKey(t, errMsg, http.StatusPreconditionFailed, exception.ERR_PRECONDITION_FAILED)
+
+	if _, errMsg := ts.Room.DeleteRoomByID(ctx, room.ID, 0); errMsg != nil {
+		t.Fatalf("DeleteRoomByID: %v", errMsg.Msg)
+	}
+	_, _, errMsg = ts.Room.FindRoomByID(ctx, room.ID)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
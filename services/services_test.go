@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/repository/memory"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+	"github.com/dimassfeb-09/sinaustudio.git/storage"
+)
+
+type testServices struct {
+	Store   *memory.Store
+	M       api.MicroServiceServer
+	Users   services.UsersService
+	Auth    services.AuthService
+	Class   services.ClassService
+	Lecture services.LectureService
+	Room    services.RoomService
+	Matkul  services.MataKuliahService
+	Import  services.ImportService
+	Export  services.ExportService
+	Restore services.RestoreService
+	Purge   services.PurgeService
+	Files   storage.Storage
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+
+	store := memory.NewStore()
+	t.Cleanup(func() { store.DB().Close() })
+
+	m := api.NewMemoryMicroService(store)
+	files := storage.NewLocal(t.TempDir(), storage.LocalPath, nil)
+	return &testServices{
+		Store:   store,
+		M:       m,
+		Users:   services.NewUserServiceImplementation(store.DB(), m),
+		Auth:    services.NewAuthServiceImplementation(store.DB(), m),
+		Class:   services.NewClassServiceImplementation(store.DB(), m),
+		Lecture: services.NewLectureServiceImplementation(store.DB(), m),
+		Room:    services.NewRoomServiceImplementation(store.DB(), m),
+		Matkul:  services.NewMataKuliahServiceImplementation(store.DB(), m),
+		Import:  services.NewImportServiceImplementation(store.DB(), m),
+		Export:  services.NewExportServiceImplementation(store.DB(), m),
+		Restore: services.NewRestoreServiceImplementation(store.DB(), m),
+		Purge:   services.NewPurgeServiceImplementation(store.DB(), m, files),
+		Files:   files,
+	}
+}
+
+func (ts *testServices) seedClass(t *testing.T, name string) int {
+	t.Helper()
+
+	ctx := context.Background()
+	if _, errMsg := ts.Class.AddClass(ctx, &requests.InsertClassRequest{Name: name}); errMsg != nil {
+		t.Fatalf("AddClass(%q): %v", name, errMsg.Msg)
+	}
+	class, _, errMsg := ts.Class.FindClassByName(ctx, name)
+	if errMsg != nil {
+		t.Fatalf("FindClassByName(%q): %v", name, errMsg.Msg)
+	}
+	return class.ID
+}
+
+func (ts *testServices) seedUser(t *testing.T, name, email, role string, classID int) int {
+	t.Helper()
+
+	ctx := context.Background()
+	r := &requests.AuthRegisterRequest{Name: name, Email: email, Password: "rahasia123", Role: role, ClassID: classID}
+	if _, errMsg := ts.Auth.AuthRegisterUser(ctx, r); errMsg != nil {
+		t.Fatalf("AuthRegisterUser(%q): %v", email, errMsg.Msg)
+	}
+	user, errMsg := ts.M.UserRepository().FindUserByEmail(ctx, ts.Store.DB(), email)
+	if errMsg != nil {
+		t.Fatalf("FindUserByEmail(%q): %v", email, errMsg.Msg)
+	}
+	return user.ID
+}
+
+func assertErrorKey(t *testing.T, errMsg *response.ErrorMsg, statusCode int, key string) {
+	t.Helper()
+
+	if errMsg == nil {
+		t.Fatalf("expected error %s (%d), got nil", key, statusCode)
+	}
+	if errMsg.StatusCode != statusCode || errMsg.ErrorKey != key {
+		t.Fatalf("expected error %s (%d), got %s (%d): %v", key, statusCode, errMsg.ErrorKey, errMsg.StatusCode, errMsg.Msg)
+	}
+}
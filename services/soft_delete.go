@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+)
+
+// deletionTime is the deleted_at stamp of one delete, shared by every row it
+// cascades to. It keeps whole seconds only, like a MySQL DATETIME, so a
+// restore can still match the dependents by their stamp.
+func deletionTime() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// deleteLecture soft deletes a lecture together with its rooms and returns
+// those rooms, so RoomDeleted can be published for each of them.
+func deleteLecture(ctx context.Context, tx *sql.Tx, db *sql.DB, M api.MicroServiceServer, lectureID int, deletedAt time.Time) ([]*domain.Room, *response.ErrorMsg) {
+	rooms, errMsg := M.RoomRepository().FindRoomByLectureIDs(ctx, db, []int{lectureID})
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	if _, errMsg := M.RoomRepository().DeleteRoomsByLectureID(ctx, tx, lectureID, deletedAt); errMsg != nil {
+		return nil, errMsg
+	}
+	if _, errMsg := M.LectureRepository().DeleteLectureByID(ctx, tx, lectureID, deletedAt); errMsg != nil {
+		return nil, errMsg
+	}
+	return rooms, nil
+}
+
+// restoreLecture restores a soft deleted lecture and the rooms deleted along with it.
+func restoreLecture(ctx context.Context, tx *sql.Tx, M api.MicroServiceServer, lecture *domain.Lecture) *response.ErrorMsg {
+	if _, errMsg := M.LectureRepository().RestoreLectureByID(ctx, tx, lecture.ID); errMsg != nil {
+		return errMsg
+	}
+	if _, errMsg := M.RoomRepository().RestoreRoomsByLectureID(ctx, tx, lecture.ID, *lecture.DeletedAt); errMsg != nil {
+		return errMsg
+	}
+	return nil
+}
+
+func publishRoomsDeleted(events *RoomEvents, rooms []*domain.Room) {
+	for _, room := range rooms {
+		events.Publish(RoomEvent{Type: RoomDeleted, Room: &response.RoomResponse{ID: room.ID}})
+	}
+}
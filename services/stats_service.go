@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
+)
+
+// StatsService answers the business gauges of the /metrics endpoint.
+type StatsService interface {
+	CountActiveRooms(ctx context.Context) (count int, errMsg *response.ErrorMsg)
+	CountUsers(ctx context.Context) (count int, errMsg *response.ErrorMsg)
+}
+
+type StatsServiceImplementation struct {
+	DB *sql.DB
+	M  api.MicroServiceServer
+}
+
+func NewStatsServiceImplementation(DB *sql.DB, m api.MicroServiceServer) StatsService {
+	return &StatsServiceImplementation{DB: DB, M: m}
+}
+
+// CountActiveRooms counts the rooms taking place right now.
+func (s *StatsServiceImplementation) CountActiveRooms(ctx context.Context) (int, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "StatsService.CountActiveRooms")
+	defer span.End()
+
+	return s.M.RoomRepository().CountActiveRooms(ctx, s.DB, time.Now())
+}
+
+// CountUsers counts the registered users that are not deleted.
+func (s *StatsServiceImplementation) CountUsers(ctx context.Context) (int, *response.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "StatsService.CountUsers")
+	defer span.End()
+
+	return s.M.UserRepository().CountUsers(ctx, s.DB)
+}
@@ -0,0 +1,169 @@
+package services_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/response"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+	"github.com/dimassfeb-09/sinaustudio.git/helpers"
+	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+)
+
+// failingMembers fails adding a class member, which runs after the user is written.
+type failingMembers struct {
+	repository.ClassMemberRepository
+}
+
+func (failingMembers) AddClassMember(ctx context.Context, tx *sql.Tx, member *domain.ClassMember) (bool, *response.ErrorMsg) {
+	return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Tambah anggota kelas gagal.")
+}
+
+// failingProfiles fails saving a profile, which runs after the user is written.
+type failingProfiles struct{ repository.ProfileRepository }
+
+func (failingProfiles) InsertProfile(ctx context.Context, tx *sql.Tx, profile *domain.Profile) (bool, *response.ErrorMsg) {
+	return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Simpan profil gagal.")
+}
+
+func (failingProfiles) UpdateProfile(ctx context.Context, tx *sql.Tx, profile *domain.Profile) (bool, *response.ErrorMsg) {
+	return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Simpan profil gagal.")
+}
+
+// failingLectureInsert fails inserting a lecture, which runs after the user is written.
+type failingLectureInsert struct{ repository.LectureRepository }
+
+func (failingLectureInsert) InsertLecture(ctx context.Context, tx *sql.Tx, lecture *domain.Lecture) (bool, *response.ErrorMsg) {
+	return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, "Tambah dosen gagal.")
+}
+
+func TestRegisterRollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+
+	tests := []struct {
+		name string
+		fail func(m *api.MicroService)
+		r    *requests.AuthRegisterRequest
+	}{
+		{
+			name: "class member",
+			fail: func(m *api.MicroService) { m.Member = failingMembers{m.Member} },
+			r:    &requests.AuthRegisterRequest{Name: "Budi Santoso", Email: "budi@mail.com", Password: "rahasia123", Role: "mahasiswa", ClassID: classID},
+		},
+		{
+			name: "profile",
+			fail: func(m *api.MicroService) { m.Profile = failingProfiles{m.Profile} },
+			r: &requests.AuthRegisterRequest{Name: "Budi Santoso", Email: "budi@mail.com", Password: "rahasia123", Role: "mahasiswa", ClassID: classID,
+				Profile: &requests.ProfileRequest{NPM: "2405100001"}},
+		},
+		{
+			name: "lecture",
+			fail: func(m *api.MicroService) { m.Lecture = failingLectureInsert{m.Lecture} },
+			r:    &requests.AuthRegisterRequest{Name: "Dimas Saputra", Email: "dimas@mail.com", Password: "rahasia123", Role: "dosen", ClassID: classID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failing := *ts.M.(*api.MicroService)
+			tt.fail(&failing)
+			auth := services.NewAuthServiceImplementation(ts.Store.DB(), &failing)
+
+			if _, errMsg := auth.AuthRegisterUser(ctx, tt.r); errMsg == nil {
+				t.Fatal("expected AuthRegisterUser to fail")
+			}
+			if _, errMsg := ts.Users.FindUserByEmail(ctx, tt.r.Email); errMsg == nil {
+				t.Fatalf("expected no user %s after the failed register", tt.r.Email)
+			}
+		})
+	}
+}
+
+func TestUserWritesRollBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+	otherClassID := ts.seedClass(t, "TI-2B")
+	userID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", classID)
+
+	assertUserUnchanged := func(t *testing.T) {
+		t.Helper()
+		user, errMsg := ts.Users.FindUserByID(ctx, userID)
+		if errMsg != nil {
+			t.Fatalf("FindUserByID: %v", errMsg.Msg)
+		}
+		if user.Name != "Siti Aminah" || user.Email != "siti@mail.com" || user.ClassID != classID {
+			t.Fatalf("expected the user untouched, got %+v", user)
+		}
+		if isMember, _ := ts.M.ClassMemberRepository().IsClassMember(ctx, ts.Store.DB(), classID, userID); !isMember {
+			t.Fatal("expected the user still in their class")
+		}
+	}
+
+	failingMember := *ts.M.(*api.MicroService)
+	failingMember.Member = failingMembers{failingMember.Member}
+	users := services.NewUserServiceImplementation(ts.Store.DB(), &failingMember)
+
+	t.Run("insert", func(t *testing.T) {
+		insert := &requests.UserInsertRequest{Name: "Rudi Hartono", Email: "rudi@mail.com", Password: "rahasia123", Role: "mahasiswa", ClassID: classID}
+		_, errMsg := users.InsertDataUser(ctx, insert)
+		assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER)
+		if _, errMsg := ts.Users.FindUserByEmail(ctx, "rudi@mail.com"); errMsg == nil {
+			t.Fatal("expected no user after the failed insert")
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		update := &requests.UserUpdateRequest{ID: userID, Name: "Siti Rahma", Email: "siti.rahma@mail.com", Role: "mahasiswa", ClassID: otherClassID}
+		_, errMsg := users.UpdateDataUser(ctx, update)
+		assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER)
+		assertUserUnchanged(t)
+	})
+
+	t.Run("patch class", func(t *testing.T) {
+		name := "Siti Rahma"
+		_, errMsg := users.PatchDataUser(ctx, userID, &requests.UserPatchRequest{Name: &name, ClassID: &otherClassID})
+		assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER)
+		assertUserUnchanged(t)
+	})
+
+	t.Run("patch profile", func(t *testing.T) {
+		failingProfile := *ts.M.(*api.MicroService)
+		failingProfile.Profile = failingProfiles{failingProfile.Profile}
+		users := services.NewUserServiceImplementation(ts.Store.DB(), &failingProfile)
+
+		owner := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: userID, Role: "mahasiswa"})
+		name, npm := "Siti Rahma", "2405100001"
+		_, errMsg := users.PatchDataUser(owner, userID, &requests.UserPatchRequest{Name: &name, Profile: &requests.ProfilePatchRequest{NPM: &npm}})
+		assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER)
+		assertUserUnchanged(t)
+	})
+}
+
+func TestSetRoleRollsBackOnFailure(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+	userID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", classID)
+
+	failing := *ts.M.(*api.MicroService)
+	failing.Lecture = failingLectureInsert{failing.Lecture}
+	users := services.NewUserServiceImplementation(ts.Store.DB(), &failing)
+
+	_, errMsg := users.SetRole(ctx, userID, "dosen")
+	assertErrorKey(t, errMsg, http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER)
+	user, errMsg := ts.Users.FindUserByID(ctx, userID)
+	if errMsg != nil {
+		t.Fatalf("FindUserByID: %v", errMsg.Msg)
+	}
+	if user.Role != "mahasiswa" {
+		t.Fatalf("expected the role kept as mahasiswa, got %q", user.Role)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/api"
 	"golang.org/x/crypto/bcrypt"
 	"net/http"
+	"strings"
 
 	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
 	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
@@ -13,6 +14,7 @@ import (
 	"github.com/dimassfeb-09/sinaustudio.git/exception"
 	"github.com/dimassfeb-09/sinaustudio.git/helpers"
 	"github.com/dimassfeb-09/sinaustudio.git/repository"
+	"github.com/dimassfeb-09/sinaustudio.git/tracing"
 )
 
 type UsersServiceImplementation struct {
@@ -32,29 +34,52 @@ func NewUserServiceImplementation(DB *sql.DB, M api.MicroServiceServer) UsersSer
 type UsersService interface {
 	InsertDataUser(ctx context.Context, r *requests.UserInsertRequest) (bool, *responseError.ErrorMsg)
 	UpdateDataUser(ctx context.Context, r *requests.UserUpdateRequest) (bool, *responseError.ErrorMsg)
+	PatchDataUser(ctx context.Context, ID int, r *requests.UserPatchRequest) (bool, *responseError.ErrorMsg)
 	DeleteDataUser(ctx context.Context, confirmPass string, ID int) (bool, *responseError.ErrorMsg)
 	FindUserByID(ctx context.Context, ID int) (*domain.Users, *responseError.ErrorMsg)
+	FindUserByEmail(ctx context.Context, email string) (*domain.Users, *responseError.ErrorMsg)
 	IsEmailRegistered(ctx context.Context, email string) (isRegistered bool, errMsg *responseError.ErrorMsg)
-	ChangePasswordUser(ctx context.Context, ID int, recentPass string, newPass string) (isSuccess bool, errNsg *responseError.ErrorMsg)
+	ChangePasswordUser(ctx context.Context, ID int, recentPass string, newPass string) (isSuccess bool, errMsg *responseError.ErrorMsg)
+	FindUsersByIDs(ctx context.Context, IDs []int) (users []*responseError.UserResponse, errMsg *responseError.ErrorMsg)
+	FindUsersByClassIDs(ctx context.Context, classIDs []int) (users []*responseError.UserResponse, errMsg *responseError.ErrorMsg)
+	ResetPassword(ctx context.Context, ID int, newPass string) (isSuccess bool, errMsg *responseError.ErrorMsg)
+	SetRole(ctx context.Context, ID int, role string) (user *domain.Users, errMsg *responseError.ErrorMsg)
+	FindUserProfile(ctx context.Context, ID int) (profile *domain.Profile, errMsg *responseError.ErrorMsg)
+	FindAllUsers(ctx context.Context, r *requests.UserListRequest) (users []*responseError.UserResponse, errMsg *responseError.ErrorMsg)
 }
 
-func (U *UsersServiceImplementation) InsertDataUser(ctx context.Context, r *requests.UserInsertRequest) (bool, *responseError.ErrorMsg) {
+func (U *UsersServiceImplementation) InsertDataUser(ctx context.Context, r *requests.UserInsertRequest) (_ bool, errMsg *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.InsertDataUser")
+	defer span.End()
+
 	tx, err := U.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	hashPassword, err := helpers.HashAndSaltPassword([]byte(r.Password))
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
 
+	if errMsg := authorizeRoleChange(ctx, "", r.Role); errMsg != nil {
+		return false, errMsg
+	}
+
 	_, isEmailRegistered, _ := U.UsersRepository.IsEmailRegistered(ctx, U.DB, r.Email)
 	if isEmailRegistered {
 		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "Email sudah digunakan")
 	}
 
+	var profile *domain.Profile
+	if patch := profilePatch(r.Profile); patch != nil {
+		var errMsg *responseError.ErrorMsg
+		if profile, _, errMsg = prepareProfile(ctx, U.DB, U.M, 0, r.Role, patch); errMsg != nil {
+			return false, errMsg
+		}
+	}
+
 	user := &domain.Users{
 		ID:       r.ID,
 		Name:     r.Name,
@@ -64,20 +89,36 @@ func (U *UsersServiceImplementation) InsertDataUser(ctx context.Context, r *requ
 		ClassID:  r.ClassID,
 	}
 
-	_, errMsg := U.UsersRepository.InsertDataUser(ctx, tx, user)
+	_, errMsg = U.UsersRepository.InsertDataUser(ctx, tx, user)
 	if errMsg != nil {
 		return false, errMsg
 	}
+	if profile != nil {
+		profile.UserID = user.ID
+		if errMsg := saveProfile(ctx, tx, U.M, profile, false); errMsg != nil {
+			return false, errMsg
+		}
+	}
+	// Only admins created from the CLI come without a class.
+	if user.ClassID != 0 {
+		if errMsg := addClassMember(ctx, tx, U.M, user.ClassID, user.ID); errMsg != nil {
+			return false, errMsg
+		}
+	}
+	r.ID = user.ID
 
 	return true, nil
 }
 
-func (U *UsersServiceImplementation) UpdateDataUser(ctx context.Context, r *requests.UserUpdateRequest) (bool, *responseError.ErrorMsg) {
+func (U *UsersServiceImplementation) UpdateDataUser(ctx context.Context, r *requests.UserUpdateRequest) (_ bool, errMsg *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.UpdateDataUser")
+	defer span.End()
+
 	tx, err := U.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	response, isEmailRegistered, _ := U.UsersRepository.IsEmailRegistered(ctx, U.DB, r.Email)
 	if isEmailRegistered {
@@ -88,28 +129,111 @@ func (U *UsersServiceImplementation) UpdateDataUser(ctx context.Context, r *requ
 		}
 	}
 
+	current, isUserRegistered, _ := U.UsersRepository.FindUserByID(ctx, U.DB, r.ID)
+	if !isUserRegistered {
+		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "ID tidak ditemukan.")
+	}
+	if errMsg := authorizeRoleChange(ctx, current.Role, r.Role); errMsg != nil {
+		return false, errMsg
+	}
+
 	user := &domain.Users{
 		ID:      r.ID,
 		Name:    r.Name,
 		Email:   r.Email,
 		Role:    r.Role,
 		ClassID: r.ClassID,
+		Version: r.Version,
+	}
+
+	_, errMsg = U.UsersRepository.UpdateDataUser(ctx, tx, user)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	if errMsg := moveClassMember(ctx, tx, U.DB, U.M, user.ID, current.ClassID, user.ClassID); errMsg != nil {
+		return false, errMsg
+	}
+
+	return true, nil
+}
+
+// PatchDataUser only changes the fields set in r. Unlike UpdateDataUser, keeping
+// the current email is allowed.
+func (U *UsersServiceImplementation) PatchDataUser(ctx context.Context, ID int, r *requests.UserPatchRequest) (_ bool, errMsg *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.PatchDataUser")
+	defer span.End()
+
+	tx, err := U.DB.Begin()
+	if err != nil {
+		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	user, isUserRegistered, _ := U.UsersRepository.FindUserByID(ctx, U.DB, ID)
+	if !isUserRegistered {
+		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "ID tidak ditemukan.")
 	}
 
-	_, errMsg := U.UsersRepository.UpdateDataUser(ctx, tx, user)
+	if r.Email != nil && *r.Email != user.Email {
+		_, isEmailRegistered, _ := U.UsersRepository.IsEmailRegistered(ctx, U.DB, *r.Email)
+		if isEmailRegistered {
+			return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_ALREADY_USE, "Email sudah digunakan.")
+		}
+		user.Email = *r.Email
+	}
+	if r.Name != nil {
+		user.Name = *r.Name
+	}
+	if r.Role != nil {
+		if errMsg := authorizeRoleChange(ctx, user.Role, *r.Role); errMsg != nil {
+			return false, errMsg
+		}
+		user.Role = *r.Role
+	}
+	previousClassID := user.ClassID
+	if r.ClassID != nil {
+		user.ClassID = *r.ClassID
+	}
+	user.Version = r.Version
+
+	var profile *domain.Profile
+	var isProfileStored bool
+	if r.Profile != nil {
+		if errMsg := authorizeOwnerOrAdmin(ctx, user.ID, "Hanya pemilik akun atau admin yang dapat mengubah profil."); errMsg != nil {
+			return false, errMsg
+		}
+		var errMsg *responseError.ErrorMsg
+		if profile, isProfileStored, errMsg = prepareProfile(ctx, U.DB, U.M, user.ID, user.Role, r.Profile); errMsg != nil {
+			return false, errMsg
+		}
+	}
+
+	_, errMsg = U.UsersRepository.UpdateDataUser(ctx, tx, user)
 	if errMsg != nil {
 		return false, errMsg
 	}
+	if errMsg := moveClassMember(ctx, tx, U.DB, U.M, user.ID, previousClassID, user.ClassID); errMsg != nil {
+		return false, errMsg
+	}
+	// The profile shares the version of its user, which the update above bumped.
+	if profile != nil {
+		if errMsg := saveProfile(ctx, tx, U.M, profile, isProfileStored); errMsg != nil {
+			return false, errMsg
+		}
+	}
 
 	return true, nil
 }
 
-func (U *UsersServiceImplementation) DeleteDataUser(ctx context.Context, confirmPass string, ID int) (bool, *responseError.ErrorMsg) {
+func (U *UsersServiceImplementation) DeleteDataUser(ctx context.Context, confirmPass string, ID int) (_ bool, errMsg *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.DeleteDataUser")
+	defer span.End()
+
 	tx, err := U.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	user, isUserRegistered, errMsg := U.UsersRepository.FindUserByID(ctx, U.DB, ID)
 	if !isUserRegistered {
@@ -122,11 +246,21 @@ func (U *UsersServiceImplementation) DeleteDataUser(ctx context.Context, confirm
 			return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Password tidak sesuai.")
 		}
 
-		isSuccess, errMsg := U.UsersRepository.DeleteDataUser(ctx, tx, user.ID)
+		// The memberships stay so a restore brings the user back into their classes.
+		deletedAt := deletionTime()
+		var rooms []*domain.Room
+		if lecture, isLecture, _ := U.M.LectureRepository().FindLectureByUserID(ctx, U.DB, user.ID); isLecture {
+			if rooms, errMsg = deleteLecture(ctx, tx, U.DB, U.M, lecture.ID, deletedAt); errMsg != nil {
+				return false, errMsg
+			}
+		}
+
+		isSuccess, errMsg := U.UsersRepository.DeleteDataUser(ctx, tx, user.ID, deletedAt)
 		if errMsg != nil && !isSuccess {
 			return false, errMsg
 		}
 
+		publishRoomsDeleted(roomEvents, rooms)
 		return isSuccess, nil
 	} else {
 		return false, errMsg
@@ -134,6 +268,9 @@ func (U *UsersServiceImplementation) DeleteDataUser(ctx context.Context, confirm
 }
 
 func (U *UsersServiceImplementation) FindUserByID(ctx context.Context, ID int) (*domain.Users, *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.FindUserByID")
+	defer span.End()
+
 	response, isIDRegistered, errMsg := U.UsersRepository.FindUserByID(ctx, U.DB, ID)
 	if errMsg != nil {
 		return nil, errMsg
@@ -146,7 +283,17 @@ func (U *UsersServiceImplementation) FindUserByID(ctx context.Context, ID int) (
 	return response, nil
 }
 
+func (U *UsersServiceImplementation) FindUserByEmail(ctx context.Context, email string) (*domain.Users, *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.FindUserByEmail")
+	defer span.End()
+
+	return U.UsersRepository.FindUserByEmail(ctx, U.DB, email)
+}
+
 func (U *UsersServiceImplementation) IsEmailRegistered(ctx context.Context, email string) (isRegistered bool, errMsg *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.IsEmailRegistered")
+	defer span.End()
+
 	_, isEmailRegistered, errMsg := U.UsersRepository.IsEmailRegistered(ctx, U.DB, email)
 	if errMsg != nil {
 		return false, errMsg
@@ -159,12 +306,15 @@ func (U *UsersServiceImplementation) IsEmailRegistered(ctx context.Context, emai
 	return true, nil
 }
 
-func (U *UsersServiceImplementation) ChangePasswordUser(ctx context.Context, ID int, recentPass string, newPass string) (isSuccess bool, errNsg *responseError.ErrorMsg) {
+func (U *UsersServiceImplementation) ChangePasswordUser(ctx context.Context, ID int, recentPass string, newPass string) (isSuccess bool, errMsg *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.ChangePasswordUser")
+	defer span.End()
+
 	tx, err := U.DB.Begin()
 	if err != nil {
 		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
 	}
-	defer helpers.RollbackOrCommit(tx)
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
 
 	user, isUserRegistered, errMsg := U.UsersRepository.FindUserByID(ctx, U.DB, ID)
 	if !isUserRegistered && errMsg != nil {
@@ -192,3 +342,161 @@ func (U *UsersServiceImplementation) ChangePasswordUser(ctx context.Context, ID
 		return false, errMsg
 	}
 }
+
+func (u *UsersServiceImplementation) FindUsersByIDs(ctx context.Context, IDs []int) ([]*responseError.UserResponse, *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.FindUsersByIDs")
+	defer span.End()
+
+	users, errMsg := u.UsersRepository.FindUsersByIDs(ctx, u.DB, IDs)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return toUserResponses(users), nil
+}
+
+func (u *UsersServiceImplementation) FindUsersByClassIDs(ctx context.Context, classIDs []int) ([]*responseError.UserResponse, *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.FindUsersByClassIDs")
+	defer span.End()
+
+	users, errMsg := u.UsersRepository.FindUsersByClassIDs(ctx, u.DB, classIDs)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	return toUserResponses(users), nil
+}
+
+// ResetPassword sets a new password without asking for the current one, for
+// users who lost theirs. Only admins and the CLI may do so.
+func (U *UsersServiceImplementation) ResetPassword(ctx context.Context, ID int, newPass string) (_ bool, errMsg *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.ResetPassword")
+	defer span.End()
+
+	if errMsg := authorizeAdmin(ctx, "Hanya admin yang dapat mereset password."); errMsg != nil {
+		return false, errMsg
+	}
+	if len(newPass) < 6 {
+		return false, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Password minimal 6 karakter.")
+	}
+
+	user, isUserRegistered, errMsg := U.UsersRepository.FindUserByID(ctx, U.DB, ID)
+	if errMsg != nil {
+		return false, errMsg
+	}
+	if !isUserRegistered {
+		return false, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "ID tidak ditemukan.")
+	}
+
+	_, hashSpan := tracing.Start(ctx, "bcrypt.GenerateFromPassword")
+	hashNewPass, err := helpers.HashAndSaltPassword([]byte(newPass))
+	hashSpan.End()
+	if err != nil {
+		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+
+	tx, err := U.DB.Begin()
+	if err != nil {
+		return false, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	return U.UsersRepository.ChangePasswordUser(ctx, tx, hashNewPass, user.ID)
+}
+
+// SetRole changes the role of a user. Only admins and the CLI may do so, since
+// it is the one way to grant the admin role. A user who becomes dosen gets a
+// lecture like one who registered as dosen; one who stops being dosen keeps
+// theirs so their rooms stay intact.
+func (U *UsersServiceImplementation) SetRole(ctx context.Context, ID int, role string) (_ *domain.Users, errMsg *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.SetRole")
+	defer span.End()
+
+	if errMsg := authorizeAdmin(ctx, "Hanya admin yang dapat mengubah role."); errMsg != nil {
+		return nil, errMsg
+	}
+	if !IsRole(role) {
+		return nil, helpers.ToErrorMsg(http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD, "Role harus salah satu dari "+strings.Join(Roles, ", ")+".")
+	}
+
+	user, isUserRegistered, errMsg := U.UsersRepository.FindUserByID(ctx, U.DB, ID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	if !isUserRegistered {
+		return nil, helpers.ToErrorMsg(http.StatusNotFound, exception.ERR_NOT_FOUND, "ID tidak ditemukan.")
+	}
+	if user.Role == role {
+		return user, nil
+	}
+	_, hasLecture, errMsg := U.M.LectureRepository().FindLectureByUserID(ctx, U.DB, user.ID)
+	if errMsg != nil && errMsg.StatusCode != http.StatusNotFound {
+		return nil, errMsg
+	}
+
+	tx, err := U.DB.Begin()
+	if err != nil {
+		return nil, helpers.ToErrorMsg(http.StatusInternalServerError, exception.ERR_INTERNAL_SERVER, err)
+	}
+	defer helpers.RollbackOrCommit(ctx, tx, &errMsg)
+
+	user.Role = role
+	user.Version = 0
+	if _, errMsg := U.UsersRepository.UpdateDataUser(ctx, tx, user); errMsg != nil {
+		return nil, errMsg
+	}
+	if role == domain.RoleDosen && !hasLecture {
+		if _, errMsg := U.M.LectureRepository().InsertLecture(ctx, tx, &domain.Lecture{Name: user.Name, UserID: user.ID}); errMsg != nil {
+			return nil, errMsg
+		}
+	}
+
+	return user, nil
+}
+
+// FindUserProfile returns the profile of the user, empty when none was saved.
+func (U *UsersServiceImplementation) FindUserProfile(ctx context.Context, ID int) (*domain.Profile, *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.FindUserProfile")
+	defer span.End()
+
+	profile, _, errMsg := findProfile(ctx, U.DB, U.M, ID)
+	return profile, errMsg
+}
+
+// FindAllUsers lists the users matching r with their profile.
+func (U *UsersServiceImplementation) FindAllUsers(ctx context.Context, r *requests.UserListRequest) ([]*responseError.UserResponse, *responseError.ErrorMsg) {
+	ctx, span := tracing.Start(ctx, "UsersService.FindAllUsers")
+	defer span.End()
+
+	filter := listFilter(&r.ListRequest)
+	filter.Profile = profileFilter(&r.ProfileListRequest)
+	users, errMsg := U.UsersRepository.FindAllUsers(ctx, U.DB, r.Role, filter)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	IDs := make([]int, len(users))
+	for i, user := range users {
+		IDs[i] = user.ID
+	}
+	profiles, errMsg := U.M.ProfileRepository().FindProfilesByUserIDs(ctx, U.DB, IDs)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	userResponses := make([]*responseError.UserResponse, len(users))
+	for i, user := range users {
+		profile, ok := profiles[user.ID]
+		if !ok {
+			profile = &domain.Profile{UserID: user.ID}
+		}
+		userResponses[i] = &responseError.UserResponse{ID: user.ID, Name: user.Name, Email: user.Email, Role: user.Role, ClassID: user.ClassID, Version: user.Version, Profile: profile, Audit: user.Audit}
+	}
+	return userResponses, nil
+}
+
+func toUserResponses(users []*domain.Users) []*responseError.UserResponse {
+	var userResponses []*responseError.UserResponse
+	for _, user := range users {
+		userResponses = append(userResponses, &responseError.UserResponse{ID: user.ID, Name: user.Name, Email: user.Email, Role: user.Role, ClassID: user.ClassID})
+	}
+	return userResponses
+}
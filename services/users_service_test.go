@@ -0,0 +1,191 @@
+package services_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/exception"
+)
+
+func TestUsersServiceInsertAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+
+	insert := &requests.UserInsertRequest{Name: "Rudi Hartono", Email: "rudi@mail.com", Password: "rahasia123", Role: "mahasiswa", ClassID: classID}
+	if _, errMsg := ts.Users.InsertDataUser(ctx, insert); errMsg != nil {
+		t.Fatalf("InsertDataUser: %v", errMsg.Msg)
+	}
+	_, errMsg := ts.Users.InsertDataUser(ctx, insert)
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_ALREADY_USE)
+
+	user, errMsg := ts.M.UserRepository().FindUserByEmail(ctx, ts.Store.DB(), "rudi@mail.com")
+	if errMsg != nil {
+		t.Fatalf("FindUserByEmail: %v", errMsg.Msg)
+	}
+
+	update := &requests.UserUpdateRequest{ID: user.ID, Name: "Rudi Hartono", Email: "rudi@mail.com", Role: "mahasiswa", ClassID: classID}
+	_, errMsg = ts.Users.UpdateDataUser(ctx, update)
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_PREVIOUS_FIELD_NOT_ALLOWED)
+
+	update.Email = "rudi.hartono@mail.com"
+	if _, errMsg := ts.Users.UpdateDataUser(ctx, update); errMsg != nil {
+		t.Fatalf("UpdateDataUser: %v", errMsg.Msg)
+	}
+	if isRegistered, _ := ts.Users.IsEmailRegistered(ctx, "rudi.hartono@mail.com"); !isRegistered {
+		t.Fatal("expected updated email to be registered")
+	}
+}
+
+func TestUsersServicePasswordAndDelete(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+	userID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", classID)
+
+	_, errMsg := ts.Users.ChangePasswordUser(ctx, userID, "salah12345", "baru123456")
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+
+	if _, errMsg := ts.Users.ChangePasswordUser(ctx, userID, "rahasia123", "baru123456"); errMsg != nil {
+		t.Fatalf("ChangePasswordUser: %v", errMsg.Msg)
+	}
+	if _, errMsg := ts.Auth.AuthLoginUser(ctx, "siti@mail.com", "baru123456"); errMsg != nil {
+		t.Fatalf("login with new password: %v", errMsg.Msg)
+	}
+
+	_, errMsg = ts.Users.DeleteDataUser(ctx, "rahasia123", userID)
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+
+	if _, errMsg := ts.Users.DeleteDataUser(ctx, "baru123456", userID); errMsg != nil {
+		t.Fatalf("DeleteDataUser: %v", errMsg.Msg)
+	}
+	_, errMsg = ts.Users.FindUserByID(ctx, userID)
+	assertErrorKey(t, errMsg, http.StatusNotFound, exception.ERR_NOT_FOUND)
+}
+
+func TestUsersServiceAdminOperations(t *testing.T) {
+	ctx := api.ContextWithSystemActor(context.Background())
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+	userID := ts.seedUser(t, "Siti Aminah", "siti@mail.com", "mahasiswa", classID)
+
+	_, errMsg := ts.Auth.AuthRegisterUser(ctx, &requests.AuthRegisterRequest{Name: "Admin Palsu", Email: "admin@mail.com", Password: "rahasia123", Role: "admin", ClassID: classID})
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+
+	student := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: userID, Role: "mahasiswa"})
+	_, errMsg = ts.Users.ResetPassword(student, userID, "baru123456")
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+	_, errMsg = ts.Users.SetRole(student, userID, "admin")
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+	role := "admin"
+	_, errMsg = ts.Users.PatchDataUser(student, userID, &requests.UserPatchRequest{Role: &role})
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+
+	// A ctx with neither a user nor the system actor is not taken for the CLI.
+	_, errMsg = ts.Users.ResetPassword(context.Background(), userID, "baru123456")
+	assertErrorKey(t, errMsg, http.StatusUnauthorized, exception.ERR_UNAUTHORIZED_BEARER)
+
+	_, errMsg = ts.Users.SetRole(ctx, userID, "rektor")
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+
+	if _, errMsg := ts.Users.ResetPassword(ctx, userID, "baru123456"); errMsg != nil {
+		t.Fatalf("ResetPassword: %v", errMsg.Msg)
+	}
+	if _, errMsg := ts.Auth.AuthLoginUser(ctx, "siti@mail.com", "baru123456"); errMsg != nil {
+		t.Fatalf("login with reset password: %v", errMsg.Msg)
+	}
+
+	user, errMsg := ts.Users.SetRole(ctx, userID, "dosen")
+	if errMsg != nil {
+		t.Fatalf("SetRole: %v", errMsg.Msg)
+	}
+	if user.Role != "dosen" {
+		t.Fatalf("expected role dosen, got %q", user.Role)
+	}
+	if _, hasLecture, _ := ts.M.LectureRepository().FindLectureByUserID(ctx, ts.Store.DB(), userID); !hasLecture {
+		t.Fatal("expected a lecture for the new dosen")
+	}
+
+	insert := &requests.UserInsertRequest{Name: "Admin Sinau", Email: "admin@mail.com", Password: "rahasia123", Role: "admin"}
+	if _, errMsg := ts.Users.InsertDataUser(ctx, insert); errMsg != nil {
+		t.Fatalf("InsertDataUser admin without class: %v", errMsg.Msg)
+	}
+	admin := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: insert.ID, Role: "admin"})
+	if _, errMsg := ts.Users.SetRole(admin, userID, "mahasiswa"); errMsg != nil {
+		t.Fatalf("SetRole by admin: %v", errMsg.Msg)
+	}
+}
+
+func TestUsersServiceProfile(t *testing.T) {
+	ctx := context.Background()
+	ts := newTestServices(t)
+	classID := ts.seedClass(t, "TI-2A")
+
+	register := &requests.AuthRegisterRequest{Name: "Siti Aminah", Email: "siti@mail.com", Password: "rahasia123", Role: "mahasiswa", ClassID: classID,
+		Profile: &requests.ProfileRequest{NPM: "2405100001", ProgramStudi: "Teknik Informatika", Angkatan: 2024}}
+	if _, errMsg := ts.Auth.AuthRegisterUser(ctx, register); errMsg != nil {
+		t.Fatalf("AuthRegisterUser: %v", errMsg.Msg)
+	}
+	siti, errMsg := ts.Users.FindUserByEmail(ctx, "siti@mail.com")
+	if errMsg != nil {
+		t.Fatalf("FindUserByEmail: %v", errMsg.Msg)
+	}
+	sitiID := siti.ID
+
+	taken := *register
+	taken.Email, taken.Profile = "budi@mail.com", &requests.ProfileRequest{NPM: "2405100001"}
+	_, errMsg = ts.Auth.AuthRegisterUser(ctx, &taken)
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_ALREADY_USE)
+	if _, errMsg := ts.Users.FindUserByEmail(ctx, "budi@mail.com"); errMsg == nil {
+		t.Fatal("expected no user for a rejected profile")
+	}
+
+	lecturer := &requests.UserInsertRequest{Name: "Dimas Saputra", Email: "dimas@mail.com", Password: "rahasia123", Role: "dosen", ClassID: classID,
+		Profile: &requests.ProfileRequest{NPM: "2405100002"}}
+	_, errMsg = ts.Users.InsertDataUser(ctx, lecturer)
+	assertErrorKey(t, errMsg, http.StatusBadRequest, exception.ERR_BAD_REQUEST_FIELD)
+	lecturer.ID = ts.seedUser(t, "Dimas Saputra", "dimas@mail.com", "dosen", classID)
+	nidn, phone := "0412345678", "+62 812 3456 7890"
+	lecturerCtx := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: lecturer.ID, Role: "dosen"})
+	if _, errMsg := ts.Users.PatchDataUser(lecturerCtx, lecturer.ID, &requests.UserPatchRequest{Profile: &requests.ProfilePatchRequest{NIDN: &nidn, Phone: &phone}}); errMsg != nil {
+		t.Fatalf("PatchDataUser: %v", errMsg.Msg)
+	}
+
+	angkatan := 2023
+	_, errMsg = ts.Users.PatchDataUser(lecturerCtx, sitiID, &requests.UserPatchRequest{Profile: &requests.ProfilePatchRequest{Angkatan: &angkatan}})
+	assertErrorKey(t, errMsg, http.StatusForbidden, exception.ERR_FORBIDDEN)
+	owner := api.ContextWithUserInfo(ctx, &api.UserInfo{ID: sitiID, Role: "mahasiswa"})
+	cleared := ""
+	if _, errMsg := ts.Users.PatchDataUser(owner, sitiID, &requests.UserPatchRequest{Profile: &requests.ProfilePatchRequest{Angkatan: &angkatan, ProgramStudi: &cleared}}); errMsg != nil {
+		t.Fatalf("PatchDataUser: %v", errMsg.Msg)
+	}
+
+	profile, errMsg := ts.Users.FindUserProfile(ctx, sitiID)
+	if errMsg != nil {
+		t.Fatalf("FindUserProfile: %v", errMsg.Msg)
+	}
+	if profile.NPM != "2405100001" || profile.Angkatan != 2023 || profile.ProgramStudi != "" {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+	if profile, _ := ts.Users.FindUserProfile(ctx, lecturer.ID); profile.Phone != "+6281234567890" {
+		t.Fatalf("expected the phone number in +62 form, got %q", profile.Phone)
+	}
+
+	users, errMsg := ts.Users.FindAllUsers(ctx, &requests.UserListRequest{ProfileListRequest: requests.ProfileListRequest{NIDN: "0412345678"}})
+	if errMsg != nil {
+		t.Fatalf("FindAllUsers: %v", errMsg.Msg)
+	}
+	if len(users) != 1 || users[0].ID != lecturer.ID || users[0].Profile.NIDN != "0412345678" {
+		t.Fatalf("expected the lecturer by NIDN, got %+v", users)
+	}
+	lectures, errMsg := ts.Lecture.FindAllLecture(ctx, &requests.LectureListRequest{ProfileListRequest: requests.ProfileListRequest{NIDN: "0412345678"}})
+	if errMsg != nil {
+		t.Fatalf("FindAllLecture: %v", errMsg.Msg)
+	}
+	if len(lectures) != 1 || lectures[0].Name != "Dimas Saputra" {
+		t.Fatalf("expected the lecture of the lecturer by NIDN, got %+v", lectures)
+	}
+}
@@ -0,0 +1,157 @@
+// Package spreadsheet reads and writes the tabular files used for bulk import
+// and export: CSV and XLSX. The first row of a file is its header.
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	XLSX   Format = "xlsx"
+	NDJSON Format = "ndjson"
+)
+
+var ErrUnknownFormat = errors.New("Format file harus csv, xlsx atau ndjson.")
+
+// ParseFormat accepts a format name or a file name with a .csv, .xlsx or
+// .ndjson extension.
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if ext := filepath.Ext(name); ext != "" {
+		name = strings.TrimPrefix(ext, ".")
+	}
+
+	switch Format(name) {
+	case CSV, XLSX, NDJSON:
+		return Format(name), nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Record is one data row keyed by the lower-cased header names. Line is the
+// row number in the file, counting the header as line 1.
+type Record struct {
+	Line   int
+	Fields map[string]string
+}
+
+func (r Record) Get(column string) string {
+	return r.Fields[column]
+}
+
+// Read reads every non-empty row after the header. It fails when the file
+// holds more than maxRows data rows; maxRows <= 0 means no limit.
+func Read(r io.Reader, format Format, maxRows int) ([]Record, error) {
+	var rows []row
+	var err error
+	switch format {
+	case CSV:
+		rows, err = readCSV(r)
+	case XLSX:
+		rows, err = readXLSX(r)
+	default:
+		return nil, fmt.Errorf("Format %s tidak didukung untuk import, gunakan csv atau xlsx.", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("File kosong, baris header tidak ditemukan.")
+	}
+
+	header := make([]string, len(rows[0].values))
+	for i, name := range rows[0].values {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	var records []Record
+	for _, row := range rows[1:] {
+		if isBlank(row.values) {
+			continue
+		}
+		if maxRows > 0 && len(records) == maxRows {
+			return nil, fmt.Errorf("File berisi lebih dari %d baris data.", maxRows)
+		}
+
+		fields := make(map[string]string, len(header))
+		for j, name := range header {
+			if name != "" && j < len(row.values) {
+				fields[name] = strings.TrimSpace(row.values[j])
+			}
+		}
+		records = append(records, Record{Line: row.line, Fields: fields})
+	}
+	return records, nil
+}
+
+// row is one line of the file with its line number; the CSV reader skips
+// empty lines, so the number cannot be derived from the position.
+type row struct {
+	line   int
+	values []string
+}
+
+func readCSV(r io.Reader) ([]row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []row
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("File CSV tidak valid: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, row{line: line, values: values})
+	}
+	if len(rows) > 0 && len(rows[0].values) > 0 {
+		rows[0].values[0] = strings.TrimPrefix(rows[0].values[0], "\ufeff")
+	}
+	return rows, nil
+}
+
+// readXLSX reads the first sheet of the workbook.
+func readXLSX(r io.Reader) ([]row, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("File XLSX tidak valid: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	values, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("File XLSX tidak valid: %w", err)
+	}
+	rows := make([]row, len(values))
+	for i := range values {
+		rows[i] = row{line: i + 1, values: values[i]}
+	}
+	return rows, nil
+}
+
+func isBlank(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
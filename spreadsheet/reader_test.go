@@ -0,0 +1,58 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"csv": CSV, "XLSX": XLSX, "mahasiswa.csv": CSV, "data/Kelas.XLSX": XLSX} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("data.xls"); err == nil {
+		t.Error("expected xls to be rejected")
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	records, err := Read(strings.NewReader("\ufeffName, Email\nBudi, budi@mail.com\n,\nSiti\n"), CSV, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].Get("name") != "Budi" || records[0].Get("email") != "budi@mail.com" {
+		t.Fatalf("unexpected first record %+v", records[0])
+	}
+	if records[1].Line != 4 || records[1].Get("email") != "" {
+		t.Fatalf("unexpected short record %+v", records[1])
+	}
+
+	if _, err := Read(strings.NewReader("name\nA\nB\nC\n"), CSV, 2); err == nil {
+		t.Fatal("expected the row limit to be enforced")
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	f.SetSheetRow("Sheet1", "A1", &[]any{"kode_matkul", "name"})
+	f.SetSheetRow("Sheet1", "A2", &[]any{"IF101", "Algoritma"})
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := Read(&buf, XLSX, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Get("kode_matkul") != "IF101" || records[0].Line != 2 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+}
@@ -0,0 +1,171 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// flushEvery is how many rows the CSV and NDJSON writers buffer before
+// flushing, including the underlying writer when it can flush (an
+// http.ResponseWriter), so large exports reach the client while they run.
+const flushEvery = 100
+
+// Writer writes one table. WriteHeader must be called once before WriteRow;
+// Close finishes the file and must be called even when no row was written.
+type Writer interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []any) error
+	Close() error
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case NDJSON:
+		return "application/x-ndjson"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{out: w, csv: csv.NewWriter(w)}, nil
+	case XLSX:
+		return newXLSXWriter(w)
+	case NDJSON:
+		return &ndjsonWriter{out: w, encoder: json.NewEncoder(w)}, nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+type flusher interface {
+	Flush()
+}
+
+type csvWriter struct {
+	out  io.Writer
+	csv  *csv.Writer
+	rows int
+}
+
+func (w *csvWriter) WriteHeader(columns []string) error {
+	return w.csv.Write(columns)
+}
+
+func (w *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = fmt.Sprint(value)
+	}
+	if err := w.csv.Write(record); err != nil {
+		return err
+	}
+
+	if w.rows++; w.rows%flushEvery == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *csvWriter) flush() error {
+	w.csv.Flush()
+	if f, ok := w.out.(flusher); ok {
+		f.Flush()
+	}
+	return w.csv.Error()
+}
+
+func (w *csvWriter) Close() error {
+	return w.flush()
+}
+
+// ndjsonWriter writes every row as one JSON object keyed by the header.
+type ndjsonWriter struct {
+	out     io.Writer
+	encoder *json.Encoder
+	columns []string
+	rows    int
+}
+
+func (w *ndjsonWriter) WriteHeader(columns []string) error {
+	w.columns = columns
+	return nil
+}
+
+func (w *ndjsonWriter) WriteRow(values []any) error {
+	object := make(map[string]any, len(w.columns))
+	for i, column := range w.columns {
+		if i < len(values) {
+			object[column] = values[i]
+		}
+	}
+	if err := w.encoder.Encode(object); err != nil {
+		return err
+	}
+
+	if w.rows++; w.rows%flushEvery == 0 {
+		if f, ok := w.out.(flusher); ok {
+			f.Flush()
+		}
+	}
+	return nil
+}
+
+func (w *ndjsonWriter) Close() error {
+	if f, ok := w.out.(flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// xlsxWriter writes rows through the excelize stream writer, which keeps
+// memory flat by spilling rows to a temporary file. An XLSX file is a zip
+// archive, so it reaches w only on Close.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: file, stream: stream}, nil
+}
+
+func (w *xlsxWriter) WriteHeader(columns []string) error {
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		values[i] = column
+	}
+	return w.WriteRow(values)
+}
+
+func (w *xlsxWriter) WriteRow(values []any) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.Write(w.out)
+}
@@ -0,0 +1,38 @@
+package storage
+
+import "fmt"
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Config picks and sets up the backend Open returns.
+type Config struct {
+	// Backend is BackendLocal or BackendS3.
+	Backend string
+	// Dir, PublicURL and Secret set up Local: the directory files are kept
+	// in, the URL the API is reached at, prefixed to LocalPath in signed
+	// URLs, and the key they are signed with.
+	Dir       string
+	PublicURL string
+	Secret    []byte
+	S3        S3Config
+	// Quota is the bytes each user may store, zero for no limit.
+	Quota int64
+}
+
+// Open returns the backend of config behind its quota.
+func Open(config Config) (*Quota, error) {
+	switch config.Backend {
+	case BackendLocal, "":
+		return NewQuota(NewLocal(config.Dir, config.PublicURL+LocalPath, config.Secret), config.Quota), nil
+	case BackendS3:
+		backend, err := NewS3(config.S3)
+		if err != nil {
+			return nil, err
+		}
+		return NewQuota(backend, config.Quota), nil
+	}
+	return nil, fmt.Errorf("storage: backend %q tidak dikenal, pilih %s atau %s", config.Backend, BackendLocal, BackendS3)
+}
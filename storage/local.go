@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalPath is where the API serves the signed URLs of Local.
+const LocalPath = "/api/v2/files"
+
+// Local stores each file at its key under Dir. The content type is not kept
+// but told from the extension of the key, or sniffed when it has none.
+// Signed URLs point at BaseURL, where the API serves them after VerifyURL,
+// and are signed with Secret.
+type Local struct {
+	Dir     string
+	BaseURL string
+	Secret  []byte
+}
+
+func NewLocal(dir string, baseURL string, secret []byte) *Local {
+	return &Local{Dir: dir, BaseURL: strings.TrimSuffix(baseURL, "/"), Secret: secret}
+}
+
+func (l *Local) path(key string) (string, error) {
+	if err := CheckKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.Dir, filepath.FromSlash(key)), nil
+}
+
+// tempPrefix names the files Put writes before renaming them into place.
+const tempPrefix = ".upload-"
+
+// Put writes content to a temporary file next to the final one and renames
+// it into place, so readers never see a partial file.
+func (l *Local) Put(ctx context.Context, key string, content io.Reader, info Info) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(name), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), name)
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, *Info, error) {
+	name, err := l.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	if stat.IsDir() {
+		file.Close()
+		return nil, nil, ErrNotFound
+	}
+
+	info := &Info{ContentType: mime.TypeByExtension(path.Ext(key)), Size: stat.Size(), ModTime: stat.ModTime()}
+	if info.ContentType == "" {
+		contentType, _, err := Sniff(file)
+		if err == nil {
+			_, err = file.Seek(0, io.SeekStart)
+		}
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		info.ContentType = contentType
+	}
+	return file, info, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	name, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Walk(ctx context.Context, prefix string, fn func(key string, info Info) error) error {
+	if err := checkPrefix(prefix); err != nil {
+		return err
+	}
+
+	root := filepath.Join(l.Dir, filepath.FromSlash(prefix))
+	err := filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), tempPrefix) {
+			return nil
+		}
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		return fn(prefix+filepath.ToSlash(rel), Info{Size: stat.Size(), ModTime: stat.ModTime()})
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// SignedURL returns BaseURL/key with the expiry, as Unix seconds, and its
+// HMAC signature in the expires and signature query.
+func (l *Local) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if err := CheckKey(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {l.sign(key, expires)}}
+	return l.BaseURL + "/" + escapePath(key) + "?" + query.Encode(), nil
+}
+
+// VerifyURL checks the expires and signature query of a URL from SignedURL
+// for key.
+func (l *Local) VerifyURL(key string, expires, signature string, now time.Time) error {
+	if CheckKey(key) != nil || !hmac.Equal([]byte(signature), []byte(l.sign(key, expires))) {
+		return ErrSignature
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	if now.Unix() > unix {
+		return ErrExpired
+	}
+	return nil
+}
+
+func (l *Local) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, l.Secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
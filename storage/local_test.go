@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testBackend runs the behaviour every Storage shares against s.
+func testBackend(t *testing.T, s Storage) {
+	t.Helper()
+	ctx := context.Background()
+
+	put := func(key, content string, info Info) {
+		t.Helper()
+		if err := s.Put(ctx, key, strings.NewReader(content), info); err != nil {
+			t.Fatalf("Put(%q): %v", key, err)
+		}
+	}
+	put("users/1/a.txt", "first", Info{ContentType: "text/plain; charset=utf-8"})
+	put("users/1/a.txt", "second", Info{ContentType: "text/plain; charset=utf-8", Size: 6})
+	put("users/1/nested/b", "%PDF-1.4 isi", Info{})
+	put("users/2/c.txt", "", Info{ContentType: "text/plain; charset=utf-8"})
+
+	file, info, err := s.Open(ctx, "users/1/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	if string(content) != "second" || info.Size != 6 || info.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("Open = %q, %+v", content, info)
+	}
+
+	// Without a type or an extension the content tells it.
+	file, info, err = s.Open(ctx, "users/1/nested/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ = io.ReadAll(file)
+	file.Close()
+	if string(content) != "%PDF-1.4 isi" || info.ContentType != "application/pdf" {
+		t.Errorf("Open sniffed = %q, %+v", content, info)
+	}
+
+	var keys []string
+	var total int64
+	err = s.Walk(ctx, "users/1/", func(key string, info Info) error {
+		keys = append(keys, key)
+		total += info.Size
+		return nil
+	})
+	sort.Strings(keys)
+	if err != nil || strings.Join(keys, ",") != "users/1/a.txt,users/1/nested/b" || total != 18 {
+		t.Errorf("Walk = %v, %d, %v", keys, total, err)
+	}
+	if err := s.Walk(ctx, "users/9/", func(string, Info) error { return errors.New("tidak ada file") }); err != nil {
+		t.Errorf("Walk of an empty prefix: %v", err)
+	}
+	if err := s.Walk(ctx, "users/1", func(string, Info) error { return nil }); err == nil {
+		t.Error("Walk accepted a prefix without a trailing slash")
+	}
+
+	if err := s.Delete(ctx, "users/1/a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Open(ctx, "users/1/a.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete: %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "users/1/a.txt"); err != nil {
+		t.Errorf("Delete of a missing key: %v", err)
+	}
+	if err := s.Put(ctx, "../escape", strings.NewReader("x"), Info{}); err == nil {
+		t.Error("Put accepted a key climbing out of the storage")
+	}
+}
+
+func TestLocal(t *testing.T) {
+	local := NewLocal(t.TempDir(), "http://localhost:8081"+LocalPath, []byte("rahasia"))
+	testBackend(t, local)
+
+	if _, _, err := local.Open(context.Background(), "users"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open of a directory: %v, want ErrNotFound", err)
+	}
+
+	signed, err := local.SignedURL(context.Background(), "users/1/nested/b", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Path != LocalPath+"/users/1/nested/b" {
+		t.Fatalf("SignedURL path = %q", u.Path)
+	}
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+	now := time.Now()
+	if err := local.VerifyURL("users/1/nested/b", expires, signature, now); err != nil {
+		t.Errorf("VerifyURL: %v", err)
+	}
+	if err := local.VerifyURL("users/1/a.txt", expires, signature, now); !errors.Is(err, ErrSignature) {
+		t.Errorf("VerifyURL of another key: %v, want ErrSignature", err)
+	}
+	if err := local.VerifyURL("users/1/nested/b", expires+"0", signature, now); !errors.Is(err, ErrSignature) {
+		t.Errorf("VerifyURL of a changed expiry: %v, want ErrSignature", err)
+	}
+	if err := local.VerifyURL("users/1/nested/b", expires, signature, now.Add(2*time.Minute)); !errors.Is(err, ErrExpired) {
+		t.Errorf("VerifyURL after expiry: %v, want ErrExpired", err)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	quota := NewQuota(NewLocal(t.TempDir(), LocalPath, nil), 10)
+
+	if err := quota.Put(ctx, "users/1/a", strings.NewReader("123456"), Info{Size: 6}); err != nil {
+		t.Fatal(err)
+	}
+	if err := quota.Put(ctx, "users/1/b", strings.NewReader("12345"), Info{Size: 5}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Put over the quota: %v, want ErrQuotaExceeded", err)
+	}
+	// An unknown size is caught while writing and leaves nothing behind.
+	if err := quota.Put(ctx, "users/1/b", strings.NewReader("12345"), Info{}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Put of unknown size over the quota: %v, want ErrQuotaExceeded", err)
+	}
+	if _, _, err := quota.Open(ctx, "users/1/b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open of a rejected file: %v, want ErrNotFound", err)
+	}
+	// Replacing a file only counts the new one.
+	if err := quota.Put(ctx, "users/1/a", strings.NewReader("1234567890"), Info{}); err != nil {
+		t.Errorf("Put replacing a file: %v", err)
+	}
+	// Other users and keys outside a user's files are not held to it.
+	if err := quota.Put(ctx, "users/2/a", bytes.NewReader(make([]byte, 10)), Info{Size: 10}); err != nil {
+		t.Errorf("Put of another user: %v", err)
+	}
+	if err := quota.Put(ctx, "shared/a", bytes.NewReader(make([]byte, 20)), Info{Size: 20}); err != nil {
+		t.Errorf("Put outside the users: %v", err)
+	}
+
+	if used, err := quota.Usage(ctx, 1); err != nil || used != 10 {
+		t.Errorf("Usage = %d, %v; want 10", used, err)
+	}
+	if err := quota.VerifyURL("users/1/a", "1", "x", time.Now()); !errors.Is(err, ErrSignature) {
+		t.Errorf("VerifyURL through the quota: %v, want ErrSignature", err)
+	}
+}
+
+func TestCheckKey(t *testing.T) {
+	for _, key := range []string{"", "/etc/passwd", "../secret", "..", "a/../../b", "a//b", "a/./b"} {
+		if err := CheckKey(key); err == nil {
+			t.Errorf("CheckKey(%q) accepted", key)
+		}
+	}
+	for _, key := range []string{"a", "users/1/avatars/3f2a.jpg", "a..b/c"} {
+		if err := CheckKey(key); err != nil {
+			t.Errorf("CheckKey(%q): %v", key, err)
+		}
+	}
+}
+
+func TestKeyUser(t *testing.T) {
+	for key, want := range map[string]int{"users/12/a": 12, "users/12/": 12, "users/0/a": 0, "users/x/a": 0, "users/12": 0, "shared/12/a": 0} {
+		if got, _ := keyUser(key); got != want {
+			t.Errorf("keyUser(%q) = %d, want %d", key, got, want)
+		}
+	}
+}
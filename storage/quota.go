@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Quota caps the bytes each user stores under their UserPrefix at Limit,
+// zero meaning no limit; other keys are not counted. The usage is summed
+// from the backend on every Put, so two uploads of one user at the same time
+// may together overshoot by the smaller of them.
+type Quota struct {
+	Storage
+	Limit int64
+}
+
+func NewQuota(backend Storage, limit int64) *Quota {
+	return &Quota{Storage: backend, Limit: limit}
+}
+
+// Usage returns the bytes userID stores.
+func (q *Quota) Usage(ctx context.Context, userID int) (int64, error) {
+	return q.usage(ctx, userID, "")
+}
+
+// usage sums the files of userID but the one under except, which a Put of
+// that key replaces.
+func (q *Quota) usage(ctx context.Context, userID int, except string) (int64, error) {
+	var used int64
+	err := q.Storage.Walk(ctx, UserPrefix(userID), func(key string, info Info) error {
+		if key != except {
+			used += info.Size
+		}
+		return nil
+	})
+	return used, err
+}
+
+// Put stores a file of a user when it fits in what is left of the quota,
+// checking a content of unknown size while it is written.
+func (q *Quota) Put(ctx context.Context, key string, content io.Reader, info Info) error {
+	userID, ok := keyUser(key)
+	if !ok || q.Limit <= 0 {
+		return q.Storage.Put(ctx, key, content, info)
+	}
+
+	used, err := q.usage(ctx, userID, key)
+	if err != nil {
+		return err
+	}
+	left := q.Limit - used
+	if info.Size > left {
+		return ErrQuotaExceeded
+	}
+	return q.Storage.Put(ctx, key, &quotaReader{r: content, left: left}, info)
+}
+
+// VerifyURL checks a signed URL when the backend serves its own through the API.
+func (q *Quota) VerifyURL(key string, expires, signature string, now time.Time) error {
+	verifier, ok := q.Storage.(URLVerifier)
+	if !ok {
+		return ErrSignature
+	}
+	return verifier.VerifyURL(key, expires, signature, now)
+}
+
+// quotaReader fails with ErrQuotaExceeded once more than left bytes are read.
+type quotaReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (r *quotaReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.left -= int64(n)
+	if r.left < 0 {
+		return n, ErrQuotaExceeded
+	}
+	return n, err
+}
@@ -0,0 +1,343 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type S3Config struct {
+	// Endpoint is the base URL of the service, such as
+	// https://s3.ap-southeast-1.amazonaws.com or http://localhost:9000.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses objects as Endpoint/Bucket/key, as MinIO expects,
+	// instead of on the bucket's own host.
+	PathStyle bool
+}
+
+// S3 stores each file as the object of its key in a bucket of an
+// S3-compatible service, talking to it over plain HTTP with requests signed
+// by AWS Signature Version 4. Payloads are sent unsigned, which S3 and MinIO
+// accept over HTTPS and plain HTTP alike.
+type S3 struct {
+	Config S3Config
+	Client *http.Client
+
+	endpoint *url.URL
+	now      func() time.Time
+}
+
+func NewS3(config S3Config) (*S3, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("storage: endpoint S3 %q tidak valid", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("storage: bucket S3 wajib diisi")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/")
+	return &S3{Config: config, Client: http.DefaultClient, endpoint: endpoint, now: time.Now}, nil
+}
+
+// objectURL is the URL of key, or of the bucket itself for an empty key.
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.Config.PathStyle {
+		u.Path += "/" + s.Config.Bucket + "/" + key
+	} else {
+		u.Host = s.Config.Bucket + "." + u.Host
+		u.Path += "/" + key
+	}
+	// SigV4 signs the path as escapePath writes it; Go would leave some
+	// reserved characters as they are.
+	u.RawPath = escapePath(u.Path)
+	return &u
+}
+
+func (s *S3) Put(ctx context.Context, key string, content io.Reader, info Info) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+	if info.ContentType == "" {
+		var err error
+		if info.ContentType, content, err = Sniff(content); err != nil {
+			return err
+		}
+	}
+	// S3 needs the length up front; a content of unknown size is read first.
+	if info.Size <= 0 {
+		body, err := io.ReadAll(content)
+		if err != nil {
+			return err
+		}
+		content, info.Size = bytes.NewReader(body), int64(len(body))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key).String(), content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size
+	if info.Size == 0 {
+		req.Body = http.NoBody
+	}
+	req.Header.Set("Content-Type", info.ContentType)
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, *Info, error) {
+	if err := CheckKey(key); err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key).String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := s.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &Info{ContentType: res.Header.Get("Content-Type"), Size: res.ContentLength}
+	info.ModTime, _ = http.ParseTime(res.Header.Get("Last-Modified"))
+	return res.Body, info, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := CheckKey(key); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string
+		Size         int64
+		LastModified time.Time
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// Walk pages through ListObjectsV2 of prefix.
+func (s *S3) Walk(ctx context.Context, prefix string, fn func(key string, info Info) error) error {
+	if err := checkPrefix(prefix); err != nil {
+		return err
+	}
+
+	var token string
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u := s.objectURL("")
+		u.RawQuery = canonicalQuery(query)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return err
+		}
+		res, err := s.do(req)
+		if err != nil {
+			return err
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return fmt.Errorf("storage: daftar objek S3 tidak dapat dibaca: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			if err := fn(object.Key, Info{Size: object.Size, ModTime: object.LastModified}); err != nil {
+				return err
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// SignedURL presigns a GET of key; S3 allows at most seven days.
+func (s *S3) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if err := CheckKey(key); err != nil {
+		return "", err
+	}
+	if ttl < time.Second || ttl > 7*24*time.Hour {
+		return "", fmt.Errorf("storage: masa berlaku URL S3 harus antara 1 detik dan 7 hari")
+	}
+
+	now := s.now().UTC()
+	u := s.objectURL(key)
+	query := url.Values{
+		"X-Amz-Algorithm":     {algorithm},
+		"X-Amz-Credential":    {s.Config.AccessKeyID + "/" + s.scope(now)},
+		"X-Amz-Date":          {now.Format(amzDateFormat)},
+		"X-Amz-Expires":       {strconv.Itoa(int(ttl / time.Second))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	u.RawQuery = canonicalQuery(query)
+	header := http.Header{"Host": {u.Host}}
+	request := canonicalRequest(http.MethodGet, u, header, []string{"host"}, unsignedPayload)
+	u.RawQuery += "&X-Amz-Signature=" + s.signature(now, request)
+	return u.String(), nil
+}
+
+// do signs and sends req, turning an error answer into an error.
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	s.sign(req, s.now().UTC())
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 300 {
+		return res, nil
+	}
+	defer res.Body.Close()
+
+	var answer struct {
+		Code    string
+		Message string
+	}
+	xml.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(&answer)
+	if res.StatusCode == http.StatusNotFound && (answer.Code == "" || answer.Code == "NoSuchKey") {
+		return nil, ErrNotFound
+	}
+	return nil, fmt.Errorf("storage: S3 %s %s: %d %s %s", req.Method, req.URL.Path, res.StatusCode, answer.Code, answer.Message)
+}
+
+const (
+	algorithm       = "AWS4-HMAC-SHA256"
+	amzDateFormat   = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// sign adds the Authorization header of req, signing its host, content type
+// and x-amz headers.
+func (s *S3) sign(req *http.Request, now time.Time) {
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	header := req.Header.Clone()
+	header.Set("Host", req.URL.Host)
+	var signed []string
+	for name := range header {
+		name = strings.ToLower(name)
+		if name == "host" || name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			signed = append(signed, name)
+		}
+	}
+	sort.Strings(signed)
+
+	request := canonicalRequest(req.Method, req.URL, header, signed, unsignedPayload)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, s.Config.AccessKeyID, s.scope(now), strings.Join(signed, ";"), s.signature(now, request)))
+}
+
+func (s *S3) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.Config.Region + "/s3/aws4_request"
+}
+
+// signature signs the canonical request made at now.
+func (s *S3) signature(now time.Time, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := algorithm + "\n" + now.Format(amzDateFormat) + "\n" + s.scope(now) + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + s.Config.SecretAccessKey)
+	for _, part := range []string{now.Format("20060102"), s.Config.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalRequest is the request as SigV4 signs it, over the headers
+// named, lowercase and sorted, in signed.
+func canonicalRequest(method string, u *url.URL, header http.Header, signed []string, payloadHash string) string {
+	var headers strings.Builder
+	for _, name := range signed {
+		headers.WriteString(name + ":" + strings.TrimSpace(strings.Join(header.Values(name), ",")) + "\n")
+	}
+	return strings.Join([]string{method, u.EscapedPath(), canonicalQuery(u.Query()), headers.String(), strings.Join(signed, ";"), payloadHash}, "\n")
+}
+
+// canonicalQuery encodes query sorted by name, with SigV4's escaping.
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, escape(name, true)+"="+escape(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// escapePath escapes a path the way SigV4 expects, keeping the slashes.
+func escapePath(p string) string {
+	return escape(p, false)
+}
+
+// escape percent-encodes every byte of s but the unreserved characters of
+// RFC 3986 and, unless escapeSlash, the slash.
+func escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
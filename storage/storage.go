@@ -0,0 +1,118 @@
+// Package storage keeps uploaded files, such as avatars and course
+// materials, behind the Storage interface so the services do not depend on
+// where the bytes end up. Local stores them under a directory on disk, S3 in
+// a bucket of any S3-compatible service such as MinIO, and Quota caps what
+// each user may store on top of either.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned by Open for a key that holds no file.
+	ErrNotFound = errors.New("storage: file tidak ditemukan")
+	// ErrQuotaExceeded is returned by Quota.Put for a file that does not fit
+	// in what is left of its owner's quota.
+	ErrQuotaExceeded = errors.New("storage: kuota penyimpanan sudah penuh")
+	// ErrSignature and ErrExpired are returned by Local.VerifyURL for a
+	// download URL it did not sign or that is past its expiry.
+	ErrSignature = errors.New("storage: tanda tangan URL tidak valid")
+	ErrExpired   = errors.New("storage: URL sudah kedaluwarsa")
+)
+
+// Storage keeps files by key, a slash separated relative path such as
+// "users/1/avatars/3f2a9c.jpg". Put replaces a file stored under the same
+// key and Delete of a missing key is not an error.
+type Storage interface {
+	Put(ctx context.Context, key string, content io.Reader, info Info) error
+	Open(ctx context.Context, key string) (io.ReadCloser, *Info, error)
+	Delete(ctx context.Context, key string) error
+	// Walk calls fn with every file whose key starts with prefix, which is
+	// empty or ends with a slash, in no particular order.
+	Walk(ctx context.Context, prefix string, fn func(key string, info Info) error) error
+	// SignedURL returns a URL that downloads key without any other
+	// credentials until ttl has passed.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Info describes a stored file. Put takes the content type, sniffed from the
+// content when empty, and the size when it is known up front; Open and Walk
+// fill in what the backend knows.
+type Info struct {
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// URLVerifier is implemented by the backends whose signed URLs the API
+// serves itself, at LocalPath; S3 answers its own.
+type URLVerifier interface {
+	VerifyURL(key string, expires, signature string, now time.Time) error
+}
+
+// CheckKey rejects keys that are empty, absolute, not clean or that climb out
+// of the storage with "..".
+func CheckKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("storage: key %q tidak valid", key)
+	}
+	return nil
+}
+
+func checkPrefix(prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		return fmt.Errorf("storage: prefix %q harus diakhiri /", prefix)
+	}
+	return CheckKey(strings.TrimSuffix(prefix, "/"))
+}
+
+// UserPrefix is the prefix of the files userID owns; they count against the
+// user's quota.
+func UserPrefix(userID int) string {
+	return "users/" + strconv.Itoa(userID) + "/"
+}
+
+// UserKey is the key of name among the files of userID.
+func UserKey(userID int, name string) string {
+	return UserPrefix(userID) + name
+}
+
+// keyUser returns the user whose files hold key.
+func keyUser(key string) (int, bool) {
+	rest := strings.TrimPrefix(key, "users/")
+	if rest == key {
+		return 0, false
+	}
+	id, _, found := strings.Cut(rest, "/")
+	userID, err := strconv.Atoi(id)
+	if !found || err != nil || userID < 1 {
+		return 0, false
+	}
+	return userID, true
+}
+
+// Sniff tells the content type of content from its first 512 bytes, the
+// way http.DetectContentType does, ignoring whatever the client claimed, and
+// returns a reader that still yields all of content.
+func Sniff(content io.Reader) (string, io.Reader, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", nil, err
+	}
+	head = head[:n]
+	return http.DetectContentType(head), io.MultiReader(bytes.NewReader(head), content), nil
+}
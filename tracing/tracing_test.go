@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := Setup(ctx, Config{Exporter: "jaeger"}); err == nil {
+		t.Error("expected an error for an unknown exporter")
+	}
+	if _, err := Setup(ctx, Config{Exporter: ExporterOTLP, Endpoint: "localhost"}); err == nil {
+		t.Error("expected an error for an endpoint without scheme")
+	}
+
+	var out bytes.Buffer
+	shutdown, err := Setup(ctx, Config{Exporter: ExporterStdout, ServiceName: "sinaustudio-test", Stdout: &out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := Start(ctx, "ClassService.AddClass")
+	span.End()
+	if err := shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"Name":"ClassService.AddClass"`) || !strings.Contains(out.String(), "sinaustudio-test") {
+		t.Fatalf("expected the span on stdout, got %s", out.String())
+	}
+}
+
+func TestOTLPOptions(t *testing.T) {
+	for endpoint, want := range map[string]int{"": 0, "https://collector:4318": 1, "http://localhost:4318": 2, "http://localhost:4318/otlp/v1/traces": 3} {
+		options, err := otlpOptions(endpoint)
+		if err != nil || len(options) != want {
+			t.Errorf("otlpOptions(%q) = %d options, %v; want %d", endpoint, len(options), err, want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dimassfeb-09/sinaustudio.git/api"
+	"github.com/dimassfeb-09/sinaustudio.git/codegen"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/domain"
+	"github.com/dimassfeb-09/sinaustudio.git/entity/requests"
+	"github.com/dimassfeb-09/sinaustudio.git/services"
+)
+
+// userResult is printed by the user commands. Password is only set when the
+// command generated it.
+type userResult struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	ClassID  int    `json:"class_id,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// runUser implements "sinaustudio user create|reset-password|set-role".
+func runUser(args []string) {
+	subcommands := map[string]func(args []string){
+		"create":         runUserCreate,
+		"reset-password": runUserResetPassword,
+		"set-role":       runUserSetRole,
+	}
+	if len(args) == 0 || subcommands[args[0]] == nil {
+		fmt.Fprintln(os.Stderr, "Usage: sinaustudio user create|reset-password|set-role [flags]")
+		os.Exit(2)
+	}
+	subcommands[args[0]](args[1:])
+}
+
+func runUserCreate(args []string) {
+	flags := flag.NewFlagSet("user create", flag.ExitOnError)
+	name := flags.String("name", "", "nama user")
+	email := flags.String("email", "", "email user")
+	role := flags.String("role", "", "role: "+strings.Join(services.Roles, ", "))
+	classID := flags.Int("class", 0, "ID kelas, wajib kecuali untuk admin")
+	password := flags.String("password", "", "password awal, dibuat acak bila kosong")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio user create -name NAME -email EMAIL -role ROLE [-class ID] [-password PASSWORD]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *name == "" || *email == "" || *role == "" || (*classID == 0 && *role != domain.RoleAdmin) {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if !services.IsRole(*role) {
+		fatal(fmt.Errorf("role %q tidak dikenal, gunakan %s", *role, strings.Join(services.Roles, ", ")))
+	}
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = codegen.Password.Generate(); err != nil {
+			fatal(err)
+		}
+	}
+
+	config, db := openDatabase()
+	defer db.Close()
+	ctx := systemContext()
+	m := api.NewSQLMicroService(config.Dialect)
+
+	// Dosen and mahasiswa are created the way they register, which also gives
+	// a dosen their lecture; admins cannot register and have no class.
+	if *role == domain.RoleAdmin {
+		r := &requests.UserInsertRequest{Name: *name, Email: *email, Password: *password, Role: *role, ClassID: *classID}
+		if _, errMsg := services.NewUserServiceImplementation(db, m).InsertDataUser(ctx, r); errMsg != nil {
+			fatalMsg(errMsg)
+		}
+	} else {
+		r := &requests.AuthRegisterRequest{Name: *name, Email: *email, Password: *password, Role: *role, ClassID: *classID}
+		if _, errMsg := services.NewAuthServiceImplementation(db, m).AuthRegisterUser(ctx, r); errMsg != nil {
+			fatalMsg(errMsg)
+		}
+	}
+
+	user, errMsg := services.NewUserServiceImplementation(db, m).FindUserByEmail(ctx, *email)
+	if errMsg != nil {
+		fatalMsg(errMsg)
+	}
+	result := toUserResult(user)
+	if generated {
+		result.Password = *password
+	}
+	printJSON(result)
+}
+
+func runUserResetPassword(args []string) {
+	flags := flag.NewFlagSet("user reset-password", flag.ExitOnError)
+	ID := flags.Int("id", 0, "ID user")
+	email := flags.String("email", "", "email user, sebagai ganti -id")
+	password := flags.String("password", "", "password baru, dibuat acak bila kosong")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio user reset-password -id ID|-email EMAIL [-password PASSWORD]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if (*ID == 0) == (*email == "") {
+		flags.Usage()
+		os.Exit(2)
+	}
+	generated := *password == ""
+	if generated {
+		var err error
+		if *password, err = codegen.Password.Generate(); err != nil {
+			fatal(err)
+		}
+	}
+
+	config, db := openDatabase()
+	defer db.Close()
+	ctx := systemContext()
+	usersService := services.NewUserServiceImplementation(db, api.NewSQLMicroService(config.Dialect))
+
+	user := findUser(ctx, usersService, *ID, *email)
+	if _, errMsg := usersService.ResetPassword(ctx, user.ID, *password); errMsg != nil {
+		fatalMsg(errMsg)
+	}
+	result := toUserResult(user)
+	if generated {
+		result.Password = *password
+	}
+	printJSON(result)
+}
+
+func runUserSetRole(args []string) {
+	flags := flag.NewFlagSet("user set-role", flag.ExitOnError)
+	ID := flags.Int("id", 0, "ID user")
+	email := flags.String("email", "", "email user, sebagai ganti -id")
+	role := flags.String("role", "", "role baru: "+strings.Join(services.Roles, ", "))
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: sinaustudio user set-role -id ID|-email EMAIL -role ROLE")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if (*ID == 0) == (*email == "") || *role == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	config, db := openDatabase()
+	defer db.Close()
+	ctx := systemContext()
+	usersService := services.NewUserServiceImplementation(db, api.NewSQLMicroService(config.Dialect))
+
+	user := findUser(ctx, usersService, *ID, *email)
+	user, errMsg := usersService.SetRole(ctx, user.ID, *role)
+	if errMsg != nil {
+		fatalMsg(errMsg)
+	}
+	printJSON(toUserResult(user))
+}
+
+// findUser looks the user up by ID, or by email when ID is 0.
+func findUser(ctx context.Context, usersService services.UsersService, ID int, email string) *domain.Users {
+	if ID == 0 {
+		user, errMsg := usersService.FindUserByEmail(ctx, email)
+		if errMsg != nil {
+			fatalMsg(errMsg)
+		}
+		return user
+	}
+	user, errMsg := usersService.FindUserByID(ctx, ID)
+	if errMsg != nil {
+		fatalMsg(errMsg)
+	}
+	return user
+}
+
+func toUserResult(user *domain.Users) *userResult {
+	return &userResult{ID: user.ID, Name: user.Name, Email: user.Email, Role: user.Role, ClassID: user.ClassID}
+}